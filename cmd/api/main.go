@@ -8,6 +8,7 @@ import (
 	"loan-billing-system/config"
 	"loan-billing-system/internal/api"
 	"loan-billing-system/internal/db"
+	"loan-billing-system/internal/pii"
 	"loan-billing-system/internal/repositories"
 	"loan-billing-system/internal/scheduler"
 	"loan-billing-system/internal/services"
@@ -24,6 +25,14 @@ import (
 // @description API for managing loans, borrowers, payments, and delinquency status
 // @host localhost:8080
 // @BasePath /api
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description Staff JWT or partner API key, as "Bearer <token>"
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name X-API-Key
+// @description Partner API key
 
 func main() {
 	// Load configuration
@@ -32,6 +41,9 @@ func main() {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
+	// Encrypt borrower PII with the configured keys
+	pii.Use(cfg.PII)
+
 	// Connect to database
 	database, err := db.Connect(cfg.DB)
 	if err != nil {
@@ -48,11 +60,14 @@ func main() {
 	repoManager := repositories.NewGormRepositoryManager(database)
 
 	// Initialize services
-	loanService := services.NewLoanService(repoManager)
-	borrowerService := services.NewBorrowerService(repoManager)
+	loanService := services.NewLoanService(repoManager).WithPolicy(cfg.Loan).WithScorer(cfg.Scorecard).WithAccess(cfg.Access)
+	log.Printf("Scoring loan applications with scorecard %s", cfg.Scorecard.Version)
+	borrowerService := services.NewBorrowerService(repoManager).WithPolicy(cfg.Borrower).WithAccess(cfg.Access)
+	auditService := services.NewAuditService(repoManager)
+	authService := services.NewAuthService(repoManager, cfg.Auth).WithAccess(cfg.Access)
 
 	// Set up scheduler
-	scheduler := scheduler.NewScheduler(database, loanService)
+	scheduler := scheduler.NewScheduler(database, loanService, borrowerService)
 	scheduler.Start()
 	defer scheduler.Stop()
 
@@ -75,7 +90,7 @@ func main() {
 	})
 
 	// Set up API routes
-	api.SetupRoutes(e, database, borrowerService, loanService)
+	api.SetupRoutes(e, database, borrowerService, loanService, auditService, authService, cfg.Access)
 
 	// Start server
 	serverAddr := fmt.Sprintf(":%s", cfg.Server.Port)
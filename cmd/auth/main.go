@@ -0,0 +1,183 @@
+// Command auth manages the keys staff and borrower tokens are signed with.
+//
+//	auth keygen <private key file> [jwks file]  creates an ES256 signing key and adds its public key to the key set (default $JWKS_FILE)
+//	auth token -key <private key file> -sub <subject> [-name <name>] [-roles a,b] [-borrower <id>] [-tenant <id>] [-ttl 8h]
+//	                                             signs a staff token, or with -borrower a token scoped
+//	                                             to one borrower, for development and for scripts;
+//	                                             -tenant names the tenant it acts for (default: the default tenant)
+//
+// Tokens are normally issued by the identity provider; the API only needs
+// its public keys in JWKS_FILE. To rotate keys, run keygen, sign new tokens
+// with the new key, and remove the old key from the key set once the tokens
+// signed with it have expired.
+package main
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"loan-billing-system/internal/auth"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		log.Fatal("usage: auth keygen <private key file> [jwks file] | auth token -key <private key file> -sub <subject>")
+	}
+
+	switch os.Args[1] {
+	case "keygen":
+		if len(os.Args) < 3 {
+			log.Fatal("usage: auth keygen <private key file> [jwks file]")
+		}
+		jwksPath := os.Getenv("JWKS_FILE")
+		if len(os.Args) > 3 {
+			jwksPath = os.Args[3]
+		}
+		if jwksPath == "" {
+			log.Fatal("usage: auth keygen <private key file> <jwks file>, or set JWKS_FILE")
+		}
+		kid, err := keygen(os.Args[2], jwksPath)
+		if err != nil {
+			log.Fatalf("Failed to generate key: %v", err)
+		}
+		log.Printf("Wrote private key %s to %s and added it to %s", kid, os.Args[2], jwksPath)
+	case "token":
+		token, err := sign(os.Args[2:])
+		if err != nil {
+			log.Fatalf("Failed to sign token: %v", err)
+		}
+		fmt.Println(token)
+	default:
+		log.Fatalf("unknown command %q", os.Args[1])
+	}
+}
+
+// keygen writes a new private key and adds its public key to the key set,
+// creating the key set if it does not exist yet
+func keygen(keyPath, jwksPath string) (string, error) {
+	var jwks auth.JWKS
+	data, err := os.ReadFile(jwksPath)
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(data, &jwks); err != nil {
+			return "", fmt.Errorf("failed to parse %s: %w", jwksPath, err)
+		}
+	case !errors.Is(err, os.ErrNotExist):
+		return "", err
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return "", err
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return "", err
+	}
+	jwk, err := auth.NewJWK(key.Public())
+	if err != nil {
+		return "", err
+	}
+
+	// Never overwrite a private key: tokens may still be signed with it
+	file, err := os.OpenFile(keyPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return "", err
+	}
+	if err := pem.Encode(file, &pem.Block{Type: "PRIVATE KEY", Bytes: der}); err != nil {
+		file.Close()
+		return "", err
+	}
+	if err := file.Close(); err != nil {
+		return "", err
+	}
+
+	jwks.Keys = append(jwks.Keys, jwk)
+	data, err = json.MarshalIndent(jwks, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return jwk.Kid, os.WriteFile(jwksPath, append(data, '\n'), 0o644)
+}
+
+// sign issues a staff or borrower token with the private key named on the command line
+func sign(args []string) (string, error) {
+	flags := flag.NewFlagSet("token", flag.ExitOnError)
+	keyPath := flags.String("key", "", "private key file written by keygen")
+	subject := flags.String("sub", "", "staff member the token is issued to")
+	name := flags.String("name", "", "display name")
+	roles := flags.String("roles", "", "comma-separated roles")
+	borrower := flags.String("borrower", "", "borrower ID to scope the token to, for borrower self-service")
+	tenantID := flags.String("tenant", "", "tenant the token acts for (default: the default tenant)")
+	ttl := flags.Duration("ttl", 8*time.Hour, "how long the token is valid")
+	issuer := flags.String("iss", os.Getenv("JWT_ISSUER"), "issuer (default $JWT_ISSUER)")
+	audience := flags.String("aud", os.Getenv("JWT_AUDIENCE"), "audience (default $JWT_AUDIENCE)")
+	if err := flags.Parse(args); err != nil {
+		return "", err
+	}
+	if *keyPath == "" || *subject == "" {
+		return "", errors.New("-key and -sub are required")
+	}
+
+	key, err := loadPrivateKey(*keyPath)
+	if err != nil {
+		return "", err
+	}
+	jwk, err := auth.NewJWK(key.Public())
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	claims := auth.Claims{
+		Issuer:     *issuer,
+		Subject:    *subject,
+		ExpiresAt:  now.Add(*ttl).Unix(),
+		IssuedAt:   now.Unix(),
+		Name:       *name,
+		BorrowerID: *borrower,
+		Tenant:     *tenantID,
+	}
+	if *audience != "" {
+		claims.Audience = auth.Audience{*audience}
+	}
+	for _, role := range strings.Split(*roles, ",") {
+		if role = strings.TrimSpace(role); role != "" {
+			claims.Roles = append(claims.Roles, role)
+		}
+	}
+	return auth.Sign(key, jwk.Kid, claims)
+}
+
+// loadPrivateKey reads a PKCS #8 private key from a PEM file
+func loadPrivateKey(path string) (crypto.Signer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%s holds no PEM block", path)
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T", auth.ErrUnsupportedKey, key)
+	}
+	return signer, nil
+}
@@ -0,0 +1,114 @@
+// Command pii manages the keys borrower PII is encrypted with.
+//
+//	pii keygen [file]  adds a new key to the key file (default $PII_KEY_FILE) and makes it current
+//	pii reencrypt      re-encrypts every PII column with the current key and rebuilds the blind indexes
+//
+// To rotate keys, run keygen, restart the API so new writes use the new key,
+// run reencrypt, and then remove the old key from the key file.
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"loan-billing-system/config"
+	"loan-billing-system/internal/db"
+	"loan-billing-system/internal/models"
+	"loan-billing-system/internal/pii"
+
+	"gorm.io/gorm"
+)
+
+// batchSize is how many rows reencrypt rewrites per query
+const batchSize = 200
+
+func main() {
+	if len(os.Args) < 2 {
+		log.Fatal("usage: pii keygen [file] | pii reencrypt")
+	}
+
+	switch os.Args[1] {
+	case "keygen":
+		path := os.Getenv("PII_KEY_FILE")
+		if len(os.Args) > 2 {
+			path = os.Args[2]
+		}
+		if path == "" {
+			log.Fatal("usage: pii keygen <file>, or set PII_KEY_FILE")
+		}
+		id, err := pii.GenerateKey(path)
+		if err != nil {
+			log.Fatalf("Failed to generate key: %v", err)
+		}
+		log.Printf("Added key %s to %s and made it current", id, path)
+	case "reencrypt":
+		if err := reencryptAll(); err != nil {
+			log.Fatalf("Failed to re-encrypt PII: %v", err)
+		}
+	default:
+		log.Fatalf("unknown command %q", os.Args[1])
+	}
+}
+
+// reencryptAll rewrites every table holding PII
+func reencryptAll() error {
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	pii.Use(cfg.PII)
+
+	database, err := db.Connect(cfg.DB)
+	if err != nil {
+		return err
+	}
+	if err := db.Migrate(database); err != nil {
+		return err
+	}
+	log.Printf("Re-encrypting PII with key %s", cfg.PII.CurrentKeyID())
+
+	borrowers, err := reencrypt(database, []string{"name", "name_index", "contact_info", "national_id", "national_id_index", "date_of_birth", "date_of_birth_index", "phone", "phone_index", "email", "employer", "monthly_income"}, (*models.Borrower).RefreshIndexes)
+	if err != nil {
+		return fmt.Errorf("borrowers: %w", err)
+	}
+	addresses, err := reencrypt[models.BorrowerAddress](database, []string{"line1", "line2", "city", "region", "postal_code"}, nil)
+	if err != nil {
+		return fmt.Errorf("borrower addresses: %w", err)
+	}
+	documents, err := reencrypt[models.BorrowerDocument](database, []string{"file_name"}, nil)
+	if err != nil {
+		return fmt.Errorf("borrower documents: %w", err)
+	}
+	merges, err := reencrypt[models.BorrowerMerge](database, []string{"source_snapshot"}, nil)
+	if err != nil {
+		return fmt.Errorf("borrower merges: %w", err)
+	}
+
+	log.Printf("Re-encrypted %d borrowers, %d addresses, %d documents and %d merge records", borrowers, addresses, documents, merges)
+	return nil
+}
+
+// reencrypt reads every row of a model, deleted or not, and writes its PII
+// columns back. The serializer decrypts with whichever key a value was
+// written under and encrypts with the current one; legacy plaintext gets
+// encrypted. prepare, when set, runs on each row before it is written.
+func reencrypt[T any](database *gorm.DB, columns []string, prepare func(*T) error) (int, error) {
+	var rows []T
+	count := 0
+	err := database.Unscoped().Model(new(T)).FindInBatches(&rows, batchSize, func(tx *gorm.DB, _ int) error {
+		for i := range rows {
+			if prepare != nil {
+				if err := prepare(&rows[i]); err != nil {
+					return err
+				}
+			}
+			if err := database.Unscoped().Model(&rows[i]).Select(columns).UpdateColumns(&rows[i]).Error; err != nil {
+				return err
+			}
+		}
+		count += len(rows)
+		return nil
+	}).Error
+	return count, err
+}
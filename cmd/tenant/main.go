@@ -0,0 +1,83 @@
+// Command tenant manages the lending entities the deployment serves.
+//
+//	tenant add <id> <name>  adds a tenant with the standard loan product and no days off
+//	tenant list             lists every tenant
+//
+// Staff act for a tenant through the tenant claim of their tokens, and API
+// keys act for the tenant they were issued in.
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"strings"
+
+	"loan-billing-system/config"
+	"loan-billing-system/internal/db"
+	"loan-billing-system/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// tenantIDPattern is what tenant IDs look like; they appear in tokens and logs
+var tenantIDPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,49}$`)
+
+func main() {
+	if len(os.Args) < 2 {
+		log.Fatal("usage: tenant add <id> <name> | tenant list")
+	}
+
+	switch os.Args[1] {
+	case "add":
+		if len(os.Args) < 4 {
+			log.Fatal("usage: tenant add <id> <name>")
+		}
+		id, name := os.Args[2], strings.Join(os.Args[3:], " ")
+		if !tenantIDPattern.MatchString(id) {
+			log.Fatalf("tenant ID %q must be lowercase letters, digits, - and _, at most 50 long", id)
+		}
+		database, err := connect()
+		if err != nil {
+			log.Fatalf("Failed to connect to database: %v", err)
+		}
+		if err := database.Where("id = ?", id).First(&models.Tenant{}).Error; err == nil {
+			log.Fatalf("Tenant %s already exists", id)
+		}
+		if err := db.SeedTenant(database, models.Tenant{ID: id, Name: name, RestDays: []string{}}); err != nil {
+			log.Fatalf("Failed to add tenant: %v", err)
+		}
+		log.Printf("Added tenant %s", id)
+	case "list":
+		database, err := connect()
+		if err != nil {
+			log.Fatalf("Failed to connect to database: %v", err)
+		}
+		var tenants []models.Tenant
+		if err := database.Order("id").Find(&tenants).Error; err != nil {
+			log.Fatalf("Failed to list tenants: %v", err)
+		}
+		for _, t := range tenants {
+			fmt.Printf("%s\t%s\t%s\n", t.ID, t.Name, strings.Join(t.RestDays, ","))
+		}
+	default:
+		log.Fatalf("unknown command %q", os.Args[1])
+	}
+}
+
+// connect opens the configured database and brings its schema up to date
+func connect() (*gorm.DB, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, err
+	}
+	database, err := db.Connect(cfg.DB)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Migrate(database); err != nil {
+		return nil, err
+	}
+	return database, nil
+}
@@ -1,18 +1,30 @@
 package config
 
 import (
+	"fmt"
 	"os"
+	"strconv"
 	"strings"
 
+	"loan-billing-system/internal/auth"
 	"loan-billing-system/internal/db"
+	"loan-billing-system/internal/pii"
+	"loan-billing-system/internal/scoring"
+	"loan-billing-system/internal/services"
 
 	"github.com/joho/godotenv"
 )
 
 // Config holds the application configuration
 type Config struct {
-	DB     db.Config
-	Server struct {
+	DB        db.Config
+	Loan      services.LoanPolicy
+	Borrower  services.BorrowerPolicy
+	Scorecard *scoring.Scorecard // Built in unless SCORECARD_PATH names a JSON or YAML file
+	PII       *pii.Vault         // Encrypts borrower PII with the keys in PII_KEY_FILE
+	Auth      *auth.Verifier     // Verifies staff tokens with the keys in JWKS_FILE
+	Access    *auth.Policy       // Built in unless RBAC_POLICY_PATH names a JSON or YAML file
+	Server    struct {
 		Port string
 	}
 }
@@ -32,6 +44,99 @@ func Load() (*Config, error) {
 	config.DB.DBName = getEnv("DB_NAME", "loan_billing_system")
 	config.DB.SSLMode = getEnv("DB_SSLMODE", "disable")
 
+	// Loan policy
+	config.Loan = services.DefaultLoanPolicy()
+	curePayments, err := getEnvUint("RESTRUCTURE_CURE_PAYMENTS", config.Loan.RestructureCurePayments)
+	if err != nil {
+		return nil, err
+	}
+	config.Loan.RestructureCurePayments = curePayments
+	guarantorsShareDelinquency, err := getEnvBool("DELINQUENCY_INCLUDES_GUARANTORS", config.Loan.GuarantorsShareDelinquency)
+	if err != nil {
+		return nil, err
+	}
+	config.Loan.GuarantorsShareDelinquency = guarantorsShareDelinquency
+	creditLimit, err := getEnvInt64("CREDIT_LIMIT", config.Loan.CreditLimit)
+	if err != nil {
+		return nil, err
+	}
+	config.Loan.CreditLimit = creditLimit
+	maxConcurrentLoans, err := getEnvUint("MAX_CONCURRENT_LOANS", config.Loan.MaxConcurrentLoans)
+	if err != nil {
+		return nil, err
+	}
+	config.Loan.MaxConcurrentLoans = maxConcurrentLoans
+	requireVerifiedKYC, err := getEnvBool("REQUIRE_VERIFIED_KYC", config.Loan.RequireVerifiedKYC)
+	if err != nil {
+		return nil, err
+	}
+	config.Loan.RequireVerifiedKYC = requireVerifiedKYC
+
+	// Borrower policy
+	config.Borrower = services.DefaultBorrowerPolicy()
+	kycValidityDays, err := getEnvUint("KYC_VALIDITY_DAYS", config.Borrower.KYCValidityDays)
+	if err != nil {
+		return nil, err
+	}
+	config.Borrower.KYCValidityDays = kycValidityDays
+	minimumAge, err := getEnvUint("BORROWER_MINIMUM_AGE", config.Borrower.MinimumAge)
+	if err != nil {
+		return nil, err
+	}
+	config.Borrower.MinimumAge = minimumAge
+	retentionDays, err := getEnvUint("DATA_RETENTION_DAYS", config.Borrower.RetentionDays)
+	if err != nil {
+		return nil, err
+	}
+	config.Borrower.RetentionDays = retentionDays
+	anonymizeAfterRetention, err := getEnvBool("ANONYMIZE_AFTER_RETENTION", config.Borrower.AnonymizeAfterRetention)
+	if err != nil {
+		return nil, err
+	}
+	config.Borrower.AnonymizeAfterRetention = anonymizeAfterRetention
+
+	// Credit scoring
+	if path := getEnv("SCORECARD_PATH", ""); path != "" {
+		config.Scorecard, err = scoring.Load(path)
+	} else {
+		config.Scorecard, err = scoring.Default()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load scorecard: %w", err)
+	}
+
+	// PII encryption
+	keyFile := getEnv("PII_KEY_FILE", "")
+	if keyFile == "" {
+		return nil, fmt.Errorf("PII_KEY_FILE is required; create one with `go run ./cmd/pii keygen`")
+	}
+	keys, err := pii.LoadKeyFile(keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load PII keys: %w", err)
+	}
+	config.PII = pii.NewVault(keys)
+
+	// Staff authentication
+	jwksFile := getEnv("JWKS_FILE", "")
+	if jwksFile == "" {
+		return nil, fmt.Errorf("JWKS_FILE is required; create one with `go run ./cmd/auth keygen`")
+	}
+	jwks, err := auth.LoadJWKS(jwksFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load JWKS: %w", err)
+	}
+	config.Auth = auth.NewVerifier(jwks, getEnv("JWT_ISSUER", ""), getEnv("JWT_AUDIENCE", ""))
+
+	// Access control
+	if path := getEnv("RBAC_POLICY_PATH", ""); path != "" {
+		config.Access, err = auth.LoadPolicy(path)
+	} else {
+		config.Access, err = auth.DefaultPolicy()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load access policy: %w", err)
+	}
+
 	// Server configuration
 	config.Server.Port = getEnv("SERVER_PORT", "8080")
 
@@ -46,3 +151,42 @@ func getEnv(key, defaultValue string) string {
 	}
 	return value
 }
+
+// getEnvUint gets an unsigned integer environment variable or returns a default value
+func getEnvUint(key string, defaultValue uint) (uint, error) {
+	value := getEnv(key, "")
+	if value == "" {
+		return defaultValue, nil
+	}
+	n, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("%s must be a non-negative integer: %w", key, err)
+	}
+	return uint(n), nil
+}
+
+// getEnvInt64 gets a non-negative 64-bit integer environment variable or returns a default value
+func getEnvInt64(key string, defaultValue int64) (int64, error) {
+	value := getEnv(key, "")
+	if value == "" {
+		return defaultValue, nil
+	}
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%s must be a non-negative integer", key)
+	}
+	return n, nil
+}
+
+// getEnvBool gets a boolean environment variable or returns a default value
+func getEnvBool(key string, defaultValue bool) (bool, error) {
+	value := getEnv(key, "")
+	if value == "" {
+		return defaultValue, nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("%s must be true or false: %w", key, err)
+	}
+	return b, nil
+}
@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"loan-billing-system/internal/auth"
+	"loan-billing-system/internal/models"
+	"loan-billing-system/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// APIKeyHandler handles HTTP requests for partner API keys
+type APIKeyHandler struct {
+	authService *services.AuthService
+}
+
+// NewAPIKeyHandler creates a new API key handler
+func NewAPIKeyHandler(authService *services.AuthService) *APIKeyHandler {
+	return &APIKeyHandler{
+		authService: authService,
+	}
+}
+
+// CreateAPIKeyRequest represents the request body for issuing an API key
+// @Description Partner system to issue an API key to
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name" validate:"required,max=100"`
+	Roles     []string   `json:"roles"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// APIKeyResponse represents an API key in responses
+// @Description API key issued to a partner system. The key itself is never shown again after it is created.
+type APIKeyResponse struct {
+	ID         uuid.UUID  `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Roles      []string   `json:"roles"`
+	CreatedBy  string     `json:"created_by"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	RevokedBy  string     `json:"revoked_by,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// CreatedAPIKeyResponse represents a newly issued API key
+// @Description New API key. Store key now: only its hash is kept.
+type CreatedAPIKeyResponse struct {
+	APIKeyResponse
+	Key string `json:"key"`
+}
+
+// CreateAPIKey godoc
+// @Summary Issue an API key
+// @Description Issues an API key to a partner system. The key is returned once and cannot be retrieved later. Staff only.
+// @Tags API Keys
+// @Accept json
+// @Produce json
+// @Param request body handlers.CreateAPIKeyRequest true "API key details"
+// @Success 201 {object} handlers.CreatedAPIKeyResponse
+// @Failure 400 {object} map[string]string "Error response"
+// @Failure 401 {object} map[string]string "Error response"
+// @Failure 403 {object} map[string]string "Error response"
+// @Failure 500 {object} map[string]string "Error response"
+// @Security BearerAuth
+// @Router /api/api-keys [post]
+func (h *APIKeyHandler) CreateAPIKey(c echo.Context) error {
+	var req CreateAPIKeyRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request format"})
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	record, key, err := h.authService.WithContext(c.Request().Context()).CreateAPIKey(services.APIKeyRequest{
+		Name:      req.Name,
+		Roles:     req.Roles,
+		ExpiresAt: req.ExpiresAt,
+	})
+	if err != nil {
+		return apiKeyError(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, CreatedAPIKeyResponse{
+		APIKeyResponse: newAPIKeyResponse(record),
+		Key:            key,
+	})
+}
+
+// ListAPIKeys godoc
+// @Summary List API keys
+// @Description Retrieves every API key issued, newest first, revoked ones included. Staff only.
+// @Tags API Keys
+// @Accept json
+// @Produce json
+// @Success 200 {array} handlers.APIKeyResponse
+// @Failure 401 {object} map[string]string "Error response"
+// @Failure 403 {object} map[string]string "Error response"
+// @Failure 500 {object} map[string]string "Error response"
+// @Security BearerAuth
+// @Router /api/api-keys [get]
+func (h *APIKeyHandler) ListAPIKeys(c echo.Context) error {
+	keys, err := h.authService.WithContext(c.Request().Context()).ListAPIKeys()
+	if err != nil {
+		return apiKeyError(c, err)
+	}
+
+	response := make([]APIKeyResponse, 0, len(keys))
+	for i := range keys {
+		response = append(response, newAPIKeyResponse(&keys[i]))
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// RevokeAPIKey godoc
+// @Summary Revoke an API key
+// @Description Stops an API key from being used. Revoked keys stay listed. Staff only.
+// @Tags API Keys
+// @Accept json
+// @Produce json
+// @Param id path string true "API key ID" format(uuid)
+// @Success 200 {object} handlers.APIKeyResponse
+// @Failure 400 {object} map[string]string "Error response"
+// @Failure 401 {object} map[string]string "Error response"
+// @Failure 403 {object} map[string]string "Error response"
+// @Failure 404 {object} map[string]string "Error response"
+// @Failure 500 {object} map[string]string "Error response"
+// @Security BearerAuth
+// @Router /api/api-keys/{id} [delete]
+func (h *APIKeyHandler) RevokeAPIKey(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid API key ID format"})
+	}
+
+	record, err := h.authService.WithContext(c.Request().Context()).RevokeAPIKey(id)
+	if err != nil {
+		return apiKeyError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, newAPIKeyResponse(record))
+}
+
+// apiKeyError maps API key management errors to responses
+func apiKeyError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, auth.ErrForbidden):
+		return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
+	case errors.Is(err, services.ErrAPIKeyNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "API key not found"})
+	case errors.Is(err, services.ErrInvalidAPIKeyRequest):
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	default:
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+}
+
+// newAPIKeyResponse converts an API key into its response
+func newAPIKeyResponse(key *models.APIKey) APIKeyResponse {
+	return APIKeyResponse{
+		ID:         key.ID,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Roles:      key.Roles,
+		CreatedBy:  key.CreatedBy,
+		ExpiresAt:  key.ExpiresAt,
+		LastUsedAt: key.LastUsedAt,
+		RevokedAt:  key.RevokedAt,
+		RevokedBy:  key.RevokedBy,
+		CreatedAt:  key.CreatedAt,
+	}
+}
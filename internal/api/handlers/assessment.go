@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"loan-billing-system/internal/models"
+	"loan-billing-system/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// ContributionResponse represents the points one scorecard rule gave an application
+// @Description Points a scorecard rule gave and why. value is null when the applicant has no value for the factor, such as a first-time borrower's on-time ratio.
+type ContributionResponse struct {
+	Rule     string   `json:"rule"`
+	Factor   string   `json:"factor"`
+	Value    *float64 `json:"value"`
+	Points   int      `json:"points"`
+	Reason   string   `json:"reason"`
+	Knockout bool     `json:"knockout"`
+}
+
+// CreditAssessmentResponse represents the score a loan application received
+// @Description Credit score, risk grade and decision of a loan application, with the points each scorecard rule gave
+type CreditAssessmentResponse struct {
+	LoanID             *uuid.UUID             `json:"loan_id,omitempty"`
+	ScorecardVersion   string                 `json:"scorecard_version"`
+	Score              int                    `json:"score"`
+	Grade              string                 `json:"grade"`
+	Decision           string                 `json:"decision" enums:"approve,refer,decline"`
+	ReferralApprovedBy string                 `json:"referral_approved_by,omitempty"`
+	Contributions      []ContributionResponse `json:"contributions"`
+	CreatedAt          *time.Time             `json:"created_at,omitempty"`
+}
+
+// GetCreditAssessment godoc
+// @Summary Get a loan's credit assessment
+// @Description Retrieves the credit score, risk grade and decision a loan received when it was created, with the points each scorecard rule gave
+// @Tags Loans
+// @Accept json
+// @Produce json
+// @Param id path string true "Loan ID" format(uuid)
+// @Success 200 {object} handlers.CreditAssessmentResponse
+// @Failure 400 {object} map[string]string "Error response"
+// @Failure 401 {object} map[string]string "Error response"
+// @Failure 403 {object} map[string]string "Error response"
+// @Failure 404 {object} map[string]string "Error response"
+// @Failure 500 {object} map[string]string "Error response"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/loans/{id}/credit-assessment [get]
+func (h *LoanHandler) GetCreditAssessment(c echo.Context) error {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid loan ID format"})
+	}
+
+	assessment, err := h.loanService.WithContext(c.Request().Context()).GetCreditAssessment(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrLoanNotFound):
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Loan not found"})
+		case errors.Is(err, services.ErrAssessmentNotFound):
+			return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		}
+	}
+
+	return c.JSON(http.StatusOK, newCreditAssessmentResponse(assessment))
+}
+
+// newCreditAssessmentResponse converts a credit assessment into its response representation.
+// Assessments of rejected applications have no loan.
+func newCreditAssessmentResponse(assessment *models.CreditAssessment) *CreditAssessmentResponse {
+	response := &CreditAssessmentResponse{
+		ScorecardVersion:   assessment.ScorecardVersion,
+		Score:              assessment.Score,
+		Grade:              assessment.Grade,
+		Decision:           assessment.Decision,
+		ReferralApprovedBy: assessment.ReferralApprovedBy,
+		Contributions:      make([]ContributionResponse, 0, len(assessment.Contributions)),
+	}
+	if assessment.LoanID != uuid.Nil {
+		response.LoanID = &assessment.LoanID
+		response.CreatedAt = &assessment.CreatedAt
+	}
+	for _, contribution := range assessment.Contributions {
+		response.Contributions = append(response.Contributions, ContributionResponse{
+			Rule:     contribution.Rule,
+			Factor:   contribution.Factor,
+			Value:    contribution.Value,
+			Points:   contribution.Points,
+			Reason:   contribution.Reason,
+			Knockout: contribution.Knockout,
+		})
+	}
+	return response
+}
@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"loan-billing-system/internal/models"
+	"loan-billing-system/internal/repositories"
+	"loan-billing-system/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// AuditHandler handles HTTP requests for the audit log
+type AuditHandler struct {
+	auditService *services.AuditService
+}
+
+// NewAuditHandler creates a new audit handler
+func NewAuditHandler(auditService *services.AuditService) *AuditHandler {
+	return &AuditHandler{
+		auditService: auditService,
+	}
+}
+
+// AuditEntryResponse represents an audit entry in responses
+// @Description One change to one row: who made it, in which request, and the old and new value of each column changed. Personal data is shown as [redacted].
+type AuditEntryResponse struct {
+	ID         uuid.UUID       `json:"id"`
+	Sequence   int64           `json:"sequence"`
+	Actor      string          `json:"actor"`
+	Action     string          `json:"action" enums:"create,update,delete"`
+	EntityType string          `json:"entity_type"`
+	EntityID   string          `json:"entity_id"`
+	Changes    json.RawMessage `json:"changes" swaggertype:"object"`
+	RequestID  string          `json:"request_id"`
+	PrevHash   string          `json:"prev_hash"`
+	Hash       string          `json:"hash"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// AuditEntryListResponse represents a page of audit entries
+// @Description Paginated list of audit entries
+type AuditEntryListResponse struct {
+	Data       []AuditEntryResponse `json:"data"`
+	NextCursor string               `json:"next_cursor"`
+	HasMore    bool                 `json:"has_more"`
+	Limit      int                  `json:"limit"`
+}
+
+// AuditVerificationResponse represents the outcome of checking the audit chain
+// @Description Whether the audit chain is intact. Record head_sequence and head_hash somewhere safe and pass them back as an anchor to detect the log being rewritten.
+type AuditVerificationResponse struct {
+	Valid        bool   `json:"valid"`
+	Checked      int64  `json:"checked"`
+	HeadSequence int64  `json:"head_sequence"`
+	HeadHash     string `json:"head_hash"`
+	Problem      string `json:"problem,omitempty"`
+}
+
+// ListAuditEntries godoc
+// @Summary List audit entries
+// @Description Retrieves a page of the audit log, oldest first by default
+// @Tags Audit
+// @Accept json
+// @Produce json
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param cursor query string false "Cursor returned as next_cursor by the previous page"
+// @Param sort query string false "Sort field" Enums(sequence)
+// @Param order query string false "Sort order" Enums(asc, desc)
+// @Param entity_type query string false "Only changes to rows of this table, e.g. loans"
+// @Param entity_id query string false "Only changes to the row with this primary key"
+// @Param actor query string false "Only changes made by this actor"
+// @Param action query string false "Only changes of this kind" Enums(create, update, delete)
+// @Param request_id query string false "Only changes made by this request"
+// @Param from query string false "Only changes made at or after this time (RFC3339 or YYYY-MM-DD)"
+// @Param to query string false "Only changes made before this time (RFC3339, or YYYY-MM-DD inclusive)"
+// @Success 200 {object} handlers.AuditEntryListResponse
+// @Failure 400 {object} map[string]string "Error response"
+// @Failure 401 {object} map[string]string "Error response"
+// @Failure 403 {object} map[string]string "Error response"
+// @Failure 500 {object} map[string]string "Error response"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/audit [get]
+func (h *AuditHandler) ListAuditEntries(c echo.Context) error {
+	page, err := parsePageRequest(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	filter := repositories.AuditFilter{
+		EntityType: c.QueryParam("entity_type"),
+		EntityID:   c.QueryParam("entity_id"),
+		Actor:      c.QueryParam("actor"),
+		Action:     c.QueryParam("action"),
+		RequestID:  c.QueryParam("request_id"),
+	}
+	if filter.From, err = parseTimeParam(c, "from", false); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	if filter.To, err = parseTimeParam(c, "to", true); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	result, err := h.auditService.WithContext(c.Request().Context()).ListAuditEntries(filter, page)
+	if err != nil {
+		if isPaginationError(err) {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, newAuditEntryListResponse(result))
+}
+
+// VerifyAuditLog godoc
+// @Summary Verify the audit log
+// @Description Walks the whole audit chain and reports the first entry that is missing, out of order or altered. With anchor_sequence and anchor_hash from an earlier check, also reports whether that entry has been rewritten or removed since.
+// @Tags Audit
+// @Accept json
+// @Produce json
+// @Param anchor_sequence query int false "Sequence number of an entry recorded by an earlier check"
+// @Param anchor_hash query string false "Hash of that entry"
+// @Success 200 {object} handlers.AuditVerificationResponse
+// @Failure 400 {object} map[string]string "Error response"
+// @Failure 401 {object} map[string]string "Error response"
+// @Failure 403 {object} map[string]string "Error response"
+// @Failure 500 {object} map[string]string "Error response"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/audit/verify [get]
+func (h *AuditHandler) VerifyAuditLog(c echo.Context) error {
+	sequence, err := parseInt64Param(c, "anchor_sequence")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	hash := c.QueryParam("anchor_hash")
+
+	var anchor *services.AuditAnchor
+	if sequence != nil || hash != "" {
+		if sequence == nil || *sequence < 1 || hash == "" {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "anchor_sequence must be a positive integer given with anchor_hash"})
+		}
+		anchor = &services.AuditAnchor{Sequence: *sequence, Hash: hash}
+	}
+
+	result, err := h.auditService.WithContext(c.Request().Context()).VerifyAuditLog(anchor)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, AuditVerificationResponse{
+		Valid:        result.Valid,
+		Checked:      result.Checked,
+		HeadSequence: result.HeadSequence,
+		HeadHash:     result.HeadHash,
+		Problem:      result.Problem,
+	})
+}
+
+// newAuditEntryListResponse converts a page of audit entries into the response envelope
+func newAuditEntryListResponse(page repositories.Page[models.AuditEntry]) AuditEntryListResponse {
+	response := AuditEntryListResponse{
+		Data:       make([]AuditEntryResponse, 0, len(page.Items)),
+		NextCursor: page.NextCursor,
+		HasMore:    page.HasMore(),
+		Limit:      page.Limit,
+	}
+	for _, entry := range page.Items {
+		response.Data = append(response.Data, AuditEntryResponse{
+			ID:         entry.ID,
+			Sequence:   entry.Sequence,
+			Actor:      entry.Actor,
+			Action:     entry.Action,
+			EntityType: entry.EntityType,
+			EntityID:   entry.EntityID,
+			Changes:    json.RawMessage(entry.Changes),
+			RequestID:  entry.RequestID,
+			PrevHash:   entry.PrevHash,
+			Hash:       entry.Hash,
+			CreatedAt:  entry.CreatedAt,
+		})
+	}
+	return response
+}
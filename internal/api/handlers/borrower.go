@@ -1,8 +1,12 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
+	"time"
 
+	"loan-billing-system/internal/models"
+	"loan-billing-system/internal/repositories"
 	"loan-billing-system/internal/services"
 
 	"github.com/google/uuid"
@@ -22,31 +26,84 @@ func NewBorrowerHandler(borrowerService *services.BorrowerService) *BorrowerHand
 }
 
 // CreateBorrowerRequest represents the request body for creating a borrower
-// @Description Request body for creating a new borrower
+// @Description Request body for creating a new borrower with their profile and addresses. A phone, email or contact_info is required.
 type CreateBorrowerRequest struct {
-	Name        string `json:"name" validate:"required"`
-	ContactInfo string `json:"contact_info" validate:"required"`
+	BorrowerProfileRequest
+	Addresses []AddressRequest `json:"addresses" validate:"dive"`
+	// Create the borrower even though it resembles an existing one; a shared national ID still blocks
+	ConfirmNotDuplicate bool `json:"confirm_not_duplicate"`
+}
+
+// CreditLimitsRequest represents the request body for setting a borrower's credit limits
+// @Description Request body for setting a borrower's own credit limits. A null field falls back to the CREDIT_LIMIT or MAX_CONCURRENT_LOANS policy; 0 means no limit.
+type CreditLimitsRequest struct {
+	CreditLimit        *int64 `json:"credit_limit" validate:"omitempty,min=0"`
+	MaxConcurrentLoans *uint  `json:"max_concurrent_loans"`
 }
 
 // BorrowerResponse represents the borrower data in responses
-// @Description Response containing borrower data
+// @Description Response containing borrower data. kyc_status reads expired once a verification has lapsed. Null credit limits fall back to the loan policy. Anonymized borrowers keep their loans but have no PII.
 type BorrowerResponse struct {
-	ID           uuid.UUID `json:"id"`
-	Name         string    `json:"name"`
-	ContactInfo  string    `json:"contact_info"`
-	IsDelinquent bool      `json:"is_delinquent"`
+	ID                 uuid.UUID  `json:"id"`
+	Name               string     `json:"name"`
+	ContactInfo        string     `json:"contact_info"`
+	NationalIDType     string     `json:"national_id_type"`
+	NationalID         string     `json:"national_id"`
+	DateOfBirth        *string    `json:"date_of_birth" example:"1990-04-21"`
+	Phone              string     `json:"phone"`
+	Email              string     `json:"email"`
+	EmploymentStatus   string     `json:"employment_status"`
+	Employer           string     `json:"employer"`
+	MonthlyIncome      int64      `json:"monthly_income"`
+	KYCStatus          string     `json:"kyc_status" enums:"pending,verified,rejected,expired"`
+	KYCExpiresAt       *time.Time `json:"kyc_expires_at"`
+	IsDelinquent       bool       `json:"is_delinquent"`
+	CreditLimit        *int64     `json:"credit_limit"`
+	MaxConcurrentLoans *uint      `json:"max_concurrent_loans"`
+	ErasureRequestedAt *time.Time `json:"erasure_requested_at"`
+	AnonymizedAt       *time.Time `json:"anonymized_at"`
+}
+
+// BorrowerLoanResponse represents a loan a borrower is a party to
+// @Description Loan a borrower is on and the role they hold on it
+type BorrowerLoanResponse struct {
+	LoanID         uuid.UUID `json:"loan_id"`
+	Role           string    `json:"role" enums:"primary,co_borrower,guarantor"`
+	Status         string    `json:"status"`
+	Amount         int64     `json:"amount"`
+	CurrentBalance int64     `json:"current_balance"`
+	IsDelinquent   bool      `json:"is_delinquent"`
+}
+
+// BorrowerDetailResponse represents a borrower with their loans and exposure
+// @Description Borrower with every loan they are a party to. Exposure counts the balances of active and defaulted loans; guaranteed loans count as contingent exposure.
+type BorrowerDetailResponse struct {
+	BorrowerResponse
+	KYCReviewedBy      string                 `json:"kyc_reviewed_by"`
+	KYCReviewedAt      *time.Time             `json:"kyc_reviewed_at"`
+	KYCRejectionReason string                 `json:"kyc_rejection_reason"`
+	Addresses          []AddressResponse      `json:"addresses"`
+	Loans              []BorrowerLoanResponse `json:"loans"`
+	DirectExposure     int64                  `json:"direct_exposure"`
+	ContingentExposure int64                  `json:"contingent_exposure"`
+	TotalExposure      int64                  `json:"total_exposure"`
 }
 
 // CreateBorrower godoc
 // @Summary Create a new borrower
-// @Description Creates a new borrower in the system
+// @Description Creates a new borrower with their profile and addresses. Their KYC starts as pending. A borrower sharing a national ID with an existing one is rejected; one sharing a phone or a similar name is rejected unless confirm_not_duplicate is set.
 // @Tags Borrowers
 // @Accept json
 // @Produce json
 // @Param request body handlers.CreateBorrowerRequest true "Borrower details"
 // @Success 201 {object} handlers.BorrowerResponse
 // @Failure 400 {object} map[string]string "Error response"
+// @Failure 401 {object} map[string]string "Error response"
+// @Failure 403 {object} map[string]string "Error response"
+// @Failure 409 {object} handlers.DuplicateBorrowerResponse "Possible duplicate"
 // @Failure 500 {object} map[string]string "Error response"
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /api/borrowers [post]
 func (h *BorrowerHandler) CreateBorrower(c echo.Context) error {
 	var req CreateBorrowerRequest
@@ -58,29 +115,45 @@ func (h *BorrowerHandler) CreateBorrower(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
 
-	borrower, err := h.borrowerService.CreateBorrower(req.Name, req.ContactInfo)
+	addresses := make([]models.BorrowerAddress, 0, len(req.Addresses))
+	for _, address := range req.Addresses {
+		addresses = append(addresses, address.toModel())
+	}
+
+	borrower, err := h.borrowerService.WithContext(c.Request().Context()).CreateBorrower(req.toProfile(), addresses, req.ConfirmNotDuplicate)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		var duplicate *services.DuplicateError
+		switch {
+		case errors.As(err, &duplicate):
+			return c.JSON(http.StatusConflict, DuplicateBorrowerResponse{
+				Error:      duplicate.Unwrap().Error(),
+				Blocking:   duplicate.Blocking,
+				Candidates: newDuplicateCandidateResponses(duplicate.Candidates),
+			})
+		case errors.Is(err, services.ErrInvalidProfile):
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		}
 	}
 
-	return c.JSON(http.StatusCreated, BorrowerResponse{
-		ID:           borrower.ID,
-		Name:         borrower.Name,
-		ContactInfo:  borrower.ContactInfo,
-		IsDelinquent: borrower.IsDelinquent,
-	})
+	return c.JSON(http.StatusCreated, newBorrowerResponse(borrower))
 }
 
 // GetBorrower godoc
 // @Summary Get borrower details
-// @Description Retrieves details for a specific borrower
+// @Description Retrieves details for a specific borrower, with every loan they are on as primary borrower, co-borrower or guarantor and their total exposure
 // @Tags Borrowers
 // @Accept json
 // @Produce json
 // @Param id path string true "Borrower ID" format(uuid)
-// @Success 200 {object} handlers.BorrowerResponse
+// @Success 200 {object} handlers.BorrowerDetailResponse
 // @Failure 400 {object} map[string]string "Error response"
+// @Failure 401 {object} map[string]string "Error response"
+// @Failure 403 {object} map[string]string "Error response"
 // @Failure 404 {object} map[string]string "Error response"
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /api/borrowers/{id} [get]
 func (h *BorrowerHandler) GetBorrower(c echo.Context) error {
 	idStr := c.Param("id")
@@ -89,71 +162,248 @@ func (h *BorrowerHandler) GetBorrower(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid borrower ID format"})
 	}
 
-	borrower, err := h.borrowerService.GetBorrower(id)
-	if err != nil {
+	if !ownsBorrower(c, id) {
 		return c.JSON(http.StatusNotFound, map[string]string{"error": "Borrower not found"})
 	}
 
-	return c.JSON(http.StatusOK, BorrowerResponse{
-		ID:           borrower.ID,
-		Name:         borrower.Name,
-		ContactInfo:  borrower.ContactInfo,
-		IsDelinquent: borrower.IsDelinquent,
+	exposure, err := h.borrowerService.WithContext(c.Request().Context()).GetBorrowerExposure(id)
+	if err != nil {
+		if errors.Is(err, services.ErrBorrowerNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Borrower not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	borrower := exposure.Borrower
+	response := BorrowerDetailResponse{
+		BorrowerResponse:   newBorrowerResponse(borrower),
+		KYCReviewedBy:      borrower.KYCReviewedBy,
+		KYCReviewedAt:      borrower.KYCReviewedAt,
+		KYCRejectionReason: borrower.KYCRejectionReason,
+		Addresses:          make([]AddressResponse, 0, len(borrower.Addresses)),
+		Loans:              make([]BorrowerLoanResponse, 0, len(exposure.Loans)),
+		DirectExposure:     exposure.DirectExposure,
+		ContingentExposure: exposure.ContingentExposure,
+		TotalExposure:      exposure.TotalExposure(),
+	}
+	for i := range borrower.Addresses {
+		response.Addresses = append(response.Addresses, newAddressResponse(&borrower.Addresses[i]))
+	}
+	for _, loan := range exposure.Loans {
+		response.Loans = append(response.Loans, BorrowerLoanResponse{
+			LoanID:         loan.Loan.ID,
+			Role:           loan.Role,
+			Status:         loan.Loan.Status,
+			Amount:         loan.Loan.Amount,
+			CurrentBalance: loan.Loan.CurrentBalance,
+			IsDelinquent:   loan.Loan.IsDelinquent,
+		})
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// SetCreditLimits godoc
+// @Summary Set a borrower's credit limits
+// @Description Sets or clears a borrower's own credit limit and maximum number of open loans, which override the loan policy when checking new loans
+// @Tags Borrowers
+// @Accept json
+// @Produce json
+// @Param id path string true "Borrower ID" format(uuid)
+// @Param request body handlers.CreditLimitsRequest true "Credit limits"
+// @Success 200 {object} handlers.BorrowerResponse
+// @Failure 400 {object} map[string]string "Error response"
+// @Failure 401 {object} map[string]string "Error response"
+// @Failure 403 {object} map[string]string "Error response"
+// @Failure 404 {object} map[string]string "Error response"
+// @Failure 500 {object} map[string]string "Error response"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/borrowers/{id}/credit-limits [put]
+func (h *BorrowerHandler) SetCreditLimits(c echo.Context) error {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid borrower ID format"})
+	}
+
+	var req CreditLimitsRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request format"})
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	borrower, err := h.borrowerService.WithContext(c.Request().Context()).SetCreditLimits(id, services.CreditLimits{
+		CreditLimit:        req.CreditLimit,
+		MaxConcurrentLoans: req.MaxConcurrentLoans,
 	})
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrBorrowerNotFound):
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Borrower not found"})
+		case errors.Is(err, services.ErrInvalidCreditLimits):
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		}
+	}
+
+	return c.JSON(http.StatusOK, newBorrowerResponse(borrower))
+}
+
+// BorrowerListResponse represents a page of borrowers
+// @Description Paginated list of borrowers
+type BorrowerListResponse struct {
+	Data       []BorrowerResponse `json:"data"`
+	NextCursor string             `json:"next_cursor"`
+	HasMore    bool               `json:"has_more"`
+	Limit      int                `json:"limit"`
 }
 
 // ListBorrowers godoc
-// @Summary List all borrowers
-// @Description Retrieves a list of all borrowers in the system
+// @Summary List borrowers
+// @Description Retrieves a page of borrowers using cursor pagination
 // @Tags Borrowers
 // @Accept json
 // @Produce json
-// @Success 200 {array} handlers.BorrowerResponse
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param cursor query string false "Cursor returned as next_cursor by the previous page"
+// @Param sort query string false "Sort field" Enums(created_at)
+// @Param order query string false "Sort order" Enums(asc, desc)
+// @Param created_from query string false "Only borrowers created at or after this time (RFC3339 or YYYY-MM-DD)"
+// @Param created_to query string false "Only borrowers created before this time (RFC3339, or YYYY-MM-DD inclusive)"
+// @Param is_delinquent query bool false "Filter by delinquency status"
+// @Param loan_status query string false "Only borrowers with at least one loan in this status"
+// @Param kyc_status query string false "Filter by KYC status" Enums(pending, verified, rejected, expired)
+// @Success 200 {object} handlers.BorrowerListResponse
+// @Failure 400 {object} map[string]string "Error response"
+// @Failure 401 {object} map[string]string "Error response"
+// @Failure 403 {object} map[string]string "Error response"
 // @Failure 500 {object} map[string]string "Error response"
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /api/borrowers [get]
 func (h *BorrowerHandler) ListBorrowers(c echo.Context) error {
-	borrowers, err := h.borrowerService.ListBorrowers()
+	filter, page, err := parseBorrowerListParams(c)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
 
-	var response []BorrowerResponse
-	for _, borrower := range borrowers {
-		response = append(response, BorrowerResponse{
-			ID:           borrower.ID,
-			Name:         borrower.Name,
-			ContactInfo:  borrower.ContactInfo,
-			IsDelinquent: borrower.IsDelinquent,
-		})
+	result, err := h.borrowerService.WithContext(c.Request().Context()).ListBorrowers(filter, page)
+	if err != nil {
+		if isPaginationError(err) {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
 
-	return c.JSON(http.StatusOK, response)
+	return c.JSON(http.StatusOK, newBorrowerListResponse(result))
 }
 
 // ListDelinquentBorrowers godoc
 // @Summary List delinquent borrowers
-// @Description Retrieves a list of all borrowers who are currently delinquent
+// @Description Retrieves a page of borrowers who are currently delinquent
 // @Tags Borrowers
 // @Accept json
 // @Produce json
-// @Success 200 {array} handlers.BorrowerResponse
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param cursor query string false "Cursor returned as next_cursor by the previous page"
+// @Param sort query string false "Sort field" Enums(created_at)
+// @Param order query string false "Sort order" Enums(asc, desc)
+// @Param created_from query string false "Only borrowers created at or after this time (RFC3339 or YYYY-MM-DD)"
+// @Param created_to query string false "Only borrowers created before this time (RFC3339, or YYYY-MM-DD inclusive)"
+// @Param loan_status query string false "Only borrowers with at least one loan in this status"
+// @Param kyc_status query string false "Filter by KYC status" Enums(pending, verified, rejected, expired)
+// @Success 200 {object} handlers.BorrowerListResponse
+// @Failure 400 {object} map[string]string "Error response"
+// @Failure 401 {object} map[string]string "Error response"
+// @Failure 403 {object} map[string]string "Error response"
 // @Failure 500 {object} map[string]string "Error response"
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /api/borrowers/delinquent [get]
 func (h *BorrowerHandler) ListDelinquentBorrowers(c echo.Context) error {
-	borrowers, err := h.borrowerService.GetDelinquentBorrowers()
+	filter, page, err := parseBorrowerListParams(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	result, err := h.borrowerService.WithContext(c.Request().Context()).GetDelinquentBorrowers(filter, page)
 	if err != nil {
+		if isPaginationError(err) {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
 
-	var response []BorrowerResponse
-	for _, borrower := range borrowers {
-		response = append(response, BorrowerResponse{
-			ID:           borrower.ID,
-			Name:         borrower.Name,
-			ContactInfo:  borrower.ContactInfo,
-			IsDelinquent: borrower.IsDelinquent,
-		})
+	return c.JSON(http.StatusOK, newBorrowerListResponse(result))
+}
+
+// parseBorrowerListParams reads the paging and filter query parameters of borrower listings
+func parseBorrowerListParams(c echo.Context) (repositories.BorrowerFilter, repositories.PageRequest, error) {
+	var filter repositories.BorrowerFilter
+
+	page, err := parsePageRequest(c)
+	if err != nil {
+		return filter, page, err
 	}
 
-	return c.JSON(http.StatusOK, response)
+	if filter.CreatedFrom, err = parseTimeParam(c, "created_from", false); err != nil {
+		return filter, page, err
+	}
+	if filter.CreatedTo, err = parseTimeParam(c, "created_to", true); err != nil {
+		return filter, page, err
+	}
+	if filter.IsDelinquent, err = parseBoolParam(c, "is_delinquent"); err != nil {
+		return filter, page, err
+	}
+	filter.LoanStatus = c.QueryParam("loan_status")
+	filter.KYCStatus = c.QueryParam("kyc_status")
+
+	return filter, page, nil
+}
+
+// newBorrowerListResponse converts a page of borrowers into the response envelope
+func newBorrowerListResponse(page repositories.Page[models.Borrower]) BorrowerListResponse {
+	response := BorrowerListResponse{
+		Data:       make([]BorrowerResponse, 0, len(page.Items)),
+		NextCursor: page.NextCursor,
+		HasMore:    page.HasMore(),
+		Limit:      page.Limit,
+	}
+	for i := range page.Items {
+		response.Data = append(response.Data, newBorrowerResponse(&page.Items[i]))
+	}
+	return response
+}
+
+// newBorrowerResponse converts a borrower into its response representation
+func newBorrowerResponse(borrower *models.Borrower) BorrowerResponse {
+	response := BorrowerResponse{
+		ID:                 borrower.ID,
+		Name:               borrower.Name,
+		ContactInfo:        borrower.ContactInfo,
+		NationalIDType:     borrower.NationalIDType,
+		NationalID:         borrower.NationalID,
+		Phone:              borrower.Phone,
+		Email:              borrower.Email,
+		EmploymentStatus:   borrower.EmploymentStatus,
+		Employer:           borrower.Employer,
+		MonthlyIncome:      borrower.MonthlyIncome,
+		KYCStatus:          borrower.KYCStatusAt(time.Now()),
+		KYCExpiresAt:       borrower.KYCExpiresAt,
+		IsDelinquent:       borrower.IsDelinquent,
+		CreditLimit:        borrower.CreditLimit,
+		MaxConcurrentLoans: borrower.MaxConcurrentLoans,
+		ErasureRequestedAt: borrower.ErasureRequestedAt,
+		AnonymizedAt:       borrower.AnonymizedAt,
+	}
+	if borrower.DateOfBirth != nil {
+		dateOfBirth := borrower.DateOfBirth.Format(time.DateOnly)
+		response.DateOfBirth = &dateOfBirth
+	}
+	return response
 }
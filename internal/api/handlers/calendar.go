@@ -0,0 +1,216 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"loan-billing-system/internal/models"
+	"loan-billing-system/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// CalendarHandler handles HTTP requests for the lending calendar of the caller's tenant
+type CalendarHandler struct {
+	loanService *services.LoanService
+}
+
+// NewCalendarHandler creates a new calendar handler
+func NewCalendarHandler(loanService *services.LoanService) *CalendarHandler {
+	return &CalendarHandler{
+		loanService: loanService,
+	}
+}
+
+// UpdateCalendarRequest represents the request body for changing the rest days
+// @Description Weekdays installments never fall due on. An empty list makes every day a working day.
+type UpdateCalendarRequest struct {
+	RestDays []string `json:"rest_days" example:"saturday,sunday"`
+}
+
+// CreateCalendarHolidayRequest represents the request body for adding a holiday
+// @Description Day installments do not fall due on
+type CreateCalendarHolidayRequest struct {
+	Date string `json:"date" validate:"required,datetime=2006-01-02" example:"2025-12-25"`
+	Name string `json:"name" validate:"required,max=100" example:"Christmas Day"`
+}
+
+// CalendarHolidayResponse represents a holiday in responses
+// @Description Day installments do not fall due on
+type CalendarHolidayResponse struct {
+	ID   uuid.UUID `json:"id"`
+	Date string    `json:"date" example:"2025-12-25"`
+	Name string    `json:"name"`
+}
+
+// CalendarResponse represents the lending calendar in responses
+// @Description Rest days and holidays of the caller's tenant. Installments falling on them are due on the next working day.
+type CalendarResponse struct {
+	Tenant   string                    `json:"tenant"`
+	RestDays []string                  `json:"rest_days"`
+	Holidays []CalendarHolidayResponse `json:"holidays"`
+}
+
+// GetCalendar godoc
+// @Summary Get the lending calendar
+// @Description Retrieves the rest days and holidays of the caller's tenant
+// @Tags Calendar
+// @Accept json
+// @Produce json
+// @Success 200 {object} handlers.CalendarResponse
+// @Failure 401 {object} map[string]string "Error response"
+// @Failure 403 {object} map[string]string "Error response"
+// @Failure 404 {object} map[string]string "Error response"
+// @Failure 500 {object} map[string]string "Error response"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/calendar [get]
+func (h *CalendarHandler) GetCalendar(c echo.Context) error {
+	calendar, err := h.loanService.WithContext(c.Request().Context()).GetCalendar()
+	if err != nil {
+		return calendarError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, newCalendarResponse(calendar))
+}
+
+// UpdateCalendar godoc
+// @Summary Set the rest days
+// @Description Replaces the weekdays the caller's tenant collects no installments on. Applies to schedules laid out from now on.
+// @Tags Calendar
+// @Accept json
+// @Produce json
+// @Param request body handlers.UpdateCalendarRequest true "Rest days"
+// @Success 200 {object} handlers.CalendarResponse
+// @Failure 400 {object} map[string]string "Error response"
+// @Failure 401 {object} map[string]string "Error response"
+// @Failure 403 {object} map[string]string "Error response"
+// @Failure 404 {object} map[string]string "Error response"
+// @Failure 500 {object} map[string]string "Error response"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/calendar [put]
+func (h *CalendarHandler) UpdateCalendar(c echo.Context) error {
+	var req UpdateCalendarRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request format"})
+	}
+
+	calendar, err := h.loanService.WithContext(c.Request().Context()).UpdateRestDays(req.RestDays)
+	if err != nil {
+		return calendarError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, newCalendarResponse(calendar))
+}
+
+// CreateCalendarHoliday godoc
+// @Summary Add a holiday
+// @Description Makes a date a holiday of the caller's tenant. Applies to schedules laid out from now on.
+// @Tags Calendar
+// @Accept json
+// @Produce json
+// @Param request body handlers.CreateCalendarHolidayRequest true "Holiday"
+// @Success 201 {object} handlers.CalendarHolidayResponse
+// @Failure 400 {object} map[string]string "Error response"
+// @Failure 401 {object} map[string]string "Error response"
+// @Failure 403 {object} map[string]string "Error response"
+// @Failure 404 {object} map[string]string "Error response"
+// @Failure 409 {object} map[string]string "Error response"
+// @Failure 500 {object} map[string]string "Error response"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/calendar/holidays [post]
+func (h *CalendarHandler) CreateCalendarHoliday(c echo.Context) error {
+	var req CreateCalendarHolidayRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request format"})
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	date, err := time.Parse(time.DateOnly, req.Date)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid date format"})
+	}
+
+	holiday, err := h.loanService.WithContext(c.Request().Context()).AddCalendarHoliday(date, req.Name)
+	if err != nil {
+		return calendarError(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, newCalendarHolidayResponse(holiday))
+}
+
+// DeleteCalendarHoliday godoc
+// @Summary Remove a holiday
+// @Description Removes a holiday of the caller's tenant. Schedules already laid out keep their due dates.
+// @Tags Calendar
+// @Accept json
+// @Produce json
+// @Param id path string true "Holiday ID" format(uuid)
+// @Success 204
+// @Failure 400 {object} map[string]string "Error response"
+// @Failure 401 {object} map[string]string "Error response"
+// @Failure 403 {object} map[string]string "Error response"
+// @Failure 404 {object} map[string]string "Error response"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/calendar/holidays/{id} [delete]
+func (h *CalendarHandler) DeleteCalendarHoliday(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid holiday ID format"})
+	}
+
+	if err := h.loanService.WithContext(c.Request().Context()).RemoveCalendarHoliday(id); err != nil {
+		return calendarError(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// calendarError maps calendar errors to responses
+func calendarError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, services.ErrTenantNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "Tenant not found"})
+	case errors.Is(err, services.ErrCalendarHolidayNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "Holiday not found"})
+	case errors.Is(err, services.ErrCalendarHolidayExists):
+		return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+	case errors.Is(err, services.ErrInvalidCalendar):
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	default:
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+}
+
+// newCalendarResponse converts a tenant's calendar into its response
+func newCalendarResponse(calendar *services.TenantCalendar) CalendarResponse {
+	response := CalendarResponse{
+		Tenant:   calendar.Tenant.ID,
+		RestDays: calendar.Tenant.RestDays,
+		Holidays: make([]CalendarHolidayResponse, 0, len(calendar.Holidays)),
+	}
+	if response.RestDays == nil {
+		response.RestDays = []string{}
+	}
+	for i := range calendar.Holidays {
+		response.Holidays = append(response.Holidays, newCalendarHolidayResponse(&calendar.Holidays[i]))
+	}
+	return response
+}
+
+// newCalendarHolidayResponse converts a holiday into its response
+func newCalendarHolidayResponse(holiday *models.CalendarHoliday) CalendarHolidayResponse {
+	return CalendarHolidayResponse{
+		ID:   holiday.ID,
+		Date: holiday.Date.Format(time.DateOnly),
+		Name: holiday.Name,
+	}
+}
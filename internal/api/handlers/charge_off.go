@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"loan-billing-system/internal/auth"
+	"loan-billing-system/internal/models"
+	"loan-billing-system/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// ChargeOffHandler handles HTTP requests related to the charge-off review queue
+type ChargeOffHandler struct {
+	loanService *services.LoanService
+}
+
+// NewChargeOffHandler creates a new charge-off handler
+func NewChargeOffHandler(loanService *services.LoanService) *ChargeOffHandler {
+	return &ChargeOffHandler{
+		loanService: loanService,
+	}
+}
+
+// ReviewChargeOffRequest represents the request body for approving or rejecting a charge-off
+// @Description Request body for reviewing a charge-off proposal. The caller is recorded as the reviewer.
+type ReviewChargeOffRequest struct {
+	Notes string `json:"notes" validate:"max=255"`
+}
+
+// ChargeOffResponse represents a charge-off proposal in responses
+// @Description Charge-off proposal raised by the nightly job
+type ChargeOffResponse struct {
+	ID          uuid.UUID  `json:"id"`
+	LoanID      uuid.UUID  `json:"loan_id"`
+	Balance     int64      `json:"balance"`
+	DaysPastDue uint       `json:"days_past_due"`
+	Rule        string     `json:"rule"`
+	Status      string     `json:"status" enums:"pending,approved,rejected"`
+	ReviewedBy  string     `json:"reviewed_by,omitempty"`
+	ReviewNotes string     `json:"review_notes,omitempty"`
+	ReviewedAt  *time.Time `json:"reviewed_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// ListChargeOffs godoc
+// @Summary List charge-off proposals
+// @Description Retrieves the charge-off review queue, oldest first
+// @Tags Charge-offs
+// @Accept json
+// @Produce json
+// @Param status query string false "Proposal status" Enums(pending, approved, rejected)
+// @Success 200 {array} handlers.ChargeOffResponse
+// @Failure 401 {object} map[string]string "Error response"
+// @Failure 403 {object} map[string]string "Error response"
+// @Failure 500 {object} map[string]string "Error response"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/charge-offs [get]
+func (h *ChargeOffHandler) ListChargeOffs(c echo.Context) error {
+	proposals, err := h.loanService.WithContext(c.Request().Context()).ListChargeOffProposals(c.QueryParam("status"))
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	response := make([]ChargeOffResponse, 0, len(proposals))
+	for i := range proposals {
+		response = append(response, newChargeOffResponse(&proposals[i]))
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// ApproveChargeOff godoc
+// @Summary Approve a charge-off
+// @Description Approves a pending charge-off proposal and writes the loan off
+// @Tags Charge-offs
+// @Accept json
+// @Produce json
+// @Param id path string true "Proposal ID" format(uuid)
+// @Param request body handlers.ReviewChargeOffRequest true "Review details"
+// @Success 200 {object} handlers.ChargeOffResponse
+// @Failure 400 {object} map[string]string "Error response"
+// @Failure 401 {object} map[string]string "Error response"
+// @Failure 403 {object} map[string]string "Error response"
+// @Failure 404 {object} map[string]string "Error response"
+// @Failure 409 {object} map[string]string "Error response"
+// @Failure 500 {object} map[string]string "Error response"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/charge-offs/{id}/approve [post]
+func (h *ChargeOffHandler) ApproveChargeOff(c echo.Context) error {
+	return h.review(c, true)
+}
+
+// RejectChargeOff godoc
+// @Summary Reject a charge-off
+// @Description Rejects a pending charge-off proposal; the loan keeps its status
+// @Tags Charge-offs
+// @Accept json
+// @Produce json
+// @Param id path string true "Proposal ID" format(uuid)
+// @Param request body handlers.ReviewChargeOffRequest true "Review details"
+// @Success 200 {object} handlers.ChargeOffResponse
+// @Failure 400 {object} map[string]string "Error response"
+// @Failure 401 {object} map[string]string "Error response"
+// @Failure 403 {object} map[string]string "Error response"
+// @Failure 404 {object} map[string]string "Error response"
+// @Failure 409 {object} map[string]string "Error response"
+// @Failure 500 {object} map[string]string "Error response"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/charge-offs/{id}/reject [post]
+func (h *ChargeOffHandler) RejectChargeOff(c echo.Context) error {
+	return h.review(c, false)
+}
+
+// review approves or rejects the charge-off proposal named in the path
+func (h *ChargeOffHandler) review(c echo.Context, approve bool) error {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid proposal ID format"})
+	}
+
+	var req ReviewChargeOffRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request format"})
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	proposal, err := h.loanService.WithContext(c.Request().Context()).ReviewChargeOff(id, approve, req.Notes)
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrForbidden):
+			return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
+		case errors.Is(err, services.ErrChargeOffNotFound):
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Charge-off proposal not found"})
+		case errors.Is(err, services.ErrChargeOffReviewed), errors.Is(err, services.ErrLoanNotWriteOffable):
+			return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		}
+	}
+
+	return c.JSON(http.StatusOK, newChargeOffResponse(proposal))
+}
+
+// newChargeOffResponse converts a charge-off proposal into its response representation
+func newChargeOffResponse(proposal *models.ChargeOffProposal) ChargeOffResponse {
+	return ChargeOffResponse{
+		ID:          proposal.ID,
+		LoanID:      proposal.LoanID,
+		Balance:     proposal.Balance,
+		DaysPastDue: proposal.DaysPastDue,
+		Rule:        proposal.Rule,
+		Status:      proposal.Status,
+		ReviewedBy:  proposal.ReviewedBy,
+		ReviewNotes: proposal.ReviewNotes,
+		ReviewedAt:  proposal.ReviewedAt,
+		CreatedAt:   proposal.CreatedAt,
+	}
+}
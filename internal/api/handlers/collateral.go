@@ -0,0 +1,319 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"loan-billing-system/internal/models"
+	"loan-billing-system/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// CollateralRequest represents the request body for pledging collateral against a loan
+// @Description Request body for recording collateral with its first valuation. valued_at defaults to now.
+type CollateralRequest struct {
+	Type        string     `json:"type" validate:"required,oneof=vehicle property equipment deposit other" enums:"vehicle,property,equipment,deposit,other"`
+	Description string     `json:"description" validate:"required,max=255"`
+	Value       int64      `json:"value" validate:"required,gt=0"`
+	ValuedAt    *time.Time `json:"valued_at"`
+	ValuedBy    string     `json:"valued_by" validate:"max=100"`
+	Notes       string     `json:"notes" validate:"max=255"`
+}
+
+// ValuationRequest represents the request body for revaluing collateral
+// @Description Request body for a new valuation of collateral. valued_at defaults to now.
+type ValuationRequest struct {
+	Value    int64      `json:"value" validate:"required,gt=0"`
+	ValuedAt *time.Time `json:"valued_at"`
+	ValuedBy string     `json:"valued_by" validate:"max=100"`
+	Notes    string     `json:"notes" validate:"max=255"`
+}
+
+// LienStatusRequest represents the request body for changing a lien's status
+// @Description Request body for changing the lien status of collateral. Releasing requires the loan to be fully paid.
+type LienStatusRequest struct {
+	Status string `json:"status" validate:"required,oneof=pending registered released" enums:"pending,registered,released"`
+}
+
+// ValuationResponse represents one valuation of collateral
+// @Description Collateral valuation
+type ValuationResponse struct {
+	Value    int64     `json:"value"`
+	ValuedAt time.Time `json:"valued_at"`
+	ValuedBy string    `json:"valued_by"`
+	Notes    string    `json:"notes"`
+}
+
+// CollateralResponse represents collateral in responses
+// @Description Collateral with its lien status and valuation history, oldest first
+type CollateralResponse struct {
+	ID          uuid.UUID           `json:"id"`
+	LoanID      uuid.UUID           `json:"loan_id"`
+	Type        string              `json:"type" enums:"vehicle,property,equipment,deposit,other"`
+	Description string              `json:"description"`
+	Value       int64               `json:"value"`
+	ValuedAt    time.Time           `json:"valued_at"`
+	LienStatus  string              `json:"lien_status" enums:"pending,registered,released"`
+	ReleasedAt  *time.Time          `json:"released_at"`
+	Valuations  []ValuationResponse `json:"valuations"`
+}
+
+// CollateralPositionResponse represents a loan's collateral and loan-to-value ratio
+// @Description Collateral pledged against a loan. loan_to_value is the current balance as a percentage of the value of the collateral not yet released.
+type CollateralPositionResponse struct {
+	LoanID         uuid.UUID            `json:"loan_id"`
+	CurrentBalance int64                `json:"current_balance"`
+	TotalValue     int64                `json:"total_value"`
+	LoanToValue    *float64             `json:"loan_to_value"`
+	Collateral     []CollateralResponse `json:"collateral"`
+}
+
+// AddCollateral godoc
+// @Summary Add collateral to a loan
+// @Description Records collateral pledged against an active or defaulted loan, with its first valuation. The lien starts as pending.
+// @Tags Loans
+// @Accept json
+// @Produce json
+// @Param id path string true "Loan ID" format(uuid)
+// @Param request body handlers.CollateralRequest true "Collateral details"
+// @Success 201 {object} handlers.CollateralResponse
+// @Failure 400 {object} map[string]string "Error response"
+// @Failure 401 {object} map[string]string "Error response"
+// @Failure 403 {object} map[string]string "Error response"
+// @Failure 404 {object} map[string]string "Error response"
+// @Failure 409 {object} map[string]string "Error response"
+// @Failure 500 {object} map[string]string "Error response"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/loans/{id}/collateral [post]
+func (h *LoanHandler) AddCollateral(c echo.Context) error {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid loan ID format"})
+	}
+
+	var req CollateralRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request format"})
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	details := services.CollateralDetails{
+		Type:        req.Type,
+		Description: req.Description,
+		Value:       req.Value,
+		ValuedBy:    req.ValuedBy,
+		Notes:       req.Notes,
+	}
+	if req.ValuedAt != nil {
+		details.ValuedAt = *req.ValuedAt
+	}
+
+	collateral, err := h.loanService.WithContext(c.Request().Context()).AddCollateral(id, details)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrLoanNotFound):
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Loan not found"})
+		case errors.Is(err, services.ErrInvalidCollateral):
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		case errors.Is(err, services.ErrLoanNotOpen):
+			return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		}
+	}
+
+	return c.JSON(http.StatusCreated, newCollateralResponse(collateral))
+}
+
+// ListCollateral godoc
+// @Summary List loan collateral
+// @Description Retrieves the collateral pledged against a loan with its valuation history, total value and loan-to-value ratio
+// @Tags Loans
+// @Accept json
+// @Produce json
+// @Param id path string true "Loan ID" format(uuid)
+// @Success 200 {object} handlers.CollateralPositionResponse
+// @Failure 400 {object} map[string]string "Error response"
+// @Failure 401 {object} map[string]string "Error response"
+// @Failure 403 {object} map[string]string "Error response"
+// @Failure 404 {object} map[string]string "Error response"
+// @Failure 500 {object} map[string]string "Error response"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/loans/{id}/collateral [get]
+func (h *LoanHandler) ListCollateral(c echo.Context) error {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid loan ID format"})
+	}
+
+	position, err := h.loanService.WithContext(c.Request().Context()).GetCollateral(id)
+	if err != nil {
+		if errors.Is(err, services.ErrLoanNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Loan not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	response := CollateralPositionResponse{
+		LoanID:         position.Loan.ID,
+		CurrentBalance: position.Loan.CurrentBalance,
+		TotalValue:     position.TotalValue,
+		LoanToValue:    position.LoanToValue,
+		Collateral:     make([]CollateralResponse, 0, len(position.Collateral)),
+	}
+	for i := range position.Collateral {
+		response.Collateral = append(response.Collateral, newCollateralResponse(&position.Collateral[i]))
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// RevalueCollateral godoc
+// @Summary Revalue collateral
+// @Description Records a new valuation of collateral. The collateral takes the new value unless a later valuation already exists.
+// @Tags Loans
+// @Accept json
+// @Produce json
+// @Param id path string true "Loan ID" format(uuid)
+// @Param collateralId path string true "Collateral ID" format(uuid)
+// @Param request body handlers.ValuationRequest true "Valuation details"
+// @Success 201 {object} handlers.CollateralResponse
+// @Failure 400 {object} map[string]string "Error response"
+// @Failure 401 {object} map[string]string "Error response"
+// @Failure 403 {object} map[string]string "Error response"
+// @Failure 404 {object} map[string]string "Error response"
+// @Failure 409 {object} map[string]string "Error response"
+// @Failure 500 {object} map[string]string "Error response"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/loans/{id}/collateral/{collateralId}/valuations [post]
+func (h *LoanHandler) RevalueCollateral(c echo.Context) error {
+	id, collateralID, ok := parseCollateralPath(c)
+	if !ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid loan or collateral ID format"})
+	}
+
+	var req ValuationRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request format"})
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	valuation := services.Valuation{Value: req.Value, ValuedBy: req.ValuedBy, Notes: req.Notes}
+	if req.ValuedAt != nil {
+		valuation.ValuedAt = *req.ValuedAt
+	}
+
+	collateral, err := h.loanService.WithContext(c.Request().Context()).RevalueCollateral(id, collateralID, valuation)
+	if err != nil {
+		return collateralError(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, newCollateralResponse(collateral))
+}
+
+// UpdateLienStatus godoc
+// @Summary Update a collateral lien
+// @Description Changes the lien status of collateral. Collateral can only be released once the loan is closed, and a release is final. Refinancing moves unreleased collateral to the new loan.
+// @Tags Loans
+// @Accept json
+// @Produce json
+// @Param id path string true "Loan ID" format(uuid)
+// @Param collateralId path string true "Collateral ID" format(uuid)
+// @Param request body handlers.LienStatusRequest true "New lien status"
+// @Success 200 {object} handlers.CollateralResponse
+// @Failure 400 {object} map[string]string "Error response"
+// @Failure 401 {object} map[string]string "Error response"
+// @Failure 403 {object} map[string]string "Error response"
+// @Failure 404 {object} map[string]string "Error response"
+// @Failure 409 {object} map[string]string "Error response"
+// @Failure 500 {object} map[string]string "Error response"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/loans/{id}/collateral/{collateralId}/lien [put]
+func (h *LoanHandler) UpdateLienStatus(c echo.Context) error {
+	id, collateralID, ok := parseCollateralPath(c)
+	if !ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid loan or collateral ID format"})
+	}
+
+	var req LienStatusRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request format"})
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	collateral, err := h.loanService.WithContext(c.Request().Context()).UpdateLienStatus(id, collateralID, req.Status)
+	if err != nil {
+		return collateralError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, newCollateralResponse(collateral))
+}
+
+// parseCollateralPath reads the loan and collateral IDs from the request path
+func parseCollateralPath(c echo.Context) (uuid.UUID, uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, false
+	}
+	collateralID, err := uuid.Parse(c.Param("collateralId"))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, false
+	}
+	return id, collateralID, true
+}
+
+// collateralError maps collateral service errors to responses
+func collateralError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, services.ErrLoanNotFound), errors.Is(err, services.ErrCollateralNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	case errors.Is(err, services.ErrInvalidCollateral), errors.Is(err, services.ErrInvalidLienStatus):
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	case errors.Is(err, services.ErrLoanNotFullyPaid), errors.Is(err, services.ErrCollateralReleased):
+		return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+	default:
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+}
+
+// newCollateralResponse converts collateral into its response representation
+func newCollateralResponse(collateral *models.Collateral) CollateralResponse {
+	response := CollateralResponse{
+		ID:          collateral.ID,
+		LoanID:      collateral.LoanID,
+		Type:        collateral.Type,
+		Description: collateral.Description,
+		Value:       collateral.Value,
+		ValuedAt:    collateral.ValuedAt,
+		LienStatus:  collateral.LienStatus,
+		ReleasedAt:  collateral.ReleasedAt,
+		Valuations:  make([]ValuationResponse, 0, len(collateral.Valuations)),
+	}
+	for _, valuation := range collateral.Valuations {
+		response.Valuations = append(response.Valuations, ValuationResponse{
+			Value:    valuation.Value,
+			ValuedAt: valuation.ValuedAt,
+			ValuedBy: valuation.ValuedBy,
+			Notes:    valuation.Notes,
+		})
+	}
+	return response
+}
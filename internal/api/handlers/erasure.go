@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"loan-billing-system/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// ErasureStatusResponse represents where a borrower's erasure stands
+// @Description Whether a borrower's PII has been erased and, if not, what it is waiting for. eligible_at is null while the borrower is on an open loan.
+type ErasureStatusResponse struct {
+	Borrower   BorrowerResponse `json:"borrower"`
+	Anonymized bool             `json:"anonymized"`
+	OpenLoans  int              `json:"open_loans"`
+	EligibleAt *time.Time       `json:"eligible_at"`
+}
+
+// RequestBorrowerErasure godoc
+// @Summary Request erasure of a borrower's data
+// @Description Records a borrower's request to have their PII erased. It is anonymized straight away (200) when every loan they are on is closed and DATA_RETENTION_DAYS have passed since the last loan activity; otherwise the request waits (202) and the nightly job anonymizes them once eligible. The caller is recorded as who took the request. Loans, schedules and payments are kept.
+// @Tags Borrowers
+// @Accept json
+// @Produce json
+// @Param id path string true "Borrower ID" format(uuid)
+// @Success 200 {object} handlers.ErasureStatusResponse "Anonymized"
+// @Success 202 {object} handlers.ErasureStatusResponse "Waiting until eligible"
+// @Failure 400 {object} map[string]string "Error response"
+// @Failure 401 {object} map[string]string "Error response"
+// @Failure 403 {object} map[string]string "Error response"
+// @Failure 404 {object} map[string]string "Error response"
+// @Failure 409 {object} map[string]string "Error response"
+// @Failure 500 {object} map[string]string "Error response"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/borrowers/{id}/erasure [post]
+func (h *BorrowerHandler) RequestBorrowerErasure(c echo.Context) error {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid borrower ID format"})
+	}
+
+	status, err := h.borrowerService.WithContext(c.Request().Context()).RequestErasure(id)
+	if err != nil {
+		return erasureError(c, err)
+	}
+
+	if !status.Anonymized {
+		return c.JSON(http.StatusAccepted, newErasureStatusResponse(status))
+	}
+	return c.JSON(http.StatusOK, newErasureStatusResponse(status))
+}
+
+// GetBorrowerErasure godoc
+// @Summary Get a borrower's erasure status
+// @Description Reports whether a borrower's PII has been erased, how many open loans they are on and when the retention period ends
+// @Tags Borrowers
+// @Accept json
+// @Produce json
+// @Param id path string true "Borrower ID" format(uuid)
+// @Success 200 {object} handlers.ErasureStatusResponse
+// @Failure 400 {object} map[string]string "Error response"
+// @Failure 401 {object} map[string]string "Error response"
+// @Failure 403 {object} map[string]string "Error response"
+// @Failure 404 {object} map[string]string "Error response"
+// @Failure 500 {object} map[string]string "Error response"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/borrowers/{id}/erasure [get]
+func (h *BorrowerHandler) GetBorrowerErasure(c echo.Context) error {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid borrower ID format"})
+	}
+
+	status, err := h.borrowerService.WithContext(c.Request().Context()).GetErasureStatus(id)
+	if err != nil {
+		return erasureError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, newErasureStatusResponse(status))
+}
+
+// erasureError maps an erasure service error to its HTTP response
+func erasureError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, services.ErrBorrowerNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "Borrower not found"})
+	case errors.Is(err, services.ErrAlreadyAnonymized):
+		return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+	default:
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+}
+
+// newErasureStatusResponse converts an erasure status into its response representation
+func newErasureStatusResponse(status *services.ErasureStatus) ErasureStatusResponse {
+	return ErasureStatusResponse{
+		Borrower:   newBorrowerResponse(status.Borrower),
+		Anonymized: status.Anonymized,
+		OpenLoans:  status.OpenLoans,
+		EligibleAt: status.EligibleAt,
+	}
+}
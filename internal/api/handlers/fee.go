@@ -0,0 +1,218 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"loan-billing-system/internal/models"
+	"loan-billing-system/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// CreateFeeRequest represents the request body for attaching a fee to a product
+// @Description Request body for attaching a fee to a product. Flat fees use amount, percentage fees use rate (percent of the requested amount).
+type CreateFeeRequest struct {
+	Name   string  `json:"name" validate:"required,max=100"`
+	Type   string  `json:"type" validate:"required,oneof=deducted capitalized installment" enums:"deducted,capitalized,installment"`
+	Method string  `json:"method" validate:"required,oneof=flat percent" enums:"flat,percent"`
+	Amount int64   `json:"amount" validate:"min=0"`
+	Rate   float64 `json:"rate" validate:"min=0"`
+}
+
+// FeeDefinitionResponse represents a product fee in responses
+// @Description Fee charged on every new loan of a product
+type FeeDefinitionResponse struct {
+	ID          uuid.UUID `json:"id"`
+	ProductCode string    `json:"product_code"`
+	Name        string    `json:"name"`
+	Type        string    `json:"type" enums:"deducted,capitalized,installment"`
+	Method      string    `json:"method" enums:"flat,percent"`
+	Amount      int64     `json:"amount"`
+	Rate        float64   `json:"rate"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// LoanFeeResponse represents a fee charged on a loan
+// @Description Fee charged on a loan. Installment fees show the total over the term.
+type LoanFeeResponse struct {
+	ID              uuid.UUID `json:"id"`
+	LoanID          uuid.UUID `json:"loan_id"`
+	FeeDefinitionID uuid.UUID `json:"fee_definition_id"`
+	Name            string    `json:"name"`
+	Type            string    `json:"type" enums:"deducted,capitalized,installment"`
+	Amount          int64     `json:"amount"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// ListProductFees godoc
+// @Summary List product fees
+// @Description Retrieves the fees charged on every new loan of a product
+// @Tags Products
+// @Accept json
+// @Produce json
+// @Param code path string true "Product code"
+// @Success 200 {array} handlers.FeeDefinitionResponse
+// @Failure 401 {object} map[string]string "Error response"
+// @Failure 403 {object} map[string]string "Error response"
+// @Failure 404 {object} map[string]string "Error response"
+// @Failure 500 {object} map[string]string "Error response"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/products/{code}/fees [get]
+func (h *ProductHandler) ListProductFees(c echo.Context) error {
+	definitions, err := h.loanService.WithContext(c.Request().Context()).ListProductFees(c.Param("code"))
+	if err != nil {
+		if errors.Is(err, services.ErrProductNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Product not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	response := make([]FeeDefinitionResponse, 0, len(definitions))
+	for i := range definitions {
+		response = append(response, newFeeDefinitionResponse(&definitions[i]))
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// CreateProductFee godoc
+// @Summary Add a product fee
+// @Description Attaches a fee to a product. It is charged on loans created afterwards; existing loans are not affected.
+// @Tags Products
+// @Accept json
+// @Produce json
+// @Param code path string true "Product code"
+// @Param request body handlers.CreateFeeRequest true "Fee details"
+// @Success 201 {object} handlers.FeeDefinitionResponse
+// @Failure 400 {object} map[string]string "Error response"
+// @Failure 401 {object} map[string]string "Error response"
+// @Failure 403 {object} map[string]string "Error response"
+// @Failure 404 {object} map[string]string "Error response"
+// @Failure 500 {object} map[string]string "Error response"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/products/{code}/fees [post]
+func (h *ProductHandler) CreateProductFee(c echo.Context) error {
+	var req CreateFeeRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request format"})
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	definition, err := h.loanService.WithContext(c.Request().Context()).AddProductFee(c.Param("code"), models.FeeDefinition{
+		Name:   req.Name,
+		Type:   req.Type,
+		Method: req.Method,
+		Amount: req.Amount,
+		Rate:   req.Rate,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrProductNotFound):
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Product not found"})
+		case errors.Is(err, services.ErrInvalidFee):
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		}
+	}
+
+	return c.JSON(http.StatusCreated, newFeeDefinitionResponse(definition))
+}
+
+// DeleteProductFee godoc
+// @Summary Remove a product fee
+// @Description Detaches a fee from a product. Loans already charged keep their fees.
+// @Tags Products
+// @Accept json
+// @Produce json
+// @Param code path string true "Product code"
+// @Param feeId path string true "Fee definition ID" format(uuid)
+// @Success 204
+// @Failure 400 {object} map[string]string "Error response"
+// @Failure 401 {object} map[string]string "Error response"
+// @Failure 403 {object} map[string]string "Error response"
+// @Failure 404 {object} map[string]string "Error response"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/products/{code}/fees/{feeId} [delete]
+func (h *ProductHandler) DeleteProductFee(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("feeId"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid fee ID format"})
+	}
+
+	if err := h.loanService.WithContext(c.Request().Context()).RemoveProductFee(c.Param("code"), id); err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "Fee not found"})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ListLoanFees godoc
+// @Summary List loan fees
+// @Description Retrieves the fees charged on a loan when it was created
+// @Tags Loans
+// @Accept json
+// @Produce json
+// @Param id path string true "Loan ID" format(uuid)
+// @Success 200 {array} handlers.LoanFeeResponse
+// @Failure 400 {object} map[string]string "Error response"
+// @Failure 401 {object} map[string]string "Error response"
+// @Failure 403 {object} map[string]string "Error response"
+// @Failure 404 {object} map[string]string "Error response"
+// @Failure 500 {object} map[string]string "Error response"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/loans/{id}/fees [get]
+func (h *LoanHandler) ListLoanFees(c echo.Context) error {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid loan ID format"})
+	}
+
+	fees, err := h.loanService.WithContext(c.Request().Context()).GetLoanFees(id)
+	if err != nil {
+		if errors.Is(err, services.ErrLoanNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Loan not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	response := make([]LoanFeeResponse, 0, len(fees))
+	for _, fee := range fees {
+		response = append(response, LoanFeeResponse{
+			ID:              fee.ID,
+			LoanID:          fee.LoanID,
+			FeeDefinitionID: fee.FeeDefinitionID,
+			Name:            fee.Name,
+			Type:            fee.Type,
+			Amount:          fee.Amount,
+			CreatedAt:       fee.CreatedAt,
+		})
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// newFeeDefinitionResponse converts a fee definition into its response representation
+func newFeeDefinitionResponse(definition *models.FeeDefinition) FeeDefinitionResponse {
+	return FeeDefinitionResponse{
+		ID:          definition.ID,
+		ProductCode: definition.ProductCode,
+		Name:        definition.Name,
+		Type:        definition.Type,
+		Method:      definition.Method,
+		Amount:      definition.Amount,
+		Rate:        definition.Rate,
+		CreatedAt:   definition.CreatedAt,
+	}
+}
@@ -0,0 +1,507 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"loan-billing-system/internal/models"
+	"loan-billing-system/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// GroupHandler handles HTTP requests related to loan groups
+type GroupHandler struct {
+	loanService *services.LoanService
+}
+
+// NewGroupHandler creates a new group handler
+func NewGroupHandler(loanService *services.LoanService) *GroupHandler {
+	return &GroupHandler{
+		loanService: loanService,
+	}
+}
+
+// CreateGroupRequest represents the request body for creating a loan group
+// @Description Request body for creating a loan group. The leader is added as a member.
+type CreateGroupRequest struct {
+	Name      string      `json:"name" validate:"required,max=255"`
+	LeaderID  uuid.UUID   `json:"leader_id" validate:"required"`
+	MemberIDs []uuid.UUID `json:"member_ids"`
+}
+
+// GroupMemberRequest represents the request body for adding a member or appointing a leader
+// @Description Borrower to add to a loan group or appoint as its leader
+type GroupMemberRequest struct {
+	BorrowerID uuid.UUID `json:"borrower_id" validate:"required"`
+}
+
+// GroupPaymentRequest represents the request body for recording a group payment
+// @Description Request body for a payment collected from a whole group
+type GroupPaymentRequest struct {
+	Amount int64 `json:"amount" validate:"required,min=1"`
+}
+
+// GroupMemberResponse represents a member of a loan group
+// @Description Member of a loan group
+type GroupMemberResponse struct {
+	BorrowerID uuid.UUID `json:"borrower_id"`
+	IsLeader   bool      `json:"is_leader"`
+	JoinedAt   time.Time `json:"joined_at"`
+}
+
+// GroupResponse represents a loan group in responses
+// @Description Loan group with its members. The group is delinquent when any member has an overdue installment.
+type GroupResponse struct {
+	ID             uuid.UUID             `json:"id"`
+	Name           string                `json:"name"`
+	LeaderID       uuid.UUID             `json:"leader_id"`
+	Members        []GroupMemberResponse `json:"members"`
+	LoanIDs        []uuid.UUID           `json:"loan_ids,omitempty"`
+	Outstanding    int64                 `json:"outstanding"`
+	IsDelinquent   bool                  `json:"is_delinquent"`
+	OverdueMembers []uuid.UUID           `json:"overdue_members,omitempty"`
+	OverdueAmount  int64                 `json:"overdue_amount"`
+}
+
+// GroupInstallmentResponse represents a member's installment within a group view
+// @Description Installment of a group member's loan
+type GroupInstallmentResponse struct {
+	LoanID     uuid.UUID `json:"loan_id"`
+	BorrowerID uuid.UUID `json:"borrower_id"`
+	InstallmentResponse
+}
+
+// GroupScheduleDayResponse represents the installments of a group falling due on one day
+// @Description Group installments falling due on the same day
+type GroupScheduleDayResponse struct {
+	DueDate      time.Time                  `json:"due_date"`
+	TotalDue     int64                      `json:"total_due"`
+	TotalPaid    int64                      `json:"total_paid"`
+	Installments []GroupInstallmentResponse `json:"installments"`
+}
+
+// GroupCollectionResponse represents what a group owes as of a date
+// @Description Unpaid installments of a group that have fallen due, for collection at a group meeting
+type GroupCollectionResponse struct {
+	GroupID      uuid.UUID                  `json:"group_id"`
+	AsOf         time.Time                  `json:"as_of"`
+	TotalDue     int64                      `json:"total_due"`
+	Installments []GroupInstallmentResponse `json:"installments"`
+}
+
+// GroupPaymentResponse represents a group payment and how it was split
+// @Description Group payment with the member payments it was split into
+type GroupPaymentResponse struct {
+	ID          uuid.UUID         `json:"id"`
+	GroupID     uuid.UUID         `json:"group_id"`
+	Amount      int64             `json:"amount"`
+	PaymentDate time.Time         `json:"payment_date"`
+	CollectedBy string            `json:"collected_by"`
+	Payments    []PaymentResponse `json:"payments"`
+}
+
+// CreateGroup godoc
+// @Summary Create a loan group
+// @Description Creates a loan group of borrowers who are jointly accountable for each other's loans
+// @Tags Groups
+// @Accept json
+// @Produce json
+// @Param request body handlers.CreateGroupRequest true "Group details"
+// @Success 201 {object} handlers.GroupResponse
+// @Failure 400 {object} map[string]string "Error response"
+// @Failure 401 {object} map[string]string "Error response"
+// @Failure 403 {object} map[string]string "Error response"
+// @Failure 500 {object} map[string]string "Error response"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/groups [post]
+func (h *GroupHandler) CreateGroup(c echo.Context) error {
+	var req CreateGroupRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request format"})
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	group, err := h.loanService.WithContext(c.Request().Context()).CreateGroup(req.Name, req.LeaderID, req.MemberIDs)
+	if err != nil {
+		if errors.Is(err, services.ErrBorrowerNotFound) {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusCreated, newGroupResponse(group))
+}
+
+// GetGroup godoc
+// @Summary Get loan group details
+// @Description Retrieves a loan group with its members, loans, outstanding balance and delinquency
+// @Tags Groups
+// @Accept json
+// @Produce json
+// @Param id path string true "Group ID" format(uuid)
+// @Success 200 {object} handlers.GroupResponse
+// @Failure 400 {object} map[string]string "Error response"
+// @Failure 401 {object} map[string]string "Error response"
+// @Failure 403 {object} map[string]string "Error response"
+// @Failure 404 {object} map[string]string "Error response"
+// @Failure 500 {object} map[string]string "Error response"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/groups/{id} [get]
+func (h *GroupHandler) GetGroup(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid group ID format"})
+	}
+
+	summary, err := h.loanService.WithContext(c.Request().Context()).GetGroup(id)
+	if err != nil {
+		if errors.Is(err, services.ErrGroupNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Group not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	response := newGroupResponse(summary.Group)
+	for _, loan := range summary.Loans {
+		response.LoanIDs = append(response.LoanIDs, loan.ID)
+	}
+	response.Outstanding = summary.Outstanding
+	response.IsDelinquent = summary.IsDelinquent
+	response.OverdueMembers = summary.OverdueMembers
+	response.OverdueAmount = summary.OverdueAmount
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// AddGroupMember godoc
+// @Summary Add a group member
+// @Description Adds a borrower to a loan group
+// @Tags Groups
+// @Accept json
+// @Produce json
+// @Param id path string true "Group ID" format(uuid)
+// @Param request body handlers.GroupMemberRequest true "Member to add"
+// @Success 201 {object} handlers.GroupMemberResponse
+// @Failure 400 {object} map[string]string "Error response"
+// @Failure 401 {object} map[string]string "Error response"
+// @Failure 403 {object} map[string]string "Error response"
+// @Failure 404 {object} map[string]string "Error response"
+// @Failure 409 {object} map[string]string "Error response"
+// @Failure 500 {object} map[string]string "Error response"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/groups/{id}/members [post]
+func (h *GroupHandler) AddGroupMember(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid group ID format"})
+	}
+
+	var req GroupMemberRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request format"})
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	member, err := h.loanService.WithContext(c.Request().Context()).AddGroupMember(id, req.BorrowerID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrGroupNotFound):
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Group not found"})
+		case errors.Is(err, services.ErrBorrowerNotFound):
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Borrower not found"})
+		case errors.Is(err, services.ErrAlreadyGroupMember):
+			return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		}
+	}
+
+	return c.JSON(http.StatusCreated, GroupMemberResponse{
+		BorrowerID: member.BorrowerID,
+		JoinedAt:   member.CreatedAt,
+	})
+}
+
+// RemoveGroupMember godoc
+// @Summary Remove a group member
+// @Description Removes a borrower from a loan group. The leader and members with an open loan through the group cannot be removed.
+// @Tags Groups
+// @Accept json
+// @Produce json
+// @Param id path string true "Group ID" format(uuid)
+// @Param borrowerId path string true "Borrower ID" format(uuid)
+// @Success 204
+// @Failure 400 {object} map[string]string "Error response"
+// @Failure 401 {object} map[string]string "Error response"
+// @Failure 403 {object} map[string]string "Error response"
+// @Failure 404 {object} map[string]string "Error response"
+// @Failure 409 {object} map[string]string "Error response"
+// @Failure 500 {object} map[string]string "Error response"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/groups/{id}/members/{borrowerId} [delete]
+func (h *GroupHandler) RemoveGroupMember(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid group ID format"})
+	}
+	borrowerID, err := uuid.Parse(c.Param("borrowerId"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid borrower ID format"})
+	}
+
+	if err := h.loanService.WithContext(c.Request().Context()).RemoveGroupMember(id, borrowerID); err != nil {
+		switch {
+		case errors.Is(err, services.ErrGroupNotFound):
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Group not found"})
+		case errors.Is(err, services.ErrNotGroupMember):
+			return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+		case errors.Is(err, services.ErrRemoveGroupLeader), errors.Is(err, services.ErrMemberHasOpenLoan):
+			return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		}
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// SetGroupLeader godoc
+// @Summary Appoint a group leader
+// @Description Appoints a member as the leader of a loan group
+// @Tags Groups
+// @Accept json
+// @Produce json
+// @Param id path string true "Group ID" format(uuid)
+// @Param request body handlers.GroupMemberRequest true "New leader"
+// @Success 200 {object} handlers.GroupResponse
+// @Failure 400 {object} map[string]string "Error response"
+// @Failure 401 {object} map[string]string "Error response"
+// @Failure 403 {object} map[string]string "Error response"
+// @Failure 404 {object} map[string]string "Error response"
+// @Failure 500 {object} map[string]string "Error response"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/groups/{id}/leader [put]
+func (h *GroupHandler) SetGroupLeader(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid group ID format"})
+	}
+
+	var req GroupMemberRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request format"})
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	group, err := h.loanService.WithContext(c.Request().Context()).SetGroupLeader(id, req.BorrowerID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrGroupNotFound):
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Group not found"})
+		case errors.Is(err, services.ErrNotGroupMember):
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		}
+	}
+
+	return c.JSON(http.StatusOK, newGroupResponse(group))
+}
+
+// GetGroupSchedule godoc
+// @Summary Get a group's schedule
+// @Description Retrieves the installments of every open loan in the group, gathered by due date with the total due and paid on each day
+// @Tags Groups
+// @Accept json
+// @Produce json
+// @Param id path string true "Group ID" format(uuid)
+// @Success 200 {array} handlers.GroupScheduleDayResponse
+// @Failure 400 {object} map[string]string "Error response"
+// @Failure 401 {object} map[string]string "Error response"
+// @Failure 403 {object} map[string]string "Error response"
+// @Failure 404 {object} map[string]string "Error response"
+// @Failure 500 {object} map[string]string "Error response"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/groups/{id}/schedule [get]
+func (h *GroupHandler) GetGroupSchedule(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid group ID format"})
+	}
+
+	days, err := h.loanService.WithContext(c.Request().Context()).GetGroupSchedule(id)
+	if err != nil {
+		if errors.Is(err, services.ErrGroupNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Group not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	response := make([]GroupScheduleDayResponse, 0, len(days))
+	for _, day := range days {
+		response = append(response, GroupScheduleDayResponse{
+			DueDate:      day.DueDate,
+			TotalDue:     day.TotalDue,
+			TotalPaid:    day.TotalPaid,
+			Installments: newGroupInstallmentResponses(day.Installments),
+		})
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// GetGroupCollection godoc
+// @Summary Get a group's collection sheet
+// @Description Lists the unpaid installments of the group's loans that have fallen due by as_of, overdue ones included, and their total
+// @Tags Groups
+// @Accept json
+// @Produce json
+// @Param id path string true "Group ID" format(uuid)
+// @Param as_of query string false "Collection date (RFC3339, or YYYY-MM-DD for the whole day); defaults to now"
+// @Success 200 {object} handlers.GroupCollectionResponse
+// @Failure 400 {object} map[string]string "Error response"
+// @Failure 401 {object} map[string]string "Error response"
+// @Failure 403 {object} map[string]string "Error response"
+// @Failure 404 {object} map[string]string "Error response"
+// @Failure 500 {object} map[string]string "Error response"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/groups/{id}/collections [get]
+func (h *GroupHandler) GetGroupCollection(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid group ID format"})
+	}
+
+	asOf := time.Now()
+	parsed, err := parseTimeParam(c, "as_of", true)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	if parsed != nil {
+		asOf = *parsed
+	}
+
+	collection, err := h.loanService.WithContext(c.Request().Context()).GetGroupCollection(id, asOf)
+	if err != nil {
+		if errors.Is(err, services.ErrGroupNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Group not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, GroupCollectionResponse{
+		GroupID:      id,
+		AsOf:         collection.AsOf,
+		TotalDue:     collection.TotalDue,
+		Installments: newGroupInstallmentResponses(collection.Installments),
+	})
+}
+
+// MakeGroupPayment godoc
+// @Summary Record a group payment
+// @Description Records one payment collected from the group and splits it across the members' loans, paying whole installments with the oldest due date first. The amount must add up to whole installments exactly. The caller is recorded as the collector.
+// @Tags Groups
+// @Accept json
+// @Produce json
+// @Param id path string true "Group ID" format(uuid)
+// @Param request body handlers.GroupPaymentRequest true "Payment details"
+// @Success 201 {object} handlers.GroupPaymentResponse
+// @Failure 400 {object} map[string]string "Error response"
+// @Failure 401 {object} map[string]string "Error response"
+// @Failure 403 {object} map[string]string "Error response"
+// @Failure 404 {object} map[string]string "Error response"
+// @Failure 500 {object} map[string]string "Error response"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/groups/{id}/payments [post]
+func (h *GroupHandler) MakeGroupPayment(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid group ID format"})
+	}
+
+	var req GroupPaymentRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request format"})
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	result, err := h.loanService.WithContext(c.Request().Context()).MakeGroupPayment(id, req.Amount)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrGroupNotFound):
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Group not found"})
+		case errors.Is(err, services.ErrGroupPaymentMismatch):
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		}
+	}
+
+	response := GroupPaymentResponse{
+		ID:          result.GroupPayment.ID,
+		GroupID:     result.GroupPayment.GroupID,
+		Amount:      result.GroupPayment.Amount,
+		PaymentDate: result.GroupPayment.PaymentDate,
+		CollectedBy: result.GroupPayment.CollectedBy,
+		Payments:    make([]PaymentResponse, 0, len(result.Payments)),
+	}
+	for i := range result.Payments {
+		response.Payments = append(response.Payments, newPaymentResponse(&result.Payments[i]))
+	}
+
+	return c.JSON(http.StatusCreated, response)
+}
+
+// newGroupResponse converts a loan group into its response representation
+func newGroupResponse(group *models.LoanGroup) GroupResponse {
+	response := GroupResponse{
+		ID:       group.ID,
+		Name:     group.Name,
+		LeaderID: group.LeaderID,
+		Members:  make([]GroupMemberResponse, 0, len(group.Members)),
+	}
+	for _, member := range group.Members {
+		response.Members = append(response.Members, GroupMemberResponse{
+			BorrowerID: member.BorrowerID,
+			IsLeader:   member.BorrowerID == group.LeaderID,
+			JoinedAt:   member.CreatedAt,
+		})
+	}
+	return response
+}
+
+// newGroupInstallmentResponses converts group installments into their response representation
+func newGroupInstallmentResponses(installments []services.GroupInstallment) []GroupInstallmentResponse {
+	response := make([]GroupInstallmentResponse, 0, len(installments))
+	for i := range installments {
+		response = append(response, GroupInstallmentResponse{
+			LoanID:              installments[i].LoanID,
+			BorrowerID:          installments[i].BorrowerID,
+			InstallmentResponse: newInstallmentResponse(&installments[i].Installment),
+		})
+	}
+	return response
+}
@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"loan-billing-system/internal/auth"
+	"loan-billing-system/internal/models"
+	"loan-billing-system/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// GrantHolidayRequest represents the request body for granting a payment holiday
+// @Description Request body for deferring a loan's installments
+type GrantHolidayRequest struct {
+	Installments   uint   `json:"installments" validate:"required,min=1,max=52"`
+	ReasonCode     string `json:"reason_code" validate:"required,oneof=hardship medical job_loss natural_disaster other"`
+	Notes          string `json:"notes" validate:"max=255"`
+	AccrueInterest bool   `json:"accrue_interest"`
+}
+
+// HolidayResponse represents a payment holiday granted on a loan
+// @Description Record of a payment holiday
+type HolidayResponse struct {
+	ID              uuid.UUID `json:"id"`
+	LoanID          uuid.UUID `json:"loan_id"`
+	Installments    uint      `json:"installments"`
+	StartDate       time.Time `json:"start_date"`
+	EndDate         time.Time `json:"end_date"`
+	ReasonCode      string    `json:"reason_code"`
+	Notes           string    `json:"notes"`
+	ApprovedBy      string    `json:"approved_by"`
+	AccruedInterest int64     `json:"accrued_interest"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// GrantHoliday godoc
+// @Summary Grant a payment holiday
+// @Description Pushes the loan's unpaid installments back by the given number of weeks. Deferred installments do not count towards delinquency until the holiday ends. Needs loans:approve; the caller is recorded as the approver.
+// @Tags Loans
+// @Accept json
+// @Produce json
+// @Param id path string true "Loan ID" format(uuid)
+// @Param request body handlers.GrantHolidayRequest true "Holiday details"
+// @Success 200 {object} handlers.HolidayResponse
+// @Failure 400 {object} map[string]string "Error response"
+// @Failure 401 {object} map[string]string "Error response"
+// @Failure 403 {object} map[string]string "Error response"
+// @Failure 404 {object} map[string]string "Error response"
+// @Failure 409 {object} map[string]string "Error response"
+// @Failure 500 {object} map[string]string "Error response"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/loans/{id}/holiday [post]
+func (h *LoanHandler) GrantHoliday(c echo.Context) error {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid loan ID format"})
+	}
+
+	var req GrantHolidayRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request format"})
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	holiday, err := h.loanService.WithContext(c.Request().Context()).GrantPaymentHoliday(id, services.HolidayTerms{
+		Installments:   req.Installments,
+		ReasonCode:     req.ReasonCode,
+		Notes:          req.Notes,
+		AccrueInterest: req.AccrueInterest,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrForbidden):
+			return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
+		case errors.Is(err, services.ErrLoanNotFound):
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Loan not found"})
+		case errors.Is(err, services.ErrLoanNotDeferrable), errors.Is(err, services.ErrHolidayInProgress), errors.Is(err, services.ErrNothingToDefer):
+			return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		}
+	}
+
+	return c.JSON(http.StatusOK, newHolidayResponse(holiday))
+}
+
+// ListHolidays godoc
+// @Summary List payment holidays
+// @Description Retrieves the payment holidays granted on a loan, oldest first
+// @Tags Loans
+// @Accept json
+// @Produce json
+// @Param id path string true "Loan ID" format(uuid)
+// @Success 200 {array} handlers.HolidayResponse
+// @Failure 400 {object} map[string]string "Error response"
+// @Failure 401 {object} map[string]string "Error response"
+// @Failure 403 {object} map[string]string "Error response"
+// @Failure 404 {object} map[string]string "Error response"
+// @Failure 500 {object} map[string]string "Error response"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/loans/{id}/holidays [get]
+func (h *LoanHandler) ListHolidays(c echo.Context) error {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid loan ID format"})
+	}
+
+	holidays, err := h.loanService.WithContext(c.Request().Context()).GetPaymentHolidays(id)
+	if err != nil {
+		if errors.Is(err, services.ErrLoanNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Loan not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	response := make([]HolidayResponse, 0, len(holidays))
+	for i := range holidays {
+		response = append(response, newHolidayResponse(&holidays[i]))
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// newHolidayResponse converts a payment holiday into its response representation
+func newHolidayResponse(holiday *models.PaymentHoliday) HolidayResponse {
+	return HolidayResponse{
+		ID:              holiday.ID,
+		LoanID:          holiday.LoanID,
+		Installments:    holiday.Installments,
+		StartDate:       holiday.StartDate,
+		EndDate:         holiday.EndDate,
+		ReasonCode:      holiday.ReasonCode,
+		Notes:           holiday.Notes,
+		ApprovedBy:      holiday.ApprovedBy,
+		AccruedInterest: holiday.AccruedInterest,
+		CreatedAt:       holiday.CreatedAt,
+	}
+}
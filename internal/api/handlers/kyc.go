@@ -0,0 +1,419 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"loan-billing-system/internal/auth"
+	"loan-billing-system/internal/models"
+	"loan-billing-system/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// BorrowerProfileRequest represents a borrower's identity, contact details and income
+// @Description Borrower profile. National ID formats depend on national_id_type; phone is E.164; the borrower must be of age; employer is required when employed or self-employed.
+type BorrowerProfileRequest struct {
+	Name             string `json:"name" validate:"required,max=255"`
+	ContactInfo      string `json:"contact_info" validate:"max=255"`
+	NationalIDType   string `json:"national_id_type" validate:"omitempty,oneof=national_id passport drivers_license tax_id" enums:"national_id,passport,drivers_license,tax_id"`
+	NationalID       string `json:"national_id" validate:"max=50"`
+	DateOfBirth      string `json:"date_of_birth" validate:"omitempty,datetime=2006-01-02" example:"1990-04-21"`
+	Phone            string `json:"phone" validate:"max=20" example:"+6281234567890"`
+	Email            string `json:"email" validate:"max=255"`
+	EmploymentStatus string `json:"employment_status" validate:"omitempty,oneof=employed self_employed unemployed retired student" enums:"employed,self_employed,unemployed,retired,student"`
+	Employer         string `json:"employer" validate:"max=255"`
+	MonthlyIncome    int64  `json:"monthly_income" validate:"min=0"`
+}
+
+// AddressRequest represents one of a borrower's addresses
+// @Description Borrower address. country is an ISO 3166-1 alpha-2 code.
+type AddressRequest struct {
+	Type       string `json:"type" validate:"required,oneof=residential mailing work" enums:"residential,mailing,work"`
+	Line1      string `json:"line1" validate:"required,max=255"`
+	Line2      string `json:"line2" validate:"max=255"`
+	City       string `json:"city" validate:"required,max=100"`
+	Region     string `json:"region" validate:"max=100"`
+	PostalCode string `json:"postal_code" validate:"max=20"`
+	Country    string `json:"country" validate:"required,len=2" example:"ID"`
+}
+
+// AddressResponse represents a borrower's address in responses
+// @Description Borrower address
+type AddressResponse struct {
+	ID         uuid.UUID `json:"id"`
+	Type       string    `json:"type"`
+	Line1      string    `json:"line1"`
+	Line2      string    `json:"line2"`
+	City       string    `json:"city"`
+	Region     string    `json:"region"`
+	PostalCode string    `json:"postal_code"`
+	Country    string    `json:"country"`
+}
+
+// DocumentRequest represents the metadata of a KYC document
+// @Description Metadata of a document already uploaded to document storage. PDF, JPEG or PNG up to 10 MB; checksum is the hex SHA-256 of the file; expires_at is the expiry printed on the document.
+type DocumentRequest struct {
+	Type        string     `json:"type" validate:"required,oneof=national_id passport drivers_license proof_of_address payslip bank_statement other" enums:"national_id,passport,drivers_license,proof_of_address,payslip,bank_statement,other"`
+	FileName    string     `json:"file_name" validate:"required,max=255"`
+	ContentType string     `json:"content_type" validate:"required" enums:"application/pdf,image/jpeg,image/png"`
+	SizeBytes   int64      `json:"size_bytes" validate:"required,min=1"`
+	StorageKey  string     `json:"storage_key" validate:"required,max=500"`
+	Checksum    string     `json:"checksum" validate:"required,len=64"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+	UploadedBy  string     `json:"uploaded_by" validate:"max=100"`
+}
+
+// DocumentResponse represents a KYC document in responses
+// @Description KYC document metadata
+type DocumentResponse struct {
+	ID          uuid.UUID  `json:"id"`
+	Type        string     `json:"type"`
+	FileName    string     `json:"file_name"`
+	ContentType string     `json:"content_type"`
+	SizeBytes   int64      `json:"size_bytes"`
+	StorageKey  string     `json:"storage_key"`
+	Checksum    string     `json:"checksum"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+	UploadedBy  string     `json:"uploaded_by"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// KYCReviewRequest represents the request body for changing a borrower's KYC status
+// @Description Moves a borrower's KYC: pending to verified or rejected, verified to pending or expired, rejected or expired back to pending. Rejections need a reason. The caller is recorded as the reviewer.
+type KYCReviewRequest struct {
+	Status string `json:"status" validate:"required,oneof=pending verified rejected expired" enums:"pending,verified,rejected,expired"`
+	Reason string `json:"reason" validate:"max=255"`
+}
+
+// toProfile converts the request into a borrower profile; the date of birth has already been validated
+func (r *BorrowerProfileRequest) toProfile() services.BorrowerProfile {
+	profile := services.BorrowerProfile{
+		Name:             r.Name,
+		ContactInfo:      r.ContactInfo,
+		NationalIDType:   r.NationalIDType,
+		NationalID:       r.NationalID,
+		Phone:            r.Phone,
+		Email:            r.Email,
+		EmploymentStatus: r.EmploymentStatus,
+		Employer:         r.Employer,
+		MonthlyIncome:    r.MonthlyIncome,
+	}
+	if dateOfBirth, err := time.Parse(time.DateOnly, r.DateOfBirth); err == nil {
+		profile.DateOfBirth = &dateOfBirth
+	}
+	return profile
+}
+
+// toModel converts the request into a borrower address
+func (r *AddressRequest) toModel() models.BorrowerAddress {
+	return models.BorrowerAddress{
+		Type:       r.Type,
+		Line1:      r.Line1,
+		Line2:      r.Line2,
+		City:       r.City,
+		Region:     r.Region,
+		PostalCode: r.PostalCode,
+		Country:    r.Country,
+	}
+}
+
+// UpdateBorrowerProfile godoc
+// @Summary Update a borrower's profile
+// @Description Replaces a borrower's profile. Changing the name, national ID or date of birth of a verified borrower sends their KYC back to pending.
+// @Tags Borrowers
+// @Accept json
+// @Produce json
+// @Param id path string true "Borrower ID" format(uuid)
+// @Param request body handlers.BorrowerProfileRequest true "Borrower profile"
+// @Success 200 {object} handlers.BorrowerResponse
+// @Failure 400 {object} map[string]string "Error response"
+// @Failure 401 {object} map[string]string "Error response"
+// @Failure 403 {object} map[string]string "Error response"
+// @Failure 404 {object} map[string]string "Error response"
+// @Failure 409 {object} map[string]string "Error response"
+// @Failure 500 {object} map[string]string "Error response"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/borrowers/{id}/profile [put]
+func (h *BorrowerHandler) UpdateBorrowerProfile(c echo.Context) error {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid borrower ID format"})
+	}
+
+	var req BorrowerProfileRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request format"})
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	borrower, err := h.borrowerService.WithContext(c.Request().Context()).UpdateProfile(id, req.toProfile())
+	if err != nil {
+		return kycError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, newBorrowerResponse(borrower))
+}
+
+// AddBorrowerAddress godoc
+// @Summary Add a borrower address
+// @Description Adds an address to a borrower
+// @Tags Borrowers
+// @Accept json
+// @Produce json
+// @Param id path string true "Borrower ID" format(uuid)
+// @Param request body handlers.AddressRequest true "Address"
+// @Success 201 {object} handlers.AddressResponse
+// @Failure 400 {object} map[string]string "Error response"
+// @Failure 401 {object} map[string]string "Error response"
+// @Failure 403 {object} map[string]string "Error response"
+// @Failure 404 {object} map[string]string "Error response"
+// @Failure 409 {object} map[string]string "Error response"
+// @Failure 500 {object} map[string]string "Error response"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/borrowers/{id}/addresses [post]
+func (h *BorrowerHandler) AddBorrowerAddress(c echo.Context) error {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid borrower ID format"})
+	}
+
+	var req AddressRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request format"})
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	address, err := h.borrowerService.WithContext(c.Request().Context()).AddAddress(id, req.toModel())
+	if err != nil {
+		return kycError(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, newAddressResponse(address))
+}
+
+// RemoveBorrowerAddress godoc
+// @Summary Remove a borrower address
+// @Description Removes one of a borrower's addresses
+// @Tags Borrowers
+// @Accept json
+// @Produce json
+// @Param id path string true "Borrower ID" format(uuid)
+// @Param addressId path string true "Address ID" format(uuid)
+// @Success 204 "No content"
+// @Failure 400 {object} map[string]string "Error response"
+// @Failure 401 {object} map[string]string "Error response"
+// @Failure 403 {object} map[string]string "Error response"
+// @Failure 404 {object} map[string]string "Error response"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/borrowers/{id}/addresses/{addressId} [delete]
+func (h *BorrowerHandler) RemoveBorrowerAddress(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid borrower ID format"})
+	}
+	addressID, err := uuid.Parse(c.Param("addressId"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid address ID format"})
+	}
+
+	if err := h.borrowerService.WithContext(c.Request().Context()).RemoveAddress(id, addressID); err != nil {
+		return kycError(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// AddBorrowerDocument godoc
+// @Summary Attach a KYC document
+// @Description Records the metadata of a document supporting a borrower's KYC. The file itself is kept in document storage under storage_key.
+// @Tags Borrowers
+// @Accept json
+// @Produce json
+// @Param id path string true "Borrower ID" format(uuid)
+// @Param request body handlers.DocumentRequest true "Document metadata"
+// @Success 201 {object} handlers.DocumentResponse
+// @Failure 400 {object} map[string]string "Error response"
+// @Failure 401 {object} map[string]string "Error response"
+// @Failure 403 {object} map[string]string "Error response"
+// @Failure 404 {object} map[string]string "Error response"
+// @Failure 409 {object} map[string]string "Error response"
+// @Failure 500 {object} map[string]string "Error response"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/borrowers/{id}/documents [post]
+func (h *BorrowerHandler) AddBorrowerDocument(c echo.Context) error {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid borrower ID format"})
+	}
+
+	var req DocumentRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request format"})
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	document, err := h.borrowerService.WithContext(c.Request().Context()).AddDocument(id, models.BorrowerDocument{
+		Type:        req.Type,
+		FileName:    req.FileName,
+		ContentType: req.ContentType,
+		SizeBytes:   req.SizeBytes,
+		StorageKey:  req.StorageKey,
+		Checksum:    req.Checksum,
+		ExpiresAt:   req.ExpiresAt,
+		UploadedBy:  req.UploadedBy,
+	})
+	if err != nil {
+		return kycError(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, newDocumentResponse(document))
+}
+
+// ListBorrowerDocuments godoc
+// @Summary List KYC documents
+// @Description Retrieves the documents supporting a borrower's KYC, oldest first
+// @Tags Borrowers
+// @Accept json
+// @Produce json
+// @Param id path string true "Borrower ID" format(uuid)
+// @Success 200 {array} handlers.DocumentResponse
+// @Failure 400 {object} map[string]string "Error response"
+// @Failure 401 {object} map[string]string "Error response"
+// @Failure 403 {object} map[string]string "Error response"
+// @Failure 404 {object} map[string]string "Error response"
+// @Failure 500 {object} map[string]string "Error response"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/borrowers/{id}/documents [get]
+func (h *BorrowerHandler) ListBorrowerDocuments(c echo.Context) error {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid borrower ID format"})
+	}
+
+	documents, err := h.borrowerService.WithContext(c.Request().Context()).GetDocuments(id)
+	if err != nil {
+		return kycError(c, err)
+	}
+
+	response := make([]DocumentResponse, 0, len(documents))
+	for i := range documents {
+		response = append(response, newDocumentResponse(&documents[i]))
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// ReviewBorrowerKYC godoc
+// @Summary Review a borrower's KYC
+// @Description Moves a borrower's KYC to a new status. Verifying needs a national ID, date of birth, address and an unexpired identity document; it lasts KYC_VALIDITY_DAYS or until that document expires.
+// @Tags Borrowers
+// @Accept json
+// @Produce json
+// @Param id path string true "Borrower ID" format(uuid)
+// @Param request body handlers.KYCReviewRequest true "KYC review"
+// @Success 200 {object} handlers.BorrowerResponse
+// @Failure 400 {object} map[string]string "Error response"
+// @Failure 401 {object} map[string]string "Error response"
+// @Failure 403 {object} map[string]string "Error response"
+// @Failure 404 {object} map[string]string "Error response"
+// @Failure 409 {object} map[string]string "Error response"
+// @Failure 500 {object} map[string]string "Error response"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/borrowers/{id}/kyc [post]
+func (h *BorrowerHandler) ReviewBorrowerKYC(c echo.Context) error {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid borrower ID format"})
+	}
+
+	var req KYCReviewRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request format"})
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	borrower, err := h.borrowerService.WithContext(c.Request().Context()).ReviewKYC(id, services.KYCReview{
+		Status: req.Status,
+		Reason: req.Reason,
+	})
+	if err != nil {
+		return kycError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, newBorrowerResponse(borrower))
+}
+
+// kycError maps borrower profile and KYC service errors to responses
+func kycError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, auth.ErrForbidden):
+		return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
+	case errors.Is(err, services.ErrBorrowerNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "Borrower not found"})
+	case errors.Is(err, services.ErrAddressNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	case errors.Is(err, services.ErrInvalidProfile):
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	case errors.Is(err, services.ErrInvalidKYCTransition), errors.Is(err, services.ErrKYCIncomplete),
+		errors.Is(err, services.ErrAlreadyAnonymized):
+		return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+	default:
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+}
+
+// newAddressResponse converts a borrower address into its response representation
+func newAddressResponse(address *models.BorrowerAddress) AddressResponse {
+	return AddressResponse{
+		ID:         address.ID,
+		Type:       address.Type,
+		Line1:      address.Line1,
+		Line2:      address.Line2,
+		City:       address.City,
+		Region:     address.Region,
+		PostalCode: address.PostalCode,
+		Country:    address.Country,
+	}
+}
+
+// newDocumentResponse converts a KYC document into its response representation
+func newDocumentResponse(document *models.BorrowerDocument) DocumentResponse {
+	return DocumentResponse{
+		ID:          document.ID,
+		Type:        document.Type,
+		FileName:    document.FileName,
+		ContentType: document.ContentType,
+		SizeBytes:   document.SizeBytes,
+		StorageKey:  document.StorageKey,
+		Checksum:    document.Checksum,
+		ExpiresAt:   document.ExpiresAt,
+		UploadedBy:  document.UploadedBy,
+		CreatedAt:   document.CreatedAt,
+	}
+}
@@ -1,9 +1,14 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
 	"time"
 
+	"loan-billing-system/internal/auth"
+	"loan-billing-system/internal/models"
+	"loan-billing-system/internal/repositories"
 	"loan-billing-system/internal/services"
 
 	"github.com/google/uuid"
@@ -22,25 +27,160 @@ func NewLoanHandler(loanService *services.LoanService) *LoanHandler {
 	}
 }
 
+// LoanTermsRequest holds the terms of a new loan, validated the same way
+// whether the loan is quoted or created
+type LoanTermsRequest struct {
+	ProductCode  string  `json:"product_code" validate:"max=50"` // Defaults to the standard product
+	Amount       int64   `json:"amount" validate:"required,min=1"`
+	InterestRate float64 `json:"interest_rate" validate:"required,min=0"`
+	TermWeeks    uint    `json:"term_weeks" validate:"required,min=1"`
+}
+
 // CreateLoanRequest represents the request body for creating a loan
 // @Description Request body for creating a new loan
 type CreateLoanRequest struct {
-	BorrowerID   uuid.UUID `json:"borrower_id" validate:"required"`
-	Amount       int64     `json:"amount" validate:"required,min=1"`
-	InterestRate float64   `json:"interest_rate" validate:"required,min=0"`
-	TermWeeks    uint      `json:"term_weeks" validate:"required,min=1"`
+	BorrowerID uuid.UUID `json:"borrower_id" validate:"required"`
+	LoanTermsRequest
+	Parties  []LoanPartyRequest     `json:"parties" validate:"dive"` // Co-borrowers and guarantors
+	GroupID  *uuid.UUID             `json:"group_id"`                // Loan group the borrower borrows through; they must be a member
+	Override *CreditOverrideRequest `json:"override"`                // Lets a delinquent borrower through the credit checks
+}
+
+// CreditOverrideRequest represents an authorized override of the delinquency check
+// @Description Lets a delinquent borrower take a new loan. The caller approves it and needs the loans:approve permission. Credit limits cannot be overridden.
+type CreditOverrideRequest struct {
+	Reason string `json:"reason" validate:"required,max=255"`
+}
+
+// RejectionReasonResponse represents one credit rule a loan application failed
+// @Description Failed credit rule
+type RejectionReasonResponse struct {
+	Code    string `json:"code" enums:"credit_limit_exceeded,max_concurrent_loans,borrower_delinquent,score_declined,score_referred"`
+	Message string `json:"message"`
+}
+
+// CreditRejectionResponse represents a loan application rejected by the credit checks
+// @Description Loan application rejected by the credit checks, with every rule it failed and how it scored
+type CreditRejectionResponse struct {
+	Error      string                    `json:"error"`
+	Reasons    []RejectionReasonResponse `json:"reasons"`
+	Assessment *CreditAssessmentResponse `json:"assessment,omitempty"`
 }
 
 // LoanResponse represents the loan data in responses
 // @Description Response containing loan data
 type LoanResponse struct {
-	ID           uuid.UUID `json:"id"`
-	BorrowerID   uuid.UUID `json:"borrower_id"`
-	Amount       int64     `json:"amount"`
-	InterestRate float64   `json:"interest_rate"`
-	TermWeeks    uint      `json:"term_weeks"`
-	StartDate    time.Time `json:"start_date"`
-	Status       string    `json:"status"`
+	ID               uuid.UUID  `json:"id"`
+	BorrowerID       uuid.UUID  `json:"borrower_id"`
+	ProductCode      string     `json:"product_code"`
+	Amount           int64      `json:"amount"`
+	DisbursedAmount  int64      `json:"disbursed_amount"`
+	InterestRate     float64    `json:"interest_rate"`
+	APR              float64    `json:"apr"`
+	EffectiveRate    float64    `json:"effective_rate"`
+	TermWeeks        uint       `json:"term_weeks"`
+	StartDate        time.Time  `json:"start_date"`
+	Status           string     `json:"status"`
+	CurrentBalance   int64      `json:"current_balance"`
+	IsRestructured   bool       `json:"is_restructured"`
+	ScheduleVersion  uint       `json:"schedule_version"`
+	HolidayUntil     *time.Time `json:"holiday_until"`
+	WrittenOffAt     *time.Time `json:"written_off_at,omitempty"`
+	WrittenOffAmount int64      `json:"written_off_amount"`
+	RecoveredAmount  int64      `json:"recovered_amount"`
+	RefinancedFromID *uuid.UUID `json:"refinanced_from_id,omitempty"`
+	RefinancedByID   *uuid.UUID `json:"refinanced_by_id,omitempty"`
+	GroupID          *uuid.UUID `json:"group_id,omitempty"`
+	CreditOverrideBy string     `json:"credit_override_by,omitempty"`
+}
+
+// LoanSummaryResponse represents a loan in listings, with its balance and next installment
+// @Description Loan with balance, delinquency and next-due summary
+type LoanSummaryResponse struct {
+	LoanResponse
+	IsDelinquent  bool       `json:"is_delinquent"`
+	IsOnHoliday   bool       `json:"is_on_holiday"`
+	DaysPastDue   uint       `json:"days_past_due"`
+	DPDBucket     string     `json:"dpd_bucket"`
+	NextDueDate   *time.Time `json:"next_due_date"`
+	NextDueAmount *int64     `json:"next_due_amount"`
+	NextDueWeek   *uint      `json:"next_due_week"`
+}
+
+// LoanListResponse represents a page of loans
+// @Description Paginated list of loans
+type LoanListResponse struct {
+	Data       []LoanSummaryResponse `json:"data"`
+	NextCursor string                `json:"next_cursor"`
+	HasMore    bool                  `json:"has_more"`
+	Limit      int                   `json:"limit"`
+}
+
+// InstallmentResponse represents one installment of a repayment schedule
+// @Description Scheduled installment with the amount paid against it
+type InstallmentResponse struct {
+	ID              uuid.UUID  `json:"id"`
+	Version         uint       `json:"version"`
+	WeekNumber      uint       `json:"week_number"`
+	DueDate         time.Time  `json:"due_date"`
+	OriginalDueDate *time.Time `json:"original_due_date"`
+	Amount          int64      `json:"amount"`
+	FeeAmount       int64      `json:"fee_amount"` // Recurring fees included in amount
+	AmountPaid      int64      `json:"amount_paid"`
+	PaidDate        *time.Time `json:"paid_date"`
+	Status          string     `json:"status" enums:"paid,partial,overdue,upcoming,deferred,cancelled"`
+	IsOverdue       bool       `json:"is_overdue"`
+}
+
+// ScheduleResponse represents a loan's repayment schedule
+// @Description Repayment schedule of a loan
+type ScheduleResponse struct {
+	LoanID       uuid.UUID             `json:"loan_id"`
+	TotalDue     int64                 `json:"total_due"`
+	TotalPaid    int64                 `json:"total_paid"`
+	Outstanding  int64                 `json:"outstanding"`
+	Installments []InstallmentResponse `json:"installments"`
+}
+
+// QuoteLoanRequest represents the request body for previewing a loan
+// @Description Request body for quoting a loan without creating it
+type QuoteLoanRequest struct {
+	LoanTermsRequest
+}
+
+// QuoteInstallmentResponse represents one installment of a quoted schedule
+// @Description Installment of a quoted schedule
+type QuoteInstallmentResponse struct {
+	WeekNumber uint      `json:"week_number"`
+	DueDate    time.Time `json:"due_date"`
+	Amount     int64     `json:"amount"`
+}
+
+// QuoteFeeResponse represents one fee a quoted loan would be charged
+// @Description Fee a quoted loan would be charged
+type QuoteFeeResponse struct {
+	Name   string `json:"name"`
+	Type   string `json:"type" enums:"deducted,capitalized,installment"`
+	Amount int64  `json:"amount"`
+}
+
+// QuoteResponse represents the cost and schedule of a quoted loan
+// @Description Preview of a loan's cost and schedule under a product. principal includes capitalized fees, disbursed_amount is net of deducted fees, and total_due includes installment fees.
+type QuoteResponse struct {
+	ProductCode         string                     `json:"product_code"`
+	Amount              int64                      `json:"amount"`
+	Principal           int64                      `json:"principal"`
+	DisbursedAmount     int64                      `json:"disbursed_amount"`
+	InterestRate        float64                    `json:"interest_rate"`
+	TermWeeks           uint                       `json:"term_weeks"`
+	TotalDue            int64                      `json:"total_due"`
+	TotalInterest       int64                      `json:"total_interest"`
+	TotalFees           int64                      `json:"total_fees"`
+	WeeklyPayment       int64                      `json:"weekly_payment"`
+	APR                 float64                    `json:"apr"`
+	EffectiveAnnualRate float64                    `json:"effective_annual_rate"`
+	Fees                []QuoteFeeResponse         `json:"fees"`
+	Installments        []QuoteInstallmentResponse `json:"installments"`
 }
 
 // PaymentRequest represents the request body for making a payment
@@ -51,14 +191,19 @@ type PaymentRequest struct {
 
 // CreateLoan godoc
 // @Summary Create a new loan
-// @Description Creates a new loan for a borrower
+// @Description Creates a new loan for a borrower under a product, charging the product's fees. Co-borrowers and guarantors can be added with parties. The borrower must be within their credit limit and maximum number of open loans, and must not be delinquent unless an authorized override is given. The application is then scored: declined applications are rejected, and referred ones need an authorized override.
 // @Tags Loans
 // @Accept json
 // @Produce json
 // @Param request body handlers.CreateLoanRequest true "Loan details"
 // @Success 201 {object} handlers.LoanResponse
 // @Failure 400 {object} map[string]string "Error response"
+// @Failure 401 {object} map[string]string "Error response"
+// @Failure 403 {object} map[string]string "Error response"
+// @Failure 422 {object} handlers.CreditRejectionResponse "Rejected by the credit checks"
 // @Failure 500 {object} map[string]string "Error response"
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /api/loans [post]
 func (h *LoanHandler) CreateLoan(c echo.Context) error {
 	var req CreateLoanRequest
@@ -70,20 +215,108 @@ func (h *LoanHandler) CreateLoan(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
 
-	loan, err := h.loanService.CreateLoan(req.BorrowerID, req.Amount, req.InterestRate, req.TermWeeks)
-	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	parties := make([]models.LoanParty, 0, len(req.Parties))
+	for _, party := range req.Parties {
+		parties = append(parties, models.LoanParty{BorrowerID: party.BorrowerID, Role: party.Role})
+	}
+	var override *services.CreditOverride
+	if req.Override != nil {
+		override = &services.CreditOverride{Reason: req.Override.Reason}
 	}
 
-	return c.JSON(http.StatusCreated, LoanResponse{
-		ID:           loan.ID,
-		BorrowerID:   loan.BorrowerID,
-		Amount:       loan.Amount,
-		InterestRate: loan.InterestRate,
-		TermWeeks:    loan.TermWeeks,
-		StartDate:    loan.StartDate,
-		Status:       loan.Status,
+	loan, err := h.loanService.WithContext(c.Request().Context()).ApplyForLoan(services.LoanApplication{
+		BorrowerID:   req.BorrowerID,
+		ProductCode:  req.ProductCode,
+		Amount:       req.Amount,
+		InterestRate: req.InterestRate,
+		TermWeeks:    req.TermWeeks,
+		Parties:      parties,
+		GroupID:      req.GroupID,
+		Override:     override,
 	})
+	if err != nil {
+		var rejection *services.CreditRejection
+		switch {
+		case errors.As(err, &rejection):
+			return c.JSON(http.StatusUnprocessableEntity, newCreditRejectionResponse(rejection))
+		case errors.Is(err, auth.ErrForbidden):
+			return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
+		case errors.Is(err, services.ErrProductNotFound), errors.Is(err, services.ErrFeesExceedAmount),
+			errors.Is(err, services.ErrBorrowerNotFound), errors.Is(err, services.ErrInvalidPartyRole),
+			errors.Is(err, services.ErrPartyExists), errors.Is(err, services.ErrGroupNotFound),
+			errors.Is(err, services.ErrNotGroupMember), errors.Is(err, services.ErrAlreadyAnonymized):
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		}
+	}
+
+	return c.JSON(http.StatusCreated, newLoanResponse(loan))
+}
+
+// QuoteLoan godoc
+// @Summary Quote a loan
+// @Description Prices a loan under a product as creating it would, charging the product's fees, and returns the disbursed amount, total due, installments, due dates, total interest and fees, APR and effective annual rate without creating anything
+// @Tags Loans
+// @Accept json
+// @Produce json
+// @Param request body handlers.QuoteLoanRequest true "Loan terms"
+// @Success 200 {object} handlers.QuoteResponse
+// @Failure 400 {object} map[string]string "Error response"
+// @Failure 401 {object} map[string]string "Error response"
+// @Failure 403 {object} map[string]string "Error response"
+// @Failure 500 {object} map[string]string "Error response"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/loans/quote [post]
+func (h *LoanHandler) QuoteLoan(c echo.Context) error {
+	var req QuoteLoanRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request format"})
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	quote, err := h.loanService.WithContext(c.Request().Context()).QuoteLoan(req.ProductCode, req.Amount, req.InterestRate, req.TermWeeks)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrProductNotFound), errors.Is(err, services.ErrFeesExceedAmount):
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		}
+	}
+
+	response := QuoteResponse{
+		ProductCode:         quote.ProductCode,
+		Amount:              quote.Amount,
+		Principal:           quote.Principal,
+		DisbursedAmount:     quote.DisbursedAmount,
+		InterestRate:        quote.InterestRate,
+		TermWeeks:           quote.TermWeeks,
+		TotalDue:            quote.TotalDue,
+		TotalInterest:       quote.TotalInterest,
+		TotalFees:           quote.TotalFees,
+		WeeklyPayment:       quote.WeeklyPayment,
+		APR:                 quote.APR,
+		EffectiveAnnualRate: quote.EffectiveAnnualRate,
+		Fees:                make([]QuoteFeeResponse, 0, len(quote.Fees)),
+		Installments:        make([]QuoteInstallmentResponse, 0, len(quote.Schedule)),
+	}
+	for _, fee := range quote.Fees {
+		response.Fees = append(response.Fees, QuoteFeeResponse{Name: fee.Name, Type: fee.Type, Amount: fee.Amount})
+	}
+	for _, installment := range quote.Schedule {
+		response.Installments = append(response.Installments, QuoteInstallmentResponse{
+			WeekNumber: installment.WeekNumber,
+			DueDate:    installment.DueDate,
+			Amount:     installment.Amount,
+		})
+	}
+
+	return c.JSON(http.StatusOK, response)
 }
 
 // GetLoan godoc
@@ -95,7 +328,11 @@ func (h *LoanHandler) CreateLoan(c echo.Context) error {
 // @Param id path string true "Loan ID" format(uuid)
 // @Success 200 {object} handlers.LoanResponse
 // @Failure 400 {object} map[string]string "Error response"
+// @Failure 401 {object} map[string]string "Error response"
+// @Failure 403 {object} map[string]string "Error response"
 // @Failure 404 {object} map[string]string "Error response"
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /api/loans/{id} [get]
 func (h *LoanHandler) GetLoan(c echo.Context) error {
 	idStr := c.Param("id")
@@ -104,20 +341,16 @@ func (h *LoanHandler) GetLoan(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid loan ID format"})
 	}
 
-	loan, err := h.loanService.GetLoan(id)
+	if !h.ownsLoan(c, id) {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "Loan not found"})
+	}
+
+	loan, err := h.loanService.WithContext(c.Request().Context()).GetLoan(id)
 	if err != nil {
 		return c.JSON(http.StatusNotFound, map[string]string{"error": "Loan not found"})
 	}
 
-	return c.JSON(http.StatusOK, LoanResponse{
-		ID:           loan.ID,
-		BorrowerID:   loan.BorrowerID,
-		Amount:       loan.Amount,
-		InterestRate: loan.InterestRate,
-		TermWeeks:    loan.TermWeeks,
-		StartDate:    loan.StartDate,
-		Status:       loan.Status,
-	})
+	return c.JSON(http.StatusOK, newLoanResponse(loan))
 }
 
 // GetOutstanding godoc
@@ -129,7 +362,11 @@ func (h *LoanHandler) GetLoan(c echo.Context) error {
 // @Param id path string true "Loan ID" format(uuid)
 // @Success 200 {object} map[string]int64 "Outstanding amount"
 // @Failure 400 {object} map[string]string "Error response"
+// @Failure 401 {object} map[string]string "Error response"
+// @Failure 403 {object} map[string]string "Error response"
 // @Failure 404 {object} map[string]string "Error response"
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /api/loans/{id}/outstanding [get]
 func (h *LoanHandler) GetOutstanding(c echo.Context) error {
 	idStr := c.Param("id")
@@ -138,7 +375,11 @@ func (h *LoanHandler) GetOutstanding(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid loan ID format"})
 	}
 
-	outstanding, err := h.loanService.GetOutstanding(id)
+	if !h.ownsLoan(c, id) {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "Loan not found"})
+	}
+
+	outstanding, err := h.loanService.WithContext(c.Request().Context()).GetOutstanding(id)
 	if err != nil {
 		return c.JSON(http.StatusNotFound, map[string]string{"error": "Loan not found"})
 	}
@@ -155,7 +396,11 @@ func (h *LoanHandler) GetOutstanding(c echo.Context) error {
 // @Param id path string true "Loan ID" format(uuid)
 // @Success 200 {object} map[string]bool "Delinquency status"
 // @Failure 400 {object} map[string]string "Error response"
+// @Failure 401 {object} map[string]string "Error response"
+// @Failure 403 {object} map[string]string "Error response"
 // @Failure 404 {object} map[string]string "Error response"
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /api/loans/{id}/delinquent [get]
 func (h *LoanHandler) IsDelinquent(c echo.Context) error {
 	idStr := c.Param("id")
@@ -164,7 +409,11 @@ func (h *LoanHandler) IsDelinquent(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid loan ID format"})
 	}
 
-	isDelinquent, err := h.loanService.IsDelinquent(id)
+	if !h.ownsLoan(c, id) {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "Loan not found"})
+	}
+
+	isDelinquent, err := h.loanService.WithContext(c.Request().Context()).IsDelinquent(id)
 	if err != nil {
 		return c.JSON(http.StatusNotFound, map[string]string{"error": "Loan not found"})
 	}
@@ -182,7 +431,11 @@ func (h *LoanHandler) IsDelinquent(c echo.Context) error {
 // @Param request body handlers.PaymentRequest true "Payment details"
 // @Success 200 {object} map[string]string "Success response"
 // @Failure 400 {object} map[string]string "Error response"
+// @Failure 401 {object} map[string]string "Error response"
+// @Failure 403 {object} map[string]string "Error response"
 // @Failure 404 {object} map[string]string "Error response"
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /api/loans/{id}/payment [post]
 func (h *LoanHandler) MakePayment(c echo.Context) error {
 	idStr := c.Param("id")
@@ -191,6 +444,10 @@ func (h *LoanHandler) MakePayment(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid loan ID format"})
 	}
 
+	if !h.ownsLoan(c, id) {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "Loan not found"})
+	}
+
 	var req PaymentRequest
 	if err := c.Bind(&req); err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request format"})
@@ -200,10 +457,344 @@ func (h *LoanHandler) MakePayment(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
 
-	err = h.loanService.MakePayment(id, req.Amount)
+	err = h.loanService.WithContext(c.Request().Context()).MakePayment(id, req.Amount)
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
 
 	return c.JSON(http.StatusOK, map[string]string{"status": "Payment successful"})
 }
+
+// ListLoans godoc
+// @Summary List loans
+// @Description Retrieves a page of loans with their balance and next installment
+// @Tags Loans
+// @Accept json
+// @Produce json
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param cursor query string false "Cursor returned as next_cursor by the previous page"
+// @Param sort query string false "Sort field" Enums(created_at, current_balance, days_past_due)
+// @Param order query string false "Sort order" Enums(asc, desc)
+// @Param status query string false "Loan status"
+// @Param borrower_id query string false "Borrower ID" format(uuid)
+// @Param product_code query string false "Loan product code"
+// @Param is_delinquent query bool false "Filter by delinquency status"
+// @Param dpd_bucket query string false "Days-past-due bucket" Enums(current, 1-30, 31-60, 61-90, 90+)
+// @Param created_from query string false "Only loans created at or after this time (RFC3339 or YYYY-MM-DD)"
+// @Param created_to query string false "Only loans created before this time (RFC3339, or YYYY-MM-DD inclusive)"
+// @Param min_balance query int false "Minimum current balance"
+// @Param max_balance query int false "Maximum current balance"
+// @Param on_holiday query bool false "Filter by whether the loan is on a payment holiday"
+// @Success 200 {object} handlers.LoanListResponse
+// @Failure 400 {object} map[string]string "Error response"
+// @Failure 401 {object} map[string]string "Error response"
+// @Failure 403 {object} map[string]string "Error response"
+// @Failure 500 {object} map[string]string "Error response"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/loans [get]
+func (h *LoanHandler) ListLoans(c echo.Context) error {
+	filter, page, err := parseLoanListParams(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	if borrowerIDStr := c.QueryParam("borrower_id"); borrowerIDStr != "" {
+		borrowerID, err := uuid.Parse(borrowerIDStr)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid borrower ID format"})
+		}
+		filter.BorrowerID = &borrowerID
+	}
+
+	result, err := h.loanService.WithContext(c.Request().Context()).ListLoans(filter, page)
+	if err != nil {
+		if isPaginationError(err) {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, newLoanListResponse(result))
+}
+
+// ListBorrowerLoans godoc
+// @Summary List a borrower's loans
+// @Description Retrieves a page of loans belonging to a borrower with their balance and next installment
+// @Tags Borrowers
+// @Accept json
+// @Produce json
+// @Param id path string true "Borrower ID" format(uuid)
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param cursor query string false "Cursor returned as next_cursor by the previous page"
+// @Param sort query string false "Sort field" Enums(created_at, current_balance, days_past_due)
+// @Param order query string false "Sort order" Enums(asc, desc)
+// @Param status query string false "Loan status"
+// @Param product_code query string false "Loan product code"
+// @Param is_delinquent query bool false "Filter by delinquency status"
+// @Param dpd_bucket query string false "Days-past-due bucket" Enums(current, 1-30, 31-60, 61-90, 90+)
+// @Param on_holiday query bool false "Filter by whether the loan is on a payment holiday"
+// @Success 200 {object} handlers.LoanListResponse
+// @Failure 400 {object} map[string]string "Error response"
+// @Failure 401 {object} map[string]string "Error response"
+// @Failure 403 {object} map[string]string "Error response"
+// @Failure 404 {object} map[string]string "Error response"
+// @Failure 500 {object} map[string]string "Error response"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/borrowers/{id}/loans [get]
+func (h *LoanHandler) ListBorrowerLoans(c echo.Context) error {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid borrower ID format"})
+	}
+
+	filter, page, err := parseLoanListParams(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	if !ownsBorrower(c, id) {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "Borrower not found"})
+	}
+
+	result, err := h.loanService.WithContext(c.Request().Context()).ListBorrowerLoans(id, filter, page)
+	if err != nil {
+		if errors.Is(err, services.ErrBorrowerNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Borrower not found"})
+		}
+		if isPaginationError(err) {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, newLoanListResponse(result))
+}
+
+// parseLoanListParams reads the paging and filter query parameters of loan listings
+func parseLoanListParams(c echo.Context) (repositories.LoanFilter, repositories.PageRequest, error) {
+	filter := repositories.LoanFilter{
+		Status:      c.QueryParam("status"),
+		ProductCode: c.QueryParam("product_code"),
+		DPDBucket:   c.QueryParam("dpd_bucket"),
+	}
+
+	page, err := parsePageRequest(c)
+	if err != nil {
+		return filter, page, err
+	}
+
+	if filter.IsDelinquent, err = parseBoolParam(c, "is_delinquent"); err != nil {
+		return filter, page, err
+	}
+	if filter.CreatedFrom, err = parseTimeParam(c, "created_from", false); err != nil {
+		return filter, page, err
+	}
+	if filter.CreatedTo, err = parseTimeParam(c, "created_to", true); err != nil {
+		return filter, page, err
+	}
+	if filter.MinBalance, err = parseInt64Param(c, "min_balance"); err != nil {
+		return filter, page, err
+	}
+	if filter.MaxBalance, err = parseInt64Param(c, "max_balance"); err != nil {
+		return filter, page, err
+	}
+	if filter.OnHoliday, err = parseBoolParam(c, "on_holiday"); err != nil {
+		return filter, page, err
+	}
+
+	return filter, page, nil
+}
+
+// newLoanResponse converts a loan into its response representation
+func newLoanResponse(loan *models.Loan) LoanResponse {
+	return LoanResponse{
+		ID:               loan.ID,
+		BorrowerID:       loan.BorrowerID,
+		ProductCode:      loan.ProductCode,
+		Amount:           loan.Amount,
+		DisbursedAmount:  loan.DisbursedAmount,
+		InterestRate:     loan.InterestRate,
+		APR:              loan.APR,
+		EffectiveRate:    loan.EffectiveRate,
+		TermWeeks:        loan.TermWeeks,
+		StartDate:        loan.StartDate,
+		Status:           loan.Status,
+		CurrentBalance:   loan.CurrentBalance,
+		IsRestructured:   loan.IsRestructured,
+		ScheduleVersion:  loan.ScheduleVersion,
+		HolidayUntil:     loan.HolidayUntil,
+		WrittenOffAt:     loan.WrittenOffAt,
+		WrittenOffAmount: loan.WrittenOffAmount,
+		RecoveredAmount:  loan.RecoveredAmount,
+		RefinancedFromID: loan.RefinancedFromID,
+		RefinancedByID:   loan.RefinancedByID,
+		GroupID:          loan.GroupID,
+		CreditOverrideBy: loan.CreditOverrideBy,
+	}
+}
+
+// newLoanListResponse converts a page of loan summaries into the response envelope
+func newLoanListResponse(page repositories.Page[services.LoanSummary]) LoanListResponse {
+	now := time.Now()
+	response := LoanListResponse{
+		Data:       make([]LoanSummaryResponse, 0, len(page.Items)),
+		NextCursor: page.NextCursor,
+		HasMore:    page.HasMore(),
+		Limit:      page.Limit,
+	}
+	for _, summary := range page.Items {
+		item := LoanSummaryResponse{
+			LoanResponse: newLoanResponse(&summary.Loan),
+			IsDelinquent: summary.Loan.IsDelinquent,
+			IsOnHoliday:  summary.Loan.OnHoliday(now),
+			DaysPastDue:  summary.Loan.DaysPastDue,
+			DPDBucket:    summary.Loan.DPDBucket(),
+		}
+		if summary.NextDue != nil {
+			item.NextDueDate = &summary.NextDue.DueDate
+			item.NextDueAmount = &summary.NextDue.Amount
+			item.NextDueWeek = &summary.NextDue.WeekNumber
+		}
+		response.Data = append(response.Data, item)
+	}
+	return response
+}
+
+// GetSchedule godoc
+// @Summary Get repayment schedule
+// @Description Retrieves a loan's repayment schedule with the paid, partial or overdue status of each installment
+// @Tags Loans
+// @Accept json
+// @Produce json
+// @Param id path string true "Loan ID" format(uuid)
+// @Param version query int false "Schedule version to show, including cancelled installments (defaults to the current schedule)"
+// @Success 200 {object} handlers.ScheduleResponse
+// @Failure 400 {object} map[string]string "Error response"
+// @Failure 401 {object} map[string]string "Error response"
+// @Failure 403 {object} map[string]string "Error response"
+// @Failure 404 {object} map[string]string "Error response"
+// @Failure 500 {object} map[string]string "Error response"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/loans/{id}/schedule [get]
+func (h *LoanHandler) GetSchedule(c echo.Context) error {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid loan ID format"})
+	}
+
+	var version uint
+	if versionStr := c.QueryParam("version"); versionStr != "" {
+		v, err := strconv.ParseUint(versionStr, 10, 32)
+		if err != nil || v == 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "version must be a positive integer"})
+		}
+		version = uint(v)
+	}
+
+	if !h.ownsLoan(c, id) {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "Loan not found"})
+	}
+
+	installments, err := h.loanService.WithContext(c.Request().Context()).GetSchedule(id, version)
+	if err != nil {
+		if errors.Is(err, services.ErrLoanNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Loan not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	response := ScheduleResponse{
+		LoanID:       id,
+		Installments: make([]InstallmentResponse, 0, len(installments)),
+	}
+	for _, installment := range installments {
+		if !installment.Schedule.Cancelled {
+			response.TotalDue += installment.Schedule.Amount
+			response.TotalPaid += installment.AmountPaid
+		}
+		response.Installments = append(response.Installments, newInstallmentResponse(&installment))
+	}
+	response.Outstanding = response.TotalDue - response.TotalPaid
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// newInstallmentResponse converts an installment into its response representation
+func newInstallmentResponse(installment *services.Installment) InstallmentResponse {
+	return InstallmentResponse{
+		ID:              installment.Schedule.ID,
+		Version:         installment.Schedule.Version,
+		WeekNumber:      installment.Schedule.WeekNumber,
+		DueDate:         installment.Schedule.DueDate,
+		OriginalDueDate: installment.Schedule.OriginalDueDate,
+		Amount:          installment.Schedule.Amount,
+		FeeAmount:       installment.Schedule.FeeAmount,
+		AmountPaid:      installment.AmountPaid,
+		PaidDate:        installment.PaidDate,
+		Status:          installment.Status,
+		IsOverdue:       installment.IsOverdue,
+	}
+}
+
+// ListPayments godoc
+// @Summary List loan payments
+// @Description Retrieves the payment history of a loan, oldest first
+// @Tags Payments
+// @Accept json
+// @Produce json
+// @Param id path string true "Loan ID" format(uuid)
+// @Success 200 {array} handlers.PaymentResponse
+// @Failure 400 {object} map[string]string "Error response"
+// @Failure 401 {object} map[string]string "Error response"
+// @Failure 403 {object} map[string]string "Error response"
+// @Failure 404 {object} map[string]string "Error response"
+// @Failure 500 {object} map[string]string "Error response"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/loans/{id}/payments [get]
+func (h *LoanHandler) ListPayments(c echo.Context) error {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid loan ID format"})
+	}
+
+	if !h.ownsLoan(c, id) {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "Loan not found"})
+	}
+
+	payments, err := h.loanService.WithContext(c.Request().Context()).GetPayments(id)
+	if err != nil {
+		if errors.Is(err, services.ErrLoanNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Loan not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	response := make([]PaymentResponse, 0, len(payments))
+	for i := range payments {
+		response = append(response, newPaymentResponse(&payments[i]))
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// newCreditRejectionResponse converts a credit rejection into its response
+func newCreditRejectionResponse(rejection *services.CreditRejection) CreditRejectionResponse {
+	response := CreditRejectionResponse{
+		Error:   services.ErrCreditRejected.Error(),
+		Reasons: make([]RejectionReasonResponse, 0, len(rejection.Reasons)),
+	}
+	for _, reason := range rejection.Reasons {
+		response.Reasons = append(response.Reasons, RejectionReasonResponse{Code: reason.Code, Message: reason.Message})
+	}
+	if rejection.Assessment != nil {
+		response.Assessment = newCreditAssessmentResponse(rejection.Assessment)
+	}
+	return response
+}
@@ -0,0 +1,208 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"loan-billing-system/internal/auth"
+	"loan-billing-system/internal/models"
+	"loan-billing-system/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// MergeBorrowerRequest represents the request body for merging a duplicate borrower
+// @Description Request body for merging a duplicate borrower into the borrower in the path. The caller is recorded as who merged them.
+type MergeBorrowerRequest struct {
+	SourceID uuid.UUID `json:"source_id" validate:"required"`
+	Reason   string    `json:"reason" validate:"max=255"`
+}
+
+// DuplicateCandidateResponse represents an existing borrower who may be the same person
+// @Description Existing borrower who may be the same person, how alike they are from 0 to 1 and which details matched
+type DuplicateCandidateResponse struct {
+	Borrower BorrowerResponse `json:"borrower"`
+	Score    float64          `json:"score"`
+	Reasons  []string         `json:"reasons" enums:"national_id,phone,name"`
+}
+
+// DuplicateBorrowerResponse represents a borrower rejected as a possible duplicate
+// @Description Borrower not created because it may duplicate existing borrowers. When blocking is false, resend with confirm_not_duplicate to create it anyway.
+type DuplicateBorrowerResponse struct {
+	Error      string                       `json:"error"`
+	Blocking   bool                         `json:"blocking"`
+	Candidates []DuplicateCandidateResponse `json:"candidates"`
+}
+
+// BorrowerMergeResponse represents a borrower merge in responses
+// @Description Record of a duplicate borrower merged into another, with what was moved and the duplicate as it was
+type BorrowerMergeResponse struct {
+	ID             uuid.UUID        `json:"id"`
+	SourceID       uuid.UUID        `json:"source_id"`
+	TargetID       uuid.UUID        `json:"target_id"`
+	MergedBy       string           `json:"merged_by"`
+	Reason         string           `json:"reason"`
+	LoansMoved     int64            `json:"loans_moved"`
+	PartiesMoved   int64            `json:"parties_moved"`
+	MembersMoved   int64            `json:"members_moved"`
+	SourceSnapshot BorrowerResponse `json:"source_snapshot"`
+	CreatedAt      time.Time        `json:"created_at"`
+}
+
+// ListBorrowerDuplicates godoc
+// @Summary List possible duplicates of a borrower
+// @Description Retrieves the other borrowers sharing the borrower's national ID or phone, or with a similar name and no conflicting date of birth, best match first
+// @Tags Borrowers
+// @Accept json
+// @Produce json
+// @Param id path string true "Borrower ID" format(uuid)
+// @Success 200 {array} handlers.DuplicateCandidateResponse
+// @Failure 400 {object} map[string]string "Error response"
+// @Failure 401 {object} map[string]string "Error response"
+// @Failure 403 {object} map[string]string "Error response"
+// @Failure 404 {object} map[string]string "Error response"
+// @Failure 500 {object} map[string]string "Error response"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/borrowers/{id}/duplicates [get]
+func (h *BorrowerHandler) ListBorrowerDuplicates(c echo.Context) error {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid borrower ID format"})
+	}
+
+	candidates, err := h.borrowerService.WithContext(c.Request().Context()).FindDuplicates(id)
+	if err != nil {
+		return mergeError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, newDuplicateCandidateResponses(candidates))
+}
+
+// MergeBorrower godoc
+// @Summary Merge a duplicate borrower
+// @Description Merges a duplicate borrower into the borrower in the path. The duplicate's loans, loan parties, group memberships, addresses, documents and credit assessments move over, the kept borrower becomes delinquent if either was, and the duplicate is deleted.
+// @Tags Borrowers
+// @Accept json
+// @Produce json
+// @Param id path string true "Borrower ID to keep" format(uuid)
+// @Param request body handlers.MergeBorrowerRequest true "Merge details"
+// @Success 201 {object} handlers.BorrowerMergeResponse
+// @Failure 400 {object} map[string]string "Error response"
+// @Failure 401 {object} map[string]string "Error response"
+// @Failure 403 {object} map[string]string "Error response"
+// @Failure 404 {object} map[string]string "Error response"
+// @Failure 409 {object} map[string]string "Error response"
+// @Failure 500 {object} map[string]string "Error response"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/borrowers/{id}/merge [post]
+func (h *BorrowerHandler) MergeBorrower(c echo.Context) error {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid borrower ID format"})
+	}
+
+	var req MergeBorrowerRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request format"})
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	merge, err := h.borrowerService.WithContext(c.Request().Context()).MergeBorrowers(id, req.SourceID, services.BorrowerMergeRequest{
+		Reason: req.Reason,
+	})
+	if err != nil {
+		return mergeError(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, newBorrowerMergeResponse(merge))
+}
+
+// ListBorrowerMerges godoc
+// @Summary List borrowers merged into a borrower
+// @Description Retrieves the merge records of the duplicates merged into a borrower, newest first
+// @Tags Borrowers
+// @Accept json
+// @Produce json
+// @Param id path string true "Borrower ID" format(uuid)
+// @Success 200 {array} handlers.BorrowerMergeResponse
+// @Failure 400 {object} map[string]string "Error response"
+// @Failure 401 {object} map[string]string "Error response"
+// @Failure 403 {object} map[string]string "Error response"
+// @Failure 404 {object} map[string]string "Error response"
+// @Failure 500 {object} map[string]string "Error response"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/borrowers/{id}/merges [get]
+func (h *BorrowerHandler) ListBorrowerMerges(c echo.Context) error {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid borrower ID format"})
+	}
+
+	merges, err := h.borrowerService.WithContext(c.Request().Context()).GetMerges(id)
+	if err != nil {
+		return mergeError(c, err)
+	}
+
+	response := make([]BorrowerMergeResponse, 0, len(merges))
+	for i := range merges {
+		response = append(response, newBorrowerMergeResponse(&merges[i]))
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// mergeError maps a duplicate or merge service error to its HTTP response
+func mergeError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, auth.ErrForbidden):
+		return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
+	case errors.Is(err, services.ErrBorrowerNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "Borrower not found"})
+	case errors.Is(err, services.ErrInvalidMerge):
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	case errors.Is(err, services.ErrAlreadyAnonymized):
+		return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+	default:
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+}
+
+// newDuplicateCandidateResponses converts duplicate candidates into their response representation
+func newDuplicateCandidateResponses(candidates []services.DuplicateCandidate) []DuplicateCandidateResponse {
+	response := make([]DuplicateCandidateResponse, 0, len(candidates))
+	for i := range candidates {
+		response = append(response, DuplicateCandidateResponse{
+			Borrower: newBorrowerResponse(&candidates[i].Borrower),
+			Score:    candidates[i].Score,
+			Reasons:  candidates[i].Reasons,
+		})
+	}
+	return response
+}
+
+// newBorrowerMergeResponse converts a borrower merge into its response representation
+func newBorrowerMergeResponse(merge *models.BorrowerMerge) BorrowerMergeResponse {
+	return BorrowerMergeResponse{
+		ID:             merge.ID,
+		SourceID:       merge.SourceID,
+		TargetID:       merge.TargetID,
+		MergedBy:       merge.MergedBy,
+		Reason:         merge.Reason,
+		LoansMoved:     merge.LoansMoved,
+		PartiesMoved:   merge.PartiesMoved,
+		MembersMoved:   merge.MembersMoved,
+		SourceSnapshot: newBorrowerResponse(&merge.SourceSnapshot),
+		CreatedAt:      merge.CreatedAt,
+	}
+}
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"loan-billing-system/internal/repositories"
+
+	"github.com/labstack/echo/v4"
+)
+
+// dateLayout is accepted by date filters in addition to RFC3339 timestamps
+const dateLayout = "2006-01-02"
+
+// parsePageRequest reads the limit, cursor, sort and order query parameters
+func parsePageRequest(c echo.Context) (repositories.PageRequest, error) {
+	page := repositories.PageRequest{
+		Cursor: c.QueryParam("cursor"),
+		SortBy: c.QueryParam("sort"),
+	}
+
+	if limitStr := c.QueryParam("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit < 1 {
+			return page, errors.New("limit must be a positive integer")
+		}
+		page.Limit = limit
+	}
+
+	switch strings.ToLower(c.QueryParam("order")) {
+	case "", "asc":
+	case "desc":
+		page.Desc = true
+	default:
+		return page, errors.New("order must be asc or desc")
+	}
+
+	return page, nil
+}
+
+// parseTimeParam reads an optional RFC3339 timestamp or YYYY-MM-DD date.
+// When endOfDay is set a bare date is moved to the start of the next day so
+// that it can be used as an exclusive upper bound covering the whole day.
+func parseTimeParam(c echo.Context, name string, endOfDay bool) (*time.Time, error) {
+	value := c.QueryParam(name)
+	if value == "" {
+		return nil, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+
+	t, err := time.Parse(dateLayout, value)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be an RFC3339 timestamp or a YYYY-MM-DD date", name)
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1)
+	}
+	return &t, nil
+}
+
+// parseBoolParam reads an optional boolean query parameter
+func parseBoolParam(c echo.Context, name string) (*bool, error) {
+	value := c.QueryParam(name)
+	if value == "" {
+		return nil, nil
+	}
+
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be true or false", name)
+	}
+	return &b, nil
+}
+
+// parseInt64Param reads an optional integer query parameter
+func parseInt64Param(c echo.Context, name string) (*int64, error) {
+	value := c.QueryParam(name)
+	if value == "" {
+		return nil, nil
+	}
+
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be an integer", name)
+	}
+	return &n, nil
+}
+
+// isPaginationError reports whether a list error was caused by bad paging or filter input
+func isPaginationError(err error) bool {
+	return errors.Is(err, repositories.ErrInvalidCursor) ||
+		errors.Is(err, repositories.ErrInvalidSort) ||
+		errors.Is(err, repositories.ErrInvalidFilter)
+}
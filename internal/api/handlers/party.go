@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"loan-billing-system/internal/models"
+	"loan-billing-system/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// LoanPartyRequest represents a co-borrower or guarantor to add to a loan
+// @Description Borrower to add to a loan as a co-borrower or guarantor
+type LoanPartyRequest struct {
+	BorrowerID uuid.UUID `json:"borrower_id" validate:"required"`
+	Role       string    `json:"role" validate:"required,oneof=co_borrower guarantor" enums:"co_borrower,guarantor"`
+}
+
+// LoanPartyResponse represents a party to a loan
+// @Description Borrower on a loan and the role they hold
+type LoanPartyResponse struct {
+	LoanID     uuid.UUID `json:"loan_id"`
+	BorrowerID uuid.UUID `json:"borrower_id"`
+	Role       string    `json:"role" enums:"primary,co_borrower,guarantor"`
+	AddedAt    time.Time `json:"added_at"`
+}
+
+// AddLoanParty godoc
+// @Summary Add a loan party
+// @Description Adds a borrower to a loan as a co-borrower or guarantor. Co-borrowers share the loan's delinquency; guarantors do too when DELINQUENCY_INCLUDES_GUARANTORS is set.
+// @Tags Loans
+// @Accept json
+// @Produce json
+// @Param id path string true "Loan ID" format(uuid)
+// @Param request body handlers.LoanPartyRequest true "Party details"
+// @Success 201 {object} handlers.LoanPartyResponse
+// @Failure 400 {object} map[string]string "Error response"
+// @Failure 401 {object} map[string]string "Error response"
+// @Failure 403 {object} map[string]string "Error response"
+// @Failure 404 {object} map[string]string "Error response"
+// @Failure 409 {object} map[string]string "Error response"
+// @Failure 500 {object} map[string]string "Error response"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/loans/{id}/parties [post]
+func (h *LoanHandler) AddLoanParty(c echo.Context) error {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid loan ID format"})
+	}
+
+	var req LoanPartyRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request format"})
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	party, err := h.loanService.WithContext(c.Request().Context()).AddLoanParty(id, req.BorrowerID, req.Role)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrLoanNotFound):
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Loan not found"})
+		case errors.Is(err, services.ErrBorrowerNotFound):
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Borrower not found"})
+		case errors.Is(err, services.ErrInvalidPartyRole):
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		case errors.Is(err, services.ErrPartyExists), errors.Is(err, services.ErrAlreadyAnonymized):
+			return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		}
+	}
+
+	return c.JSON(http.StatusCreated, newLoanPartyResponse(party))
+}
+
+// ListLoanParties godoc
+// @Summary List loan parties
+// @Description Retrieves every borrower on a loan with their role, the primary borrower first
+// @Tags Loans
+// @Accept json
+// @Produce json
+// @Param id path string true "Loan ID" format(uuid)
+// @Success 200 {array} handlers.LoanPartyResponse
+// @Failure 400 {object} map[string]string "Error response"
+// @Failure 401 {object} map[string]string "Error response"
+// @Failure 403 {object} map[string]string "Error response"
+// @Failure 404 {object} map[string]string "Error response"
+// @Failure 500 {object} map[string]string "Error response"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/loans/{id}/parties [get]
+func (h *LoanHandler) ListLoanParties(c echo.Context) error {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid loan ID format"})
+	}
+
+	parties, err := h.loanService.WithContext(c.Request().Context()).GetLoanParties(id)
+	if err != nil {
+		if errors.Is(err, services.ErrLoanNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Loan not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	response := make([]LoanPartyResponse, 0, len(parties))
+	for i := range parties {
+		response = append(response, newLoanPartyResponse(&parties[i]))
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// RemoveLoanParty godoc
+// @Summary Remove a loan party
+// @Description Removes a co-borrower or guarantor from a loan. The primary borrower cannot be removed.
+// @Tags Loans
+// @Accept json
+// @Produce json
+// @Param id path string true "Loan ID" format(uuid)
+// @Param borrowerId path string true "Borrower ID" format(uuid)
+// @Success 204
+// @Failure 400 {object} map[string]string "Error response"
+// @Failure 401 {object} map[string]string "Error response"
+// @Failure 403 {object} map[string]string "Error response"
+// @Failure 404 {object} map[string]string "Error response"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/loans/{id}/parties/{borrowerId} [delete]
+func (h *LoanHandler) RemoveLoanParty(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid loan ID format"})
+	}
+	borrowerID, err := uuid.Parse(c.Param("borrowerId"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid borrower ID format"})
+	}
+
+	if err := h.loanService.WithContext(c.Request().Context()).RemoveLoanParty(id, borrowerID); err != nil {
+		if errors.Is(err, services.ErrLoanNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Loan not found"})
+		}
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// newLoanPartyResponse converts a loan party into its response representation
+func newLoanPartyResponse(party *models.LoanParty) LoanPartyResponse {
+	return LoanPartyResponse{
+		LoanID:     party.LoanID,
+		BorrowerID: party.BorrowerID,
+		Role:       party.Role,
+		AddedAt:    party.CreatedAt,
+	}
+}
@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"loan-billing-system/internal/auth"
+	"loan-billing-system/internal/models"
+	"loan-billing-system/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// PaymentHandler handles HTTP requests related to payments
+type PaymentHandler struct {
+	loanService *services.LoanService
+}
+
+// NewPaymentHandler creates a new payment handler
+func NewPaymentHandler(loanService *services.LoanService) *PaymentHandler {
+	return &PaymentHandler{
+		loanService: loanService,
+	}
+}
+
+// PaymentResponse represents a recorded payment in responses
+// @Description Response containing payment data
+type PaymentResponse struct {
+	ID             uuid.UUID  `json:"id"`
+	LoanID         uuid.UUID  `json:"loan_id"`
+	ScheduleID     uuid.UUID  `json:"schedule_id"`
+	Amount         int64      `json:"amount"`
+	PaymentDate    time.Time  `json:"payment_date"`
+	Source         string     `json:"source" enums:"borrower,refinance,group"`
+	Principal      int64      `json:"principal"`
+	Interest       int64      `json:"interest"`
+	Fees           int64      `json:"fees"`
+	GroupPaymentID *uuid.UUID `json:"group_payment_id,omitempty"`
+	ReversedAt     *time.Time `json:"reversed_at,omitempty"`
+	ReversedBy     string     `json:"reversed_by,omitempty"`
+	ReversalReason string     `json:"reversal_reason,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// ReversePaymentRequest represents the request body for reversing a payment
+// @Description Request body for reversing a repayment taken in error. The caller is recorded as who reversed it.
+type ReversePaymentRequest struct {
+	Reason string `json:"reason" validate:"required,max=255"`
+}
+
+// GetPayment godoc
+// @Summary Get payment details
+// @Description Retrieves a single payment
+// @Tags Payments
+// @Accept json
+// @Produce json
+// @Param id path string true "Payment ID" format(uuid)
+// @Success 200 {object} handlers.PaymentResponse
+// @Failure 400 {object} map[string]string "Error response"
+// @Failure 401 {object} map[string]string "Error response"
+// @Failure 403 {object} map[string]string "Error response"
+// @Failure 404 {object} map[string]string "Error response"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/payments/{id} [get]
+func (h *PaymentHandler) GetPayment(c echo.Context) error {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid payment ID format"})
+	}
+
+	payment, err := h.loanService.WithContext(c.Request().Context()).GetPayment(id)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "Payment not found"})
+	}
+
+	return c.JSON(http.StatusOK, newPaymentResponse(payment))
+}
+
+// ReversePayment godoc
+// @Summary Reverse a payment
+// @Description Reverses a borrower's repayment taken in error. The payment is kept but no longer counts: its installment is unpaid again, the balance goes back up, a loan it closed is reopened and delinquency is re-evaluated. Group and refinance payments cannot be reversed.
+// @Tags Payments
+// @Accept json
+// @Produce json
+// @Param id path string true "Payment ID" format(uuid)
+// @Param request body handlers.ReversePaymentRequest true "Reversal details"
+// @Success 200 {object} handlers.PaymentResponse
+// @Failure 400 {object} map[string]string "Error response"
+// @Failure 401 {object} map[string]string "Error response"
+// @Failure 403 {object} map[string]string "Error response"
+// @Failure 404 {object} map[string]string "Error response"
+// @Failure 409 {object} map[string]string "Error response"
+// @Failure 500 {object} map[string]string "Error response"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/payments/{id}/reverse [post]
+func (h *PaymentHandler) ReversePayment(c echo.Context) error {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid payment ID format"})
+	}
+
+	var req ReversePaymentRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request format"})
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	payment, err := h.loanService.WithContext(c.Request().Context()).ReversePayment(id, req.Reason)
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrForbidden):
+			return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
+		case errors.Is(err, services.ErrPaymentNotFound):
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Payment not found"})
+		case errors.Is(err, services.ErrPaymentAlreadyReversed), errors.Is(err, services.ErrPaymentNotReversible):
+			return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		}
+	}
+
+	return c.JSON(http.StatusOK, newPaymentResponse(payment))
+}
+
+// newPaymentResponse converts a payment into its response representation
+func newPaymentResponse(payment *models.Payment) PaymentResponse {
+	return PaymentResponse{
+		ID:             payment.ID,
+		LoanID:         payment.LoanID,
+		ScheduleID:     payment.ScheduleID,
+		Amount:         payment.Amount,
+		PaymentDate:    payment.PaymentDate,
+		Source:         payment.Source,
+		Principal:      payment.Principal,
+		Interest:       payment.Interest,
+		Fees:           payment.Fees,
+		GroupPaymentID: payment.GroupPaymentID,
+		ReversedAt:     payment.ReversedAt,
+		ReversedBy:     payment.ReversedBy,
+		ReversalReason: payment.ReversalReason,
+		CreatedAt:      payment.CreatedAt,
+	}
+}
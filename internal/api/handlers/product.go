@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"loan-billing-system/internal/models"
+	"loan-billing-system/internal/services"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ProductHandler handles HTTP requests related to loan products
+type ProductHandler struct {
+	loanService *services.LoanService
+}
+
+// NewProductHandler creates a new product handler
+func NewProductHandler(loanService *services.LoanService) *ProductHandler {
+	return &ProductHandler{
+		loanService: loanService,
+	}
+}
+
+// CreateProductRequest represents the request body for adding a loan product
+// @Description New loan product of the caller's tenant. 0 disables an escalation rule.
+type CreateProductRequest struct {
+	Code              string `json:"code" validate:"required,max=50" example:"micro"`
+	Name              string `json:"name" validate:"required,max=100" example:"Micro weekly loan"`
+	DefaultAfterDPD   uint   `json:"default_after_dpd" example:"90"`
+	ChargeOffAfterDPD uint   `json:"charge_off_after_dpd" example:"180"`
+}
+
+// UpdateProductRequest represents the request body for changing a product's escalation thresholds
+// @Description Request body for changing a product's escalation thresholds. 0 disables a rule.
+type UpdateProductRequest struct {
+	DefaultAfterDPD   *uint `json:"default_after_dpd"`
+	ChargeOffAfterDPD *uint `json:"charge_off_after_dpd"`
+}
+
+// ProductResponse represents a loan product in responses
+// @Description Loan product with its escalation thresholds
+type ProductResponse struct {
+	Code              string `json:"code"`
+	Name              string `json:"name"`
+	DefaultAfterDPD   uint   `json:"default_after_dpd"`
+	ChargeOffAfterDPD uint   `json:"charge_off_after_dpd"`
+}
+
+// ListProducts godoc
+// @Summary List loan products
+// @Description Retrieves every loan product with its default and charge-off thresholds
+// @Tags Products
+// @Accept json
+// @Produce json
+// @Success 200 {array} handlers.ProductResponse
+// @Failure 401 {object} map[string]string "Error response"
+// @Failure 403 {object} map[string]string "Error response"
+// @Failure 500 {object} map[string]string "Error response"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/products [get]
+func (h *ProductHandler) ListProducts(c echo.Context) error {
+	products, err := h.loanService.WithContext(c.Request().Context()).ListProducts()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	response := make([]ProductResponse, 0, len(products))
+	for i := range products {
+		response = append(response, newProductResponse(&products[i]))
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// CreateProduct godoc
+// @Summary Create a loan product
+// @Description Adds a loan product to the caller's tenant. Codes are unique within a tenant.
+// @Tags Products
+// @Accept json
+// @Produce json
+// @Param request body handlers.CreateProductRequest true "Product details"
+// @Success 201 {object} handlers.ProductResponse
+// @Failure 400 {object} map[string]string "Error response"
+// @Failure 401 {object} map[string]string "Error response"
+// @Failure 403 {object} map[string]string "Error response"
+// @Failure 409 {object} map[string]string "Error response"
+// @Failure 500 {object} map[string]string "Error response"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/products [post]
+func (h *ProductHandler) CreateProduct(c echo.Context) error {
+	var req CreateProductRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request format"})
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	product, err := h.loanService.WithContext(c.Request().Context()).CreateProduct(models.LoanProduct{
+		Code:              req.Code,
+		Name:              req.Name,
+		DefaultAfterDPD:   req.DefaultAfterDPD,
+		ChargeOffAfterDPD: req.ChargeOffAfterDPD,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrProductExists):
+			return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+		case errors.Is(err, services.ErrInvalidProduct), errors.Is(err, services.ErrInvalidThresholds):
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		}
+	}
+
+	return c.JSON(http.StatusCreated, newProductResponse(product))
+}
+
+// UpdateProduct godoc
+// @Summary Update product thresholds
+// @Description Changes the days past due after which the product's loans are marked defaulted or proposed for charge-off
+// @Tags Products
+// @Accept json
+// @Produce json
+// @Param code path string true "Product code"
+// @Param request body handlers.UpdateProductRequest true "New thresholds"
+// @Success 200 {object} handlers.ProductResponse
+// @Failure 400 {object} map[string]string "Error response"
+// @Failure 401 {object} map[string]string "Error response"
+// @Failure 403 {object} map[string]string "Error response"
+// @Failure 404 {object} map[string]string "Error response"
+// @Failure 500 {object} map[string]string "Error response"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/products/{code} [patch]
+func (h *ProductHandler) UpdateProduct(c echo.Context) error {
+	var req UpdateProductRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request format"})
+	}
+
+	product, err := h.loanService.WithContext(c.Request().Context()).UpdateProductThresholds(c.Param("code"), services.ProductThresholds{
+		DefaultAfterDPD:   req.DefaultAfterDPD,
+		ChargeOffAfterDPD: req.ChargeOffAfterDPD,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrProductNotFound):
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Product not found"})
+		case errors.Is(err, services.ErrInvalidThresholds):
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		}
+	}
+
+	return c.JSON(http.StatusOK, newProductResponse(product))
+}
+
+// newProductResponse converts a loan product into its response representation
+func newProductResponse(product *models.LoanProduct) ProductResponse {
+	return ProductResponse{
+		Code:              product.Code,
+		Name:              product.Name,
+		DefaultAfterDPD:   product.DefaultAfterDPD,
+		ChargeOffAfterDPD: product.ChargeOffAfterDPD,
+	}
+}
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"loan-billing-system/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// RefinanceLoanRequest represents the request body for refinancing a loan
+// @Description Request body for refinancing a loan into a new, larger loan
+type RefinanceLoanRequest struct {
+	Amount       int64    `json:"amount" validate:"required,gt=0"`
+	InterestRate *float64 `json:"interest_rate" validate:"omitempty,min=0"`
+	TermWeeks    uint     `json:"term_weeks" validate:"required,min=1"`
+}
+
+// RefinanceResponse represents the outcome of a refinancing
+// @Description Settled loan, new loan and the amounts involved
+type RefinanceResponse struct {
+	OldLoan      LoanResponse `json:"old_loan"`
+	NewLoan      LoanResponse `json:"new_loan"`
+	PayoffAmount int64        `json:"payoff_amount"`
+	NetDisbursed int64        `json:"net_disbursed"`
+}
+
+// RefinanceLoan godoc
+// @Summary Refinance a loan
+// @Description Creates a new loan whose disbursement first settles the old loan's payoff amount (arrears plus principal and installment fees not yet due). The old loan is closed as refinanced and only the difference is disbursed. The new loan goes through the same credit checks and scoring as an application, without counting the old loan's balance.
+// @Tags Loans
+// @Accept json
+// @Produce json
+// @Param id path string true "Loan ID" format(uuid)
+// @Param request body handlers.RefinanceLoanRequest true "New loan terms"
+// @Success 201 {object} handlers.RefinanceResponse
+// @Failure 400 {object} map[string]string "Error response"
+// @Failure 401 {object} map[string]string "Error response"
+// @Failure 403 {object} map[string]string "Error response"
+// @Failure 404 {object} map[string]string "Error response"
+// @Failure 409 {object} map[string]string "Error response"
+// @Failure 422 {object} handlers.CreditRejectionResponse "Rejected by the credit checks"
+// @Failure 500 {object} map[string]string "Error response"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/loans/{id}/refinance [post]
+func (h *LoanHandler) RefinanceLoan(c echo.Context) error {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid loan ID format"})
+	}
+
+	var req RefinanceLoanRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request format"})
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	result, err := h.loanService.WithContext(c.Request().Context()).RefinanceLoan(id, services.RefinanceTerms{
+		Amount:       req.Amount,
+		InterestRate: req.InterestRate,
+		TermWeeks:    req.TermWeeks,
+	})
+	if err != nil {
+		var rejection *services.CreditRejection
+		switch {
+		case errors.As(err, &rejection):
+			return c.JSON(http.StatusUnprocessableEntity, newCreditRejectionResponse(rejection))
+		case errors.Is(err, services.ErrLoanNotFound):
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Loan not found"})
+		case errors.Is(err, services.ErrLoanNotRefinanceable):
+			return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+		case errors.Is(err, services.ErrRefinanceTooSmall):
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		}
+	}
+
+	return c.JSON(http.StatusCreated, RefinanceResponse{
+		OldLoan:      newLoanResponse(result.OldLoan),
+		NewLoan:      newLoanResponse(result.NewLoan),
+		PayoffAmount: result.PayoffAmount,
+		NetDisbursed: result.NewLoan.DisbursedAmount,
+	})
+}
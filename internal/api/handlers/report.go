@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"loan-billing-system/internal/services"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ReportHandler handles HTTP requests for financial reports
+type ReportHandler struct {
+	loanService *services.LoanService
+}
+
+// NewReportHandler creates a new report handler
+func NewReportHandler(loanService *services.LoanService) *ReportHandler {
+	return &ReportHandler{
+		loanService: loanService,
+	}
+}
+
+// IncomeReportResponse represents the income earned over a period
+// @Description Principal collected and income earned over a period, with fee income kept apart from interest
+type IncomeReportResponse struct {
+	From                 time.Time `json:"from"`
+	To                   time.Time `json:"to"`
+	PrincipalCollected   int64     `json:"principal_collected"`
+	InterestIncome       int64     `json:"interest_income"`
+	InstallmentFeeIncome int64     `json:"installment_fee_income"`
+	UpfrontFeeIncome     int64     `json:"upfront_fee_income"`
+	FeeIncome            int64     `json:"fee_income"`
+}
+
+// GetIncomeReport godoc
+// @Summary Income report
+// @Description Reports the principal collected and the interest and fee income earned in a period. Upfront fees count when the loan is created; interest and installment fees count when they are paid.
+// @Tags Reports
+// @Accept json
+// @Produce json
+// @Param from query string true "Period start (RFC3339 or YYYY-MM-DD)"
+// @Param to query string true "Period end (RFC3339, or YYYY-MM-DD for the whole day)"
+// @Success 200 {object} handlers.IncomeReportResponse
+// @Failure 400 {object} map[string]string "Error response"
+// @Failure 401 {object} map[string]string "Error response"
+// @Failure 403 {object} map[string]string "Error response"
+// @Failure 500 {object} map[string]string "Error response"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/reports/income [get]
+func (h *ReportHandler) GetIncomeReport(c echo.Context) error {
+	from, err := parseTimeParam(c, "from", false)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	to, err := parseTimeParam(c, "to", true)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	if from == nil || to == nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "from and to are required"})
+	}
+
+	report, err := h.loanService.WithContext(c.Request().Context()).GetIncomeReport(*from, *to)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidReportPeriod) {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, IncomeReportResponse{
+		From:                 report.From,
+		To:                   report.To,
+		PrincipalCollected:   report.PrincipalCollected,
+		InterestIncome:       report.InterestIncome,
+		InstallmentFeeIncome: report.InstallmentFeeIncome,
+		UpfrontFeeIncome:     report.UpfrontFeeIncome,
+		FeeIncome:            report.FeeIncome(),
+	})
+}
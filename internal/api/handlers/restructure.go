@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"loan-billing-system/internal/models"
+	"loan-billing-system/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// RestructureLoanRequest represents the request body for restructuring a loan
+// @Description Request body for restructuring a loan. Omitted terms keep the current rate and the number of installments left.
+type RestructureLoanRequest struct {
+	InterestRate *float64 `json:"interest_rate" validate:"omitempty,min=0"`
+	TermWeeks    *uint    `json:"term_weeks" validate:"omitempty,min=1"`
+	Reason       string   `json:"reason" validate:"required,max=255"`
+}
+
+// RestructureResponse represents one restructuring of a loan
+// @Description Record of a loan restructuring
+type RestructureResponse struct {
+	ID                   uuid.UUID `json:"id"`
+	LoanID               uuid.UUID `json:"loan_id"`
+	FromVersion          uint      `json:"from_version"`
+	ToVersion            uint      `json:"to_version"`
+	PreviousInterestRate float64   `json:"previous_interest_rate"`
+	NewInterestRate      float64   `json:"new_interest_rate"`
+	PreviousTermWeeks    uint      `json:"previous_term_weeks"`
+	NewTermWeeks         uint      `json:"new_term_weeks"`
+	PreviousPrincipal    int64     `json:"previous_principal"`
+	PreviousBalance      int64     `json:"previous_balance"`
+	ArrearsCapitalized   int64     `json:"arrears_capitalized"`
+	FeesCarried          int64     `json:"fees_carried"`
+	NewPrincipal         int64     `json:"new_principal"`
+	NewBalance           int64     `json:"new_balance"`
+	Reason               string    `json:"reason"`
+	CreatedAt            time.Time `json:"created_at"`
+}
+
+// RestructureLoan godoc
+// @Summary Restructure a loan
+// @Description Capitalizes arrears, optionally changes the rate and term, cancels the remaining unpaid installments and generates a new schedule version. Installment fees not yet due are spread over the new installments.
+// @Tags Loans
+// @Accept json
+// @Produce json
+// @Param id path string true "Loan ID" format(uuid)
+// @Param request body handlers.RestructureLoanRequest true "New terms"
+// @Success 200 {object} handlers.RestructureResponse
+// @Failure 400 {object} map[string]string "Error response"
+// @Failure 401 {object} map[string]string "Error response"
+// @Failure 403 {object} map[string]string "Error response"
+// @Failure 404 {object} map[string]string "Error response"
+// @Failure 409 {object} map[string]string "Error response"
+// @Failure 500 {object} map[string]string "Error response"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/loans/{id}/restructure [post]
+func (h *LoanHandler) RestructureLoan(c echo.Context) error {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid loan ID format"})
+	}
+
+	var req RestructureLoanRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request format"})
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	restructure, err := h.loanService.WithContext(c.Request().Context()).RestructureLoan(id, services.RestructureTerms{
+		InterestRate: req.InterestRate,
+		TermWeeks:    req.TermWeeks,
+		Reason:       req.Reason,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrLoanNotFound):
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Loan not found"})
+		case errors.Is(err, services.ErrLoanNotRestructurable), errors.Is(err, services.ErrNothingToRestructure):
+			return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		}
+	}
+
+	return c.JSON(http.StatusOK, newRestructureResponse(restructure))
+}
+
+// ListRestructures godoc
+// @Summary List loan restructurings
+// @Description Retrieves the restructuring history of a loan, oldest first
+// @Tags Loans
+// @Accept json
+// @Produce json
+// @Param id path string true "Loan ID" format(uuid)
+// @Success 200 {array} handlers.RestructureResponse
+// @Failure 400 {object} map[string]string "Error response"
+// @Failure 401 {object} map[string]string "Error response"
+// @Failure 403 {object} map[string]string "Error response"
+// @Failure 404 {object} map[string]string "Error response"
+// @Failure 500 {object} map[string]string "Error response"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/loans/{id}/restructures [get]
+func (h *LoanHandler) ListRestructures(c echo.Context) error {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid loan ID format"})
+	}
+
+	restructures, err := h.loanService.WithContext(c.Request().Context()).GetRestructures(id)
+	if err != nil {
+		if errors.Is(err, services.ErrLoanNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Loan not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	response := make([]RestructureResponse, 0, len(restructures))
+	for i := range restructures {
+		response = append(response, newRestructureResponse(&restructures[i]))
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// newRestructureResponse converts a restructuring record into its response representation
+func newRestructureResponse(restructure *models.LoanRestructure) RestructureResponse {
+	return RestructureResponse{
+		ID:                   restructure.ID,
+		LoanID:               restructure.LoanID,
+		FromVersion:          restructure.FromVersion,
+		ToVersion:            restructure.ToVersion,
+		PreviousInterestRate: restructure.PreviousInterestRate,
+		NewInterestRate:      restructure.NewInterestRate,
+		PreviousTermWeeks:    restructure.PreviousTermWeeks,
+		NewTermWeeks:         restructure.NewTermWeeks,
+		PreviousPrincipal:    restructure.PreviousPrincipal,
+		PreviousBalance:      restructure.PreviousBalance,
+		ArrearsCapitalized:   restructure.ArrearsCapitalized,
+		FeesCarried:          restructure.FeesCarried,
+		NewPrincipal:         restructure.NewPrincipal,
+		NewBalance:           restructure.NewBalance,
+		Reason:               restructure.Reason,
+		CreatedAt:            restructure.CreatedAt,
+	}
+}
@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"loan-billing-system/internal/auth"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// ownsBorrower reports whether the caller may see a borrower's data.
+// Borrowers may only see their own; staff and partners are limited by their
+// permissions alone. Other borrowers are reported as not found, so a
+// borrower cannot tell whether they exist.
+func ownsBorrower(c echo.Context, borrowerID uuid.UUID) bool {
+	principal := auth.PrincipalFrom(c.Request().Context())
+	return principal == nil || principal.OwnsBorrower(borrowerID)
+}
+
+// ownsLoan reports whether the caller may see and repay a loan. Borrowers
+// may only reach the loans they are liable for: as the primary borrower, a
+// co-borrower or a guarantor.
+func (h *LoanHandler) ownsLoan(c echo.Context, loanID uuid.UUID) bool {
+	principal := auth.PrincipalFrom(c.Request().Context())
+	if principal == nil || principal.BorrowerID == nil {
+		return true
+	}
+	parties, err := h.loanService.WithContext(c.Request().Context()).GetLoanParties(loanID)
+	if err != nil {
+		return false
+	}
+	for _, party := range parties {
+		if principal.OwnsBorrower(party.BorrowerID) {
+			return true
+		}
+	}
+	return false
+}
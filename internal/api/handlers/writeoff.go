@@ -0,0 +1,251 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"loan-billing-system/internal/auth"
+	"loan-billing-system/internal/models"
+	"loan-billing-system/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// WriteOffRequest represents the request body for writing off a loan
+// @Description Request body for writing off a loan. The caller is recorded as who wrote it off.
+type WriteOffRequest struct {
+	Reason string `json:"reason" validate:"required,max=255"`
+}
+
+// RecoveryRequest represents the request body for recording a recovery
+// @Description Request body for recording money collected on a written-off loan
+type RecoveryRequest struct {
+	Amount    int64  `json:"amount" validate:"required,gt=0"`
+	Reference string `json:"reference" validate:"max=100"`
+	Notes     string `json:"notes" validate:"max=255"`
+}
+
+// RecoveryResponse represents a recovery on a written-off loan
+// @Description Recovery details
+type RecoveryResponse struct {
+	ID           uuid.UUID `json:"id"`
+	LoanID       uuid.UUID `json:"loan_id"`
+	Amount       int64     `json:"amount"`
+	RecoveryDate time.Time `json:"recovery_date"`
+	Reference    string    `json:"reference"`
+	Notes        string    `json:"notes"`
+}
+
+// WriteOffLoan godoc
+// @Summary Write off a loan
+// @Description Moves the loan's remaining balance to the written-off bucket, cancels its unpaid installments and removes it from delinquency checks
+// @Tags Loans
+// @Accept json
+// @Produce json
+// @Param id path string true "Loan ID" format(uuid)
+// @Param request body handlers.WriteOffRequest true "Write-off details"
+// @Success 200 {object} handlers.LoanResponse
+// @Failure 400 {object} map[string]string "Error response"
+// @Failure 401 {object} map[string]string "Error response"
+// @Failure 403 {object} map[string]string "Error response"
+// @Failure 404 {object} map[string]string "Error response"
+// @Failure 409 {object} map[string]string "Error response"
+// @Failure 500 {object} map[string]string "Error response"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/loans/{id}/write-off [post]
+func (h *LoanHandler) WriteOffLoan(c echo.Context) error {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid loan ID format"})
+	}
+
+	var req WriteOffRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request format"})
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	loan, err := h.loanService.WithContext(c.Request().Context()).WriteOffLoan(id, req.Reason)
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrForbidden):
+			return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
+		case errors.Is(err, services.ErrLoanNotFound):
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Loan not found"})
+		case errors.Is(err, services.ErrLoanNotWriteOffable):
+			return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		}
+	}
+
+	return c.JSON(http.StatusOK, newLoanResponse(loan))
+}
+
+// RecordRecovery godoc
+// @Summary Record a recovery
+// @Description Records money collected on a written-off loan. Recoveries are tracked separately from repayments.
+// @Tags Loans
+// @Accept json
+// @Produce json
+// @Param id path string true "Loan ID" format(uuid)
+// @Param request body handlers.RecoveryRequest true "Recovery details"
+// @Success 201 {object} handlers.RecoveryResponse
+// @Failure 400 {object} map[string]string "Error response"
+// @Failure 401 {object} map[string]string "Error response"
+// @Failure 403 {object} map[string]string "Error response"
+// @Failure 404 {object} map[string]string "Error response"
+// @Failure 409 {object} map[string]string "Error response"
+// @Failure 500 {object} map[string]string "Error response"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/loans/{id}/recoveries [post]
+func (h *LoanHandler) RecordRecovery(c echo.Context) error {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid loan ID format"})
+	}
+
+	var req RecoveryRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request format"})
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	recovery, err := h.loanService.WithContext(c.Request().Context()).RecordRecovery(id, services.RecoveryDetails{
+		Amount:    req.Amount,
+		Reference: req.Reference,
+		Notes:     req.Notes,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrLoanNotFound):
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Loan not found"})
+		case errors.Is(err, services.ErrLoanNotWrittenOff), errors.Is(err, services.ErrRecoveryExceedsWrittenOff):
+			return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		}
+	}
+
+	return c.JSON(http.StatusCreated, newRecoveryResponse(recovery))
+}
+
+// ListRecoveries godoc
+// @Summary List recoveries
+// @Description Retrieves the recoveries collected on a written-off loan, oldest first
+// @Tags Loans
+// @Accept json
+// @Produce json
+// @Param id path string true "Loan ID" format(uuid)
+// @Success 200 {array} handlers.RecoveryResponse
+// @Failure 400 {object} map[string]string "Error response"
+// @Failure 401 {object} map[string]string "Error response"
+// @Failure 403 {object} map[string]string "Error response"
+// @Failure 404 {object} map[string]string "Error response"
+// @Failure 500 {object} map[string]string "Error response"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/loans/{id}/recoveries [get]
+func (h *LoanHandler) ListRecoveries(c echo.Context) error {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid loan ID format"})
+	}
+
+	recoveries, err := h.loanService.WithContext(c.Request().Context()).GetRecoveries(id)
+	if err != nil {
+		if errors.Is(err, services.ErrLoanNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Loan not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	response := make([]RecoveryResponse, 0, len(recoveries))
+	for i := range recoveries {
+		response = append(response, newRecoveryResponse(&recoveries[i]))
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// newRecoveryResponse converts a recovery into its response representation
+func newRecoveryResponse(recovery *models.Recovery) RecoveryResponse {
+	return RecoveryResponse{
+		ID:           recovery.ID,
+		LoanID:       recovery.LoanID,
+		Amount:       recovery.Amount,
+		RecoveryDate: recovery.RecoveryDate,
+		Reference:    recovery.Reference,
+		Notes:        recovery.Notes,
+	}
+}
+
+// StatusChangeResponse represents one change of a loan's status
+// @Description Loan status change and the rule that caused it
+type StatusChangeResponse struct {
+	FromStatus  string    `json:"from_status"`
+	ToStatus    string    `json:"to_status"`
+	Rule        string    `json:"rule"`
+	DaysPastDue uint      `json:"days_past_due"`
+	ChangedBy   string    `json:"changed_by"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// ListStatusHistory godoc
+// @Summary List loan status changes
+// @Description Retrieves every status change of a loan with the rule that fired, oldest first
+// @Tags Loans
+// @Accept json
+// @Produce json
+// @Param id path string true "Loan ID" format(uuid)
+// @Success 200 {array} handlers.StatusChangeResponse
+// @Failure 400 {object} map[string]string "Error response"
+// @Failure 401 {object} map[string]string "Error response"
+// @Failure 403 {object} map[string]string "Error response"
+// @Failure 404 {object} map[string]string "Error response"
+// @Failure 500 {object} map[string]string "Error response"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/loans/{id}/status-history [get]
+func (h *LoanHandler) ListStatusHistory(c echo.Context) error {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid loan ID format"})
+	}
+
+	changes, err := h.loanService.WithContext(c.Request().Context()).GetStatusHistory(id)
+	if err != nil {
+		if errors.Is(err, services.ErrLoanNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Loan not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	response := make([]StatusChangeResponse, 0, len(changes))
+	for _, change := range changes {
+		response = append(response, StatusChangeResponse{
+			FromStatus:  change.FromStatus,
+			ToStatus:    change.ToStatus,
+			Rule:        change.Rule,
+			DaysPastDue: change.DaysPastDue,
+			ChangedBy:   change.ChangedBy,
+			CreatedAt:   change.CreatedAt,
+		})
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"loan-billing-system/internal/audit"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// AuditContext tags each request's context with the request ID recorded in
+// the audit log. The request ID is taken from X-Request-ID, or generated,
+// and echoed back in the response. The actor is set by Authenticate.
+func AuditContext() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			requestID := c.Request().Header.Get(echo.HeaderXRequestID)
+			if requestID == "" {
+				requestID = uuid.NewString()
+			}
+			c.Response().Header().Set(echo.HeaderXRequestID, requestID)
+
+			ctx := audit.WithRequestID(c.Request().Context(), requestID)
+			c.SetRequest(c.Request().WithContext(ctx))
+			return next(c)
+		}
+	}
+}
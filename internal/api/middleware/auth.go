@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"loan-billing-system/internal/audit"
+	"loan-billing-system/internal/auth"
+	"loan-billing-system/internal/tenant"
+
+	"github.com/labstack/echo/v4"
+)
+
+// HeaderAPIKey carries a partner system's API key
+const HeaderAPIKey = "X-API-Key"
+
+// Authenticate requires every request to carry a staff JWT or a partner API
+// key, and puts the authenticated principal in the request context, where
+// services and the audit log find it, and scopes the request to the
+// principal's tenant. Tokens are sent as
+// "Authorization: Bearer <token>"; API keys the same way or in X-API-Key.
+func Authenticate(authenticator auth.Authenticator) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			credential := c.Request().Header.Get(HeaderAPIKey)
+			if credential == "" {
+				scheme, token, ok := strings.Cut(c.Request().Header.Get(echo.HeaderAuthorization), " ")
+				if ok && strings.EqualFold(scheme, "Bearer") {
+					credential = strings.TrimSpace(token)
+				}
+			}
+			if credential == "" {
+				return unauthorized(c, auth.ErrUnauthenticated)
+			}
+
+			var principal *auth.Principal
+			var err error
+			if auth.IsAPIKey(credential) {
+				principal, err = authenticator.AuthenticateAPIKey(credential)
+			} else {
+				principal, err = authenticator.AuthenticateToken(credential)
+			}
+			if err != nil {
+				if errors.Is(err, auth.ErrInvalidToken) || errors.Is(err, auth.ErrInvalidAPIKey) {
+					return unauthorized(c, err)
+				}
+				return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			}
+
+			ctx := audit.WithActor(auth.WithPrincipal(c.Request().Context(), principal), principal.Actor())
+			ctx = tenant.WithID(ctx, principal.Tenant)
+			c.SetRequest(c.Request().WithContext(ctx))
+			return next(c)
+		}
+	}
+}
+
+// unauthorized rejects a request that is not authenticated
+func unauthorized(c echo.Context, err error) error {
+	c.Response().Header().Set(echo.HeaderWWWAuthenticate, `Bearer realm="loan-billing-system"`)
+	return c.JSON(http.StatusUnauthorized, map[string]string{"error": err.Error()})
+}
+
+// Require lets a request through only when its principal holds one of the
+// permissions under the access policy. It runs after Authenticate.
+func Require(access *auth.Policy, permissions ...auth.Permission) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if auth.PrincipalFrom(c.Request().Context()) == nil {
+				return unauthorized(c, auth.ErrUnauthenticated)
+			}
+			if err := access.Authorize(c.Request().Context(), permissions...); err != nil {
+				return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
+			}
+			return next(c)
+		}
+	}
+}
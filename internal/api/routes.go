@@ -3,6 +3,7 @@ package api
 import (
 	"loan-billing-system/internal/api/handlers"
 	"loan-billing-system/internal/api/middleware"
+	"loan-billing-system/internal/auth"
 	"loan-billing-system/internal/services"
 
 	"github.com/go-playground/validator/v10"
@@ -21,7 +22,7 @@ func (cv *CustomValidator) Validate(i interface{}) error {
 }
 
 // SetupRoutes configures all API routes
-func SetupRoutes(e *echo.Echo, db *gorm.DB, borrowerService *services.BorrowerService, loanService *services.LoanService) {
+func SetupRoutes(e *echo.Echo, db *gorm.DB, borrowerService *services.BorrowerService, loanService *services.LoanService, auditService *services.AuditService, authService *services.AuthService, access *auth.Policy) {
 	// Setup validator and custom binder
 	e.Validator = &CustomValidator{validator: validator.New()}
 	e.Binder = &middleware.UUIDBinder{DefaultBinder: echo.DefaultBinder{}}
@@ -29,22 +30,125 @@ func SetupRoutes(e *echo.Echo, db *gorm.DB, borrowerService *services.BorrowerSe
 	// Initialize handlers
 	borrowerHandler := handlers.NewBorrowerHandler(borrowerService)
 	loanHandler := handlers.NewLoanHandler(loanService)
+	paymentHandler := handlers.NewPaymentHandler(loanService)
+	productHandler := handlers.NewProductHandler(loanService)
+	calendarHandler := handlers.NewCalendarHandler(loanService)
+	chargeOffHandler := handlers.NewChargeOffHandler(loanService)
+	reportHandler := handlers.NewReportHandler(loanService)
+	groupHandler := handlers.NewGroupHandler(loanService)
+	auditHandler := handlers.NewAuditHandler(auditService)
+	apiKeyHandler := handlers.NewAPIKeyHandler(authService)
 
-	// API group
-	api := e.Group("/api")
+	// API group; every request must be authenticated, and the changes it makes
+	// are audited with the caller and request ID
+	api := e.Group("/api", middleware.AuditContext(), middleware.Authenticate(authService))
+
+	// Every route needs one of its permissions granted by the access policy;
+	// routes borrowers may call also take a self-service permission
+	require := func(permissions ...auth.Permission) echo.MiddlewareFunc {
+		return middleware.Require(access, permissions...)
+	}
 
 	// Borrower routes
 	borrowers := api.Group("/borrowers")
-	borrowers.POST("", borrowerHandler.CreateBorrower)
-	borrowers.GET("", borrowerHandler.ListBorrowers)
-	borrowers.GET("/:id", borrowerHandler.GetBorrower) //use this to check borrower delinquency status
-	borrowers.GET("/delinquent", borrowerHandler.ListDelinquentBorrowers)
+	borrowers.POST("", borrowerHandler.CreateBorrower, require(auth.PermBorrowersWrite))
+	borrowers.GET("", borrowerHandler.ListBorrowers, require(auth.PermBorrowersRead))
+	borrowers.GET("/:id", borrowerHandler.GetBorrower, require(auth.PermBorrowersRead, auth.PermSelfRead)) //use this to check borrower delinquency status
+	borrowers.GET("/delinquent", borrowerHandler.ListDelinquentBorrowers, require(auth.PermBorrowersRead))
+	borrowers.GET("/:id/loans", loanHandler.ListBorrowerLoans, require(auth.PermLoansRead, auth.PermSelfRead))
+	borrowers.PUT("/:id/credit-limits", borrowerHandler.SetCreditLimits, require(auth.PermBorrowersWrite))
+	borrowers.PUT("/:id/profile", borrowerHandler.UpdateBorrowerProfile, require(auth.PermBorrowersWrite))
+	borrowers.POST("/:id/addresses", borrowerHandler.AddBorrowerAddress, require(auth.PermBorrowersWrite))
+	borrowers.DELETE("/:id/addresses/:addressId", borrowerHandler.RemoveBorrowerAddress, require(auth.PermBorrowersWrite))
+	borrowers.POST("/:id/documents", borrowerHandler.AddBorrowerDocument, require(auth.PermBorrowersWrite))
+	borrowers.GET("/:id/documents", borrowerHandler.ListBorrowerDocuments, require(auth.PermBorrowersRead))
+	borrowers.POST("/:id/kyc", borrowerHandler.ReviewBorrowerKYC, require(auth.PermBorrowersKYC))
+	borrowers.GET("/:id/duplicates", borrowerHandler.ListBorrowerDuplicates, require(auth.PermBorrowersRead))
+	borrowers.POST("/:id/merge", borrowerHandler.MergeBorrower, require(auth.PermBorrowersMerge))
+	borrowers.GET("/:id/merges", borrowerHandler.ListBorrowerMerges, require(auth.PermBorrowersRead))
+	borrowers.POST("/:id/erasure", borrowerHandler.RequestBorrowerErasure, require(auth.PermBorrowersWrite))
+	borrowers.GET("/:id/erasure", borrowerHandler.GetBorrowerErasure, require(auth.PermBorrowersRead))
 
 	// Loan routes
 	loans := api.Group("/loans")
-	loans.POST("", loanHandler.CreateLoan)
-	loans.GET("/:id", loanHandler.GetLoan)
-	loans.GET("/:id/outstanding", loanHandler.GetOutstanding)
-	loans.GET("/:id/delinquent", loanHandler.IsDelinquent) //check delinquency in loan level
-	loans.POST("/:id/payment", loanHandler.MakePayment)
+	loans.POST("", loanHandler.CreateLoan, require(auth.PermLoansWrite))
+	loans.GET("", loanHandler.ListLoans, require(auth.PermLoansRead))
+	loans.POST("/quote", loanHandler.QuoteLoan, require(auth.PermLoansRead))
+	loans.GET("/:id", loanHandler.GetLoan, require(auth.PermLoansRead, auth.PermSelfRead))
+	loans.GET("/:id/outstanding", loanHandler.GetOutstanding, require(auth.PermLoansRead, auth.PermSelfRead))
+	loans.GET("/:id/delinquent", loanHandler.IsDelinquent, require(auth.PermLoansRead, auth.PermSelfRead)) //check delinquency in loan level
+	loans.POST("/:id/payment", loanHandler.MakePayment, require(auth.PermPaymentsWrite, auth.PermSelfPay))
+	loans.GET("/:id/schedule", loanHandler.GetSchedule, require(auth.PermLoansRead, auth.PermSelfRead))
+	loans.GET("/:id/payments", loanHandler.ListPayments, require(auth.PermLoansRead, auth.PermSelfRead))
+	loans.POST("/:id/restructure", loanHandler.RestructureLoan, require(auth.PermLoansWrite))
+	loans.GET("/:id/restructures", loanHandler.ListRestructures, require(auth.PermLoansRead))
+	loans.POST("/:id/holiday", loanHandler.GrantHoliday, require(auth.PermLoansApprove))
+	loans.GET("/:id/holidays", loanHandler.ListHolidays, require(auth.PermLoansRead))
+	loans.POST("/:id/write-off", loanHandler.WriteOffLoan, require(auth.PermLoansWriteOff))
+	loans.POST("/:id/recoveries", loanHandler.RecordRecovery, require(auth.PermPaymentsWrite))
+	loans.GET("/:id/recoveries", loanHandler.ListRecoveries, require(auth.PermLoansRead))
+	loans.GET("/:id/status-history", loanHandler.ListStatusHistory, require(auth.PermLoansRead))
+	loans.POST("/:id/refinance", loanHandler.RefinanceLoan, require(auth.PermLoansWrite))
+	loans.GET("/:id/fees", loanHandler.ListLoanFees, require(auth.PermLoansRead))
+	loans.POST("/:id/parties", loanHandler.AddLoanParty, require(auth.PermLoansWrite))
+	loans.GET("/:id/parties", loanHandler.ListLoanParties, require(auth.PermLoansRead))
+	loans.DELETE("/:id/parties/:borrowerId", loanHandler.RemoveLoanParty, require(auth.PermLoansWrite))
+	loans.GET("/:id/credit-assessment", loanHandler.GetCreditAssessment, require(auth.PermLoansRead))
+	loans.POST("/:id/collateral", loanHandler.AddCollateral, require(auth.PermLoansWrite))
+	loans.GET("/:id/collateral", loanHandler.ListCollateral, require(auth.PermLoansRead))
+	loans.POST("/:id/collateral/:collateralId/valuations", loanHandler.RevalueCollateral, require(auth.PermLoansWrite))
+	loans.PUT("/:id/collateral/:collateralId/lien", loanHandler.UpdateLienStatus, require(auth.PermLoansWrite))
+
+	// Payment routes
+	payments := api.Group("/payments")
+	payments.GET("/:id", paymentHandler.GetPayment, require(auth.PermLoansRead))
+	payments.POST("/:id/reverse", paymentHandler.ReversePayment, require(auth.PermPaymentsReverse))
+
+	// Loan group routes
+	groups := api.Group("/groups")
+	groups.POST("", groupHandler.CreateGroup, require(auth.PermLoansWrite))
+	groups.GET("/:id", groupHandler.GetGroup, require(auth.PermLoansRead))
+	groups.POST("/:id/members", groupHandler.AddGroupMember, require(auth.PermLoansWrite))
+	groups.DELETE("/:id/members/:borrowerId", groupHandler.RemoveGroupMember, require(auth.PermLoansWrite))
+	groups.PUT("/:id/leader", groupHandler.SetGroupLeader, require(auth.PermLoansWrite))
+	groups.GET("/:id/schedule", groupHandler.GetGroupSchedule, require(auth.PermLoansRead))
+	groups.GET("/:id/collections", groupHandler.GetGroupCollection, require(auth.PermLoansRead))
+	groups.POST("/:id/payments", groupHandler.MakeGroupPayment, require(auth.PermPaymentsWrite))
+
+	// Product routes
+	products := api.Group("/products")
+	products.GET("", productHandler.ListProducts, require(auth.PermLoansRead))
+	products.POST("", productHandler.CreateProduct, require(auth.PermProductsWrite))
+	products.PATCH("/:code", productHandler.UpdateProduct, require(auth.PermProductsWrite))
+	products.GET("/:code/fees", productHandler.ListProductFees, require(auth.PermLoansRead))
+	products.POST("/:code/fees", productHandler.CreateProductFee, require(auth.PermProductsWrite))
+	products.DELETE("/:code/fees/:feeId", productHandler.DeleteProductFee, require(auth.PermProductsWrite))
+
+	// Lending calendar routes
+	calendar := api.Group("/calendar")
+	calendar.GET("", calendarHandler.GetCalendar, require(auth.PermLoansRead))
+	calendar.PUT("", calendarHandler.UpdateCalendar, require(auth.PermProductsWrite))
+	calendar.POST("/holidays", calendarHandler.CreateCalendarHoliday, require(auth.PermProductsWrite))
+	calendar.DELETE("/holidays/:id", calendarHandler.DeleteCalendarHoliday, require(auth.PermProductsWrite))
+
+	// Charge-off review routes
+	chargeOffs := api.Group("/charge-offs")
+	chargeOffs.GET("", chargeOffHandler.ListChargeOffs, require(auth.PermLoansRead))
+	chargeOffs.POST("/:id/approve", chargeOffHandler.ApproveChargeOff, require(auth.PermLoansApprove))
+	chargeOffs.POST("/:id/reject", chargeOffHandler.RejectChargeOff, require(auth.PermLoansApprove))
+
+	// Report routes
+	reports := api.Group("/reports")
+	reports.GET("/income", reportHandler.GetIncomeReport, require(auth.PermReportsRead))
+
+	// Audit log routes
+	auditLog := api.Group("/audit")
+	auditLog.GET("", auditHandler.ListAuditEntries, require(auth.PermAuditRead))
+	auditLog.GET("/verify", auditHandler.VerifyAuditLog, require(auth.PermAuditRead))
+
+	// API key routes
+	apiKeys := api.Group("/api-keys")
+	apiKeys.POST("", apiKeyHandler.CreateAPIKey, require(auth.PermAPIKeysManage))
+	apiKeys.GET("", apiKeyHandler.ListAPIKeys, require(auth.PermAPIKeysManage))
+	apiKeys.DELETE("/:id", apiKeyHandler.RevokeAPIKey, require(auth.PermAPIKeysManage))
 }
@@ -0,0 +1,58 @@
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"loan-billing-system/internal/models"
+)
+
+// ErrBrokenChain is returned when an audit entry does not follow the one before it
+var ErrBrokenChain = errors.New("audit chain is broken")
+
+// Hash returns the hash of an entry, covering its content and the hash of
+// the entry before it
+func Hash(entry *models.AuditEntry) string {
+	fields := []any{
+		entry.Sequence,
+		entry.PrevHash,
+		entry.CreatedAt.UTC().Format(time.RFC3339Nano),
+		entry.Actor,
+		entry.RequestID,
+		entry.Action,
+		entry.EntityType,
+		entry.EntityID,
+		entry.Changes,
+	}
+	// Entries recorded before tenants existed have none and keep their hash
+	if entry.TenantID != "" {
+		fields = append(fields, entry.TenantID)
+	}
+	content, _ := json.Marshal(fields)
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// Verify checks that entries continue the chain after the entry with
+// sequence number prevSequence and hash prevHash (0 and "" at the start of
+// the log). It returns the sequence number and hash of the last entry, or
+// ErrBrokenChain naming the first entry out of place.
+func Verify(prevSequence int64, prevHash string, entries []models.AuditEntry) (int64, string, error) {
+	for i := range entries {
+		entry := &entries[i]
+		switch {
+		case entry.Sequence != prevSequence+1:
+			return prevSequence, prevHash, fmt.Errorf("%w: expected entry %d, found %d", ErrBrokenChain, prevSequence+1, entry.Sequence)
+		case entry.PrevHash != prevHash:
+			return prevSequence, prevHash, fmt.Errorf("%w: entry %d does not follow entry %d", ErrBrokenChain, entry.Sequence, prevSequence)
+		case entry.Hash != Hash(entry):
+			return prevSequence, prevHash, fmt.Errorf("%w: entry %d has been altered", ErrBrokenChain, entry.Sequence)
+		}
+		prevSequence, prevHash = entry.Sequence, entry.Hash
+	}
+	return prevSequence, prevHash, nil
+}
@@ -0,0 +1,42 @@
+// Package audit keeps an append-only log of every row created, updated or
+// deleted through GORM. Entries are written by a GORM plugin in the same
+// transaction as the change they describe, record who made it and in which
+// request, and are chained by hash so that editing or removing an entry
+// breaks the chain.
+package audit
+
+import "context"
+
+// SystemActor is recorded for changes made outside of an API request
+const SystemActor = "system"
+
+type contextKey int
+
+const (
+	actorKey contextKey = iota
+	requestIDKey
+)
+
+// WithActor returns a context whose changes are attributed to actor
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey, actor)
+}
+
+// WithRequestID returns a context whose changes are tagged with a request ID
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+// ActorFrom returns the actor of a context, or SystemActor when there is none
+func ActorFrom(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey).(string); ok && actor != "" {
+		return actor
+	}
+	return SystemActor
+}
+
+// RequestIDFrom returns the request ID of a context, if any
+func RequestIDFrom(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey).(string)
+	return requestID
+}
@@ -0,0 +1,402 @@
+package audit
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"loan-billing-system/internal/models"
+	"loan-billing-system/internal/pii"
+	"loan-billing-system/internal/tenant"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// ErrImmutable is returned when audit entries are updated or deleted
+var ErrImmutable = errors.New("audit entries cannot be changed")
+
+const (
+	// beforeKey holds the rows an update or delete is about to change
+	beforeKey = "audit:before"
+	// chainLockKey serializes writers of the audit chain on postgres
+	chainLockKey int64 = 0x61756469
+	// redacted stands in for the value of a redacted column
+	redacted = "[redacted]"
+)
+
+var auditEntryType = reflect.TypeOf(models.AuditEntry{})
+
+// Change is the value of a column before and after a change
+type Change struct {
+	Old any `json:"old"`
+	New any `json:"new"`
+}
+
+// Plugin is a GORM plugin that writes an audit entry for every row a model
+// statement creates, updates or deletes. Entries are written in the same
+// transaction as the change, so a change cannot be committed without them.
+// Columns are left out of the diff when tagged `audit:"-"` or updated
+// automatically, and their values are redacted when they are encrypted PII
+// or tagged `audit:"redact"`. Raw SQL is not audited.
+type Plugin struct{}
+
+// Name returns the name of the plugin
+func (Plugin) Name() string {
+	return "audit"
+}
+
+// Initialize registers the plugin's callbacks
+func (Plugin) Initialize(db *gorm.DB) error {
+	if err := db.Callback().Create().After("gorm:create").Before("gorm:commit_or_rollback_transaction").
+		Register("audit:after_create", afterCreate); err != nil {
+		return err
+	}
+	if err := db.Callback().Update().After("gorm:before_update").Before("gorm:update").
+		Register("audit:before_update", captureBefore); err != nil {
+		return err
+	}
+	if err := db.Callback().Update().After("gorm:update").Before("gorm:commit_or_rollback_transaction").
+		Register("audit:after_update", afterUpdate); err != nil {
+		return err
+	}
+	if err := db.Callback().Delete().After("gorm:before_delete").Before("gorm:delete").
+		Register("audit:before_delete", captureBefore); err != nil {
+		return err
+	}
+	return db.Callback().Delete().After("gorm:delete").Before("gorm:commit_or_rollback_transaction").
+		Register("audit:after_delete", afterDelete)
+}
+
+// audited reports whether a statement changes rows of an audited model
+func audited(db *gorm.DB) bool {
+	return db.Error == nil && db.Statement.Schema != nil &&
+		keyField(db.Statement.Schema) != nil &&
+		db.Statement.Schema.ModelType != auditEntryType
+}
+
+// captureBefore keeps the rows an update or delete is about to change
+func captureBefore(db *gorm.DB) {
+	if db.Statement.Schema != nil && db.Statement.Schema.ModelType == auditEntryType {
+		db.AddError(ErrImmutable)
+		return
+	}
+	if !audited(db) {
+		return
+	}
+
+	exprs := conditions(db.Statement)
+	if len(exprs) == 0 {
+		return
+	}
+	rows, err := snapshot(db, db.Statement.Unscoped, exprs...)
+	if err != nil {
+		db.AddError(fmt.Errorf("failed to read rows for the audit log: %w", err))
+		return
+	}
+	db.Statement.Settings.Store(beforeKey, rows)
+}
+
+// afterCreate records the rows a statement created
+func afterCreate(db *gorm.DB) {
+	if !audited(db) || db.Statement.RowsAffected == 0 {
+		return
+	}
+	keys := primaryKeys(db.Statement)
+	if len(keys) == 0 {
+		return
+	}
+	rows, err := snapshot(db, true, keyIn(db.Statement.Schema, keys))
+	if err != nil {
+		db.AddError(fmt.Errorf("failed to read rows for the audit log: %w", err))
+		return
+	}
+
+	entries := make([]models.AuditEntry, 0, len(rows))
+	for _, row := range rows {
+		entries = append(entries, newEntry(db.Statement, models.AuditActionCreate, row, diff(db.Statement.Schema, nil, row)))
+	}
+	record(db, entries)
+}
+
+// afterUpdate records the columns an update changed in each row
+func afterUpdate(db *gorm.DB) {
+	before := takeBefore(db)
+	if !audited(db) || len(before) == 0 || db.Statement.RowsAffected == 0 {
+		return
+	}
+
+	pk := keyField(db.Statement.Schema).DBName
+	keys := make([]any, len(before))
+	for i, row := range before {
+		keys[i] = row[pk]
+	}
+	rows, err := snapshot(db, true, keyIn(db.Statement.Schema, keys))
+	if err != nil {
+		db.AddError(fmt.Errorf("failed to read rows for the audit log: %w", err))
+		return
+	}
+	after := make(map[string]map[string]any, len(rows))
+	for _, row := range rows {
+		after[fmt.Sprint(normalize(row[pk]))] = row
+	}
+
+	var entries []models.AuditEntry
+	for _, row := range before {
+		changes := diff(db.Statement.Schema, row, after[fmt.Sprint(normalize(row[pk]))])
+		if len(changes) == 0 {
+			continue
+		}
+		entries = append(entries, newEntry(db.Statement, models.AuditActionUpdate, row, changes))
+	}
+	record(db, entries)
+}
+
+// afterDelete records the rows a delete removed
+func afterDelete(db *gorm.DB) {
+	before := takeBefore(db)
+	if !audited(db) || len(before) == 0 || db.Statement.RowsAffected == 0 {
+		return
+	}
+
+	entries := make([]models.AuditEntry, 0, len(before))
+	for _, row := range before {
+		entries = append(entries, newEntry(db.Statement, models.AuditActionDelete, row, diff(db.Statement.Schema, row, nil)))
+	}
+	record(db, entries)
+}
+
+// takeBefore returns the rows kept by captureBefore
+func takeBefore(db *gorm.DB) []map[string]any {
+	rows, _ := db.Statement.Settings.LoadAndDelete(beforeKey)
+	before, _ := rows.([]map[string]any)
+	return before
+}
+
+// conditions returns the conditions selecting the rows a statement changes:
+// its WHERE clause and the primary keys of the models it was given
+func conditions(stmt *gorm.Statement) []clause.Expression {
+	var exprs []clause.Expression
+	if c, ok := stmt.Clauses["WHERE"]; ok {
+		if where, ok := c.Expression.(clause.Where); ok {
+			exprs = append(exprs, where.Exprs...)
+		}
+	}
+	if keys := primaryKeys(stmt); len(keys) > 0 {
+		exprs = append(exprs, keyIn(stmt.Schema, keys))
+	}
+	return exprs
+}
+
+// keyField returns the field identifying a model's rows: its primary key or,
+// for models keyed by tenant as well, the rest of the key. Entries already
+// name the row's tenant.
+func keyField(s *schema.Schema) *schema.Field {
+	if s.PrioritizedPrimaryField != nil {
+		return s.PrioritizedPrimaryField
+	}
+	var key *schema.Field
+	for _, field := range s.PrimaryFields {
+		if field.DBName == "tenant_id" {
+			continue
+		}
+		if key != nil {
+			return nil
+		}
+		key = field
+	}
+	return key
+}
+
+// primaryKeys returns the non-zero primary keys of the models a statement was given
+func primaryKeys(stmt *gorm.Statement) []any {
+	field := keyField(stmt.Schema)
+	var keys []any
+	add := func(v reflect.Value) {
+		v = reflect.Indirect(v)
+		if v.Kind() != reflect.Struct || v.Type() != stmt.Schema.ModelType {
+			return
+		}
+		if key, zero := field.ValueOf(stmt.Context, v); !zero {
+			keys = append(keys, key)
+		}
+	}
+
+	v := reflect.Indirect(stmt.ReflectValue)
+	switch v.Kind() {
+	case reflect.Struct:
+		add(v)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			add(v.Index(i))
+		}
+	}
+	return keys
+}
+
+// keyIn matches the rows with the given primary keys
+func keyIn(s *schema.Schema, keys []any) clause.Expression {
+	return clause.IN{Column: clause.Column{Table: clause.CurrentTable, Name: keyField(s).DBName}, Values: keys}
+}
+
+// snapshot reads the raw column values of the rows matching exprs, in the
+// statement's transaction. Encrypted columns are read as they are stored,
+// not scanned into their fields' types.
+func snapshot(db *gorm.DB, unscoped bool, exprs ...clause.Expression) ([]map[string]any, error) {
+	tx := db.Session(&gorm.Session{NewDB: true}).Model(reflect.New(db.Statement.Schema.ModelType).Interface())
+	if unscoped {
+		tx = tx.Unscoped()
+	}
+	result, err := tx.Clauses(clause.Where{Exprs: exprs}).Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer result.Close()
+
+	columns, err := result.Columns()
+	if err != nil {
+		return nil, err
+	}
+	var rows []map[string]any
+	for result.Next() {
+		values := make([]any, len(columns))
+		pointers := make([]any, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err := result.Scan(pointers...); err != nil {
+			return nil, err
+		}
+		row := make(map[string]any, len(columns))
+		for i, column := range columns {
+			row[column] = values[i]
+		}
+		rows = append(rows, row)
+	}
+	return rows, result.Err()
+}
+
+// diff returns the audited columns whose values differ between two versions
+// of a row; either may be nil for a row created or deleted
+func diff(s *schema.Schema, old, new map[string]any) map[string]Change {
+	changes := make(map[string]Change)
+	for _, field := range s.Fields {
+		if field.DBName == "" || field.AutoUpdateTime > 0 || field.Tag.Get("audit") == "-" {
+			continue
+		}
+		oldValue, newValue := normalize(old[field.DBName]), normalize(new[field.DBName])
+
+		if field.TagSettings["SERIALIZER"] == "pii" || field.Tag.Get("audit") == "redact" {
+			if reflect.DeepEqual(plaintext(oldValue), plaintext(newValue)) {
+				continue
+			}
+			changes[field.DBName] = Change{Old: redact(oldValue), New: redact(newValue)}
+			continue
+		}
+		if reflect.DeepEqual(oldValue, newValue) {
+			continue
+		}
+		changes[field.DBName] = Change{Old: oldValue, New: newValue}
+	}
+	return changes
+}
+
+// normalize converts a column value read by the driver into a comparable,
+// JSON friendly value
+func normalize(v any) any {
+	switch value := v.(type) {
+	case []byte:
+		return string(value)
+	case time.Time:
+		return value.UTC().Format(time.RFC3339Nano)
+	default:
+		return v
+	}
+}
+
+// plaintext decrypts an encrypted column value so that re-encrypting an
+// unchanged value does not count as a change
+func plaintext(v any) any {
+	s, ok := v.(string)
+	if !ok || !pii.IsEncrypted(s) {
+		return v
+	}
+	vault, err := pii.Current()
+	if err != nil {
+		return v
+	}
+	if decrypted, err := vault.Decrypt(s); err == nil {
+		return decrypted
+	}
+	return v
+}
+
+// redact hides a column value, keeping whether it was empty
+func redact(v any) any {
+	if v == nil || v == "" {
+		return v
+	}
+	return redacted
+}
+
+// newEntry builds the audit entry of a change to one row. The entry belongs
+// to the row's tenant, or to the statement's for rows of no tenant.
+func newEntry(stmt *gorm.Statement, action string, row map[string]any, changes map[string]Change) models.AuditEntry {
+	// Column values come from the driver and always encode
+	encoded, _ := json.Marshal(changes)
+	tenantID := tenant.IDFrom(stmt.Context)
+	if value, ok := row["tenant_id"]; ok && value != nil {
+		tenantID = fmt.Sprint(normalize(value))
+	}
+	return models.AuditEntry{
+		ID:         uuid.New(),
+		Actor:      ActorFrom(stmt.Context),
+		Action:     action,
+		EntityType: stmt.Schema.Table,
+		EntityID:   fmt.Sprint(normalize(row[keyField(stmt.Schema).DBName])),
+		Changes:    string(encoded),
+		RequestID:  RequestIDFrom(stmt.Context),
+		TenantID:   tenantID,
+	}
+}
+
+// record appends entries to the chain in the statement's transaction. On
+// postgres an advisory lock held until the transaction ends keeps
+// concurrent writers from forking the chain; the unique sequence number
+// rejects a fork on any database. The chain spans every tenant.
+func record(db *gorm.DB, entries []models.AuditEntry) {
+	if len(entries) == 0 {
+		return
+	}
+	tx := db.Session(&gorm.Session{NewDB: true, Context: tenant.Unscoped(db.Statement.Context)})
+
+	if tx.Dialector.Name() == "postgres" {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", chainLockKey).Error; err != nil {
+			db.AddError(fmt.Errorf("failed to lock the audit log: %w", err))
+			return
+		}
+	}
+	var last models.AuditEntry
+	if err := tx.Order("sequence DESC").Limit(1).Find(&last).Error; err != nil {
+		db.AddError(fmt.Errorf("failed to read the audit log: %w", err))
+		return
+	}
+
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	sequence, prevHash := last.Sequence, last.Hash
+	for i := range entries {
+		sequence++
+		entries[i].Sequence = sequence
+		entries[i].PrevHash = prevHash
+		entries[i].CreatedAt = now
+		entries[i].Hash = Hash(&entries[i])
+		prevHash = entries[i].Hash
+	}
+	if err := tx.Create(&entries).Error; err != nil {
+		db.AddError(fmt.Errorf("failed to write the audit log: %w", err))
+	}
+}
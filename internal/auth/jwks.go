@@ -0,0 +1,213 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+)
+
+// Signature algorithms tokens can be signed with
+const (
+	AlgRS256 = "RS256"
+	AlgES256 = "ES256"
+	AlgEdDSA = "EdDSA"
+)
+
+// ErrUnsupportedKey is returned for keys of a type or curve that cannot sign tokens
+var ErrUnsupportedKey = errors.New("unsupported key")
+
+// JWK is a public key in JSON Web Key form (RFC 7517)
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// JWKS is the JSON layout of a key set file
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// verificationKey is a public key and the one algorithm it verifies
+type verificationKey struct {
+	alg string
+	key crypto.PublicKey
+}
+
+// KeySet holds the public keys tokens are verified with, by key ID. Keeping
+// the previous keys in the set while tokens signed with them are still valid
+// lets signing keys be rotated.
+type KeySet struct {
+	keys map[string]verificationKey
+}
+
+// ParseJWKS creates a key set from a JWKS document
+func ParseJWKS(data []byte) (*KeySet, error) {
+	var jwks JWKS
+	if err := json.Unmarshal(data, &jwks); err != nil {
+		return nil, fmt.Errorf("failed to parse key set: %w", err)
+	}
+
+	set := &KeySet{keys: make(map[string]verificationKey, len(jwks.Keys))}
+	for _, jwk := range jwks.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		if jwk.Kid == "" {
+			return nil, errors.New("every key in the key set needs a kid")
+		}
+		if _, ok := set.keys[jwk.Kid]; ok {
+			return nil, fmt.Errorf("key %q appears twice in the key set", jwk.Kid)
+		}
+		key, err := jwk.PublicKey()
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", jwk.Kid, err)
+		}
+		alg, err := algorithm(key)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", jwk.Kid, err)
+		}
+		if jwk.Alg != "" && jwk.Alg != alg {
+			return nil, fmt.Errorf("key %q: %w: algorithm %s", jwk.Kid, ErrUnsupportedKey, jwk.Alg)
+		}
+		set.keys[jwk.Kid] = verificationKey{alg: alg, key: key}
+	}
+	if len(set.keys) == 0 {
+		return nil, errors.New("key set holds no signing keys")
+	}
+	return set, nil
+}
+
+// LoadJWKS creates a key set from a JWKS file
+func LoadJWKS(path string) (*KeySet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseJWKS(data)
+}
+
+// PublicKey decodes the key. RSA keys must be at least 2048 bits.
+func (k JWK) PublicKey() (crypto.PublicKey, error) {
+	switch {
+	case k.Kty == "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if n.BitLen() < 2048 || !e.IsInt64() {
+			return nil, fmt.Errorf("%w: RSA keys must be at least 2048 bits", ErrUnsupportedKey)
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case k.Kty == "EC" && k.Crv == "P-256":
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		key := &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}
+		if !key.Curve.IsOnCurve(x, y) {
+			return nil, fmt.Errorf("%w: point is not on the curve", ErrUnsupportedKey)
+		}
+		return key, nil
+	case k.Kty == "OKP" && k.Crv == "Ed25519":
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("%w: invalid Ed25519 key", ErrUnsupportedKey)
+		}
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, fmt.Errorf("%w: kty %q crv %q", ErrUnsupportedKey, k.Kty, k.Crv)
+	}
+}
+
+// NewJWK returns the JWK of a public key, identified by its RFC 7638 thumbprint
+func NewJWK(key crypto.PublicKey) (JWK, error) {
+	alg, err := algorithm(key)
+	if err != nil {
+		return JWK{}, err
+	}
+
+	var jwk JWK
+	switch key := key.(type) {
+	case *rsa.PublicKey:
+		jwk = JWK{Kty: "RSA", N: encodeBigInt(key.N), E: encodeBigInt(big.NewInt(int64(key.E)))}
+	case *ecdsa.PublicKey:
+		jwk = JWK{Kty: "EC", Crv: "P-256", X: encodeCoordinate(key.X), Y: encodeCoordinate(key.Y)}
+	case ed25519.PublicKey:
+		jwk = JWK{Kty: "OKP", Crv: "Ed25519", X: base64.RawURLEncoding.EncodeToString(key)}
+	}
+	jwk.Kid = jwk.thumbprint()
+	jwk.Use = "sig"
+	jwk.Alg = alg
+	return jwk, nil
+}
+
+// thumbprint hashes the required members of the key in lexicographic order
+func (k JWK) thumbprint() string {
+	var members string
+	switch k.Kty {
+	case "RSA":
+		members = fmt.Sprintf(`{"e":%q,"kty":"RSA","n":%q}`, k.E, k.N)
+	case "EC":
+		members = fmt.Sprintf(`{"crv":%q,"kty":"EC","x":%q,"y":%q}`, k.Crv, k.X, k.Y)
+	case "OKP":
+		members = fmt.Sprintf(`{"crv":%q,"kty":"OKP","x":%q}`, k.Crv, k.X)
+	}
+	sum := sha256.Sum256([]byte(members))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// algorithm returns the signature algorithm a public key is used with
+func algorithm(key crypto.PublicKey) (string, error) {
+	switch key := key.(type) {
+	case *rsa.PublicKey:
+		return AlgRS256, nil
+	case *ecdsa.PublicKey:
+		if key.Curve == elliptic.P256() {
+			return AlgES256, nil
+		}
+	case ed25519.PublicKey:
+		return AlgEdDSA, nil
+	}
+	return "", fmt.Errorf("%w: %T", ErrUnsupportedKey, key)
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || len(b) == 0 {
+		return nil, fmt.Errorf("%w: invalid key parameter", ErrUnsupportedKey)
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+func encodeBigInt(n *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(n.Bytes())
+}
+
+// encodeCoordinate encodes a P-256 coordinate padded to its full 32 bytes
+func encodeCoordinate(n *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(n.FillBytes(make([]byte, 32)))
+}
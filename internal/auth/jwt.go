@@ -0,0 +1,200 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
+)
+
+// clockSkew is how far the clocks of the token issuer and the API may drift apart
+const clockSkew = time.Minute
+
+// ErrInvalidToken is returned when a token is malformed, badly signed, expired or meant for someone else
+var ErrInvalidToken = errors.New("invalid token")
+
+// Audience is the aud claim, which may be a single string or a list
+type Audience []string
+
+// UnmarshalJSON accepts a single audience or a list
+func (a *Audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = Audience{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*a = list
+	return nil
+}
+
+// Claims are the JWT claims the API reads
+type Claims struct {
+	Issuer    string   `json:"iss,omitempty"`
+	Subject   string   `json:"sub"`
+	Audience  Audience `json:"aud,omitempty"`
+	ExpiresAt int64    `json:"exp"`
+	NotBefore int64    `json:"nbf,omitempty"`
+	IssuedAt  int64    `json:"iat,omitempty"`
+	Name      string   `json:"name,omitempty"`
+	Roles     []string `json:"roles,omitempty"`
+	// BorrowerID scopes the token to one borrower's own data
+	BorrowerID string `json:"borrower_id,omitempty"`
+	// Tenant names the lending entity the token acts for; tokens without it act for the default tenant
+	Tenant string `json:"tenant,omitempty"`
+}
+
+// header is the JOSE header of a token
+type header struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	Typ string `json:"typ,omitempty"`
+}
+
+// Verifier checks staff tokens against a key set and, when set, the
+// expected issuer and audience
+type Verifier struct {
+	keys     *KeySet
+	issuer   string
+	audience string
+}
+
+// NewVerifier creates a verifier. An empty issuer or audience is not checked.
+func NewVerifier(keys *KeySet, issuer, audience string) *Verifier {
+	return &Verifier{keys: keys, issuer: issuer, audience: audience}
+}
+
+// Verify checks a token's signature and claims at the given time and
+// returns its claims. Tokens must name their key, be signed with the
+// algorithm of that key, carry a subject and expire.
+func (v *Verifier) Verify(token string, now time.Time) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: malformed", ErrInvalidToken)
+	}
+
+	var h header
+	if err := decodeSegment(parts[0], &h); err != nil {
+		return nil, fmt.Errorf("%w: malformed header", ErrInvalidToken)
+	}
+	key, ok := v.keys.keys[h.Kid]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown key %q", ErrInvalidToken, h.Kid)
+	}
+	if h.Alg != key.alg {
+		return nil, fmt.Errorf("%w: key %q does not sign with %s", ErrInvalidToken, h.Kid, h.Alg)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed signature", ErrInvalidToken)
+	}
+	if !verifySignature(key, []byte(parts[0]+"."+parts[1]), signature) {
+		return nil, fmt.Errorf("%w: bad signature", ErrInvalidToken)
+	}
+
+	var claims Claims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("%w: malformed claims", ErrInvalidToken)
+	}
+	switch {
+	case claims.Subject == "":
+		return nil, fmt.Errorf("%w: no subject", ErrInvalidToken)
+	case claims.ExpiresAt == 0:
+		return nil, fmt.Errorf("%w: no expiry", ErrInvalidToken)
+	case now.Add(-clockSkew).Unix() >= claims.ExpiresAt:
+		return nil, fmt.Errorf("%w: expired", ErrInvalidToken)
+	case claims.NotBefore != 0 && now.Add(clockSkew).Unix() < claims.NotBefore:
+		return nil, fmt.Errorf("%w: not valid yet", ErrInvalidToken)
+	case v.issuer != "" && claims.Issuer != v.issuer:
+		return nil, fmt.Errorf("%w: wrong issuer", ErrInvalidToken)
+	case v.audience != "" && !slices.Contains(claims.Audience, v.audience):
+		return nil, fmt.Errorf("%w: wrong audience", ErrInvalidToken)
+	}
+	return &claims, nil
+}
+
+// Sign issues a token for claims, signed with key under the key ID kid
+func Sign(key crypto.Signer, kid string, claims Claims) (string, error) {
+	alg, err := algorithm(key.Public())
+	if err != nil {
+		return "", err
+	}
+	encodedHeader, err := encodeSegment(header{Alg: alg, Kid: kid, Typ: "JWT"})
+	if err != nil {
+		return "", err
+	}
+	encodedClaims, err := encodeSegment(claims)
+	if err != nil {
+		return "", err
+	}
+	signingInput := encodedHeader + "." + encodedClaims
+
+	var signature []byte
+	switch key := key.(type) {
+	case *rsa.PrivateKey:
+		digest := sha256.Sum256([]byte(signingInput))
+		signature, err = rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	case *ecdsa.PrivateKey:
+		digest := sha256.Sum256([]byte(signingInput))
+		var r, s *big.Int
+		if r, s, err = ecdsa.Sign(rand.Reader, key, digest[:]); err == nil {
+			signature = append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+		}
+	case ed25519.PrivateKey:
+		signature = ed25519.Sign(key, []byte(signingInput))
+	default:
+		err = fmt.Errorf("%w: %T", ErrUnsupportedKey, key)
+	}
+	if err != nil {
+		return "", err
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// verifySignature checks a JWS signature with the key's algorithm
+func verifySignature(key verificationKey, signingInput, signature []byte) bool {
+	switch pub := key.key.(type) {
+	case *rsa.PublicKey:
+		digest := sha256.Sum256(signingInput)
+		return rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest[:], signature) == nil
+	case *ecdsa.PublicKey:
+		if len(signature) != 64 {
+			return false
+		}
+		digest := sha256.Sum256(signingInput)
+		r, s := new(big.Int).SetBytes(signature[:32]), new(big.Int).SetBytes(signature[32:])
+		return ecdsa.Verify(pub, digest[:], r, s)
+	case ed25519.PublicKey:
+		return ed25519.Verify(pub, signingInput, signature)
+	}
+	return false
+}
+
+func decodeSegment(segment string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+func encodeSegment(v any) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
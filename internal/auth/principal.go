@@ -0,0 +1,75 @@
+// Package auth authenticates API callers: staff with JWTs signed by keys
+// from a JWKS file, and partner systems with API keys stored hashed. It
+// authorizes them with a policy granting permissions to their roles.
+package auth
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+// Principal types
+const (
+	PrincipalStaff    = "staff"
+	PrincipalAPIKey   = "api_key"
+	PrincipalBorrower = "borrower"
+)
+
+// RoleBorrower is the one role borrowers get, whatever their token claims
+const RoleBorrower = "borrower"
+
+// ErrUnauthenticated is returned when a request carries no usable credentials
+var ErrUnauthenticated = errors.New("authentication required")
+
+// Principal is the authenticated caller of a request
+type Principal struct {
+	Type string
+	// Subject identifies the caller: the token subject for staff, the key prefix for API keys
+	Subject string
+	Name    string
+	Roles   []string
+	// BorrowerID is set for borrowers, who may only see and pay their own loans
+	BorrowerID *uuid.UUID
+	// Tenant is the lending entity the principal acts for
+	Tenant string
+}
+
+// Actor returns how the principal is named in the audit log, e.g. staff:jane.doe
+func (p *Principal) Actor() string {
+	return p.Type + ":" + p.Subject
+}
+
+// OwnsBorrower reports whether the principal may see a borrower's data:
+// anyone but a borrower may, and a borrower only their own
+func (p *Principal) OwnsBorrower(borrowerID uuid.UUID) bool {
+	return p.BorrowerID == nil || *p.BorrowerID == borrowerID
+}
+
+// HasRole reports whether the principal holds a role
+func (p *Principal) HasRole(role string) bool {
+	return slices.Contains(p.Roles, role)
+}
+
+// Authenticator checks the credentials presented with a request
+type Authenticator interface {
+	AuthenticateToken(token string) (*Principal, error)
+	AuthenticateAPIKey(key string) (*Principal, error)
+}
+
+type contextKey int
+
+const principalKey contextKey = iota
+
+// WithPrincipal returns a context carrying the authenticated principal
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey, principal)
+}
+
+// PrincipalFrom returns the principal of a context, or nil when there is none
+func PrincipalFrom(ctx context.Context) *Principal {
+	principal, _ := ctx.Value(principalKey).(*Principal)
+	return principal
+}
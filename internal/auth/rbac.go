@@ -0,0 +1,175 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Permission allows one kind of operation. Roles are granted permissions by
+// the access policy; principals are granted roles by their token or API key.
+type Permission string
+
+// Permissions checked by the API
+const (
+	PermBorrowersRead   Permission = "borrowers:read"   // View borrowers, their documents, duplicates, merges and erasure requests
+	PermBorrowersWrite  Permission = "borrowers:write"  // Create borrowers, edit profiles, addresses, documents and credit limits, request erasure
+	PermBorrowersKYC    Permission = "borrowers:kyc"    // Verify or reject a borrower's KYC
+	PermBorrowersMerge  Permission = "borrowers:merge"  // Merge duplicate borrowers
+	PermLoansRead       Permission = "loans:read"       // View loans, schedules, payments, groups, products and charge-offs, and quote loans
+	PermLoansWrite      Permission = "loans:write"      // Create, restructure and refinance loans, manage parties, collateral and groups
+	PermLoansApprove    Permission = "loans:approve"    // Approve loans referred by the credit checks, grant payment holidays and review charge-offs
+	PermLoansWriteOff   Permission = "loans:write_off"  // Write off loans
+	PermPaymentsWrite   Permission = "payments:write"   // Take repayments and group repayments, and record recoveries
+	PermPaymentsReverse Permission = "payments:reverse" // Reverse repayments
+	PermProductsWrite   Permission = "products:write"   // Create and change loan products and their fees, and set the lending calendar
+	PermReportsRead     Permission = "reports:read"     // View financial reports
+	PermAuditRead       Permission = "audit:read"       // View and verify the audit log
+	PermAPIKeysManage   Permission = "api_keys:manage"  // Issue, list and revoke API keys
+	PermSelfRead        Permission = "self:read"        // Borrowers: view their own profile, loans, schedules, balances and payments
+	PermSelfPay         Permission = "self:pay"         // Borrowers: repay their own loans
+)
+
+// PermAll in a role's permissions grants every permission
+const PermAll Permission = "*"
+
+// Permissions lists every permission a policy can grant
+var Permissions = []Permission{
+	PermBorrowersRead, PermBorrowersWrite, PermBorrowersKYC, PermBorrowersMerge,
+	PermLoansRead, PermLoansWrite, PermLoansApprove, PermLoansWriteOff,
+	PermPaymentsWrite, PermPaymentsReverse, PermProductsWrite, PermReportsRead, PermAuditRead, PermAPIKeysManage,
+	PermSelfRead, PermSelfPay,
+}
+
+// SelfService reports whether a permission is one borrowers act on their own
+// data with. Only borrowers are granted these, and only these.
+func (p Permission) SelfService() bool {
+	return strings.HasPrefix(string(p), "self:")
+}
+
+var (
+	// ErrForbidden is returned when the caller lacks the permission an operation needs
+	ErrForbidden = errors.New("not allowed")
+	// ErrInvalidPolicy is returned when an access policy file cannot be used
+	ErrInvalidPolicy = errors.New("invalid access policy")
+)
+
+//go:embed default_policy.yaml
+var defaultPolicy []byte
+
+// Policy maps roles to the permissions they grant
+type Policy struct {
+	Roles map[string][]Permission `json:"roles" yaml:"roles"`
+}
+
+// DefaultPolicy returns the built-in access policy, used when none is configured
+func DefaultPolicy() (*Policy, error) {
+	return ParsePolicy(defaultPolicy, "yaml")
+}
+
+// LoadPolicy reads an access policy from a JSON or YAML file, chosen by its extension
+func LoadPolicy(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read access policy: %w", err)
+	}
+
+	format := strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+	return ParsePolicy(data, format)
+}
+
+// ParsePolicy decodes and validates an access policy in the given format
+// (json, yaml or yml). Unknown fields and permissions are rejected so that
+// typos do not silently take access away, or grant it.
+func ParsePolicy(data []byte, format string) (*Policy, error) {
+	var policy Policy
+
+	switch format {
+	case "json":
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&policy); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPolicy, err)
+		}
+	case "yaml", "yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(&policy); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPolicy, err)
+		}
+	default:
+		return nil, fmt.Errorf("%w: unsupported format %q, use json or yaml", ErrInvalidPolicy, format)
+	}
+
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// Validate checks that the policy defines roles and only grants known permissions
+func (p *Policy) Validate() error {
+	if len(p.Roles) == 0 {
+		return fmt.Errorf("%w: no roles defined", ErrInvalidPolicy)
+	}
+	for role, permissions := range p.Roles {
+		if strings.TrimSpace(role) == "" {
+			return fmt.Errorf("%w: role without a name", ErrInvalidPolicy)
+		}
+		for _, permission := range permissions {
+			if permission != PermAll && !slices.Contains(Permissions, permission) {
+				return fmt.Errorf("%w: role %q grants unknown permission %q", ErrInvalidPolicy, role, permission)
+			}
+		}
+	}
+	return nil
+}
+
+// HasRole reports whether the policy defines a role
+func (p *Policy) HasRole(role string) bool {
+	_, ok := p.Roles[role]
+	return ok
+}
+
+// Allows reports whether any of the principal's roles grants a permission.
+// Roles the policy does not define grant nothing, and borrowers are never
+// granted more than self-service, whatever their role grants.
+func (p *Policy) Allows(principal *Principal, permission Permission) bool {
+	if principal == nil || permission.SelfService() != (principal.Type == PrincipalBorrower) {
+		return false
+	}
+	for _, role := range principal.Roles {
+		granted := p.Roles[role]
+		if slices.Contains(granted, PermAll) || slices.Contains(granted, permission) {
+			return true
+		}
+	}
+	return false
+}
+
+// Authorize checks that the principal of ctx holds one of the permissions.
+// Calls without a principal come from inside the system, such as the
+// scheduler and command line tools, and are allowed; every API request has one.
+func (p *Policy) Authorize(ctx context.Context, permissions ...Permission) error {
+	principal := PrincipalFrom(ctx)
+	if principal == nil {
+		return nil
+	}
+	names := make([]string, 0, len(permissions))
+	for _, permission := range permissions {
+		if p.Allows(principal, permission) {
+			return nil
+		}
+		names = append(names, string(permission))
+	}
+	return fmt.Errorf("%w: %s needs the %s permission", ErrForbidden, principal.Actor(), strings.Join(names, " or "))
+}
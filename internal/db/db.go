@@ -5,7 +5,9 @@ import (
 	"log"
 	"time"
 
+	"loan-billing-system/internal/audit"
 	"loan-billing-system/internal/models"
+	"loan-billing-system/internal/tenant"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -50,16 +52,60 @@ func Connect(config Config) (*gorm.DB, error) {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
+	// Record every change in the audit log
+	if err := db.Use(audit.Plugin{}); err != nil {
+		return nil, fmt.Errorf("failed to register audit plugin: %w", err)
+	}
+
+	// Keep every tenant's rows apart
+	if err := db.Use(tenant.Plugin{}); err != nil {
+		return nil, fmt.Errorf("failed to register tenant plugin: %w", err)
+	}
+
 	return db, nil
 }
 
 // Migrate performs database migrations
 func Migrate(db *gorm.DB) error {
+	// The audit log comes first, since every later write is recorded in it
+	if err := db.AutoMigrate(&models.AuditEntry{}); err != nil {
+		return fmt.Errorf("failed to migrate audit entries table: %w", err)
+	}
+
+	// Audit entries are append-only, whoever connects to the database
+	if err := db.Exec(`CREATE OR REPLACE FUNCTION audit_entries_append_only() RETURNS trigger AS $$
+		BEGIN
+			RAISE EXCEPTION 'audit entries cannot be changed';
+		END;
+		$$ LANGUAGE plpgsql`).Error; err != nil {
+		return fmt.Errorf("failed to create audit entries trigger function: %w", err)
+	}
+	if err := db.Exec(`DROP TRIGGER IF EXISTS audit_entries_append_only ON audit_entries`).Error; err != nil {
+		return fmt.Errorf("failed to drop audit entries trigger: %w", err)
+	}
+	if err := db.Exec(`CREATE TRIGGER audit_entries_append_only BEFORE UPDATE OR DELETE OR TRUNCATE ON audit_entries
+		FOR EACH STATEMENT EXECUTE FUNCTION audit_entries_append_only()`).Error; err != nil {
+		return fmt.Errorf("failed to create audit entries trigger: %w", err)
+	}
+
+	if err := db.AutoMigrate(&models.Tenant{}, &models.CalendarHoliday{}); err != nil {
+		return fmt.Errorf("failed to migrate tenant tables: %w", err)
+	}
+
 	// Migrate tables in the correct order to avoid foreign key constraint issues
 	if err := db.AutoMigrate(&models.Borrower{}); err != nil {
 		return fmt.Errorf("failed to migrate borrowers table: %w", err)
 	}
 
+	if err := db.AutoMigrate(&models.LoanProduct{}); err != nil {
+		return fmt.Errorf("failed to migrate loan products table: %w", err)
+	}
+
+	// Existing rows belong to the default tenant
+	if err := SeedTenant(db, models.DefaultTenant()); err != nil {
+		return err
+	}
+
 	if err := db.AutoMigrate(&models.Loan{}); err != nil {
 		return fmt.Errorf("failed to migrate loans table: %w", err)
 	}
@@ -72,5 +118,87 @@ func Migrate(db *gorm.DB) error {
 		return fmt.Errorf("failed to migrate payments table: %w", err)
 	}
 
+	if err := db.AutoMigrate(&models.LoanRestructure{}); err != nil {
+		return fmt.Errorf("failed to migrate loan restructures table: %w", err)
+	}
+
+	if err := db.AutoMigrate(&models.PaymentHoliday{}); err != nil {
+		return fmt.Errorf("failed to migrate payment holidays table: %w", err)
+	}
+
+	if err := db.AutoMigrate(&models.Recovery{}); err != nil {
+		return fmt.Errorf("failed to migrate recoveries table: %w", err)
+	}
+
+	if err := db.AutoMigrate(&models.LoanStatusChange{}); err != nil {
+		return fmt.Errorf("failed to migrate loan status changes table: %w", err)
+	}
+
+	if err := db.AutoMigrate(&models.ChargeOffProposal{}); err != nil {
+		return fmt.Errorf("failed to migrate charge-off proposals table: %w", err)
+	}
+
+	if err := db.AutoMigrate(&models.FeeDefinition{}, &models.LoanFee{}); err != nil {
+		return fmt.Errorf("failed to migrate fee tables: %w", err)
+	}
+
+	if err := db.AutoMigrate(&models.LoanParty{}); err != nil {
+		return fmt.Errorf("failed to migrate loan parties table: %w", err)
+	}
+
+	if err := db.AutoMigrate(&models.LoanGroup{}, &models.LoanGroupMember{}, &models.GroupPayment{}); err != nil {
+		return fmt.Errorf("failed to migrate loan group tables: %w", err)
+	}
+
+	if err := db.AutoMigrate(&models.Collateral{}, &models.CollateralValuation{}); err != nil {
+		return fmt.Errorf("failed to migrate collateral tables: %w", err)
+	}
+
+	if err := db.AutoMigrate(&models.CreditAssessment{}); err != nil {
+		return fmt.Errorf("failed to migrate credit assessments table: %w", err)
+	}
+
+	if err := db.AutoMigrate(&models.BorrowerAddress{}, &models.BorrowerDocument{}); err != nil {
+		return fmt.Errorf("failed to migrate borrower KYC tables: %w", err)
+	}
+
+	if err := db.AutoMigrate(&models.BorrowerMerge{}); err != nil {
+		return fmt.Errorf("failed to migrate borrower merges table: %w", err)
+	}
+
+	if err := db.AutoMigrate(&models.APIKey{}); err != nil {
+		return fmt.Errorf("failed to migrate API keys table: %w", err)
+	}
+
+	// PII is encrypted and matched through blind indexes now, so drop the
+	// plaintext name key and the indexes over columns that hold ciphertext
+	if db.Migrator().HasColumn(&models.Borrower{}, "name_key") {
+		if err := db.Migrator().DropColumn(&models.Borrower{}, "name_key"); err != nil {
+			return fmt.Errorf("failed to drop borrower name keys: %w", err)
+		}
+	}
+	for _, index := range []string{"idx_borrowers_name_id", "idx_borrowers_national_id"} {
+		if db.Migrator().HasIndex(&models.Borrower{}, index) {
+			if err := db.Migrator().DropIndex(&models.Borrower{}, index); err != nil {
+				return fmt.Errorf("failed to drop borrower index %s: %w", index, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// SeedTenant creates a tenant, unless it exists already, with the product
+// assigned to loans created without an explicit product
+func SeedTenant(db *gorm.DB, t models.Tenant) error {
+	if err := db.Where("id = ?", t.ID).FirstOrCreate(&t).Error; err != nil {
+		return fmt.Errorf("failed to seed tenant %s: %w", t.ID, err)
+	}
+
+	product := models.DefaultProduct()
+	product.TenantID = t.ID
+	if err := db.Where("tenant_id = ? AND code = ?", t.ID, product.Code).FirstOrCreate(&product).Error; err != nil {
+		return fmt.Errorf("failed to seed default loan product of tenant %s: %w", t.ID, err)
+	}
 	return nil
 }
@@ -0,0 +1,112 @@
+package finance
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// ErrInvalidCashFlows is returned when a loan's cash flows cannot be priced
+var ErrInvalidCashFlows = errors.New("amount financed and payments must be positive")
+
+// daysPerYear is the year length used to time dated cash flows
+const daysPerYear = 365
+
+// CashFlow is a dated movement of money seen from the lender's side:
+// negative when the borrower receives funds, positive when they repay
+type CashFlow struct {
+	Date   time.Time
+	Amount float64
+}
+
+// ScheduledPayment is a repayment due on a given date
+type ScheduledPayment struct {
+	Date   time.Time
+	Amount float64
+}
+
+// Disclosure holds the cost-of-credit figures of a loan, as percentages
+type Disclosure struct {
+	// APR is the nominal annual percentage rate: the periodic rate implied by
+	// the cash flows multiplied by the number of periods in a year. This is the
+	// actuarial method of US Regulation Z, Appendix J.
+	APR float64
+	// EffectiveRate is the compounded annual rate implied by the dated cash
+	// flows, as in the APRC formula of the EU Consumer Credit Directive, Annex I.
+	EffectiveRate float64
+}
+
+// AnnualPercentageRate returns the nominal APR of a loan repaid in equal
+// periods. amountFinanced is what the borrower actually receives, i.e. net of
+// any fees withheld at disbursement.
+func AnnualPercentageRate(amountFinanced float64, payments []float64, periodsPerYear int) (float64, error) {
+	if amountFinanced <= 0 || len(payments) == 0 {
+		return 0, ErrInvalidCashFlows
+	}
+
+	cashFlows := make([]float64, 0, len(payments)+1)
+	cashFlows = append(cashFlows, -amountFinanced)
+	cashFlows = append(cashFlows, payments...)
+
+	periodicRate, err := IRR(cashFlows)
+	if err != nil {
+		return 0, err
+	}
+	return periodicRate * float64(periodsPerYear) * 100, nil
+}
+
+// AnnualPercentageRateOfCharge returns the effective annual rate at which the
+// present value of the dated cash flows is zero. Time is measured in years of
+// 365 days from the first cash flow.
+func AnnualPercentageRateOfCharge(cashFlows []CashFlow) (float64, error) {
+	if len(cashFlows) < 2 {
+		return 0, ErrInvalidCashFlows
+	}
+
+	amounts := make([]float64, len(cashFlows))
+	years := make([]float64, len(cashFlows))
+	origin := cashFlows[0].Date
+	for i, cashFlow := range cashFlows {
+		amounts[i] = cashFlow.Amount
+		years[i] = cashFlow.Date.Sub(origin).Hours() / 24 / daysPerYear
+	}
+	if !changesSign(amounts) {
+		return 0, ErrNoIRR
+	}
+
+	rate, err := solveRate(func(rate float64) float64 {
+		var npv float64
+		for i := range amounts {
+			npv += amounts[i] / math.Pow(1+rate, years[i])
+		}
+		return npv
+	})
+	if err != nil {
+		return 0, err
+	}
+	return rate * 100, nil
+}
+
+// Disclose computes the APR and effective rate of a loan that disburses
+// amountFinanced on disbursedAt and is repaid by payments every period
+func Disclose(amountFinanced float64, disbursedAt time.Time, payments []ScheduledPayment, periodsPerYear int) (Disclosure, error) {
+	amounts := make([]float64, 0, len(payments))
+	cashFlows := make([]CashFlow, 0, len(payments)+1)
+	cashFlows = append(cashFlows, CashFlow{Date: disbursedAt, Amount: -amountFinanced})
+	for _, payment := range payments {
+		amounts = append(amounts, payment.Amount)
+		cashFlows = append(cashFlows, CashFlow{Date: payment.Date, Amount: payment.Amount})
+	}
+
+	apr, err := AnnualPercentageRate(amountFinanced, amounts, periodsPerYear)
+	if err != nil {
+		return Disclosure{}, err
+	}
+
+	effectiveRate, err := AnnualPercentageRateOfCharge(cashFlows)
+	if err != nil {
+		return Disclosure{}, err
+	}
+
+	return Disclosure{APR: apr, EffectiveRate: effectiveRate}, nil
+}
@@ -0,0 +1,82 @@
+// Package finance holds the loan pricing calculations that do not depend on storage
+package finance
+
+import (
+	"errors"
+	"math"
+)
+
+// ErrNoIRR is returned when the cash flows have no internal rate of return
+var ErrNoIRR = errors.New("cash flows have no internal rate of return")
+
+const (
+	irrTolerance     = 1e-10
+	irrMaxIterations = 200
+)
+
+// NPV returns the net present value of evenly spaced cash flows at a periodic rate.
+// The first cash flow happens at period 0 and is not discounted.
+func NPV(rate float64, cashFlows []float64) float64 {
+	var npv float64
+	for period, cashFlow := range cashFlows {
+		npv += cashFlow / math.Pow(1+rate, float64(period))
+	}
+	return npv
+}
+
+// IRR returns the periodic internal rate of return of evenly spaced cash flows,
+// i.e. the rate at which their net present value is zero. The cash flows must
+// change sign at least once, as a loan does: money out first, repayments after.
+func IRR(cashFlows []float64) (float64, error) {
+	if !changesSign(cashFlows) {
+		return 0, ErrNoIRR
+	}
+
+	return solveRate(func(rate float64) float64 { return NPV(rate, cashFlows) })
+}
+
+// solveRate finds the rate at which npv is zero.
+// NPV falls as the rate rises for a conventional loan, so the root is
+// bracketed by widening the upper bound until the sign flips and then
+// narrowed by bisection. Bisection is slower than Newton's method but never
+// diverges, which matters more here than speed: schedules are at most a few
+// hundred cash flows.
+func solveRate(npv func(rate float64) float64) (float64, error) {
+	low, high := -0.9999, 1.0
+	npvLow := npv(low)
+	for npv(high)*npvLow > 0 {
+		high *= 2
+		if high > 1e6 {
+			return 0, ErrNoIRR
+		}
+	}
+
+	for i := 0; i < irrMaxIterations; i++ {
+		mid := (low + high) / 2
+		npvMid := npv(mid)
+		if math.Abs(npvMid) < irrTolerance || (high-low)/2 < irrTolerance {
+			return mid, nil
+		}
+		if npvMid*npvLow > 0 {
+			low, npvLow = mid, npvMid
+		} else {
+			high = mid
+		}
+	}
+
+	return (low + high) / 2, nil
+}
+
+// changesSign reports whether a series has both positive and negative values
+func changesSign(cashFlows []float64) bool {
+	var positive, negative bool
+	for _, cashFlow := range cashFlows {
+		if cashFlow > 0 {
+			positive = true
+		}
+		if cashFlow < 0 {
+			negative = true
+		}
+	}
+	return positive && negative
+}
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// APIKey lets a partner system call the API. Only the hash of the key is
+// stored; the key itself is shown once, when it is created.
+type APIKey struct {
+	ID         uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	TenantID   string     `gorm:"size:50;not null;default:'default';index" json:"-" audit:"-"`
+	Name       string     `gorm:"size:100;not null" json:"name"`
+	Prefix     string     `gorm:"size:12;not null;uniqueIndex" json:"prefix"` // Public part of the key, which it is looked up by
+	Hash       string     `gorm:"size:64;not null" json:"-" audit:"-"`        // SHA-256 of the whole key
+	Roles      []string   `gorm:"type:text;serializer:json" json:"roles"`
+	CreatedBy  string     `gorm:"size:100;not null" json:"created_by"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at" audit:"-"`
+	RevokedAt  *time.Time `gorm:"index" json:"revoked_at"`
+	RevokedBy  string     `gorm:"size:100" json:"revoked_by"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// IsActive reports whether the key can be used at a given time
+func (k *APIKey) IsActive(t time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || t.Before(*k.ExpiresAt))
+}
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"loan-billing-system/internal/scoring"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CreditAssessment is the score a loan received at origination and how it was reached
+type CreditAssessment struct {
+	ID                 uuid.UUID              `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	TenantID           string                 `gorm:"size:50;not null;default:'default';index" json:"-" audit:"-"`
+	LoanID             uuid.UUID              `gorm:"type:uuid;not null;uniqueIndex" json:"loan_id"`
+	BorrowerID         uuid.UUID              `gorm:"type:uuid;not null;index" json:"borrower_id"`
+	ScorecardVersion   string                 `gorm:"size:50;not null" json:"scorecard_version"`
+	Score              int                    `gorm:"not null" json:"score"`
+	Grade              string                 `gorm:"size:20;not null" json:"grade"`
+	Decision           string                 `gorm:"size:20;not null" json:"decision"`
+	Contributions      []scoring.Contribution `gorm:"serializer:json" json:"contributions"` // Points each rule gave and why
+	ReferralApprovedBy string                 `gorm:"size:100" json:"referral_approved_by"` // Who approved a referred application
+	CreatedAt          time.Time              `json:"created_at"`
+}
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Audit entry actions
+const (
+	AuditActionCreate = "create"
+	AuditActionUpdate = "update"
+	AuditActionDelete = "delete"
+)
+
+// AuditEntry records one change to one row. Entries are numbered without
+// gaps and each one carries the hash of the entry before it, so the log can
+// be checked for entries that were altered, removed or inserted afterwards.
+type AuditEntry struct {
+	ID         uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	Sequence   int64     `gorm:"not null;uniqueIndex" json:"sequence"`
+	Actor      string    `gorm:"size:100;not null;index" json:"actor"`
+	Action     string    `gorm:"size:20;not null" json:"action"`
+	EntityType string    `gorm:"size:50;not null;index:idx_audit_entries_entity,priority:1" json:"entity_type"` // Table of the changed row
+	EntityID   string    `gorm:"size:100;not null;index:idx_audit_entries_entity,priority:2" json:"entity_id"`  // Primary key of the changed row
+	Changes    string    `gorm:"type:text;not null" json:"changes"`                                             // JSON object of column to old and new value
+	RequestID  string    `gorm:"size:100;index" json:"request_id"`
+	TenantID   string    `gorm:"size:50;not null;default:'';index" json:"tenant_id" tenant:"optional"` // Tenant whose data changed; empty for changes made outside of a tenant
+	PrevHash   string    `gorm:"size:64;not null" json:"prev_hash"`
+	Hash       string    `gorm:"size:64;not null" json:"hash"`
+	CreatedAt  time.Time `gorm:"not null;index" json:"created_at"`
+}
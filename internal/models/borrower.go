@@ -1,20 +1,135 @@
 package models
 
 import (
+	"sort"
+	"strings"
 	"time"
+	"unicode"
+
+	"loan-billing-system/internal/pii"
 
 	"github.com/google/uuid"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
 	"gorm.io/gorm"
 )
 
+// AnonymizedName replaces the name of a borrower whose PII has been erased
+const AnonymizedName = "Anonymized borrower"
+
 // Borrower represents a person who borrows money
 type Borrower struct {
-	ID           uuid.UUID      `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
-	Name         string         `gorm:"size:255;not null" json:"name"`
-	ContactInfo  string         `gorm:"size:255" json:"contact_info"`
-	IsDelinquent bool           `gorm:"default:false" json:"is_delinquent"`
-	Loans        []Loan         `gorm:"foreignKey:BorrowerID" json:"loans,omitempty"`
-	CreatedAt    time.Time      `json:"created_at"`
-	UpdatedAt    time.Time      `json:"updated_at"`
-	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
+	ID                 uuid.UUID         `gorm:"type:uuid;primaryKey;default:gen_random_uuid();index:idx_borrowers_created_at_id,priority:2" json:"id"`
+	TenantID           string            `gorm:"size:50;not null;default:'default';index" json:"-" audit:"-"`
+	Name               string            `gorm:"type:text;not null;serializer:pii" json:"name"`
+	NameIndex          string            `gorm:"size:64;index" json:"-" audit:"-"` // Blind index of NameKey(Name), for duplicate detection
+	ContactInfo        string            `gorm:"type:text;serializer:pii" json:"contact_info"`
+	IsDelinquent       bool              `gorm:"default:false" json:"is_delinquent"`
+	NationalIDType     string            `gorm:"size:20" json:"national_id_type"`
+	NationalID         string            `gorm:"type:text;serializer:pii" json:"national_id"`
+	NationalIDIndex    string            `gorm:"size:64;index" json:"-" audit:"-"` // Blind index of the national ID type and number
+	DateOfBirth        *time.Time        `gorm:"type:text;serializer:pii" json:"date_of_birth"`
+	DateOfBirthIndex   string            `gorm:"size:64;index" json:"-" audit:"-"`      // Blind index of the date of birth, for duplicate detection
+	Phone              string            `gorm:"type:text;serializer:pii" json:"phone"` // E.164, e.g. +6281234567890
+	PhoneIndex         string            `gorm:"size:64;index" json:"-" audit:"-"`
+	Email              string            `gorm:"type:text;serializer:pii" json:"email"`
+	EmploymentStatus   string            `gorm:"size:20" json:"employment_status"`
+	Employer           string            `gorm:"type:text;serializer:pii" json:"employer"`
+	MonthlyIncome      int64             `gorm:"type:text;serializer:pii" json:"monthly_income"`
+	KYCStatus          string            `gorm:"size:20;not null;default:'pending';index" json:"kyc_status"`
+	KYCReviewedBy      string            `gorm:"size:100" json:"kyc_reviewed_by"`
+	KYCReviewedAt      *time.Time        `json:"kyc_reviewed_at"`
+	KYCRejectionReason string            `gorm:"size:255" json:"kyc_rejection_reason" audit:"redact"`
+	KYCExpiresAt       *time.Time        `gorm:"index" json:"kyc_expires_at"`           // When a verification lapses and must be renewed
+	CreditLimit        *int64            `json:"credit_limit"`                          // Overrides the policy's credit limit when set
+	MaxConcurrentLoans *uint             `json:"max_concurrent_loans"`                  // Overrides the policy's maximum number of open loans when set
+	MergedIntoID       *uuid.UUID        `gorm:"type:uuid;index" json:"merged_into_id"` // Borrower this duplicate was merged into; merged borrowers are deleted
+	ErasureRequestedAt *time.Time        `gorm:"index" json:"erasure_requested_at"`     // When the borrower asked for their data to be erased
+	ErasureRequestedBy string            `gorm:"size:100" json:"erasure_requested_by"`
+	AnonymizedAt       *time.Time        `gorm:"index" json:"anonymized_at"` // When the borrower's PII was erased; their loans and payments are kept
+	Addresses          []BorrowerAddress `gorm:"foreignKey:BorrowerID" json:"addresses,omitempty"`
+	Loans              []Loan            `gorm:"foreignKey:BorrowerID" json:"loans,omitempty"`
+	CreatedAt          time.Time         `gorm:"index:idx_borrowers_created_at_id,priority:1" json:"created_at"`
+	UpdatedAt          time.Time         `json:"updated_at"`
+	DeletedAt          gorm.DeletedAt    `gorm:"index" json:"-"`
+}
+
+// KYCStatusAt returns the borrower's KYC status at a given time; a verification past its expiry counts as expired
+func (b *Borrower) KYCStatusAt(t time.Time) string {
+	if b.KYCStatus == KYCStatusVerified && b.KYCExpiresAt != nil && !t.Before(*b.KYCExpiresAt) {
+		return KYCStatusExpired
+	}
+	return b.KYCStatus
+}
+
+// NameKey normalizes a name for comparison: accents and punctuation are
+// dropped, letters lowercased and the words sorted, so "Rahma, Siti" and
+// "siti rahmá" share a key
+func NameKey(name string) string {
+	stripped, _, err := transform.String(transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC), name)
+	if err != nil {
+		stripped = name
+	}
+	words := strings.FieldsFunc(strings.ToLower(stripped), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	sort.Strings(words)
+	return strings.Join(words, " ")
+}
+
+// NameIndex returns the blind index of a name, matching names that share a NameKey
+func NameIndex(name string) (string, error) {
+	return pii.BlindIndex("name", NameKey(name))
+}
+
+// NationalIDIndex returns the blind index of an identity document number
+func NationalIDIndex(idType, id string) (string, error) {
+	if id == "" {
+		return "", nil
+	}
+	return pii.BlindIndex("national_id", idType+":"+id)
+}
+
+// PhoneIndex returns the blind index of a phone number
+func PhoneIndex(phone string) (string, error) {
+	return pii.BlindIndex("phone", phone)
+}
+
+// DateOfBirthIndex returns the blind index of a date of birth
+func DateOfBirthIndex(dateOfBirth *time.Time) (string, error) {
+	if dateOfBirth == nil {
+		return "", nil
+	}
+	return pii.BlindIndex("date_of_birth", dateOfBirth.Format(time.DateOnly))
+}
+
+// RefreshIndexes recomputes the borrower's blind indexes from their PII.
+// Anonymized borrowers have none, so they never match anyone.
+func (b *Borrower) RefreshIndexes() error {
+	if b.AnonymizedAt != nil {
+		b.NameIndex, b.NationalIDIndex, b.PhoneIndex, b.DateOfBirthIndex = "", "", "", ""
+		return nil
+	}
+	var err error
+	if b.NameIndex, err = NameIndex(b.Name); err != nil {
+		return err
+	}
+	if b.NationalIDIndex, err = NationalIDIndex(b.NationalIDType, b.NationalID); err != nil {
+		return err
+	}
+	if b.PhoneIndex, err = PhoneIndex(b.Phone); err != nil {
+		return err
+	}
+	b.DateOfBirthIndex, err = DateOfBirthIndex(b.DateOfBirth)
+	return err
+}
+
+// BeforeSave keeps the blind indexes in step with the PII. Column updates
+// through an empty model leave them alone.
+func (b *Borrower) BeforeSave(tx *gorm.DB) error {
+	if b.Name == "" {
+		return nil
+	}
+	return b.RefreshIndexes()
 }
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Charge-off proposal statuses
+const (
+	ChargeOffStatusPending  = "pending"
+	ChargeOffStatusApproved = "approved"
+	ChargeOffStatusRejected = "rejected"
+)
+
+// ChargeOffProposal is a charge-off suggested by the nightly job and waiting for review
+type ChargeOffProposal struct {
+	ID          uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	TenantID    string     `gorm:"size:50;not null;default:'default';index" json:"-" audit:"-"`
+	LoanID      uuid.UUID  `gorm:"type:uuid;not null;index" json:"loan_id"`
+	Balance     int64      `gorm:"not null" json:"balance"`
+	DaysPastDue uint       `gorm:"not null" json:"days_past_due"`
+	Rule        string     `gorm:"size:100;not null" json:"rule"`
+	Status      string     `gorm:"size:20;not null;default:'pending';index" json:"status"`
+	ReviewedBy  string     `gorm:"size:100" json:"reviewed_by"`
+	ReviewNotes string     `gorm:"size:255" json:"review_notes"`
+	ReviewedAt  *time.Time `json:"reviewed_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
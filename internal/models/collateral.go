@@ -0,0 +1,58 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Collateral types
+const (
+	CollateralTypeVehicle   = "vehicle"
+	CollateralTypeProperty  = "property"
+	CollateralTypeEquipment = "equipment"
+	CollateralTypeDeposit   = "deposit"
+	CollateralTypeOther     = "other"
+)
+
+// Lien statuses
+const (
+	LienStatusPending    = "pending"    // Lien not yet registered with the registry
+	LienStatusRegistered = "registered" // Lien registered in the lender's favour
+	LienStatusReleased   = "released"   // Lien lifted after the loan was fully paid
+)
+
+// Collateral is an asset pledged against a loan
+type Collateral struct {
+	ID          uuid.UUID             `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	TenantID    string                `gorm:"size:50;not null;default:'default';index" json:"-" audit:"-"`
+	LoanID      uuid.UUID             `gorm:"type:uuid;not null;index" json:"loan_id"`
+	Type        string                `gorm:"size:20;not null" json:"type"`
+	Description string                `gorm:"size:255;not null" json:"description"`
+	Value       int64                 `gorm:"not null" json:"value"` // Latest valuation
+	ValuedAt    time.Time             `gorm:"not null" json:"valued_at"`
+	LienStatus  string                `gorm:"size:20;not null;default:'pending'" json:"lien_status"`
+	ReleasedAt  *time.Time            `json:"released_at"`
+	Valuations  []CollateralValuation `gorm:"foreignKey:CollateralID" json:"valuations,omitempty"`
+	CreatedAt   time.Time             `json:"created_at"`
+	UpdatedAt   time.Time             `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt        `gorm:"index" json:"-"`
+}
+
+// IsReleased reports whether the collateral no longer secures the loan
+func (c *Collateral) IsReleased() bool {
+	return c.LienStatus == LienStatusReleased
+}
+
+// CollateralValuation is one valuation of a piece of collateral
+type CollateralValuation struct {
+	ID           uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	TenantID     string    `gorm:"size:50;not null;default:'default';index" json:"-" audit:"-"`
+	CollateralID uuid.UUID `gorm:"type:uuid;not null;index" json:"collateral_id"`
+	Value        int64     `gorm:"not null" json:"value"`
+	ValuedAt     time.Time `gorm:"not null" json:"valued_at"`
+	ValuedBy     string    `gorm:"size:100" json:"valued_by"`
+	Notes        string    `gorm:"size:255" json:"notes"`
+	CreatedAt    time.Time `json:"created_at"`
+}
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Fee types
+const (
+	FeeTypeDeducted    = "deducted"    // Charged upfront and deducted from the disbursement
+	FeeTypeCapitalized = "capitalized" // Charged upfront and added to the principal
+	FeeTypeInstallment = "installment" // Charged on every installment
+)
+
+// Fee calculation methods
+const (
+	FeeMethodFlat    = "flat"    // A fixed amount
+	FeeMethodPercent = "percent" // A percentage of the requested amount
+)
+
+// FeeDefinition is a fee charged on every loan of a product
+type FeeDefinition struct {
+	ID          uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	TenantID    string    `gorm:"size:50;not null;default:'default';index" json:"-" audit:"-"`
+	ProductCode string    `gorm:"size:50;not null;index" json:"product_code"`
+	Name        string    `gorm:"size:100;not null" json:"name"`
+	Type        string    `gorm:"size:20;not null" json:"type"`
+	Method      string    `gorm:"size:20;not null" json:"method"`
+	Amount      int64     `gorm:"not null;default:0" json:"amount"` // Used by flat fees
+	Rate        float64   `gorm:"not null;default:0" json:"rate"`   // Used by percentage fees, in percent
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// Charge returns the fee charged on a loan of the given requested amount
+func (f *FeeDefinition) Charge(requestedAmount int64) int64 {
+	if f.Method == FeeMethodPercent {
+		return int64(float64(requestedAmount) * f.Rate / 100)
+	}
+	return f.Amount
+}
+
+// LoanFee is a fee charged on a loan when it was created. Installment fees
+// record the total over the term.
+type LoanFee struct {
+	ID              uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	TenantID        string    `gorm:"size:50;not null;default:'default';index" json:"-" audit:"-"`
+	LoanID          uuid.UUID `gorm:"type:uuid;not null;index" json:"loan_id"`
+	FeeDefinitionID uuid.UUID `gorm:"type:uuid;not null" json:"fee_definition_id"`
+	Name            string    `gorm:"size:100;not null" json:"name"`
+	Type            string    `gorm:"size:20;not null" json:"type"`
+	Amount          int64     `gorm:"not null" json:"amount"`
+	CreatedAt       time.Time `gorm:"index" json:"created_at"`
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// LoanGroup is a group of borrowers who each hold their own loan but are jointly accountable for repaying them
+type LoanGroup struct {
+	ID        uuid.UUID         `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	TenantID  string            `gorm:"size:50;not null;default:'default';index" json:"-" audit:"-"`
+	Name      string            `gorm:"size:255;not null" json:"name"`
+	LeaderID  uuid.UUID         `gorm:"type:uuid;not null" json:"leader_id"` // Member who represents the group and collects its payments
+	Members   []LoanGroupMember `gorm:"foreignKey:GroupID" json:"members,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+	DeletedAt gorm.DeletedAt    `gorm:"index" json:"-"`
+}
+
+// LoanGroupMember is a borrower's membership of a loan group
+type LoanGroupMember struct {
+	ID         uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	TenantID   string    `gorm:"size:50;not null;default:'default';index" json:"-" audit:"-"`
+	GroupID    uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_loan_group_members_group_borrower,priority:1" json:"group_id"`
+	BorrowerID uuid.UUID `gorm:"type:uuid;not null;index;uniqueIndex:idx_loan_group_members_group_borrower,priority:2" json:"borrower_id"`
+	Borrower   Borrower  `gorm:"foreignKey:BorrowerID" json:"borrower,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// GroupPayment is a single payment collected from a group and split across its members' loans
+type GroupPayment struct {
+	ID          uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	TenantID    string    `gorm:"size:50;not null;default:'default';index" json:"-" audit:"-"`
+	GroupID     uuid.UUID `gorm:"type:uuid;not null;index" json:"group_id"`
+	Amount      int64     `gorm:"not null" json:"amount"`
+	PaymentDate time.Time `gorm:"not null" json:"payment_date"`
+	CollectedBy string    `gorm:"size:100" json:"collected_by"`
+	Payments    []Payment `gorm:"foreignKey:GroupPaymentID" json:"payments,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
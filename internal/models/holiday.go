@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Payment holiday reason codes
+const (
+	HolidayReasonHardship        = "hardship"
+	HolidayReasonMedical         = "medical"
+	HolidayReasonJobLoss         = "job_loss"
+	HolidayReasonNaturalDisaster = "natural_disaster"
+	HolidayReasonOther           = "other"
+)
+
+// PaymentHoliday records a deferral of a loan's remaining installments
+type PaymentHoliday struct {
+	ID              uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	TenantID        string    `gorm:"size:50;not null;default:'default';index" json:"-" audit:"-"`
+	LoanID          uuid.UUID `gorm:"type:uuid;not null;index" json:"loan_id"`
+	Installments    uint      `gorm:"not null" json:"installments"` // Number of weekly installments the schedule was pushed back by
+	StartDate       time.Time `gorm:"not null" json:"start_date"`
+	EndDate         time.Time `gorm:"not null" json:"end_date"`
+	ReasonCode      string    `gorm:"size:50;not null" json:"reason_code"`
+	Notes           string    `gorm:"size:255" json:"notes"`
+	ApprovedBy      string    `gorm:"size:100;not null" json:"approved_by"`
+	AccruedInterest int64     `gorm:"not null;default:0" json:"accrued_interest"` // Interest charged for the holiday, added to the final installment
+	CreatedAt       time.Time `json:"created_at"`
+}
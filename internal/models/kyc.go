@@ -0,0 +1,90 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// KYC statuses
+const (
+	KYCStatusPending  = "pending"
+	KYCStatusVerified = "verified"
+	KYCStatusRejected = "rejected"
+	KYCStatusExpired  = "expired"
+)
+
+// National identity document types
+const (
+	NationalIDTypeNationalID     = "national_id"
+	NationalIDTypePassport       = "passport"
+	NationalIDTypeDriversLicense = "drivers_license"
+	NationalIDTypeTaxID          = "tax_id"
+)
+
+// Employment statuses
+const (
+	EmploymentEmployed     = "employed"
+	EmploymentSelfEmployed = "self_employed"
+	EmploymentUnemployed   = "unemployed"
+	EmploymentRetired      = "retired"
+	EmploymentStudent      = "student"
+)
+
+// Address types
+const (
+	AddressTypeResidential = "residential"
+	AddressTypeMailing     = "mailing"
+	AddressTypeWork        = "work"
+)
+
+// Document types
+const (
+	DocumentTypeNationalID     = "national_id"
+	DocumentTypePassport       = "passport"
+	DocumentTypeDriversLicense = "drivers_license"
+	DocumentTypeProofOfAddress = "proof_of_address"
+	DocumentTypePayslip        = "payslip"
+	DocumentTypeBankStatement  = "bank_statement"
+	DocumentTypeOther          = "other"
+)
+
+// BorrowerAddress is one of a borrower's addresses
+type BorrowerAddress struct {
+	ID         uuid.UUID      `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	TenantID   string         `gorm:"size:50;not null;default:'default';index" json:"-" audit:"-"`
+	BorrowerID uuid.UUID      `gorm:"type:uuid;not null;index" json:"borrower_id"`
+	Type       string         `gorm:"size:20;not null" json:"type"`
+	Line1      string         `gorm:"type:text;not null;serializer:pii" json:"line1"`
+	Line2      string         `gorm:"type:text;serializer:pii" json:"line2"`
+	City       string         `gorm:"type:text;not null;serializer:pii" json:"city"`
+	Region     string         `gorm:"type:text;serializer:pii" json:"region"`
+	PostalCode string         `gorm:"type:text;serializer:pii" json:"postal_code"`
+	Country    string         `gorm:"size:2;not null" json:"country"` // ISO 3166-1 alpha-2
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+	DeletedAt  gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// BorrowerDocument describes a file supporting a borrower's KYC. Only the
+// metadata is kept here; the file itself lives in document storage under StorageKey.
+type BorrowerDocument struct {
+	ID          uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	TenantID    string     `gorm:"size:50;not null;default:'default';index" json:"-" audit:"-"`
+	BorrowerID  uuid.UUID  `gorm:"type:uuid;not null;index" json:"borrower_id"`
+	Type        string     `gorm:"size:30;not null" json:"type"`
+	FileName    string     `gorm:"type:text;not null;serializer:pii" json:"file_name"`
+	ContentType string     `gorm:"size:100;not null" json:"content_type"`
+	SizeBytes   int64      `gorm:"not null" json:"size_bytes"`
+	StorageKey  string     `gorm:"size:500;not null" json:"storage_key"`
+	Checksum    string     `gorm:"size:64;not null" json:"checksum"` // SHA-256, hex encoded
+	ExpiresAt   *time.Time `gorm:"type:date" json:"expires_at"`      // Expiry printed on the document, if any
+	UploadedBy  string     `gorm:"size:100" json:"uploaded_by"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// IsIdentity reports whether the document proves the borrower's identity
+func (d *BorrowerDocument) IsIdentity() bool {
+	return d.Type == DocumentTypeNationalID || d.Type == DocumentTypePassport || d.Type == DocumentTypeDriversLicense
+}
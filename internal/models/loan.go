@@ -7,23 +7,65 @@ import (
 	"gorm.io/gorm"
 )
 
+// Loan statuses
+const (
+	LoanStatusActive     = "active"
+	LoanStatusDefaulted  = "defaulted"
+	LoanStatusClosed     = "closed"
+	LoanStatusRefinanced = "refinanced"
+	LoanStatusWrittenOff = "written_off"
+)
+
+// DefaultProductCode is assigned to loans created without an explicit product
+const DefaultProductCode = "standard"
+
+// Days-past-due buckets used for reporting and filtering
+const (
+	DPDBucketCurrent = "current"
+	DPDBucket1To30   = "1-30"
+	DPDBucket31To60  = "31-60"
+	DPDBucket61To90  = "61-90"
+	DPDBucketOver90  = "90+"
+)
+
 // Loan represents a loan issued to a borrower
 type Loan struct {
-	ID              uuid.UUID      `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
-	BorrowerID      uuid.UUID      `gorm:"type:uuid;not null" json:"borrower_id"`
-	Borrower        Borrower       `gorm:"foreignKey:BorrowerID" json:"borrower,omitempty"`
-	Amount          int64          `gorm:"not null" json:"amount"`
-	InterestRate    float64        `gorm:"not null" json:"interest_rate"`
-	TermWeeks       uint           `gorm:"not null" json:"term_weeks"`
-	StartDate       time.Time      `gorm:"not null" json:"start_date"`
-	Status          string         `gorm:"size:20;not null;default:'active'" json:"status"`
-	CurrentBalance  int64          `gorm:"not null" json:"current_balance"`
-	LastPaymentDate *time.Time     `json:"last_payment_date"` // Date of last payment for query optimization
-	Schedules       []Schedule     `gorm:"foreignKey:LoanID" json:"schedules,omitempty"`
-	Payments        []Payment      `gorm:"foreignKey:LoanID" json:"payments,omitempty"`
-	CreatedAt       time.Time      `json:"created_at"`
-	UpdatedAt       time.Time      `json:"updated_at"`
-	DeletedAt       gorm.DeletedAt `gorm:"index" json:"-"`
+	ID                    uuid.UUID      `gorm:"type:uuid;primaryKey;default:gen_random_uuid();index:idx_loans_created_at_id,priority:2" json:"id"`
+	TenantID              string         `gorm:"size:50;not null;default:'default';index" json:"-" audit:"-"`
+	BorrowerID            uuid.UUID      `gorm:"type:uuid;not null;index" json:"borrower_id"`
+	Borrower              Borrower       `gorm:"foreignKey:BorrowerID" json:"borrower,omitempty"`
+	ProductCode           string         `gorm:"size:50;not null;default:'standard';index" json:"product_code"`
+	Amount                int64          `gorm:"not null" json:"amount"`
+	DisbursedAmount       int64          `gorm:"not null;default:0" json:"disbursed_amount"` // Cash paid out, net of any loan settled by a refinancing
+	InterestRate          float64        `gorm:"not null" json:"interest_rate"`
+	APR                   float64        `gorm:"not null;default:0" json:"apr"`            // Nominal annual percentage rate from the cash flows
+	EffectiveRate         float64        `gorm:"not null;default:0" json:"effective_rate"` // Compounded annual rate from the cash flows
+	TermWeeks             uint           `gorm:"not null" json:"term_weeks"`
+	StartDate             time.Time      `gorm:"not null" json:"start_date"`
+	Status                string         `gorm:"size:20;not null;default:'active';index" json:"status"`
+	CurrentBalance        int64          `gorm:"not null" json:"current_balance"`
+	IsDelinquent          bool           `gorm:"default:false" json:"is_delinquent"`
+	DaysPastDue           uint           `gorm:"not null;default:0;index" json:"days_past_due"` // Refreshed whenever delinquency is evaluated
+	LastPaymentDate       *time.Time     `json:"last_payment_date"`                             // Date of last payment for query optimization
+	ScheduleVersion       uint           `gorm:"not null;default:1" json:"schedule_version"`
+	IsRestructured        bool           `gorm:"default:false" json:"is_restructured"`
+	RestructuredAt        *time.Time     `json:"restructured_at"`
+	CurePaymentsRemaining uint           `gorm:"not null;default:0" json:"cure_payments_remaining"` // Payments left before a restructured loan's delinquency flag may clear
+	HolidayUntil          *time.Time     `gorm:"index" json:"holiday_until"`                        // End of the current or last payment holiday
+	WrittenOffAmount      int64          `gorm:"not null;default:0" json:"written_off_amount"`      // Balance moved off the books when the loan was written off
+	WrittenOffAt          *time.Time     `json:"written_off_at"`
+	WriteOffReason        string         `gorm:"size:255" json:"write_off_reason"`
+	RecoveredAmount       int64          `gorm:"not null;default:0" json:"recovered_amount"` // Total collected after the write-off
+	RefinancedFromID      *uuid.UUID     `gorm:"type:uuid;index" json:"refinanced_from_id"`  // Loan settled by this loan's disbursement
+	RefinancedByID        *uuid.UUID     `gorm:"type:uuid" json:"refinanced_by_id"`          // Loan that settled this one
+	GroupID               *uuid.UUID     `gorm:"type:uuid;index" json:"group_id"`            // Loan group the borrower holds this loan through
+	CreditOverrideBy      string         `gorm:"size:100" json:"credit_override_by"`         // Who let a delinquent borrower take this loan
+	CreditOverrideReason  string         `gorm:"size:255" json:"credit_override_reason"`
+	Schedules             []Schedule     `gorm:"foreignKey:LoanID" json:"schedules,omitempty"`
+	Payments              []Payment      `gorm:"foreignKey:LoanID" json:"payments,omitempty"`
+	CreatedAt             time.Time      `gorm:"index:idx_loans_created_at_id,priority:1" json:"created_at"`
+	UpdatedAt             time.Time      `json:"updated_at"`
+	DeletedAt             gorm.DeletedAt `gorm:"index" json:"-"`
 }
 
 // CalculateTotalDue returns the total amount due including interest
@@ -38,3 +80,53 @@ func (l *Loan) CalculateWeeklyPayment() int64 {
 	totalDue := l.CalculateTotalDue()
 	return totalDue / int64(l.TermWeeks)
 }
+
+// OutstandingWrittenOff returns the written-off balance not yet recovered
+func (l *Loan) OutstandingWrittenOff() int64 {
+	return l.WrittenOffAmount - l.RecoveredAmount
+}
+
+// OnHoliday reports whether the loan is in a payment holiday at the given time
+func (l *Loan) OnHoliday(at time.Time) bool {
+	return l.HolidayUntil != nil && at.Before(*l.HolidayUntil)
+}
+
+// DPDBucket returns the reporting bucket of the loan's days past due
+func (l *Loan) DPDBucket() string {
+	return DPDBucketFor(l.DaysPastDue)
+}
+
+// DPDBucketFor maps a days-past-due count to its reporting bucket
+func DPDBucketFor(daysPastDue uint) string {
+	switch {
+	case daysPastDue == 0:
+		return DPDBucketCurrent
+	case daysPastDue <= 30:
+		return DPDBucket1To30
+	case daysPastDue <= 60:
+		return DPDBucket31To60
+	case daysPastDue <= 90:
+		return DPDBucket61To90
+	default:
+		return DPDBucketOver90
+	}
+}
+
+// DPDBucketRange returns the inclusive days-past-due range of a bucket.
+// A max of 0 on a non-current bucket means the range is open-ended.
+func DPDBucketRange(bucket string) (min, max uint, ok bool) {
+	switch bucket {
+	case DPDBucketCurrent:
+		return 0, 0, true
+	case DPDBucket1To30:
+		return 1, 30, true
+	case DPDBucket31To60:
+		return 31, 60, true
+	case DPDBucket61To90:
+		return 61, 90, true
+	case DPDBucketOver90:
+		return 91, 0, true
+	default:
+		return 0, 0, false
+	}
+}
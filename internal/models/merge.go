@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BorrowerMerge records a duplicate borrower being merged into another
+type BorrowerMerge struct {
+	ID             uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	TenantID       string    `gorm:"size:50;not null;default:'default';index" json:"-" audit:"-"`
+	SourceID       uuid.UUID `gorm:"type:uuid;not null;index" json:"source_id"` // Duplicate merged away and deleted
+	TargetID       uuid.UUID `gorm:"type:uuid;not null;index" json:"target_id"` // Borrower that was kept
+	MergedBy       string    `gorm:"size:100;not null" json:"merged_by"`
+	Reason         string    `gorm:"size:255" json:"reason"`
+	LoansMoved     int64     `gorm:"not null;default:0" json:"loans_moved"`
+	PartiesMoved   int64     `gorm:"not null;default:0" json:"parties_moved"`
+	MembersMoved   int64     `gorm:"not null;default:0" json:"members_moved"`
+	SourceSnapshot Borrower  `gorm:"type:text;serializer:pii" json:"source_snapshot"` // The duplicate as it was before the merge, encrypted whole
+	CreatedAt      time.Time `json:"created_at"`
+}
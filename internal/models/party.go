@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Loan party roles
+const (
+	LoanPartyRolePrimary    = "primary"     // The loan's BorrowerID; not stored as a party
+	LoanPartyRoleCoBorrower = "co_borrower" // Jointly liable for the loan
+	LoanPartyRoleGuarantor  = "guarantor"   // Liable only if the borrowers fail to pay
+)
+
+// LoanParty links an additional borrower to a loan in a given role
+type LoanParty struct {
+	ID         uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	TenantID   string    `gorm:"size:50;not null;default:'default';index" json:"-" audit:"-"`
+	LoanID     uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_loan_parties_loan_borrower,priority:1" json:"loan_id"`
+	BorrowerID uuid.UUID `gorm:"type:uuid;not null;index;uniqueIndex:idx_loan_parties_loan_borrower,priority:2" json:"borrower_id"`
+	Borrower   Borrower  `gorm:"foreignKey:BorrowerID" json:"-"`
+	Role       string    `gorm:"size:20;not null" json:"role"`
+	CreatedAt  time.Time `json:"created_at"`
+}
@@ -7,16 +7,32 @@ import (
 	"gorm.io/gorm"
 )
 
+// Payment sources
+const (
+	PaymentSourceBorrower  = "borrower"  // Repayment made by the borrower
+	PaymentSourceRefinance = "refinance" // Settled out of a refinancing loan's disbursement
+	PaymentSourceGroup     = "group"     // Split from a payment made by the borrower's loan group
+)
+
 // Payment represents an actual payment made by a borrower
 type Payment struct {
-	ID          uuid.UUID      `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
-	LoanID      uuid.UUID      `gorm:"type:uuid;not null" json:"loan_id"`
-	Loan        Loan           `gorm:"foreignKey:LoanID" json:"-"`
-	ScheduleID  uuid.UUID      `gorm:"type:uuid;not null" json:"schedule_id"`
-	Schedule    Schedule       `gorm:"foreignKey:ScheduleID" json:"-"`
-	Amount      int64          `gorm:"not null" json:"amount"`
-	PaymentDate time.Time      `gorm:"not null" json:"payment_date"`
-	CreatedAt   time.Time      `json:"created_at"`
-	UpdatedAt   time.Time      `json:"updated_at"`
-	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+	ID             uuid.UUID      `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	TenantID       string         `gorm:"size:50;not null;default:'default';index" json:"-" audit:"-"`
+	LoanID         uuid.UUID      `gorm:"type:uuid;not null" json:"loan_id"`
+	Loan           Loan           `gorm:"foreignKey:LoanID" json:"-"`
+	ScheduleID     uuid.UUID      `gorm:"type:uuid;not null" json:"schedule_id"`
+	Schedule       Schedule       `gorm:"foreignKey:ScheduleID" json:"-"`
+	Amount         int64          `gorm:"not null" json:"amount"`
+	PaymentDate    time.Time      `gorm:"not null;index" json:"payment_date"`
+	Source         string         `gorm:"size:20;not null;default:'borrower'" json:"source"`
+	Principal      int64          `gorm:"not null;default:0" json:"principal"`     // Part of Amount applied to principal
+	Interest       int64          `gorm:"not null;default:0" json:"interest"`      // Part of Amount applied to interest
+	Fees           int64          `gorm:"not null;default:0" json:"fees"`          // Part of Amount applied to fees
+	GroupPaymentID *uuid.UUID     `gorm:"type:uuid;index" json:"group_payment_id"` // Group payment this payment was split from
+	ReversedAt     *time.Time     `json:"reversed_at"`                             // When the payment was reversed; reversed payments no longer count
+	ReversedBy     string         `gorm:"size:100" json:"reversed_by"`
+	ReversalReason string         `gorm:"size:255" json:"reversal_reason"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `gorm:"index" json:"-"`
 }
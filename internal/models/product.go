@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// LoanProduct holds the rules shared by every loan of a product. Each tenant
+// has its own products, keyed by tenant and code.
+type LoanProduct struct {
+	TenantID          string    `gorm:"size:50;primaryKey;default:'default'" json:"-" audit:"-"`
+	Code              string    `gorm:"size:50;primaryKey" json:"code"`
+	Name              string    `gorm:"size:100;not null" json:"name"`
+	DefaultAfterDPD   uint      `gorm:"not null;default:0" json:"default_after_dpd"`    // Days past due after which a loan is marked defaulted, 0 disables
+	ChargeOffAfterDPD uint      `gorm:"not null;default:0" json:"charge_off_after_dpd"` // Days past due after which a charge-off is proposed, 0 disables
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// DefaultProduct returns the product assigned to loans created without an explicit product
+func DefaultProduct() LoanProduct {
+	return LoanProduct{
+		Code:              DefaultProductCode,
+		Name:              "Standard weekly loan",
+		DefaultAfterDPD:   90,
+		ChargeOffAfterDPD: 180,
+	}
+}
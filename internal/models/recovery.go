@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Recovery represents money collected on a loan after it was written off.
+// Recoveries are kept apart from payments so they never count as repayments.
+type Recovery struct {
+	ID           uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	TenantID     string    `gorm:"size:50;not null;default:'default';index" json:"-" audit:"-"`
+	LoanID       uuid.UUID `gorm:"type:uuid;not null;index" json:"loan_id"`
+	Amount       int64     `gorm:"not null" json:"amount"`
+	RecoveryDate time.Time `gorm:"not null" json:"recovery_date"`
+	Reference    string    `gorm:"size:100" json:"reference"`
+	Notes        string    `gorm:"size:255" json:"notes"`
+	CreatedAt    time.Time `json:"created_at"`
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LoanRestructure records one restructuring of a loan's terms and schedule
+type LoanRestructure struct {
+	ID                   uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	TenantID             string    `gorm:"size:50;not null;default:'default';index" json:"-" audit:"-"`
+	LoanID               uuid.UUID `gorm:"type:uuid;not null;index" json:"loan_id"`
+	FromVersion          uint      `gorm:"not null" json:"from_version"`
+	ToVersion            uint      `gorm:"not null" json:"to_version"`
+	PreviousInterestRate float64   `gorm:"not null" json:"previous_interest_rate"`
+	NewInterestRate      float64   `gorm:"not null" json:"new_interest_rate"`
+	PreviousTermWeeks    uint      `gorm:"not null" json:"previous_term_weeks"`
+	NewTermWeeks         uint      `gorm:"not null" json:"new_term_weeks"`
+	PreviousPrincipal    int64     `gorm:"not null;default:0" json:"previous_principal"`
+	PreviousBalance      int64     `gorm:"not null" json:"previous_balance"`
+	ArrearsCapitalized   int64     `gorm:"not null" json:"arrears_capitalized"`
+	FeesCarried          int64     `gorm:"not null;default:0" json:"fees_carried"` // Installment fees not yet due, spread over the new installments
+	NewPrincipal         int64     `gorm:"not null" json:"new_principal"`
+	NewBalance           int64     `gorm:"not null" json:"new_balance"`
+	Reason               string    `gorm:"size:255;not null" json:"reason"`
+	CreatedAt            time.Time `json:"created_at"`
+}
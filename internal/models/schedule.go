@@ -9,14 +9,43 @@ import (
 
 // Schedule represents a weekly payment schedule for a loan
 type Schedule struct {
-	ID         uuid.UUID      `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
-	LoanID     uuid.UUID      `gorm:"type:uuid;not null" json:"loan_id"`
-	Loan       Loan           `gorm:"foreignKey:LoanID" json:"-"`
-	WeekNumber uint           `gorm:"not null" json:"week_number"`
-	DueDate    time.Time      `gorm:"not null" json:"due_date"`
-	Amount     int64          `gorm:"not null" json:"amount"`
-	Paid       bool           `gorm:"default:false" json:"paid"`
-	CreatedAt  time.Time      `json:"created_at"`
-	UpdatedAt  time.Time      `json:"updated_at"`
-	DeletedAt  gorm.DeletedAt `gorm:"index" json:"-"`
+	ID              uuid.UUID      `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	TenantID        string         `gorm:"size:50;not null;default:'default';index" json:"-" audit:"-"`
+	LoanID          uuid.UUID      `gorm:"type:uuid;not null;index" json:"loan_id"`
+	Loan            Loan           `gorm:"foreignKey:LoanID" json:"-"`
+	Version         uint           `gorm:"not null;default:1" json:"version"` // Incremented each time the loan is restructured
+	WeekNumber      uint           `gorm:"not null" json:"week_number"`
+	DueDate         time.Time      `gorm:"not null" json:"due_date"`
+	Amount          int64          `gorm:"not null" json:"amount"`
+	PrincipalAmount int64          `gorm:"not null;default:0" json:"principal_amount"`
+	InterestAmount  int64          `gorm:"not null;default:0" json:"interest_amount"`
+	FeeAmount       int64          `gorm:"not null;default:0" json:"fee_amount"` // Recurring fees included in Amount
+	Paid            bool           `gorm:"default:false" json:"paid"`
+	Cancelled       bool           `gorm:"default:false" json:"cancelled"` // Superseded by a newer schedule version, kept for history
+	OriginalDueDate *time.Time     `json:"original_due_date"`              // Due date before the first payment holiday moved it
+	DeferredUntil   *time.Time     `json:"deferred_until"`                 // End of the payment holiday that last moved the installment
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+	DeletedAt       gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// Principal returns the principal part of the installment. Installments
+// created before the split was recorded fall back to the loan's
+// principal-to-total ratio.
+func (s *Schedule) Principal(principal, totalDue int64) int64 {
+	if s.PrincipalAmount != 0 || s.InterestAmount != 0 || totalDue == 0 {
+		return s.PrincipalAmount
+	}
+	return s.Amount * principal / totalDue
+}
+
+// IsDeferred reports whether the installment is covered by a payment holiday at the given time
+func (s *Schedule) IsDeferred(at time.Time) bool {
+	return s.DeferredUntil != nil && at.Before(*s.DeferredUntil)
+}
+
+// Allocation returns how a full payment of the installment splits into principal, interest and fees
+func (s *Schedule) Allocation(principal, totalDue int64) (principalPortion, interestPortion, feePortion int64) {
+	principalPortion = s.Principal(principal, totalDue)
+	return principalPortion, s.Amount - principalPortion - s.FeeAmount, s.FeeAmount
 }
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StatusChangedBySystem marks status changes made by scheduled jobs
+const StatusChangedBySystem = "system"
+
+// LoanStatusChange records a change of a loan's status and why it happened
+type LoanStatusChange struct {
+	ID          uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	TenantID    string    `gorm:"size:50;not null;default:'default';index" json:"-" audit:"-"`
+	LoanID      uuid.UUID `gorm:"type:uuid;not null;index" json:"loan_id"`
+	FromStatus  string    `gorm:"size:20;not null" json:"from_status"`
+	ToStatus    string    `gorm:"size:20;not null" json:"to_status"`
+	Rule        string    `gorm:"size:100;not null" json:"rule"` // Rule that fired, e.g. standard.default_after_dpd=90
+	DaysPastDue uint      `gorm:"not null;default:0" json:"days_past_due"`
+	ChangedBy   string    `gorm:"size:100;not null" json:"changed_by"`
+	CreatedAt   time.Time `json:"created_at"`
+}
@@ -0,0 +1,100 @@
+package models
+
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultTenantID owns the rows created before the deployment served several
+// lending entities, and is used for callers whose credentials name no tenant
+const DefaultTenantID = "default"
+
+// Tenant is a lending entity, such as a brand, served by the deployment.
+// Tenant-owned rows carry the tenant's ID and are only ever seen by callers
+// acting for that tenant. A tenant has its own products and calendar.
+type Tenant struct {
+	ID        string    `gorm:"size:50;primaryKey" json:"id"`
+	Name      string    `gorm:"size:100;not null" json:"name"`
+	RestDays  []string  `gorm:"type:text;serializer:json" json:"rest_days"` // Weekdays installments never fall due on, e.g. "sunday"
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// DefaultTenant returns the tenant every deployment starts with
+func DefaultTenant() Tenant {
+	return Tenant{
+		ID:       DefaultTenantID,
+		Name:     "Default",
+		RestDays: []string{},
+	}
+}
+
+// CalendarHoliday is a day a tenant does not collect installments on
+type CalendarHoliday struct {
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	TenantID  string    `gorm:"size:50;not null;uniqueIndex:idx_calendar_holidays_tenant_date,priority:1" json:"-" audit:"-"`
+	Date      time.Time `gorm:"type:date;not null;uniqueIndex:idx_calendar_holidays_tenant_date,priority:2" json:"date"`
+	Name      string    `gorm:"size:100;not null" json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Calendar tells the days a tenant collects installments on from its rest
+// days and holidays. The zero Calendar has no days off.
+type Calendar struct {
+	RestDays []time.Weekday
+	Holidays []time.Time
+}
+
+// Weekdays maps the weekday names used in rest days to their weekday
+var Weekdays = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+}
+
+// NewCalendar builds a tenant's calendar from its rest days and holidays.
+// Unknown weekday names are ignored.
+func NewCalendar(tenant *Tenant, holidays []CalendarHoliday) Calendar {
+	var calendar Calendar
+	for _, name := range tenant.RestDays {
+		if day, ok := Weekdays[name]; ok {
+			calendar.RestDays = append(calendar.RestDays, day)
+		}
+	}
+	for _, holiday := range holidays {
+		calendar.Holidays = append(calendar.Holidays, holiday.Date)
+	}
+	return calendar
+}
+
+// IsWorkingDay reports whether a day is neither a rest day nor a holiday
+func (c Calendar) IsWorkingDay(day time.Time) bool {
+	if slices.Contains(c.RestDays, day.Weekday()) {
+		return false
+	}
+	year, month, date := day.Date()
+	for _, holiday := range c.Holidays {
+		if y, m, d := holiday.Date(); y == year && m == month && d == date {
+			return false
+		}
+	}
+	return true
+}
+
+// NextWorkingDay returns day if it is a working day, or else the same time
+// on the first working day after it. A calendar without any working day in
+// the year ahead leaves day unchanged.
+func (c Calendar) NextWorkingDay(day time.Time) time.Time {
+	for next := 0; next <= 366; next++ {
+		if candidate := day.AddDate(0, 0, next); c.IsWorkingDay(candidate) {
+			return candidate
+		}
+	}
+	return day
+}
@@ -0,0 +1,199 @@
+package pii
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// keySize is the size of every key-encryption, data and index key: AES-256
+const keySize = 32
+
+// ErrUnknownKey is returned when a value was encrypted under a key the provider does not hold
+var ErrUnknownKey = errors.New("unknown encryption key")
+
+// KeyProvider holds the key-encryption keys that protect each value's data
+// key, and the key blind indexes are computed with. New data keys are
+// wrapped with the current key; older keys are kept to unwrap existing values
+// until they have been re-encrypted.
+type KeyProvider interface {
+	// CurrentKeyID names the key new data keys are wrapped with
+	CurrentKeyID() string
+	// WrapKey encrypts a data key with the current key
+	WrapKey(dataKey []byte) (keyID string, wrapped []byte, err error)
+	// UnwrapKey decrypts a data key wrapped with the named key
+	UnwrapKey(keyID string, wrapped []byte) ([]byte, error)
+	// IndexKey is the HMAC key of blind indexes. It cannot be rotated without rebuilding every index.
+	IndexKey() []byte
+}
+
+// KeyFile is the JSON layout of a local key file. Keys are base64 encoded 32-byte values.
+type KeyFile struct {
+	CurrentKey string            `json:"current_key"`
+	Keys       map[string]string `json:"keys"`
+	IndexKey   string            `json:"index_key"`
+}
+
+// LocalKeyProvider keeps its keys in memory, loaded from a key file. It is
+// meant for development; production deployments should plug in a KMS.
+type LocalKeyProvider struct {
+	current  string
+	keys     map[string][]byte
+	indexKey []byte
+}
+
+// NewLocalKeyProvider creates a provider from raw keys
+func NewLocalKeyProvider(current string, keys map[string][]byte, indexKey []byte) (*LocalKeyProvider, error) {
+	if _, ok := keys[current]; !ok {
+		return nil, fmt.Errorf("%w: current key %q", ErrUnknownKey, current)
+	}
+	for id, key := range keys {
+		if id == "" || strings.Contains(id, ":") {
+			return nil, fmt.Errorf("key ID %q must be non-empty and must not contain ':'", id)
+		}
+		if len(key) != keySize {
+			return nil, fmt.Errorf("key %q must be %d bytes", id, keySize)
+		}
+	}
+	if len(indexKey) != keySize {
+		return nil, fmt.Errorf("index key must be %d bytes", keySize)
+	}
+	return &LocalKeyProvider{current: current, keys: keys, indexKey: indexKey}, nil
+}
+
+// LoadKeyFile creates a provider from a JSON key file
+func LoadKeyFile(path string) (*LocalKeyProvider, error) {
+	file, err := readKeyFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string][]byte, len(file.Keys))
+	for id, encoded := range file.Keys {
+		if keys[id], err = base64.StdEncoding.DecodeString(encoded); err != nil {
+			return nil, fmt.Errorf("key %q in %s is not valid base64: %w", id, path, err)
+		}
+	}
+	indexKey, err := base64.StdEncoding.DecodeString(file.IndexKey)
+	if err != nil {
+		return nil, fmt.Errorf("index key in %s is not valid base64: %w", path, err)
+	}
+	return NewLocalKeyProvider(file.CurrentKey, keys, indexKey)
+}
+
+// GenerateKey adds a new key to a key file and makes it current, creating
+// the file with a fresh index key if it does not exist. It returns the new key's ID.
+func GenerateKey(path string) (string, error) {
+	file, err := readKeyFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		file = &KeyFile{Keys: map[string]string{}, IndexKey: base64.StdEncoding.EncodeToString(randomKey())}
+	} else if err != nil {
+		return "", err
+	}
+
+	id := "k" + time.Now().UTC().Format("20060102") + "-" + hex.EncodeToString(randomKey()[:4])
+	file.Keys[id] = base64.StdEncoding.EncodeToString(randomKey())
+	file.CurrentKey = id
+
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o600); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// readKeyFile reads and decodes a key file
+func readKeyFile(path string) (*KeyFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var file KeyFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse key file %s: %w", path, err)
+	}
+	return &file, nil
+}
+
+// CurrentKeyID names the key new data keys are wrapped with
+func (p *LocalKeyProvider) CurrentKeyID() string {
+	return p.current
+}
+
+// WrapKey encrypts a data key with the current key
+func (p *LocalKeyProvider) WrapKey(dataKey []byte) (string, []byte, error) {
+	wrapped, err := seal(p.keys[p.current], dataKey, []byte(p.current))
+	return p.current, wrapped, err
+}
+
+// UnwrapKey decrypts a data key wrapped with the named key
+func (p *LocalKeyProvider) UnwrapKey(keyID string, wrapped []byte) ([]byte, error) {
+	key, ok := p.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKey, keyID)
+	}
+	return open(key, wrapped, []byte(keyID))
+}
+
+// IndexKey is the HMAC key of blind indexes
+func (p *LocalKeyProvider) IndexKey() []byte {
+	return p.indexKey
+}
+
+// randomKey returns a new random key
+func randomKey() []byte {
+	key := make([]byte, keySize)
+	if _, err := rand.Read(key); err != nil {
+		panic(err)
+	}
+	return key
+}
+
+// seal encrypts with AES-GCM, returning the nonce followed by the ciphertext
+func seal(key, plaintext, additionalData []byte) ([]byte, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+// open decrypts what seal encrypted
+func open(key, sealed, additionalData []byte) ([]byte, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return nil, ErrCorrupt
+	}
+	plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], additionalData)
+	if err != nil {
+		return nil, ErrCorrupt
+	}
+	return plaintext, nil
+}
+
+// newGCM creates an AES-GCM cipher
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
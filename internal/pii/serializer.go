@@ -0,0 +1,107 @@
+package pii
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	"gorm.io/gorm/schema"
+)
+
+func init() {
+	schema.RegisterSerializer("pii", Serializer{})
+}
+
+// Serializer encrypts a column with the vault in use. Tag a field with
+// `gorm:"serializer:pii;type:text"`. Strings are encrypted as they are and
+// other types as JSON; empty strings and nil pointers are stored empty.
+// Legacy plaintext rows read back unchanged until they are re-encrypted,
+// including dates left by a column that was a date before it was encrypted.
+type Serializer struct{}
+
+// legacyTimeLayouts are the layouts databases print dates and times in
+var legacyTimeLayouts = []string{time.DateOnly, time.RFC3339Nano, "2006-01-02 15:04:05.999999999-07:00", "2006-01-02 15:04:05"}
+
+// Scan decrypts a column value into the field
+func (Serializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue any) error {
+	var stored string
+	switch value := dbValue.(type) {
+	case nil:
+	case string:
+		stored = value
+	case []byte:
+		stored = string(value)
+	default:
+		return fmt.Errorf("unsupported PII column value %T", dbValue)
+	}
+
+	plaintext := stored
+	if IsEncrypted(stored) {
+		v, err := Current()
+		if err != nil {
+			return err
+		}
+		if plaintext, err = v.Decrypt(stored); err != nil {
+			return fmt.Errorf("failed to decrypt %s: %w", field.Name, err)
+		}
+	}
+
+	fieldValue := reflect.New(field.FieldType)
+	if field.FieldType.Kind() == reflect.String {
+		fieldValue.Elem().SetString(plaintext)
+	} else if plaintext != "" {
+		err := json.Unmarshal([]byte(plaintext), fieldValue.Interface())
+		if err != nil && !IsEncrypted(stored) {
+			err = scanLegacyTime(plaintext, fieldValue.Interface())
+		}
+		if err != nil {
+			return fmt.Errorf("failed to decode %s: %w", field.Name, err)
+		}
+	}
+	field.ReflectValueOf(ctx, dst).Set(fieldValue.Elem())
+	return nil
+}
+
+// Value encrypts the field for storage
+func (Serializer) Value(ctx context.Context, field *schema.Field, dst reflect.Value, fieldValue any) (any, error) {
+	var plaintext string
+	if s, ok := fieldValue.(string); ok {
+		if s == "" {
+			return "", nil
+		}
+		plaintext = s
+	} else if value := reflect.ValueOf(fieldValue); !value.IsValid() || (value.Kind() == reflect.Pointer && value.IsNil()) {
+		return "", nil
+	} else {
+		data, err := json.Marshal(fieldValue)
+		if err != nil {
+			return nil, err
+		}
+		plaintext = string(data)
+	}
+
+	v, err := Current()
+	if err != nil {
+		return nil, err
+	}
+	return v.Encrypt(plaintext)
+}
+
+// scanLegacyTime decodes a plaintext date or time written by the database
+// rather than as JSON
+func scanLegacyTime(plaintext string, dst any) error {
+	for _, layout := range legacyTimeLayouts {
+		t, err := time.Parse(layout, plaintext)
+		if err != nil {
+			continue
+		}
+		data, err := json.Marshal(t)
+		if err != nil {
+			return err
+		}
+		return json.Unmarshal(data, dst)
+	}
+	return fmt.Errorf("unrecognized plaintext value")
+}
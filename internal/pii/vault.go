@@ -0,0 +1,140 @@
+// Package pii encrypts personal data at rest. Each value is sealed with its
+// own random data key, which is in turn wrapped by a key-encryption key from
+// a KeyProvider (envelope encryption). Equality lookups go through blind
+// indexes: keyed HMACs of the normalized plaintext stored next to it.
+package pii
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+// prefix marks an encrypted value; anything else is legacy plaintext
+const prefix = "pii:v1:"
+
+var (
+	// ErrNotConfigured is returned when PII is read or written before a vault is in use
+	ErrNotConfigured = errors.New("PII encryption is not configured")
+	// ErrCorrupt is returned when an encrypted value cannot be decoded or fails authentication
+	ErrCorrupt = errors.New("encrypted value is corrupt")
+)
+
+// Vault encrypts and decrypts PII and computes blind indexes
+type Vault struct {
+	keys KeyProvider
+}
+
+// NewVault creates a vault over a key provider
+func NewVault(keys KeyProvider) *Vault {
+	return &Vault{keys: keys}
+}
+
+// Encrypt seals a value under a new data key wrapped with the current key.
+// The result reads pii:v1:<key ID>:<wrapped data key>:<ciphertext>.
+func (v *Vault) Encrypt(plaintext string) (string, error) {
+	dataKey := randomKey()
+	keyID, wrapped, err := v.keys.WrapKey(dataKey)
+	if err != nil {
+		return "", err
+	}
+	sealed, err := seal(dataKey, []byte(plaintext), []byte(keyID))
+	if err != nil {
+		return "", err
+	}
+	return prefix + keyID + ":" + base64.RawStdEncoding.EncodeToString(wrapped) + ":" + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt opens an encrypted value. Legacy plaintext is returned unchanged.
+func (v *Vault) Decrypt(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+	parts := strings.Split(strings.TrimPrefix(value, prefix), ":")
+	if len(parts) != 3 {
+		return "", ErrCorrupt
+	}
+	wrapped, err := base64.RawStdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", ErrCorrupt
+	}
+	sealed, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", ErrCorrupt
+	}
+
+	dataKey, err := v.keys.UnwrapKey(parts[0], wrapped)
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := open(dataKey, sealed, []byte(parts[0]))
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// CurrentKeyID names the key new values are encrypted under
+func (v *Vault) CurrentKeyID() string {
+	return v.keys.CurrentKeyID()
+}
+
+// BlindIndex returns the keyed hash of a field's value, hex encoded; empty values index as empty
+func (v *Vault) BlindIndex(field, value string) string {
+	if value == "" {
+		return ""
+	}
+	mac := hmac.New(sha256.New, v.keys.IndexKey())
+	mac.Write([]byte(field))
+	mac.Write([]byte{0})
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// IsEncrypted reports whether a stored value was encrypted by a vault
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, prefix)
+}
+
+// KeyID returns the ID of the key an encrypted value's data key is wrapped with
+func KeyID(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return "", fmt.Errorf("%w: not encrypted", ErrCorrupt)
+	}
+	id, _, ok := strings.Cut(strings.TrimPrefix(value, prefix), ":")
+	if !ok {
+		return "", ErrCorrupt
+	}
+	return id, nil
+}
+
+// current is the vault the GORM serializer and package-level helpers use
+var current atomic.Pointer[Vault]
+
+// Use makes a vault the one PII columns are encrypted with
+func Use(v *Vault) {
+	current.Store(v)
+}
+
+// Current returns the vault in use
+func Current() (*Vault, error) {
+	v := current.Load()
+	if v == nil {
+		return nil, ErrNotConfigured
+	}
+	return v, nil
+}
+
+// BlindIndex computes a blind index with the vault in use
+func BlindIndex(field, value string) (string, error) {
+	v, err := Current()
+	if err != nil {
+		return "", err
+	}
+	return v.BlindIndex(field, value), nil
+}
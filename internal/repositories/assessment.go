@@ -0,0 +1,30 @@
+package repositories
+
+import (
+	"loan-billing-system/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type GormAssessmentRepository struct {
+	db *gorm.DB
+}
+
+func NewGormAssessmentRepository(db *gorm.DB) *GormAssessmentRepository {
+	return &GormAssessmentRepository{db: db}
+}
+
+// GetByLoanID retrieves the credit assessment made when a loan was created
+func (r *GormAssessmentRepository) GetByLoanID(loanID uuid.UUID) (*models.CreditAssessment, error) {
+	var assessment models.CreditAssessment
+	if err := r.db.Where("loan_id = ?", loanID).First(&assessment).Error; err != nil {
+		return nil, err
+	}
+	return &assessment, nil
+}
+
+// Create records a credit assessment
+func (r *GormAssessmentRepository) Create(assessment *models.CreditAssessment) error {
+	return r.db.Create(assessment).Error
+}
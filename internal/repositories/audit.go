@@ -0,0 +1,75 @@
+package repositories
+
+import (
+	"loan-billing-system/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type GormAuditRepository struct {
+	db *gorm.DB
+}
+
+func NewGormAuditRepository(db *gorm.DB) *GormAuditRepository {
+	return &GormAuditRepository{db: db}
+}
+
+// AuditFilter narrows down an audit log listing
+type AuditFilter struct {
+	EntityType string
+	EntityID   string
+	Actor      string
+	Action     string
+	RequestID  string
+	From       *time.Time
+	To         *time.Time
+}
+
+// auditSortKeys lists the fields an audit log listing can be sorted by
+var auditSortKeys = map[string]sortKey[models.AuditEntry]{
+	"sequence": {column: "audit_entries.sequence", parse: parseIntSortValue, value: func(e *models.AuditEntry) any { return e.Sequence }},
+}
+
+// List retrieves one page of audit entries matching the filter
+func (r *GormAuditRepository) List(filter AuditFilter, page PageRequest) (Page[models.AuditEntry], error) {
+	q := r.db.Model(&models.AuditEntry{})
+
+	if filter.EntityType != "" {
+		q = q.Where("audit_entries.entity_type = ?", filter.EntityType)
+	}
+	if filter.EntityID != "" {
+		q = q.Where("audit_entries.entity_id = ?", filter.EntityID)
+	}
+	if filter.Actor != "" {
+		q = q.Where("audit_entries.actor = ?", filter.Actor)
+	}
+	switch filter.Action {
+	case "":
+	case models.AuditActionCreate, models.AuditActionUpdate, models.AuditActionDelete:
+		q = q.Where("audit_entries.action = ?", filter.Action)
+	default:
+		return Page[models.AuditEntry]{}, ErrInvalidFilter
+	}
+	if filter.RequestID != "" {
+		q = q.Where("audit_entries.request_id = ?", filter.RequestID)
+	}
+	if filter.From != nil {
+		q = q.Where("audit_entries.created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		q = q.Where("audit_entries.created_at < ?", *filter.To)
+	}
+
+	return paginate(q, "audit_entries", page, auditSortKeys, "sequence", func(e *models.AuditEntry) uuid.UUID { return e.ID })
+}
+
+// GetChain retrieves up to limit entries following the given sequence number, in order
+func (r *GormAuditRepository) GetChain(afterSequence int64, limit int) ([]models.AuditEntry, error) {
+	var entries []models.AuditEntry
+	if err := r.db.Where("sequence > ?", afterSequence).Order("sequence").Limit(limit).Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
@@ -2,9 +2,11 @@ package repositories
 
 import (
 	"loan-billing-system/internal/models"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // GormBorrowerRepository implements BorrowerRepository using GORM
@@ -20,46 +22,69 @@ func NewGormBorrowerRepository(db *gorm.DB) *GormBorrowerRepository {
 // GetByID retrieves a borrower by ID
 func (r *GormBorrowerRepository) GetByID(id uuid.UUID) (*models.Borrower, error) {
 	var borrower models.Borrower
-	if err := r.db.Preload("Loans").First(&borrower, id).Error; err != nil {
+	if err := r.db.Preload("Loans").Preload("Addresses", func(db *gorm.DB) *gorm.DB {
+		return db.Order("created_at")
+	}).First(&borrower, id).Error; err != nil {
 		return nil, err
 	}
 	return &borrower, nil
 }
 
-// GetAll retrieves all borrowers
-func (r *GormBorrowerRepository) GetAll() ([]models.Borrower, error) {
-	var borrowers []models.Borrower
-	if err := r.db.Find(&borrowers).Error; err != nil {
-		return nil, err
-	}
-	return borrowers, nil
+// BorrowerFilter narrows down a borrower listing
+type BorrowerFilter struct {
+	CreatedFrom  *time.Time
+	CreatedTo    *time.Time
+	IsDelinquent *bool
+	// LoanStatus keeps only borrowers holding at least one loan in this status
+	LoanStatus string
+	// KYCStatus keeps only borrowers in this KYC status; lapsed verifications count as expired
+	KYCStatus string
 }
 
-// GetDelinquent retrieves all delinquent borrowers
-func (r *GormBorrowerRepository) GetDelinquent() ([]models.Borrower, error) {
-	var borrowers []models.Borrower
-	if err := r.db.Where("is_delinquent = ?", true).Find(&borrowers).Error; err != nil {
-		return nil, err
-	}
-	return borrowers, nil
+// borrowerSortKeys lists the fields a borrower listing can be sorted by.
+// Names are encrypted, so they cannot be sorted on.
+var borrowerSortKeys = map[string]sortKey[models.Borrower]{
+	"created_at": {column: "borrowers.created_at", parse: parseTimeSortValue, value: func(b *models.Borrower) any { return b.CreatedAt }},
 }
 
-// Create creates a new borrower
-func (r *GormBorrowerRepository) Create(name, contactInfo string) (*models.Borrower, error) {
-	borrower := models.Borrower{
-		Name:         name,
-		ContactInfo:  contactInfo,
-		IsDelinquent: false,
+// List retrieves one page of borrowers matching the filter
+func (r *GormBorrowerRepository) List(filter BorrowerFilter, page PageRequest) (Page[models.Borrower], error) {
+	q := r.db.Model(&models.Borrower{})
+
+	if filter.CreatedFrom != nil {
+		q = q.Where("borrowers.created_at >= ?", *filter.CreatedFrom)
 	}
-	if err := r.db.Create(&borrower).Error; err != nil {
-		return nil, err
+	if filter.CreatedTo != nil {
+		q = q.Where("borrowers.created_at < ?", *filter.CreatedTo)
 	}
-	return &borrower, nil
+	if filter.IsDelinquent != nil {
+		q = q.Where("borrowers.is_delinquent = ?", *filter.IsDelinquent)
+	}
+	switch filter.KYCStatus {
+	case "":
+	case models.KYCStatusVerified:
+		q = q.Where("borrowers.kyc_status = ? AND (borrowers.kyc_expires_at IS NULL OR borrowers.kyc_expires_at > ?)", models.KYCStatusVerified, time.Now())
+	case models.KYCStatusExpired:
+		q = q.Where("borrowers.kyc_status = ? OR (borrowers.kyc_status = ? AND borrowers.kyc_expires_at <= ?)", models.KYCStatusExpired, models.KYCStatusVerified, time.Now())
+	default:
+		q = q.Where("borrowers.kyc_status = ?", filter.KYCStatus)
+	}
+	if filter.LoanStatus != "" {
+		// The tenant plugin does not reach into raw subqueries, so keep the loans to the borrower's tenant here
+		q = q.Where("EXISTS (SELECT 1 FROM loans WHERE loans.tenant_id = borrowers.tenant_id AND loans.borrower_id = borrowers.id AND loans.status = ? AND loans.deleted_at IS NULL)", filter.LoanStatus)
+	}
+
+	return paginate(q, "borrowers", page, borrowerSortKeys, "created_at", func(b *models.Borrower) uuid.UUID { return b.ID })
 }
 
-// Update updates a borrower
+// Create creates a new borrower with their addresses
+func (r *GormBorrowerRepository) Create(borrower *models.Borrower) error {
+	return r.db.Create(borrower).Error
+}
+
+// Update updates a borrower's own fields, leaving their loans and addresses alone
 func (r *GormBorrowerRepository) Update(borrower *models.Borrower) error {
-	return r.db.Save(borrower).Error
+	return r.db.Omit(clause.Associations).Save(borrower).Error
 }
 
 // UpdateDelinquencyStatus updates a borrower's delinquency status
@@ -67,3 +92,79 @@ func (r *GormBorrowerRepository) UpdateDelinquencyStatus(id uuid.UUID, isDelinqu
 	return r.db.Model(&models.Borrower{}).Where("id = ?", id).
 		Update("is_delinquent", isDelinquent).Error
 }
+
+// BorrowerMatch holds the identifying details duplicate borrowers are looked up by; empty fields are ignored
+type BorrowerMatch struct {
+	ExcludeID      uuid.UUID
+	NationalIDType string
+	NationalID     string
+	Phone          string
+	Name           string
+	DateOfBirth    *time.Time
+}
+
+// FindMatches retrieves the borrowers sharing the national ID, phone, name
+// key or date of birth. The PII is matched through its blind indexes.
+func (r *GormBorrowerRepository) FindMatches(match BorrowerMatch) ([]models.Borrower, error) {
+	nationalIDIndex, err := models.NationalIDIndex(match.NationalIDType, match.NationalID)
+	if err != nil {
+		return nil, err
+	}
+	phoneIndex, err := models.PhoneIndex(match.Phone)
+	if err != nil {
+		return nil, err
+	}
+	nameIndex, err := models.NameIndex(match.Name)
+	if err != nil {
+		return nil, err
+	}
+	dateOfBirthIndex, err := models.DateOfBirthIndex(match.DateOfBirth)
+	if err != nil {
+		return nil, err
+	}
+
+	conditions := r.db.Where("1 = 0")
+	if nationalIDIndex != "" {
+		conditions = conditions.Or("national_id_index = ?", nationalIDIndex)
+	}
+	if phoneIndex != "" {
+		conditions = conditions.Or("phone_index = ?", phoneIndex)
+	}
+	if nameIndex != "" {
+		conditions = conditions.Or("name_index = ?", nameIndex)
+	}
+	if dateOfBirthIndex != "" {
+		conditions = conditions.Or("date_of_birth_index = ?", dateOfBirthIndex)
+	}
+
+	var borrowers []models.Borrower
+	if err := r.db.Where(conditions).Where("id <> ?", match.ExcludeID).Order("created_at").Find(&borrowers).Error; err != nil {
+		return nil, err
+	}
+	return borrowers, nil
+}
+
+// Delete soft-deletes a borrower
+func (r *GormBorrowerRepository) Delete(id uuid.UUID) error {
+	result := r.db.Delete(&models.Borrower{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// UpdateCreditLimits sets a borrower's credit limit and maximum number of open loans; nil clears them
+func (r *GormBorrowerRepository) UpdateCreditLimits(id uuid.UUID, creditLimit *int64, maxConcurrentLoans *uint) error {
+	result := r.db.Model(&models.Borrower{}).Where("id = ?", id).
+		Updates(map[string]any{"credit_limit": creditLimit, "max_concurrent_loans": maxConcurrentLoans})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
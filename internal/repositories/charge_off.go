@@ -0,0 +1,56 @@
+package repositories
+
+import (
+	"loan-billing-system/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type GormChargeOffRepository struct {
+	db *gorm.DB
+}
+
+func NewGormChargeOffRepository(db *gorm.DB) *GormChargeOffRepository {
+	return &GormChargeOffRepository{db: db}
+}
+
+// GetByID retrieves a charge-off proposal by ID
+func (r *GormChargeOffRepository) GetByID(id uuid.UUID) (*models.ChargeOffProposal, error) {
+	var proposal models.ChargeOffProposal
+	if err := r.db.First(&proposal, id).Error; err != nil {
+		return nil, err
+	}
+	return &proposal, nil
+}
+
+// List retrieves charge-off proposals, oldest first, optionally narrowed to one status
+func (r *GormChargeOffRepository) List(status string) ([]models.ChargeOffProposal, error) {
+	q := r.db.Order("created_at")
+	if status != "" {
+		q = q.Where("status = ?", status)
+	}
+
+	var proposals []models.ChargeOffProposal
+	if err := q.Find(&proposals).Error; err != nil {
+		return nil, err
+	}
+	return proposals, nil
+}
+
+// ExistsForLoan reports whether a charge-off has already been proposed for a loan
+func (r *GormChargeOffRepository) ExistsForLoan(loanID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.ChargeOffProposal{}).Where("loan_id = ?", loanID).Count(&count).Error
+	return count > 0, err
+}
+
+// Create creates a new charge-off proposal
+func (r *GormChargeOffRepository) Create(proposal *models.ChargeOffProposal) error {
+	return r.db.Create(proposal).Error
+}
+
+// Update saves a charge-off proposal
+func (r *GormChargeOffRepository) Update(proposal *models.ChargeOffProposal) error {
+	return r.db.Save(proposal).Error
+}
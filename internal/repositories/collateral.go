@@ -0,0 +1,56 @@
+package repositories
+
+import (
+	"loan-billing-system/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type GormCollateralRepository struct {
+	db *gorm.DB
+}
+
+func NewGormCollateralRepository(db *gorm.DB) *GormCollateralRepository {
+	return &GormCollateralRepository{db: db}
+}
+
+// valuationHistory preloads valuations oldest first
+func valuationHistory(db *gorm.DB) *gorm.DB {
+	return db.Order("valued_at, created_at")
+}
+
+// GetByID retrieves a piece of collateral with its valuation history
+func (r *GormCollateralRepository) GetByID(id uuid.UUID) (*models.Collateral, error) {
+	var collateral models.Collateral
+	if err := r.db.Preload("Valuations", valuationHistory).First(&collateral, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &collateral, nil
+}
+
+// GetByLoanID retrieves the collateral pledged against a loan with its valuation history
+func (r *GormCollateralRepository) GetByLoanID(loanID uuid.UUID) ([]models.Collateral, error) {
+	var collateral []models.Collateral
+	err := r.db.Preload("Valuations", valuationHistory).
+		Where("loan_id = ?", loanID).Order("created_at").Find(&collateral).Error
+	if err != nil {
+		return nil, err
+	}
+	return collateral, nil
+}
+
+// Create registers a piece of collateral together with its first valuation
+func (r *GormCollateralRepository) Create(collateral *models.Collateral) error {
+	return r.db.Create(collateral).Error
+}
+
+// Update saves a piece of collateral's own fields; valuations are added separately
+func (r *GormCollateralRepository) Update(collateral *models.Collateral) error {
+	return r.db.Omit("Valuations").Save(collateral).Error
+}
+
+// CreateValuation records a new valuation
+func (r *GormCollateralRepository) CreateValuation(valuation *models.CollateralValuation) error {
+	return r.db.Create(valuation).Error
+}
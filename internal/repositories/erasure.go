@@ -0,0 +1,72 @@
+package repositories
+
+import (
+	"loan-billing-system/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// anonymizedColumns are the borrower columns cleared when their PII is erased
+var anonymizedColumns = []string{
+	"name", "name_index", "contact_info", "national_id_type", "national_id", "national_id_index",
+	"date_of_birth", "date_of_birth_index", "phone", "phone_index", "email", "employment_status", "employer", "monthly_income",
+	"kyc_reviewed_by", "kyc_rejection_reason", "anonymized_at",
+}
+
+type GormErasureRepository struct {
+	db *gorm.DB
+}
+
+func NewGormErasureRepository(db *gorm.DB) *GormErasureRepository {
+	return &GormErasureRepository{db: db}
+}
+
+// FindCandidates retrieves the borrowers not yet anonymized who were created
+// before the cutoff and have no open loan, as primary borrower or party,
+// nor any loan updated after it. With requestedOnly, only borrowers who
+// asked for erasure are returned.
+func (r *GormErasureRepository) FindCandidates(cutoff time.Time, requestedOnly bool) ([]models.Borrower, error) {
+	q := r.db.Where("anonymized_at IS NULL AND created_at <= ?", cutoff).
+		Where(`NOT EXISTS (SELECT 1 FROM loans WHERE loans.deleted_at IS NULL
+			AND (loans.borrower_id = borrowers.id OR loans.id IN (SELECT loan_id FROM loan_parties WHERE loan_parties.borrower_id = borrowers.id))
+			AND (loans.status IN ? OR loans.updated_at > ?))`,
+			[]string{models.LoanStatusActive, models.LoanStatusDefaulted}, cutoff)
+	if requestedOnly {
+		q = q.Where("erasure_requested_at IS NOT NULL")
+	}
+
+	var borrowers []models.Borrower
+	if err := q.Order("created_at").Find(&borrowers).Error; err != nil {
+		return nil, err
+	}
+	return borrowers, nil
+}
+
+// Anonymize erases a borrower's PII and that of the duplicates merged into
+// them. Their names are replaced, identifying columns cleared, addresses and
+// KYC documents deleted for good and merge snapshots dropped. Loans,
+// schedules and payments are left as they are.
+func (r *GormErasureRepository) Anonymize(borrowerID uuid.UUID, at time.Time) error {
+	ids := r.db.Unscoped().Model(&models.Borrower{}).Select("id").Where("id = ? OR merged_into_id = ?", borrowerID, borrowerID)
+
+	anonymized := models.Borrower{Name: models.AnonymizedName, AnonymizedAt: &at}
+	result := r.db.Unscoped().Model(&anonymized).Where("id = ? OR merged_into_id = ?", borrowerID, borrowerID).
+		Select(anonymizedColumns).UpdateColumns(&anonymized)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	if err := r.db.Unscoped().Where("borrower_id IN (?)", ids).Delete(&models.BorrowerAddress{}).Error; err != nil {
+		return err
+	}
+	if err := r.db.Where("borrower_id IN (?)", ids).Delete(&models.BorrowerDocument{}).Error; err != nil {
+		return err
+	}
+	return r.db.Model(&models.BorrowerMerge{}).Where("target_id = ? OR source_id IN (?)", borrowerID, ids).
+		UpdateColumn("source_snapshot", nil).Error
+}
@@ -0,0 +1,69 @@
+package repositories
+
+import (
+	"loan-billing-system/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type GormFeeRepository struct {
+	db *gorm.DB
+}
+
+func NewGormFeeRepository(db *gorm.DB) *GormFeeRepository {
+	return &GormFeeRepository{db: db}
+}
+
+// GetDefinitionsByProductCode retrieves the fees charged on a product's loans
+func (r *GormFeeRepository) GetDefinitionsByProductCode(productCode string) ([]models.FeeDefinition, error) {
+	var definitions []models.FeeDefinition
+	if err := r.db.Where("product_code = ?", productCode).Order("created_at").Find(&definitions).Error; err != nil {
+		return nil, err
+	}
+	return definitions, nil
+}
+
+// CreateDefinition creates a new fee definition
+func (r *GormFeeRepository) CreateDefinition(definition *models.FeeDefinition) error {
+	return r.db.Create(definition).Error
+}
+
+// DeleteDefinition removes a fee definition from a product. Fees already charged on loans are kept.
+func (r *GormFeeRepository) DeleteDefinition(productCode string, id uuid.UUID) error {
+	result := r.db.Where("product_code = ? AND id = ?", productCode, id).Delete(&models.FeeDefinition{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// GetByLoanID retrieves the fees charged on a loan
+func (r *GormFeeRepository) GetByLoanID(loanID uuid.UUID) ([]models.LoanFee, error) {
+	var fees []models.LoanFee
+	if err := r.db.Where("loan_id = ?", loanID).Order("created_at").Find(&fees).Error; err != nil {
+		return nil, err
+	}
+	return fees, nil
+}
+
+// CreateBatch records the fees charged on a loan
+func (r *GormFeeRepository) CreateBatch(fees []models.LoanFee) error {
+	if len(fees) == 0 {
+		return nil
+	}
+	return r.db.Create(&fees).Error
+}
+
+// SumUpfront totals the upfront fees charged on loans created in [from, to)
+func (r *GormFeeRepository) SumUpfront(from, to time.Time) (int64, error) {
+	var total int64
+	err := r.db.Model(&models.LoanFee{}).
+		Where("type IN ? AND created_at >= ? AND created_at < ?", []string{models.FeeTypeDeducted, models.FeeTypeCapitalized}, from, to).
+		Select("COALESCE(SUM(amount), 0)").Scan(&total).Error
+	return total, err
+}
@@ -0,0 +1,60 @@
+package repositories
+
+import (
+	"loan-billing-system/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type GormGroupRepository struct {
+	db *gorm.DB
+}
+
+func NewGormGroupRepository(db *gorm.DB) *GormGroupRepository {
+	return &GormGroupRepository{db: db}
+}
+
+// GetByID retrieves a loan group with its members in the order they joined
+func (r *GormGroupRepository) GetByID(id uuid.UUID) (*models.LoanGroup, error) {
+	var group models.LoanGroup
+	err := r.db.Preload("Members", func(db *gorm.DB) *gorm.DB {
+		return db.Order("created_at")
+	}).First(&group, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &group, nil
+}
+
+// Create creates a new loan group together with its members
+func (r *GormGroupRepository) Create(group *models.LoanGroup) error {
+	return r.db.Create(group).Error
+}
+
+// Update saves a loan group's own fields; members are managed separately
+func (r *GormGroupRepository) Update(group *models.LoanGroup) error {
+	return r.db.Omit("Members").Save(group).Error
+}
+
+// AddMember adds a borrower to a loan group
+func (r *GormGroupRepository) AddMember(member *models.LoanGroupMember) error {
+	return r.db.Create(member).Error
+}
+
+// RemoveMember removes a borrower from a loan group
+func (r *GormGroupRepository) RemoveMember(groupID, borrowerID uuid.UUID) error {
+	result := r.db.Where("group_id = ? AND borrower_id = ?", groupID, borrowerID).Delete(&models.LoanGroupMember{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// CreatePayment records a payment collected from a group
+func (r *GormGroupRepository) CreatePayment(payment *models.GroupPayment) error {
+	return r.db.Omit("Payments").Create(payment).Error
+}
@@ -0,0 +1,30 @@
+package repositories
+
+import (
+	"loan-billing-system/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type GormHolidayRepository struct {
+	db *gorm.DB
+}
+
+func NewGormHolidayRepository(db *gorm.DB) *GormHolidayRepository {
+	return &GormHolidayRepository{db: db}
+}
+
+// GetByLoanID retrieves the payment holidays granted on a loan, oldest first
+func (r *GormHolidayRepository) GetByLoanID(loanID uuid.UUID) ([]models.PaymentHoliday, error) {
+	var holidays []models.PaymentHoliday
+	if err := r.db.Where("loan_id = ?", loanID).Order("created_at").Find(&holidays).Error; err != nil {
+		return nil, err
+	}
+	return holidays, nil
+}
+
+// Create records a payment holiday
+func (r *GormHolidayRepository) Create(holiday *models.PaymentHoliday) error {
+	return r.db.Create(holiday).Error
+}
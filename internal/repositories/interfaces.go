@@ -1,6 +1,7 @@
 package repositories
 
 import (
+	"context"
 	"loan-billing-system/internal/models"
 	"time"
 
@@ -12,24 +13,32 @@ import (
 // BorrowerRepository defines the interface for borrower data access
 type BorrowerRepository interface {
 	GetByID(id uuid.UUID) (*models.Borrower, error)
-	GetAll() ([]models.Borrower, error)
-	GetDelinquent() ([]models.Borrower, error)
-	Create(name, contactInfo string) (*models.Borrower, error)
+	List(filter BorrowerFilter, page PageRequest) (Page[models.Borrower], error)
+	Create(borrower *models.Borrower) error
 	Update(borrower *models.Borrower) error
 	UpdateDelinquencyStatus(id uuid.UUID, isDelinquent bool) error
+	UpdateCreditLimits(id uuid.UUID, creditLimit *int64, maxConcurrentLoans *uint) error
+	FindMatches(match BorrowerMatch) ([]models.Borrower, error)
+	Delete(id uuid.UUID) error
 }
 
 // LoanRepository defines the interface for loan data access
 type LoanRepository interface {
 	GetByID(id uuid.UUID) (*models.Loan, error)
 	GetByBorrowerID(borrowerID uuid.UUID) ([]models.Loan, error)
+	GetByParty(borrowerID uuid.UUID) ([]models.Loan, error)
+	GetByGroupID(groupID uuid.UUID) ([]models.Loan, error)
 	GetAllActive() ([]models.Loan, error)
+	List(filter LoanFilter, page PageRequest) (Page[models.Loan], error)
 	Create(loan *models.Loan) error
 	Update(loan *models.Loan) error
 	UpdateStatus(id uuid.UUID, status string) error
 	UpdateBalance(id uuid.UUID, balance int64) error
+	UpdateDelinquency(id uuid.UUID, isDelinquent bool, daysPastDue uint) error
+	UpdateCurePaymentsRemaining(id uuid.UUID, remaining uint) error
 	UpdateLastPaymentDate(id uuid.UUID, date time.Time) error
 	GetPotentialDelinquent() ([]models.Loan, error)
+	GetEscalationCandidates(productCode string, statuses []string, minDaysPastDue uint) ([]models.Loan, error)
 }
 
 // ScheduleRepository defines the interface for schedule data access
@@ -37,10 +46,14 @@ type ScheduleRepository interface {
 	GetByID(id uuid.UUID) (*models.Schedule, error)
 	GetByLoanID(loanID uuid.UUID) ([]models.Schedule, error)
 	GetUnpaidByLoanID(loanID uuid.UUID) ([]models.Schedule, error)
+	GetNextUnpaidByLoanIDs(loanIDs []uuid.UUID) (map[uuid.UUID]models.Schedule, error)
+	GetByLoanIDAndVersion(loanID uuid.UUID, version uint) ([]models.Schedule, error)
 	Create(schedule *models.Schedule) error
 	CreateBatch(schedules []models.Schedule) error
+	UpdateBatch(schedules []models.Schedule) error
 	UpdatePaidStatus(id uuid.UUID, paid bool) error
 	CountUnpaidByLoanID(loanID uuid.UUID) (int64, error)
+	CancelUnpaidByLoanID(loanID uuid.UUID) error
 }
 
 // PaymentRepository defines the interface for payment data access
@@ -48,6 +61,148 @@ type PaymentRepository interface {
 	GetByID(id uuid.UUID) (*models.Payment, error)
 	GetByLoanID(loanID uuid.UUID) ([]models.Payment, error)
 	Create(payment *models.Payment) error
+	Update(payment *models.Payment) error
+	SumAllocations(from, to time.Time) (PaymentTotals, error)
+}
+
+// RestructureRepository defines the interface for loan restructuring history
+type RestructureRepository interface {
+	GetByLoanID(loanID uuid.UUID) ([]models.LoanRestructure, error)
+	Create(restructure *models.LoanRestructure) error
+}
+
+// HolidayRepository defines the interface for payment holiday history
+type HolidayRepository interface {
+	GetByLoanID(loanID uuid.UUID) ([]models.PaymentHoliday, error)
+	Create(holiday *models.PaymentHoliday) error
+}
+
+// RecoveryRepository defines the interface for recoveries on written-off loans
+type RecoveryRepository interface {
+	GetByLoanID(loanID uuid.UUID) ([]models.Recovery, error)
+	Create(recovery *models.Recovery) error
+}
+
+// ProductRepository defines the interface for loan product data access
+type ProductRepository interface {
+	GetByCode(code string) (*models.LoanProduct, error)
+	List() ([]models.LoanProduct, error)
+	Create(product *models.LoanProduct) error
+	Update(product *models.LoanProduct) error
+}
+
+// StatusChangeRepository defines the interface for the loan status change log
+type StatusChangeRepository interface {
+	GetByLoanID(loanID uuid.UUID) ([]models.LoanStatusChange, error)
+	Create(change *models.LoanStatusChange) error
+}
+
+// ChargeOffRepository defines the interface for the charge-off review queue
+type ChargeOffRepository interface {
+	GetByID(id uuid.UUID) (*models.ChargeOffProposal, error)
+	List(status string) ([]models.ChargeOffProposal, error)
+	ExistsForLoan(loanID uuid.UUID) (bool, error)
+	Create(proposal *models.ChargeOffProposal) error
+	Update(proposal *models.ChargeOffProposal) error
+}
+
+// FeeRepository defines the interface for product fee definitions and the fees charged on loans
+type FeeRepository interface {
+	GetDefinitionsByProductCode(productCode string) ([]models.FeeDefinition, error)
+	CreateDefinition(definition *models.FeeDefinition) error
+	DeleteDefinition(productCode string, id uuid.UUID) error
+	GetByLoanID(loanID uuid.UUID) ([]models.LoanFee, error)
+	CreateBatch(fees []models.LoanFee) error
+	SumUpfront(from, to time.Time) (int64, error)
+}
+
+// LoanPartyRepository defines the interface for the co-borrowers and guarantors of loans
+type LoanPartyRepository interface {
+	GetByLoanID(loanID uuid.UUID) ([]models.LoanParty, error)
+	GetByBorrowerID(borrowerID uuid.UUID) ([]models.LoanParty, error)
+	Create(party *models.LoanParty) error
+	CreateBatch(parties []models.LoanParty) error
+	Delete(loanID, borrowerID uuid.UUID) error
+}
+
+// GroupRepository defines the interface for loan groups, their members and group payments
+type GroupRepository interface {
+	GetByID(id uuid.UUID) (*models.LoanGroup, error)
+	Create(group *models.LoanGroup) error
+	Update(group *models.LoanGroup) error
+	AddMember(member *models.LoanGroupMember) error
+	RemoveMember(groupID, borrowerID uuid.UUID) error
+	CreatePayment(payment *models.GroupPayment) error
+}
+
+// CollateralRepository defines the interface for collateral and its valuations
+type CollateralRepository interface {
+	GetByID(id uuid.UUID) (*models.Collateral, error)
+	GetByLoanID(loanID uuid.UUID) ([]models.Collateral, error)
+	Create(collateral *models.Collateral) error
+	Update(collateral *models.Collateral) error
+	CreateValuation(valuation *models.CollateralValuation) error
+}
+
+// AssessmentRepository defines the interface for credit assessment data access
+type AssessmentRepository interface {
+	GetByLoanID(loanID uuid.UUID) (*models.CreditAssessment, error)
+	Create(assessment *models.CreditAssessment) error
+}
+
+// KYCRepository defines the interface for borrower addresses and KYC documents
+type KYCRepository interface {
+	CreateAddress(address *models.BorrowerAddress) error
+	DeleteAddress(borrowerID, id uuid.UUID) error
+	GetDocuments(borrowerID uuid.UUID) ([]models.BorrowerDocument, error)
+	CreateDocument(document *models.BorrowerDocument) error
+}
+
+// MergeRepository defines the interface for merging duplicate borrowers
+type MergeRepository interface {
+	Create(merge *models.BorrowerMerge) error
+	GetByTargetID(targetID uuid.UUID) ([]models.BorrowerMerge, error)
+	Reassign(sourceID, targetID uuid.UUID) (MergeCounts, error)
+}
+
+// MergeCounts are how many records a borrower merge moved
+type MergeCounts struct {
+	Loans   int64
+	Parties int64
+	Members int64
+}
+
+// ErasureRepository defines the interface for erasing borrower PII
+type ErasureRepository interface {
+	FindCandidates(cutoff time.Time, requestedOnly bool) ([]models.Borrower, error)
+	Anonymize(borrowerID uuid.UUID, at time.Time) error
+}
+
+// AuditRepository defines the interface for reading the audit log. Entries
+// are only ever written by the audit plugin, alongside the change they record.
+type AuditRepository interface {
+	List(filter AuditFilter, page PageRequest) (Page[models.AuditEntry], error)
+	GetChain(afterSequence int64, limit int) ([]models.AuditEntry, error)
+}
+
+// APIKeyRepository defines the interface for API key data access
+type APIKeyRepository interface {
+	Create(key *models.APIKey) error
+	GetByID(id uuid.UUID) (*models.APIKey, error)
+	GetByPrefix(prefix string) (*models.APIKey, error)
+	List() ([]models.APIKey, error)
+	Revoke(id uuid.UUID, revokedBy string, at time.Time) error
+	TouchLastUsed(id uuid.UUID, at time.Time) error
+}
+
+// TenantRepository defines the interface for tenants and their calendars
+type TenantRepository interface {
+	GetByID(id string) (*models.Tenant, error)
+	List() ([]models.Tenant, error)
+	Update(tenant *models.Tenant) error
+	GetHolidays(tenantID string) ([]models.CalendarHoliday, error)
+	CreateHoliday(holiday *models.CalendarHoliday) error
+	DeleteHoliday(id uuid.UUID) error
 }
 
 // RepositoryManager provides access to all repositories
@@ -56,5 +211,23 @@ type RepositoryManager interface {
 	Loans() LoanRepository
 	Schedules() ScheduleRepository
 	Payments() PaymentRepository
+	Restructures() RestructureRepository
+	Holidays() HolidayRepository
+	Recoveries() RecoveryRepository
+	Products() ProductRepository
+	StatusChanges() StatusChangeRepository
+	ChargeOffs() ChargeOffRepository
+	Fees() FeeRepository
+	Parties() LoanPartyRepository
+	Groups() GroupRepository
+	Collateral() CollateralRepository
+	Assessments() AssessmentRepository
+	KYC() KYCRepository
+	Merges() MergeRepository
+	Erasures() ErasureRepository
+	Audit() AuditRepository
+	APIKeys() APIKeyRepository
+	Tenants() TenantRepository
+	WithContext(ctx context.Context) RepositoryManager
 	WithTransaction(fn func(repo RepositoryManager) error) error
 }
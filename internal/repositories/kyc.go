@@ -0,0 +1,47 @@
+package repositories
+
+import (
+	"loan-billing-system/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type GormKYCRepository struct {
+	db *gorm.DB
+}
+
+func NewGormKYCRepository(db *gorm.DB) *GormKYCRepository {
+	return &GormKYCRepository{db: db}
+}
+
+// CreateAddress adds an address to a borrower
+func (r *GormKYCRepository) CreateAddress(address *models.BorrowerAddress) error {
+	return r.db.Create(address).Error
+}
+
+// DeleteAddress removes one of a borrower's addresses
+func (r *GormKYCRepository) DeleteAddress(borrowerID, id uuid.UUID) error {
+	result := r.db.Where("borrower_id = ? AND id = ?", borrowerID, id).Delete(&models.BorrowerAddress{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// GetDocuments retrieves a borrower's KYC documents, oldest first
+func (r *GormKYCRepository) GetDocuments(borrowerID uuid.UUID) ([]models.BorrowerDocument, error) {
+	var documents []models.BorrowerDocument
+	if err := r.db.Where("borrower_id = ?", borrowerID).Order("created_at").Find(&documents).Error; err != nil {
+		return nil, err
+	}
+	return documents, nil
+}
+
+// CreateDocument records a KYC document's metadata
+func (r *GormKYCRepository) CreateDocument(document *models.BorrowerDocument) error {
+	return r.db.Create(document).Error
+}
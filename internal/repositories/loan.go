@@ -6,6 +6,7 @@ import (
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type GormLoanRepository struct {
@@ -34,23 +35,114 @@ func (r *GormLoanRepository) GetByBorrowerID(borrowerID uuid.UUID) ([]models.Loa
 	return loans, nil
 }
 
+// GetByParty retrieves the loans a borrower is on, either as the primary borrower or as an additional party
+func (r *GormLoanRepository) GetByParty(borrowerID uuid.UUID) ([]models.Loan, error) {
+	var loans []models.Loan
+	err := r.db.Where("borrower_id = ? OR id IN (?)", borrowerID,
+		r.db.Model(&models.LoanParty{}).Select("loan_id").Where("borrower_id = ?", borrowerID)).
+		Order("created_at").Find(&loans).Error
+	if err != nil {
+		return nil, err
+	}
+	return loans, nil
+}
+
+// GetByGroupID retrieves the loans held through a loan group, oldest first
+func (r *GormLoanRepository) GetByGroupID(groupID uuid.UUID) ([]models.Loan, error) {
+	var loans []models.Loan
+	if err := r.db.Where("group_id = ?", groupID).Order("created_at").Find(&loans).Error; err != nil {
+		return nil, err
+	}
+	return loans, nil
+}
+
 // GetAllActive retrieves all active loans
 func (r *GormLoanRepository) GetAllActive() ([]models.Loan, error) {
 	var loans []models.Loan
-	if err := r.db.Where("status = ?", "active").Find(&loans).Error; err != nil {
+	if err := r.db.Where("status = ?", models.LoanStatusActive).Find(&loans).Error; err != nil {
 		return nil, err
 	}
 	return loans, nil
 }
 
+// LoanFilter narrows down a loan listing
+type LoanFilter struct {
+	Status       string
+	BorrowerID   *uuid.UUID
+	ProductCode  string
+	IsDelinquent *bool
+	DPDBucket    string
+	CreatedFrom  *time.Time
+	CreatedTo    *time.Time
+	MinBalance   *int64
+	MaxBalance   *int64
+	OnHoliday    *bool
+}
+
+// loanSortKeys lists the fields a loan listing can be sorted by
+var loanSortKeys = map[string]sortKey[models.Loan]{
+	"created_at":      {column: "loans.created_at", parse: parseTimeSortValue, value: func(l *models.Loan) any { return l.CreatedAt }},
+	"current_balance": {column: "loans.current_balance", parse: parseIntSortValue, value: func(l *models.Loan) any { return l.CurrentBalance }},
+	"days_past_due":   {column: "loans.days_past_due", parse: parseIntSortValue, value: func(l *models.Loan) any { return l.DaysPastDue }},
+}
+
+// List retrieves one page of loans matching the filter
+func (r *GormLoanRepository) List(filter LoanFilter, page PageRequest) (Page[models.Loan], error) {
+	q := r.db.Model(&models.Loan{})
+
+	if filter.Status != "" {
+		q = q.Where("loans.status = ?", filter.Status)
+	}
+	if filter.BorrowerID != nil {
+		q = q.Where("loans.borrower_id = ?", *filter.BorrowerID)
+	}
+	if filter.ProductCode != "" {
+		q = q.Where("loans.product_code = ?", filter.ProductCode)
+	}
+	if filter.IsDelinquent != nil {
+		q = q.Where("loans.is_delinquent = ?", *filter.IsDelinquent)
+	}
+	if filter.DPDBucket != "" {
+		min, max, ok := models.DPDBucketRange(filter.DPDBucket)
+		if !ok {
+			return Page[models.Loan]{}, ErrInvalidFilter
+		}
+		q = q.Where("loans.days_past_due >= ?", min)
+		if max > 0 || filter.DPDBucket == models.DPDBucketCurrent {
+			q = q.Where("loans.days_past_due <= ?", max)
+		}
+	}
+	if filter.CreatedFrom != nil {
+		q = q.Where("loans.created_at >= ?", *filter.CreatedFrom)
+	}
+	if filter.CreatedTo != nil {
+		q = q.Where("loans.created_at < ?", *filter.CreatedTo)
+	}
+	if filter.MinBalance != nil {
+		q = q.Where("loans.current_balance >= ?", *filter.MinBalance)
+	}
+	if filter.MaxBalance != nil {
+		q = q.Where("loans.current_balance <= ?", *filter.MaxBalance)
+	}
+	if filter.OnHoliday != nil {
+		if *filter.OnHoliday {
+			q = q.Where("loans.holiday_until > ?", time.Now())
+		} else {
+			q = q.Where("loans.holiday_until IS NULL OR loans.holiday_until <= ?", time.Now())
+		}
+	}
+
+	return paginate(q, "loans", page, loanSortKeys, "created_at", func(l *models.Loan) uuid.UUID { return l.ID })
+}
+
 // Create creates a new loan
 func (r *GormLoanRepository) Create(loan *models.Loan) error {
 	return r.db.Create(loan).Error
 }
 
-// Update updates a loan
+// Update updates a loan, leaving its borrower, schedules and payments untouched
 func (r *GormLoanRepository) Update(loan *models.Loan) error {
-	return r.db.Save(loan).Error
+	return r.db.Omit(clause.Associations).Save(loan).Error
 }
 
 // UpdateStatus updates a loan's status
@@ -65,13 +157,37 @@ func (r *GormLoanRepository) UpdateBalance(id uuid.UUID, balance int64) error {
 		Update("current_balance", balance).Error
 }
 
+// UpdateDelinquency stores the result of the latest delinquency evaluation
+func (r *GormLoanRepository) UpdateDelinquency(id uuid.UUID, isDelinquent bool, daysPastDue uint) error {
+	return r.db.Model(&models.Loan{}).Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"is_delinquent": isDelinquent,
+			"days_past_due": daysPastDue,
+		}).Error
+}
+
+// UpdateCurePaymentsRemaining updates how many payments are left in a loan's cure period
+func (r *GormLoanRepository) UpdateCurePaymentsRemaining(id uuid.UUID, remaining uint) error {
+	return r.db.Model(&models.Loan{}).Where("id = ?", id).
+		Update("cure_payments_remaining", remaining).Error
+}
+
 // UpdateLastPaymentDate updates the last payment date of a loan
 func (r *GormLoanRepository) UpdateLastPaymentDate(id uuid.UUID, date time.Time) error {
 	return r.db.Model(&models.Loan{}).Where("id = ?", id).
 		Update("last_payment_date", date).Error
 }
 
-// GetPotentialDelinquent retrieves active loans that haven't received a payment recently
+// GetEscalationCandidates retrieves loans of a product in one of the given
+// statuses whose days past due have reached a threshold
+func (r *GormLoanRepository) GetEscalationCandidates(productCode string, statuses []string, minDaysPastDue uint) ([]models.Loan, error) {
+	var loans []models.Loan
+	err := r.db.Where("product_code = ? AND status IN ? AND days_past_due >= ?", productCode, statuses, minDaysPastDue).
+		Order("days_past_due DESC").Find(&loans).Error
+	return loans, err
+}
+
+// GetPotentialDelinquent retrieves active or defaulted loans that haven't received a payment recently
 func (r *GormLoanRepository) GetPotentialDelinquent() ([]models.Loan, error) {
 	var loans []models.Loan
 
@@ -82,10 +198,12 @@ func (r *GormLoanRepository) GetPotentialDelinquent() ([]models.Loan, error) {
 	// This means we only check loans that haven't had a payment in the last 2 weeks
 	cutoffDate := now.AddDate(0, 0, -14)
 
-	// Get loans that are active and either have no payments or
-	// haven't had a payment since the cutoff date
-	err := r.db.Where("status = ? AND (last_payment_date IS NULL OR last_payment_date < ?)",
-		"active", cutoffDate).Find(&loans).Error
+	// Get loans still being repaid that either have no payments or
+	// haven't had a payment since the cutoff date, skipping loans on a payment holiday
+	err := r.db.Where("status IN ? AND (last_payment_date IS NULL OR last_payment_date < ?)",
+		[]string{models.LoanStatusActive, models.LoanStatusDefaulted}, cutoffDate).
+		Where("holiday_until IS NULL OR holiday_until <= ?", now).
+		Find(&loans).Error
 
 	return loans, err
 }
@@ -0,0 +1,78 @@
+package repositories
+
+import (
+	"loan-billing-system/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type GormMergeRepository struct {
+	db *gorm.DB
+}
+
+func NewGormMergeRepository(db *gorm.DB) *GormMergeRepository {
+	return &GormMergeRepository{db: db}
+}
+
+// Create records a borrower merge
+func (r *GormMergeRepository) Create(merge *models.BorrowerMerge) error {
+	return r.db.Create(merge).Error
+}
+
+// GetByTargetID retrieves the merges into a borrower, newest first
+func (r *GormMergeRepository) GetByTargetID(targetID uuid.UUID) ([]models.BorrowerMerge, error) {
+	var merges []models.BorrowerMerge
+	if err := r.db.Where("target_id = ?", targetID).Order("created_at DESC").Find(&merges).Error; err != nil {
+		return nil, err
+	}
+	return merges, nil
+}
+
+// Reassign moves everything a borrower owns or takes part in to another
+// borrower: loans, loan parties, group memberships and leadership, addresses,
+// KYC documents and credit assessments. Party and group records the target
+// already holds are dropped rather than duplicated, as are parties on loans
+// the target becomes the primary borrower of.
+func (r *GormMergeRepository) Reassign(sourceID, targetID uuid.UUID) (MergeCounts, error) {
+	var counts MergeCounts
+
+	result := r.db.Model(&models.Loan{}).Where("borrower_id = ?", sourceID).Update("borrower_id", targetID)
+	if result.Error != nil {
+		return counts, result.Error
+	}
+	counts.Loans = result.RowsAffected
+
+	if err := r.db.Where("borrower_id = ? AND (loan_id IN (?) OR loan_id IN (?))", sourceID,
+		r.db.Model(&models.LoanParty{}).Select("loan_id").Where("borrower_id = ?", targetID),
+		r.db.Model(&models.Loan{}).Select("id").Where("borrower_id = ?", targetID),
+	).Delete(&models.LoanParty{}).Error; err != nil {
+		return counts, err
+	}
+	result = r.db.Model(&models.LoanParty{}).Where("borrower_id = ?", sourceID).Update("borrower_id", targetID)
+	if result.Error != nil {
+		return counts, result.Error
+	}
+	counts.Parties = result.RowsAffected
+
+	if err := r.db.Where("borrower_id = ? AND group_id IN (?)", sourceID,
+		r.db.Model(&models.LoanGroupMember{}).Select("group_id").Where("borrower_id = ?", targetID),
+	).Delete(&models.LoanGroupMember{}).Error; err != nil {
+		return counts, err
+	}
+	result = r.db.Model(&models.LoanGroupMember{}).Where("borrower_id = ?", sourceID).Update("borrower_id", targetID)
+	if result.Error != nil {
+		return counts, result.Error
+	}
+	counts.Members = result.RowsAffected
+	if err := r.db.Model(&models.LoanGroup{}).Where("leader_id = ?", sourceID).Update("leader_id", targetID).Error; err != nil {
+		return counts, err
+	}
+
+	for _, model := range []any{&models.BorrowerAddress{}, &models.BorrowerDocument{}, &models.CreditAssessment{}} {
+		if err := r.db.Model(model).Where("borrower_id = ?", sourceID).Update("borrower_id", targetID).Error; err != nil {
+			return counts, err
+		}
+	}
+	return counts, nil
+}
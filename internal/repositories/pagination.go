@@ -0,0 +1,186 @@
+package repositories
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	// DefaultPageLimit is used when a list request does not specify a limit
+	DefaultPageLimit = 20
+	// MaxPageLimit caps the number of rows returned in a single page
+	MaxPageLimit = 100
+)
+
+var (
+	// ErrInvalidCursor is returned when a pagination cursor cannot be decoded
+	ErrInvalidCursor = errors.New("invalid cursor")
+	// ErrInvalidSort is returned when a list is sorted by an unsupported field
+	ErrInvalidSort = errors.New("invalid sort field")
+	// ErrInvalidFilter is returned when a list filter has an unsupported value
+	ErrInvalidFilter = errors.New("invalid filter value")
+)
+
+// PageRequest describes which page of a list to fetch
+type PageRequest struct {
+	Limit  int
+	Cursor string
+	SortBy string
+	Desc   bool
+}
+
+// Page holds one page of results and the cursor pointing at the next one
+type Page[T any] struct {
+	Items      []T
+	NextCursor string
+	Limit      int
+}
+
+// HasMore reports whether there are more rows after this page
+func (p Page[T]) HasMore() bool {
+	return p.NextCursor != ""
+}
+
+// sortKey describes a column a list can be ordered by. parse converts the
+// cursor representation back into a typed value; nil keeps it a string.
+type sortKey[T any] struct {
+	column string
+	parse  func(value string) (any, error)
+	value  func(row *T) any
+}
+
+// parseTimeSortValue parses a cursor value of a timestamp column
+func parseTimeSortValue(value string) (any, error) {
+	return time.Parse(time.RFC3339Nano, value)
+}
+
+// parseIntSortValue parses a cursor value of an integer column
+func parseIntSortValue(value string) (any, error) {
+	return strconv.ParseInt(value, 10, 64)
+}
+
+// cursor is the opaque position handed back to clients as next_cursor.
+// It holds the sort field, the sort value and the ID of the last row so the
+// next page can continue with a keyset condition instead of an OFFSET.
+type cursor struct {
+	Sort  string    `json:"s"`
+	Desc  bool      `json:"d"`
+	Value string    `json:"v"`
+	ID    uuid.UUID `json:"id"`
+}
+
+func encodeCursor(c cursor) string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeCursor(s string) (*cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var c cursor
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &c, nil
+}
+
+// normalizeLimit clamps a requested page size to the allowed range
+func normalizeLimit(limit int) int {
+	if limit <= 0 {
+		return DefaultPageLimit
+	}
+	if limit > MaxPageLimit {
+		return MaxPageLimit
+	}
+	return limit
+}
+
+// formatSortValue turns a sort column value into its cursor representation
+func formatSortValue(v any) string {
+	switch val := v.(type) {
+	case time.Time:
+		return val.UTC().Format(time.RFC3339Nano)
+	case *time.Time:
+		if val == nil {
+			return ""
+		}
+		return val.UTC().Format(time.RFC3339Nano)
+	default:
+		return fmt.Sprint(val)
+	}
+}
+
+// paginate applies keyset pagination to a query and fetches one page.
+// Rows are ordered by the sort column and then by ID so that the order is
+// stable even when many rows share the same sort value.
+func paginate[T any](q *gorm.DB, table string, page PageRequest, keys map[string]sortKey[T], defaultSort string, idOf func(row *T) uuid.UUID) (Page[T], error) {
+	sortBy := page.SortBy
+	if sortBy == "" {
+		sortBy = defaultSort
+	}
+	key, ok := keys[sortBy]
+	if !ok {
+		return Page[T]{}, ErrInvalidSort
+	}
+	limit := normalizeLimit(page.Limit)
+
+	idColumn := table + ".id"
+	dir, op := "ASC", ">"
+	if page.Desc {
+		dir, op = "DESC", "<"
+	}
+
+	if page.Cursor != "" {
+		c, err := decodeCursor(page.Cursor)
+		if err != nil {
+			return Page[T]{}, err
+		}
+		if c.Sort != sortBy || c.Desc != page.Desc {
+			return Page[T]{}, ErrInvalidCursor
+		}
+
+		var value any = c.Value
+		if key.parse != nil {
+			if value, err = key.parse(c.Value); err != nil {
+				return Page[T]{}, ErrInvalidCursor
+			}
+		}
+
+		q = q.Where(
+			fmt.Sprintf("((%s %s ?) OR (%s = ? AND %s %s ?))", key.column, op, key.column, idColumn, op),
+			value, value, c.ID,
+		)
+	}
+
+	var rows []T
+	err := q.Order(fmt.Sprintf("%s %s", key.column, dir)).
+		Order(fmt.Sprintf("%s %s", idColumn, dir)).
+		Limit(limit + 1).
+		Find(&rows).Error
+	if err != nil {
+		return Page[T]{}, err
+	}
+
+	result := Page[T]{Items: rows, Limit: limit}
+	if len(rows) > limit {
+		result.Items = rows[:limit]
+		last := &result.Items[limit-1]
+		result.NextCursor = encodeCursor(cursor{
+			Sort:  sortBy,
+			Desc:  page.Desc,
+			Value: formatSortValue(key.value(last)),
+			ID:    idOf(last),
+		})
+	}
+
+	return result, nil
+}
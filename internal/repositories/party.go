@@ -0,0 +1,59 @@
+package repositories
+
+import (
+	"loan-billing-system/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type GormLoanPartyRepository struct {
+	db *gorm.DB
+}
+
+func NewGormLoanPartyRepository(db *gorm.DB) *GormLoanPartyRepository {
+	return &GormLoanPartyRepository{db: db}
+}
+
+// GetByLoanID retrieves the additional parties of a loan in the order they were added
+func (r *GormLoanPartyRepository) GetByLoanID(loanID uuid.UUID) ([]models.LoanParty, error) {
+	var parties []models.LoanParty
+	if err := r.db.Where("loan_id = ?", loanID).Order("created_at").Find(&parties).Error; err != nil {
+		return nil, err
+	}
+	return parties, nil
+}
+
+// GetByBorrowerID retrieves every loan party record of a borrower
+func (r *GormLoanPartyRepository) GetByBorrowerID(borrowerID uuid.UUID) ([]models.LoanParty, error) {
+	var parties []models.LoanParty
+	if err := r.db.Where("borrower_id = ?", borrowerID).Find(&parties).Error; err != nil {
+		return nil, err
+	}
+	return parties, nil
+}
+
+// Create adds a party to a loan
+func (r *GormLoanPartyRepository) Create(party *models.LoanParty) error {
+	return r.db.Create(party).Error
+}
+
+// CreateBatch adds several parties to a loan
+func (r *GormLoanPartyRepository) CreateBatch(parties []models.LoanParty) error {
+	if len(parties) == 0 {
+		return nil
+	}
+	return r.db.Create(&parties).Error
+}
+
+// Delete removes a borrower from a loan's parties
+func (r *GormLoanPartyRepository) Delete(loanID, borrowerID uuid.UUID) error {
+	result := r.db.Where("loan_id = ? AND borrower_id = ?", loanID, borrowerID).Delete(&models.LoanParty{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"loan-billing-system/internal/models"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -37,3 +38,26 @@ func (r *GormPaymentRepository) GetByLoanID(loanID uuid.UUID) ([]models.Payment,
 func (r *GormPaymentRepository) Create(payment *models.Payment) error {
 	return r.db.Create(payment).Error
 }
+
+// Update updates an existing payment
+func (r *GormPaymentRepository) Update(payment *models.Payment) error {
+	return r.db.Save(payment).Error
+}
+
+// PaymentTotals are the sums of the payments received over a period, by what they paid off
+type PaymentTotals struct {
+	Principal int64
+	Interest  int64
+	Fees      int64
+}
+
+// SumAllocations totals the principal, interest and fee portions of payments made in [from, to),
+// leaving out reversed payments
+func (r *GormPaymentRepository) SumAllocations(from, to time.Time) (PaymentTotals, error) {
+	var totals PaymentTotals
+	err := r.db.Model(&models.Payment{}).
+		Where("payment_date >= ? AND payment_date < ? AND reversed_at IS NULL", from, to).
+		Select("COALESCE(SUM(principal), 0) AS principal, COALESCE(SUM(interest), 0) AS interest, COALESCE(SUM(fees), 0) AS fees").
+		Scan(&totals).Error
+	return totals, err
+}
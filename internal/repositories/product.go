@@ -0,0 +1,43 @@
+package repositories
+
+import (
+	"loan-billing-system/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type GormProductRepository struct {
+	db *gorm.DB
+}
+
+func NewGormProductRepository(db *gorm.DB) *GormProductRepository {
+	return &GormProductRepository{db: db}
+}
+
+// GetByCode retrieves a loan product by its code
+func (r *GormProductRepository) GetByCode(code string) (*models.LoanProduct, error) {
+	var product models.LoanProduct
+	if err := r.db.Where("code = ?", code).First(&product).Error; err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+// List retrieves every loan product ordered by code
+func (r *GormProductRepository) List() ([]models.LoanProduct, error) {
+	var products []models.LoanProduct
+	if err := r.db.Order("code").Find(&products).Error; err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+// Create creates a new loan product
+func (r *GormProductRepository) Create(product *models.LoanProduct) error {
+	return r.db.Create(product).Error
+}
+
+// Update saves a loan product
+func (r *GormProductRepository) Update(product *models.LoanProduct) error {
+	return r.db.Save(product).Error
+}
@@ -0,0 +1,30 @@
+package repositories
+
+import (
+	"loan-billing-system/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type GormRecoveryRepository struct {
+	db *gorm.DB
+}
+
+func NewGormRecoveryRepository(db *gorm.DB) *GormRecoveryRepository {
+	return &GormRecoveryRepository{db: db}
+}
+
+// GetByLoanID retrieves the recoveries collected on a loan, oldest first
+func (r *GormRecoveryRepository) GetByLoanID(loanID uuid.UUID) ([]models.Recovery, error) {
+	var recoveries []models.Recovery
+	if err := r.db.Where("loan_id = ?", loanID).Order("recovery_date").Find(&recoveries).Error; err != nil {
+		return nil, err
+	}
+	return recoveries, nil
+}
+
+// Create records a recovery
+func (r *GormRecoveryRepository) Create(recovery *models.Recovery) error {
+	return r.db.Create(recovery).Error
+}
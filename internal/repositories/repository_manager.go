@@ -1,26 +1,62 @@
 package repositories
 
 import (
+	"context"
+
 	"gorm.io/gorm"
 )
 
 //Centralized repo
 
 type GormRepositoryManager struct {
-	db                 *gorm.DB
-	borrowerRepository BorrowerRepository
-	loanRepository     LoanRepository
-	scheduleRepository ScheduleRepository
-	paymentRepository  PaymentRepository
+	db                     *gorm.DB
+	borrowerRepository     BorrowerRepository
+	loanRepository         LoanRepository
+	scheduleRepository     ScheduleRepository
+	paymentRepository      PaymentRepository
+	restructureRepository  RestructureRepository
+	holidayRepository      HolidayRepository
+	recoveryRepository     RecoveryRepository
+	productRepository      ProductRepository
+	statusChangeRepository StatusChangeRepository
+	chargeOffRepository    ChargeOffRepository
+	feeRepository          FeeRepository
+	partyRepository        LoanPartyRepository
+	groupRepository        GroupRepository
+	collateralRepository   CollateralRepository
+	assessmentRepository   AssessmentRepository
+	kycRepository          KYCRepository
+	mergeRepository        MergeRepository
+	erasureRepository      ErasureRepository
+	auditRepository        AuditRepository
+	apiKeyRepository       APIKeyRepository
+	tenantRepository       TenantRepository
 }
 
 func NewGormRepositoryManager(db *gorm.DB) *GormRepositoryManager {
 	return &GormRepositoryManager{
-		db:                 db,
-		borrowerRepository: NewGormBorrowerRepository(db),
-		loanRepository:     NewGormLoanRepository(db),
-		scheduleRepository: NewGormScheduleRepository(db),
-		paymentRepository:  NewGormPaymentRepository(db),
+		db:                     db,
+		borrowerRepository:     NewGormBorrowerRepository(db),
+		loanRepository:         NewGormLoanRepository(db),
+		scheduleRepository:     NewGormScheduleRepository(db),
+		paymentRepository:      NewGormPaymentRepository(db),
+		restructureRepository:  NewGormRestructureRepository(db),
+		holidayRepository:      NewGormHolidayRepository(db),
+		recoveryRepository:     NewGormRecoveryRepository(db),
+		productRepository:      NewGormProductRepository(db),
+		statusChangeRepository: NewGormStatusChangeRepository(db),
+		chargeOffRepository:    NewGormChargeOffRepository(db),
+		feeRepository:          NewGormFeeRepository(db),
+		partyRepository:        NewGormLoanPartyRepository(db),
+		groupRepository:        NewGormGroupRepository(db),
+		collateralRepository:   NewGormCollateralRepository(db),
+		assessmentRepository:   NewGormAssessmentRepository(db),
+		kycRepository:          NewGormKYCRepository(db),
+		mergeRepository:        NewGormMergeRepository(db),
+		erasureRepository:      NewGormErasureRepository(db),
+		auditRepository:        NewGormAuditRepository(db),
+		apiKeyRepository:       NewGormAPIKeyRepository(db),
+		tenantRepository:       NewGormTenantRepository(db),
 	}
 }
 
@@ -44,19 +80,102 @@ func (r *GormRepositoryManager) Payments() PaymentRepository {
 	return r.paymentRepository
 }
 
+// Restructures returns the restructure repository
+func (r *GormRepositoryManager) Restructures() RestructureRepository {
+	return r.restructureRepository
+}
+
+// Holidays returns the payment holiday repository
+func (r *GormRepositoryManager) Holidays() HolidayRepository {
+	return r.holidayRepository
+}
+
+// Recoveries returns the recovery repository
+func (r *GormRepositoryManager) Recoveries() RecoveryRepository {
+	return r.recoveryRepository
+}
+
+// Products returns the loan product repository
+func (r *GormRepositoryManager) Products() ProductRepository {
+	return r.productRepository
+}
+
+// StatusChanges returns the loan status change repository
+func (r *GormRepositoryManager) StatusChanges() StatusChangeRepository {
+	return r.statusChangeRepository
+}
+
+// ChargeOffs returns the charge-off proposal repository
+func (r *GormRepositoryManager) ChargeOffs() ChargeOffRepository {
+	return r.chargeOffRepository
+}
+
+// Fees returns the fee repository
+func (r *GormRepositoryManager) Fees() FeeRepository {
+	return r.feeRepository
+}
+
+// Parties returns the loan party repository
+func (r *GormRepositoryManager) Parties() LoanPartyRepository {
+	return r.partyRepository
+}
+
+// Groups returns the loan group repository
+func (r *GormRepositoryManager) Groups() GroupRepository {
+	return r.groupRepository
+}
+
+// Collateral returns the collateral repository
+func (r *GormRepositoryManager) Collateral() CollateralRepository {
+	return r.collateralRepository
+}
+
+// Assessments returns the credit assessment repository
+func (r *GormRepositoryManager) Assessments() AssessmentRepository {
+	return r.assessmentRepository
+}
+
+// KYC returns the borrower address and KYC document repository
+func (r *GormRepositoryManager) KYC() KYCRepository {
+	return r.kycRepository
+}
+
+// Merges returns the borrower merge repository
+func (r *GormRepositoryManager) Merges() MergeRepository {
+	return r.mergeRepository
+}
+
+// Erasures returns the borrower erasure repository
+func (r *GormRepositoryManager) Erasures() ErasureRepository {
+	return r.erasureRepository
+}
+
+// Audit returns the audit log repository
+func (r *GormRepositoryManager) Audit() AuditRepository {
+	return r.auditRepository
+}
+
+// APIKeys returns the API key repository
+func (r *GormRepositoryManager) APIKeys() APIKeyRepository {
+	return r.apiKeyRepository
+}
+
+// Tenants returns the tenant repository
+func (r *GormRepositoryManager) Tenants() TenantRepository {
+	return r.tenantRepository
+}
+
+// WithContext returns a repository manager whose queries run with ctx, so
+// that they are scoped to its tenant and the changes they make are audited
+// with its actor and request ID
+func (r *GormRepositoryManager) WithContext(ctx context.Context) RepositoryManager {
+	return NewGormRepositoryManager(r.db.WithContext(ctx))
+}
+
 // WithTransaction runs a function within a database transaction
 func (r *GormRepositoryManager) WithTransaction(fn func(repo RepositoryManager) error) error {
 	return r.db.Transaction(func(tx *gorm.DB) error {
-		// Create a new repository manager with the transaction
-		txRepo := &GormRepositoryManager{
-			db:                 tx,
-			borrowerRepository: NewGormBorrowerRepository(tx),
-			loanRepository:     NewGormLoanRepository(tx),
-			scheduleRepository: NewGormScheduleRepository(tx),
-			paymentRepository:  NewGormPaymentRepository(tx),
-		}
-
-		// Run the provided function with the transaction-aware repository
-		return fn(txRepo)
+		// Run the provided function with a transaction-aware repository manager
+		return fn(NewGormRepositoryManager(tx))
 	})
 }
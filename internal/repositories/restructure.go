@@ -0,0 +1,30 @@
+package repositories
+
+import (
+	"loan-billing-system/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type GormRestructureRepository struct {
+	db *gorm.DB
+}
+
+func NewGormRestructureRepository(db *gorm.DB) *GormRestructureRepository {
+	return &GormRestructureRepository{db: db}
+}
+
+// GetByLoanID retrieves the restructuring history of a loan, oldest first
+func (r *GormRestructureRepository) GetByLoanID(loanID uuid.UUID) ([]models.LoanRestructure, error) {
+	var restructures []models.LoanRestructure
+	if err := r.db.Where("loan_id = ?", loanID).Order("created_at").Find(&restructures).Error; err != nil {
+		return nil, err
+	}
+	return restructures, nil
+}
+
+// Create records a restructuring
+func (r *GormRestructureRepository) Create(restructure *models.LoanRestructure) error {
+	return r.db.Create(restructure).Error
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type GormScheduleRepository struct {
@@ -24,10 +25,10 @@ func (r *GormScheduleRepository) GetByID(id uuid.UUID) (*models.Schedule, error)
 	return &schedule, nil
 }
 
-// GetByLoanID retrieves schedules by loan ID
+// GetByLoanID retrieves the current schedules of a loan, leaving out cancelled ones
 func (r *GormScheduleRepository) GetByLoanID(loanID uuid.UUID) ([]models.Schedule, error) {
 	var schedules []models.Schedule
-	if err := r.db.Where("loan_id = ?", loanID).Order("week_number").Find(&schedules).Error; err != nil {
+	if err := r.db.Where("loan_id = ? AND cancelled = ?", loanID, false).Order("version, week_number").Find(&schedules).Error; err != nil {
 		return nil, err
 	}
 	return schedules, nil
@@ -36,7 +37,37 @@ func (r *GormScheduleRepository) GetByLoanID(loanID uuid.UUID) ([]models.Schedul
 // GetUnpaidByLoanID retrieves unpaid schedules by loan ID
 func (r *GormScheduleRepository) GetUnpaidByLoanID(loanID uuid.UUID) ([]models.Schedule, error) {
 	var schedules []models.Schedule
-	if err := r.db.Where("loan_id = ? AND paid = ?", loanID, false).Order("week_number").Find(&schedules).Error; err != nil {
+	if err := r.db.Where("loan_id = ? AND paid = ? AND cancelled = ?", loanID, false, false).Order("version, week_number").Find(&schedules).Error; err != nil {
+		return nil, err
+	}
+	return schedules, nil
+}
+
+// GetNextUnpaidByLoanIDs retrieves the earliest unpaid schedule of each loan, keyed by loan ID
+func (r *GormScheduleRepository) GetNextUnpaidByLoanIDs(loanIDs []uuid.UUID) (map[uuid.UUID]models.Schedule, error) {
+	next := make(map[uuid.UUID]models.Schedule, len(loanIDs))
+	if len(loanIDs) == 0 {
+		return next, nil
+	}
+
+	var schedules []models.Schedule
+	err := r.db.Where("loan_id IN ? AND paid = ? AND cancelled = ?", loanIDs, false, false).
+		Where("week_number = (SELECT MIN(s2.week_number) FROM schedules s2 WHERE s2.loan_id = schedules.loan_id AND s2.paid = ? AND s2.cancelled = ? AND s2.deleted_at IS NULL)", false, false).
+		Find(&schedules).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, schedule := range schedules {
+		next[schedule.LoanID] = schedule
+	}
+	return next, nil
+}
+
+// GetByLoanIDAndVersion retrieves every schedule of one version of a loan's schedule, including cancelled ones
+func (r *GormScheduleRepository) GetByLoanIDAndVersion(loanID uuid.UUID, version uint) ([]models.Schedule, error) {
+	var schedules []models.Schedule
+	if err := r.db.Where("loan_id = ? AND version = ?", loanID, version).Order("week_number").Find(&schedules).Error; err != nil {
 		return nil, err
 	}
 	return schedules, nil
@@ -52,6 +83,16 @@ func (r *GormScheduleRepository) CreateBatch(schedules []models.Schedule) error
 	return r.db.Create(&schedules).Error
 }
 
+// UpdateBatch saves changes to multiple schedules, leaving their loan untouched
+func (r *GormScheduleRepository) UpdateBatch(schedules []models.Schedule) error {
+	for i := range schedules {
+		if err := r.db.Omit(clause.Associations).Save(&schedules[i]).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // UpdatePaidStatus updates a schedule's paid status
 func (r *GormScheduleRepository) UpdatePaidStatus(id uuid.UUID, paid bool) error {
 	return r.db.Model(&models.Schedule{}).Where("id = ?", id).
@@ -61,6 +102,12 @@ func (r *GormScheduleRepository) UpdatePaidStatus(id uuid.UUID, paid bool) error
 // CountUnpaidByLoanID counts the number of unpaid schedules for a loan
 func (r *GormScheduleRepository) CountUnpaidByLoanID(loanID uuid.UUID) (int64, error) {
 	var count int64
-	err := r.db.Model(&models.Schedule{}).Where("loan_id = ? AND paid = ?", loanID, false).Count(&count).Error
+	err := r.db.Model(&models.Schedule{}).Where("loan_id = ? AND paid = ? AND cancelled = ?", loanID, false, false).Count(&count).Error
 	return count, err
 }
+
+// CancelUnpaidByLoanID cancels every unpaid schedule of a loan so it can be replaced by a new version
+func (r *GormScheduleRepository) CancelUnpaidByLoanID(loanID uuid.UUID) error {
+	return r.db.Model(&models.Schedule{}).Where("loan_id = ? AND paid = ? AND cancelled = ?", loanID, false, false).
+		Update("cancelled", true).Error
+}
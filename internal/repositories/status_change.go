@@ -0,0 +1,30 @@
+package repositories
+
+import (
+	"loan-billing-system/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type GormStatusChangeRepository struct {
+	db *gorm.DB
+}
+
+func NewGormStatusChangeRepository(db *gorm.DB) *GormStatusChangeRepository {
+	return &GormStatusChangeRepository{db: db}
+}
+
+// GetByLoanID retrieves the status history of a loan, oldest first
+func (r *GormStatusChangeRepository) GetByLoanID(loanID uuid.UUID) ([]models.LoanStatusChange, error) {
+	var changes []models.LoanStatusChange
+	if err := r.db.Where("loan_id = ?", loanID).Order("created_at").Find(&changes).Error; err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// Create records a loan status change
+func (r *GormStatusChangeRepository) Create(change *models.LoanStatusChange) error {
+	return r.db.Create(change).Error
+}
@@ -0,0 +1,65 @@
+package repositories
+
+import (
+	"loan-billing-system/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type GormTenantRepository struct {
+	db *gorm.DB
+}
+
+func NewGormTenantRepository(db *gorm.DB) *GormTenantRepository {
+	return &GormTenantRepository{db: db}
+}
+
+// GetByID retrieves a tenant by ID
+func (r *GormTenantRepository) GetByID(id string) (*models.Tenant, error) {
+	var tenant models.Tenant
+	if err := r.db.Where("id = ?", id).First(&tenant).Error; err != nil {
+		return nil, err
+	}
+	return &tenant, nil
+}
+
+// List retrieves every tenant ordered by ID
+func (r *GormTenantRepository) List() ([]models.Tenant, error) {
+	var tenants []models.Tenant
+	if err := r.db.Order("id").Find(&tenants).Error; err != nil {
+		return nil, err
+	}
+	return tenants, nil
+}
+
+// Update saves a tenant
+func (r *GormTenantRepository) Update(tenant *models.Tenant) error {
+	return r.db.Save(tenant).Error
+}
+
+// GetHolidays retrieves a tenant's calendar holidays ordered by date
+func (r *GormTenantRepository) GetHolidays(tenantID string) ([]models.CalendarHoliday, error) {
+	var holidays []models.CalendarHoliday
+	if err := r.db.Where("tenant_id = ?", tenantID).Order("date").Find(&holidays).Error; err != nil {
+		return nil, err
+	}
+	return holidays, nil
+}
+
+// CreateHoliday adds a holiday to a tenant's calendar
+func (r *GormTenantRepository) CreateHoliday(holiday *models.CalendarHoliday) error {
+	return r.db.Create(holiday).Error
+}
+
+// DeleteHoliday removes a holiday from a tenant's calendar
+func (r *GormTenantRepository) DeleteHoliday(id uuid.UUID) error {
+	result := r.db.Where("id = ?", id).Delete(&models.CalendarHoliday{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
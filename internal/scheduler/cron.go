@@ -1,7 +1,10 @@
 package scheduler
 
 import (
+	"context"
+	"loan-billing-system/internal/audit"
 	"loan-billing-system/internal/services"
+	"loan-billing-system/internal/tenant"
 	"log"
 	"time"
 
@@ -9,24 +12,35 @@ import (
 	"gorm.io/gorm"
 )
 
+// schedulerActor is recorded in the audit log for changes made by scheduled jobs
+const schedulerActor = "scheduler"
+
 type Scheduler struct {
-	cron        *cron.Cron
-	db          *gorm.DB
-	loanService *services.LoanService
+	cron            *cron.Cron
+	db              *gorm.DB
+	ctx             context.Context
+	loanService     *services.LoanService
+	borrowerService *services.BorrowerService
 }
 
-func NewScheduler(db *gorm.DB, loanService *services.LoanService) *Scheduler {
+func NewScheduler(db *gorm.DB, loanService *services.LoanService, borrowerService *services.BorrowerService) *Scheduler {
+	// Changes made by scheduled jobs are audited as the scheduler's
+	ctx := audit.WithActor(context.Background(), schedulerActor)
 	return &Scheduler{
-		cron:        cron.New(),
-		db:          db,
-		loanService: loanService,
+		cron:            cron.New(),
+		db:              db,
+		ctx:             ctx,
+		loanService:     loanService.WithContext(ctx),
+		borrowerService: borrowerService.WithContext(ctx),
 	}
 }
 
 // Start starts the scheduler
 func (s *Scheduler) Start() {
-	// Run delinquency check daily at midnight
-	s.cron.AddFunc("0 0 * * *", s.checkDelinquency)
+	// Run delinquency check and escalation rules daily at midnight
+	s.cron.AddFunc("0 0 * * *", s.runNightly)
+	// Erase the PII of borrowers past the retention period daily at 2am
+	s.cron.AddFunc("0 2 * * *", s.runRetention)
 	s.cron.Start()
 	log.Println("Scheduler started")
 }
@@ -37,13 +51,28 @@ func (s *Scheduler) Stop() {
 	log.Println("Scheduler stopped")
 }
 
-// checkDelinquency checks all active loans for delinquency
-func (s *Scheduler) checkDelinquency() {
-	log.Println("Running delinquency check...")
+// forEachTenant runs a job once for every tenant, with services acting for
+// that tenant, so that each tenant's loans are handled by its own products
+func (s *Scheduler) forEachTenant(job func(tenantID string, loanService *services.LoanService, borrowerService *services.BorrowerService)) {
+	tenants, err := s.loanService.ListTenants()
+	if err != nil {
+		log.Printf("Error fetching tenants: %v", err)
+		return
+	}
+
+	for _, t := range tenants {
+		ctx := tenant.WithID(s.ctx, t.ID)
+		job(t.ID, s.loanService.WithContext(ctx), s.borrowerService.WithContext(ctx))
+	}
+}
+
+// checkDelinquency checks all active loans of a tenant for delinquency
+func checkDelinquency(tenantID string, loanService *services.LoanService) {
+	log.Printf("Running delinquency check for tenant %s...", tenantID)
 	startTime := time.Now()
 
 	// Get potentially delinquent loans (haven't been paid in the last 2 weeks)
-	potentialDelinquentLoans, err := s.loanService.GetPotentialDelinquentLoans()
+	potentialDelinquentLoans, err := loanService.GetPotentialDelinquentLoans()
 	if err != nil {
 		log.Printf("Error fetching potential delinquent loans: %v", err)
 		return
@@ -66,7 +95,7 @@ func (s *Scheduler) checkDelinquency() {
 		log.Printf("Processing batch %d to %d of %d", i, end-1, totalLoans)
 
 		for _, loan := range batch {
-			isDelinquent, err := s.loanService.IsDelinquent(loan.ID)
+			isDelinquent, err := loanService.IsDelinquent(loan.ID)
 			if err != nil {
 				log.Printf("Error checking delinquency for loan %s: %v", loan.ID, err)
 				continue
@@ -89,7 +118,49 @@ func (s *Scheduler) checkDelinquency() {
 	log.Printf("Processed %d loans, found %d delinquent", totalLoans, delinquentCount)
 }
 
-// RunNow runs the delinquency check immediately
+// runNightly refreshes delinquency first so that escalation works on current days past due
+func (s *Scheduler) runNightly() {
+	s.forEachTenant(func(tenantID string, loanService *services.LoanService, _ *services.BorrowerService) {
+		checkDelinquency(tenantID, loanService)
+		applyEscalationRules(tenantID, loanService)
+	})
+}
+
+// runRetention erases the PII every tenant no longer needs to keep
+func (s *Scheduler) runRetention() {
+	s.forEachTenant(func(tenantID string, _ *services.LoanService, borrowerService *services.BorrowerService) {
+		anonymizeBorrowers(tenantID, borrowerService)
+	})
+}
+
+// applyEscalationRules marks a tenant's loans defaulted and proposes charge-offs per product thresholds
+func applyEscalationRules(tenantID string, loanService *services.LoanService) {
+	log.Printf("Applying escalation rules for tenant %s...", tenantID)
+	startTime := time.Now()
+
+	result, err := loanService.ApplyEscalationRules()
+	if err != nil {
+		log.Printf("Errors while applying escalation rules: %v", err)
+	}
+
+	log.Printf("Escalation completed in %v: %d loans defaulted, %d charge-offs proposed",
+		time.Since(startTime), result.Defaulted, result.ChargeOffsProposed)
+}
+
+// anonymizeBorrowers erases the PII of a tenant's borrowers whose retention period has ended
+func anonymizeBorrowers(tenantID string, borrowerService *services.BorrowerService) {
+	log.Printf("Anonymizing borrowers of tenant %s past retention...", tenantID)
+	startTime := time.Now()
+
+	result, err := borrowerService.AnonymizeEligible(startTime)
+	if err != nil {
+		log.Printf("Errors while anonymizing borrowers: %v", err)
+	}
+
+	log.Printf("Anonymization completed in %v: %d borrowers anonymized", time.Since(startTime), result.Anonymized)
+}
+
+// RunNow runs the nightly checks immediately
 func (s *Scheduler) RunNow() {
-	s.checkDelinquency()
+	s.runNightly()
 }
@@ -0,0 +1,60 @@
+package scoring
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed default.yaml
+var defaultScorecard []byte
+
+// Default returns the built-in scorecard, used when none is configured
+func Default() (*Scorecard, error) {
+	return Parse(defaultScorecard, "yaml")
+}
+
+// Load reads a scorecard from a JSON or YAML file, chosen by its extension
+func Load(path string) (*Scorecard, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scorecard: %w", err)
+	}
+
+	format := strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+	return Parse(data, format)
+}
+
+// Parse decodes and validates a scorecard in the given format (json, yaml or yml).
+// Unknown fields are rejected so that typos do not silently drop a rule.
+func Parse(data []byte, format string) (*Scorecard, error) {
+	var scorecard Scorecard
+
+	switch format {
+	case "json":
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&scorecard); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidScorecard, err)
+		}
+	case "yaml", "yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(&scorecard); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidScorecard, err)
+		}
+	default:
+		return nil, fmt.Errorf("%w: unsupported format %q, use json or yaml", ErrInvalidScorecard, format)
+	}
+
+	if err := scorecard.Validate(); err != nil {
+		return nil, err
+	}
+	return &scorecard, nil
+}
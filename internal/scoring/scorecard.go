@@ -0,0 +1,215 @@
+package scoring
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// Decisions a scorecard can reach
+const (
+	DecisionApprove = "approve"
+	DecisionRefer   = "refer"
+	DecisionDecline = "decline"
+)
+
+// ErrInvalidScorecard is wrapped by every scorecard validation error
+var ErrInvalidScorecard = errors.New("invalid scorecard")
+
+// Applicant is what a scorecard knows about a loan application: the
+// borrower's repayment history, their current exposure and the loan applied for
+type Applicant struct {
+	// InstallmentsDue counts the borrower's installments whose due date has passed
+	InstallmentsDue int
+	// InstallmentsOnTime counts the due installments paid in full by their due date
+	InstallmentsOnTime int
+	// MaxDaysLate is the longest any installment was, or still is, overdue
+	MaxDaysLate int
+	// PriorDefaults counts the borrower's loans that defaulted or were written off
+	PriorDefaults int
+	IsDelinquent  bool
+	// Exposure is the current balance of the borrower's open loans
+	Exposure        int64
+	OpenLoans       int
+	RequestedAmount int64
+	TermWeeks       uint
+	// TenureDays is how long the borrower has been a customer
+	TenureDays int
+}
+
+// factor reads one measure of an applicant; ok is false when the applicant has no value for it
+type factor func(a Applicant) (value float64, ok bool)
+
+// factors are the measures a scorecard rule can score
+var factors = map[string]factor{
+	"on_time_ratio": func(a Applicant) (float64, bool) {
+		if a.InstallmentsDue == 0 {
+			return 0, false
+		}
+		return float64(a.InstallmentsOnTime) / float64(a.InstallmentsDue), true
+	},
+	"late_installments": func(a Applicant) (float64, bool) {
+		return float64(a.InstallmentsDue - a.InstallmentsOnTime), true
+	},
+	"max_days_late":  func(a Applicant) (float64, bool) { return float64(a.MaxDaysLate), true },
+	"prior_defaults": func(a Applicant) (float64, bool) { return float64(a.PriorDefaults), true },
+	"is_delinquent": func(a Applicant) (float64, bool) {
+		if a.IsDelinquent {
+			return 1, true
+		}
+		return 0, true
+	},
+	"exposure":         func(a Applicant) (float64, bool) { return float64(a.Exposure), true },
+	"open_loans":       func(a Applicant) (float64, bool) { return float64(a.OpenLoans), true },
+	"requested_amount": func(a Applicant) (float64, bool) { return float64(a.RequestedAmount), true },
+	"term_weeks":       func(a Applicant) (float64, bool) { return float64(a.TermWeeks), true },
+	"tenure_days":      func(a Applicant) (float64, bool) { return float64(a.TenureDays), true },
+}
+
+// Band awards points when a factor falls in [Min, Max). A nil bound is open.
+// A knockout band declines the application whatever the score.
+type Band struct {
+	Min      *float64 `json:"min,omitempty" yaml:"min,omitempty"`
+	Max      *float64 `json:"max,omitempty" yaml:"max,omitempty"`
+	Points   int      `json:"points" yaml:"points"`
+	Reason   string   `json:"reason" yaml:"reason"`
+	Knockout bool     `json:"knockout,omitempty" yaml:"knockout,omitempty"`
+}
+
+// contains reports whether a value falls in the band
+func (b Band) contains(value float64) bool {
+	return (b.Min == nil || value >= *b.Min) && (b.Max == nil || value < *b.Max)
+}
+
+// Rule scores one factor by the first band it falls in. Missing scores
+// applicants without a value for the factor, such as a first-time borrower's
+// on-time ratio.
+type Rule struct {
+	Name    string `json:"name" yaml:"name"`
+	Factor  string `json:"factor" yaml:"factor"`
+	Bands   []Band `json:"bands" yaml:"bands"`
+	Missing *Band  `json:"missing,omitempty" yaml:"missing,omitempty"`
+}
+
+// Grade is the risk grade given to scores of at least MinScore
+type Grade struct {
+	Name     string `json:"name" yaml:"name"`
+	MinScore int    `json:"min_score" yaml:"min_score"`
+}
+
+// Scorecard adds the points of its rules to a base score, grades the total
+// and decides: scores from ApproveFrom are approved, scores below
+// DeclineBelow are declined and the rest are referred for review.
+type Scorecard struct {
+	Version      string  `json:"version" yaml:"version"`
+	BaseScore    int     `json:"base_score" yaml:"base_score"`
+	Rules        []Rule  `json:"rules" yaml:"rules"`
+	Grades       []Grade `json:"grades" yaml:"grades"`
+	ApproveFrom  int     `json:"approve_from" yaml:"approve_from"`
+	DeclineBelow int     `json:"decline_below" yaml:"decline_below"`
+}
+
+// Scorer scores loan applications
+type Scorer interface {
+	Score(applicant Applicant) Result
+}
+
+// Contribution explains the points one rule gave an applicant
+type Contribution struct {
+	Rule     string   `json:"rule"`
+	Factor   string   `json:"factor"`
+	Value    *float64 `json:"value"`
+	Points   int      `json:"points"`
+	Reason   string   `json:"reason"`
+	Knockout bool     `json:"knockout,omitempty"`
+}
+
+// Result is a scored application and how the score was reached
+type Result struct {
+	ScorecardVersion string
+	Score            int
+	Grade            string
+	Decision         string
+	Contributions    []Contribution
+}
+
+// Validate checks that every rule scores a known factor and that the grades and thresholds make sense
+func (c *Scorecard) Validate() error {
+	if c.Version == "" {
+		return fmt.Errorf("%w: version is required", ErrInvalidScorecard)
+	}
+	for _, rule := range c.Rules {
+		if _, ok := factors[rule.Factor]; !ok {
+			return fmt.Errorf("%w: rule %q scores unknown factor %q", ErrInvalidScorecard, rule.Name, rule.Factor)
+		}
+		if len(rule.Bands) == 0 {
+			return fmt.Errorf("%w: rule %q has no bands", ErrInvalidScorecard, rule.Name)
+		}
+	}
+	if len(c.Grades) == 0 {
+		return fmt.Errorf("%w: at least one grade is required", ErrInvalidScorecard)
+	}
+	if c.DeclineBelow > c.ApproveFrom {
+		return fmt.Errorf("%w: decline_below must not be above approve_from", ErrInvalidScorecard)
+	}
+
+	// Grade from the highest minimum score down
+	sort.SliceStable(c.Grades, func(i, j int) bool { return c.Grades[i].MinScore > c.Grades[j].MinScore })
+	return nil
+}
+
+// Score evaluates an applicant against the scorecard
+func (c *Scorecard) Score(applicant Applicant) Result {
+	result := Result{
+		ScorecardVersion: c.Version,
+		Score:            c.BaseScore,
+		Contributions:    make([]Contribution, 0, len(c.Rules)),
+	}
+
+	knockedOut := false
+	for _, rule := range c.Rules {
+		contribution := Contribution{Rule: rule.Name, Factor: rule.Factor}
+
+		var band *Band
+		if value, ok := factors[rule.Factor](applicant); ok {
+			contribution.Value = &value
+			for i := range rule.Bands {
+				if rule.Bands[i].contains(value) {
+					band = &rule.Bands[i]
+					break
+				}
+			}
+		} else {
+			band = rule.Missing
+		}
+
+		if band != nil {
+			contribution.Points = band.Points
+			contribution.Reason = band.Reason
+			contribution.Knockout = band.Knockout
+			knockedOut = knockedOut || band.Knockout
+		}
+		result.Score += contribution.Points
+		result.Contributions = append(result.Contributions, contribution)
+	}
+
+	// Grades are ordered from the highest minimum; the lowest grade takes any score below it
+	result.Grade = c.Grades[len(c.Grades)-1].Name
+	for _, grade := range c.Grades {
+		if result.Score >= grade.MinScore {
+			result.Grade = grade.Name
+			break
+		}
+	}
+
+	switch {
+	case knockedOut || result.Score < c.DeclineBelow:
+		result.Decision = DecisionDecline
+	case result.Score >= c.ApproveFrom:
+		result.Decision = DecisionApprove
+	default:
+		result.Decision = DecisionRefer
+	}
+
+	return result
+}
@@ -0,0 +1,112 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"loan-billing-system/internal/audit"
+	"loan-billing-system/internal/models"
+	"loan-billing-system/internal/repositories"
+	"loan-billing-system/internal/tenant"
+)
+
+// auditVerifyBatch is how many audit entries are checked at a time
+const auditVerifyBatch = 1000
+
+// AuditService reads and verifies the audit log
+type AuditService struct {
+	repos repositories.RepositoryManager
+	ctx   context.Context
+}
+
+// NewAuditService creates a new audit service
+func NewAuditService(repos repositories.RepositoryManager) *AuditService {
+	return &AuditService{repos: repos, ctx: context.Background()}
+}
+
+// WithContext returns a copy of the service reading the audit log for the
+// tenant of ctx
+func (s *AuditService) WithContext(ctx context.Context) *AuditService {
+	bound := *s
+	bound.repos = s.repos.WithContext(ctx)
+	bound.ctx = ctx
+	return &bound
+}
+
+// AuditAnchor is the sequence number and hash of an entry recorded outside
+// of the database. Because the whole chain could be recomputed by someone
+// with write access, checking that an anchor still matches is what shows
+// the log has not been rewritten since.
+type AuditAnchor struct {
+	Sequence int64
+	Hash     string
+}
+
+// AuditVerification is the outcome of checking the audit chain
+type AuditVerification struct {
+	Valid bool
+	// Checked counts the entries found in order, up to the first broken one
+	Checked int64
+	// HeadSequence and HeadHash identify the last entry in order; record
+	// them as an anchor for later checks
+	HeadSequence int64
+	HeadHash     string
+	// Problem explains why the chain is not valid
+	Problem string
+}
+
+// ListAuditEntries retrieves one page of audit entries matching the filter
+func (s *AuditService) ListAuditEntries(filter repositories.AuditFilter, page repositories.PageRequest) (repositories.Page[models.AuditEntry], error) {
+	return s.repos.Audit().List(filter, page)
+}
+
+// VerifyAuditLog walks the audit chain from the first entry, checking that
+// entries are numbered without gaps, that each follows the one before it and
+// that none has been altered, and that the anchor, if any, still matches.
+// The chain spans every tenant, so all of it is checked; only the outcome
+// is returned.
+func (s *AuditService) VerifyAuditLog(anchor *AuditAnchor) (*AuditVerification, error) {
+	result := &AuditVerification{Valid: true}
+	anchorFound := anchor == nil
+	chain := s.repos.WithContext(tenant.Unscoped(s.ctx)).Audit()
+
+	for {
+		entries, err := chain.GetChain(result.HeadSequence, auditVerifyBatch)
+		if err != nil {
+			return nil, err
+		}
+
+		sequence, hash, err := audit.Verify(result.HeadSequence, result.HeadHash, entries)
+		result.Checked += sequence - result.HeadSequence
+		result.HeadSequence, result.HeadHash = sequence, hash
+		if err != nil {
+			if !errors.Is(err, audit.ErrBrokenChain) {
+				return nil, err
+			}
+			result.Valid = false
+			result.Problem = err.Error()
+			return result, nil
+		}
+
+		if !anchorFound && anchor.Sequence <= result.HeadSequence {
+			anchorFound = true
+			for _, entry := range entries {
+				if entry.Sequence == anchor.Sequence && entry.Hash != anchor.Hash {
+					result.Valid = false
+					result.Problem = fmt.Sprintf("entry %d no longer matches the anchor; the log has been rewritten", anchor.Sequence)
+					return result, nil
+				}
+			}
+		}
+
+		if len(entries) < auditVerifyBatch {
+			break
+		}
+	}
+
+	if !anchorFound {
+		result.Valid = false
+		result.Problem = fmt.Sprintf("entry %d of the anchor is missing; the log has been truncated", anchor.Sequence)
+	}
+	return result, nil
+}
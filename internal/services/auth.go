@@ -0,0 +1,222 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"loan-billing-system/internal/audit"
+	"loan-billing-system/internal/auth"
+	"loan-billing-system/internal/models"
+	"loan-billing-system/internal/repositories"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrAPIKeyNotFound is returned when an API key does not exist or is already revoked
+	ErrAPIKeyNotFound = errors.New("API key not found")
+	// ErrInvalidAPIKeyRequest is returned when a new API key is missing a name, already expired or grants unknown roles
+	ErrInvalidAPIKeyRequest = errors.New("invalid API key request")
+)
+
+// apiKeyTouchInterval is how often an API key's last use is written at most
+const apiKeyTouchInterval = time.Minute
+
+// AuthService authenticates callers and manages partner API keys
+type AuthService struct {
+	repos    repositories.RepositoryManager
+	verifier *auth.Verifier
+	access   *auth.Policy
+	ctx      context.Context
+}
+
+// NewAuthService creates a new auth service verifying staff tokens with verifier
+func NewAuthService(repos repositories.RepositoryManager, verifier *auth.Verifier) *AuthService {
+	return &AuthService{
+		repos:    repos,
+		verifier: verifier,
+		ctx:      context.Background(),
+	}
+}
+
+// WithAccess sets the access policy managing API keys is checked against
+// and whose roles keys may be given
+func (s *AuthService) WithAccess(access *auth.Policy) *AuthService {
+	s.access = access
+	return s
+}
+
+// WithContext returns a copy of the service acting for the principal of ctx
+func (s *AuthService) WithContext(ctx context.Context) *AuthService {
+	bound := *s
+	bound.repos = s.repos.WithContext(ctx)
+	bound.ctx = ctx
+	return &bound
+}
+
+// APIKeyRequest describes an API key to issue
+type APIKeyRequest struct {
+	Name      string
+	Roles     []string
+	ExpiresAt *time.Time
+}
+
+// AuthenticateToken returns the staff member, or the borrower, a JWT was
+// issued to. Borrower tokens carry a borrower_id claim; whatever roles they
+// claim, borrowers only get the borrower role. The tenant claim names the
+// tenant the caller acts for, the default tenant when it is absent.
+func (s *AuthService) AuthenticateToken(token string) (*auth.Principal, error) {
+	claims, err := s.verifier.Verify(token, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	tenantID := claims.Tenant
+	if tenantID == "" {
+		tenantID = models.DefaultTenantID
+	}
+	if claims.BorrowerID != "" {
+		borrowerID, err := uuid.Parse(claims.BorrowerID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: malformed borrower_id", auth.ErrInvalidToken)
+		}
+		return &auth.Principal{
+			Type:       auth.PrincipalBorrower,
+			Subject:    claims.Subject,
+			Name:       claims.Name,
+			Roles:      []string{auth.RoleBorrower},
+			BorrowerID: &borrowerID,
+			Tenant:     tenantID,
+		}, nil
+	}
+	return &auth.Principal{
+		Type:    auth.PrincipalStaff,
+		Subject: claims.Subject,
+		Name:    claims.Name,
+		Roles:   claims.Roles,
+		Tenant:  tenantID,
+	}, nil
+}
+
+// AuthenticateAPIKey returns the partner system an API key was issued to.
+// The key acts for the tenant it was issued in.
+func (s *AuthService) AuthenticateAPIKey(key string) (*auth.Principal, error) {
+	prefix, err := auth.ParseAPIKey(key)
+	if err != nil {
+		return nil, err
+	}
+	stored, err := s.repos.APIKeys().GetByPrefix(prefix)
+	if err != nil || !auth.MatchAPIKey(key, stored.Hash) {
+		return nil, fmt.Errorf("%w: unknown key", auth.ErrInvalidAPIKey)
+	}
+	now := time.Now()
+	if !stored.IsActive(now) {
+		return nil, fmt.Errorf("%w: revoked or expired", auth.ErrInvalidAPIKey)
+	}
+
+	if stored.LastUsedAt == nil || now.Sub(*stored.LastUsedAt) >= apiKeyTouchInterval {
+		if err := s.repos.APIKeys().TouchLastUsed(stored.ID, now); err != nil {
+			return nil, err
+		}
+	}
+	return &auth.Principal{
+		Type:    auth.PrincipalAPIKey,
+		Subject: stored.Prefix,
+		Name:    stored.Name,
+		Roles:   stored.Roles,
+		Tenant:  stored.TenantID,
+	}, nil
+}
+
+// CreateAPIKey issues an API key to a partner system. The key itself is
+// returned only here; just its hash is stored.
+func (s *AuthService) CreateAPIKey(req APIKeyRequest) (*models.APIKey, string, error) {
+	principal, err := s.staff()
+	if err != nil {
+		return nil, "", err
+	}
+	if strings.TrimSpace(req.Name) == "" {
+		return nil, "", fmt.Errorf("%w: name is required", ErrInvalidAPIKeyRequest)
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, "", fmt.Errorf("%w: expiry must be in the future", ErrInvalidAPIKeyRequest)
+	}
+	for _, role := range req.Roles {
+		if s.access != nil && !s.access.HasRole(role) {
+			return nil, "", fmt.Errorf("%w: unknown role %q", ErrInvalidAPIKeyRequest, role)
+		}
+	}
+
+	key, prefix, err := auth.GenerateAPIKey()
+	if err != nil {
+		return nil, "", err
+	}
+	roles := req.Roles
+	if roles == nil {
+		roles = []string{}
+	}
+	record := &models.APIKey{
+		Name:      strings.TrimSpace(req.Name),
+		Prefix:    prefix,
+		Hash:      auth.HashAPIKey(key),
+		Roles:     roles,
+		CreatedBy: principal.Actor(),
+		ExpiresAt: req.ExpiresAt,
+	}
+	if err := s.repos.APIKeys().Create(record); err != nil {
+		return nil, "", err
+	}
+	return record, key, nil
+}
+
+// ListAPIKeys returns every API key, revoked ones included
+func (s *AuthService) ListAPIKeys() ([]models.APIKey, error) {
+	if _, err := s.staff(); err != nil {
+		return nil, err
+	}
+	return s.repos.APIKeys().List()
+}
+
+// RevokeAPIKey stops an API key from being used
+func (s *AuthService) RevokeAPIKey(id uuid.UUID) (*models.APIKey, error) {
+	principal, err := s.staff()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repos.APIKeys().Revoke(id, principal.Actor(), time.Now()); err != nil {
+		return nil, ErrAPIKeyNotFound
+	}
+	return s.repos.APIKeys().GetByID(id)
+}
+
+// staff returns the calling principal if they are a member of staff allowed
+// to manage API keys. API keys cannot manage API keys.
+func (s *AuthService) staff() (*auth.Principal, error) {
+	principal := auth.PrincipalFrom(s.ctx)
+	if principal == nil || principal.Type != auth.PrincipalStaff {
+		return nil, fmt.Errorf("%w: only staff can manage API keys", auth.ErrForbidden)
+	}
+	if err := authorize(s.ctx, s.access, auth.PermAPIKeysManage); err != nil {
+		return nil, err
+	}
+	return principal, nil
+}
+
+// actor returns who the caller of ctx is recorded as in the records it
+// changes: the authenticated principal, or else the actor of its audit trail
+func actor(ctx context.Context) string {
+	if principal := auth.PrincipalFrom(ctx); principal != nil {
+		return principal.Actor()
+	}
+	return audit.ActorFrom(ctx)
+}
+
+// authorize checks that the caller of ctx holds a permission. Services
+// without an access policy check nothing.
+func authorize(ctx context.Context, access *auth.Policy, permission auth.Permission) error {
+	if access == nil {
+		return nil
+	}
+	return access.Authorize(ctx, permission)
+}
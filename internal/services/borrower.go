@@ -1,20 +1,73 @@
 package services
 
 import (
+	"context"
+	"loan-billing-system/internal/auth"
 	"loan-billing-system/internal/models"
 	"loan-billing-system/internal/repositories"
+	"time"
 
 	"github.com/google/uuid"
 )
 
+// BorrowerPolicy holds the configurable rules applied by the borrower service
+type BorrowerPolicy struct {
+	// KYCValidityDays is how long a KYC verification lasts; 0 means it does not lapse
+	KYCValidityDays uint
+	// MinimumAge is the youngest a borrower may be, in years
+	MinimumAge uint
+	// RetentionDays is how long a borrower's PII is kept after their last loan activity before it may be erased
+	RetentionDays uint
+	// AnonymizeAfterRetention erases every borrower past the retention period, not just those who asked
+	AnonymizeAfterRetention bool
+}
+
+// DefaultBorrowerPolicy returns the rules used when no policy is configured
+func DefaultBorrowerPolicy() BorrowerPolicy {
+	return BorrowerPolicy{
+		KYCValidityDays: 365,
+		MinimumAge:      18,
+		RetentionDays:   1825,
+	}
+}
+
 // BorrowerService handles borrower business logic
 type BorrowerService struct {
-	repos repositories.RepositoryManager
+	repos  repositories.RepositoryManager
+	policy BorrowerPolicy
+	access *auth.Policy
+	ctx    context.Context
 }
 
 // NewBorrowerService creates a new borrower service
 func NewBorrowerService(repos repositories.RepositoryManager) *BorrowerService {
-	return &BorrowerService{repos: repos}
+	return &BorrowerService{
+		repos:  repos,
+		policy: DefaultBorrowerPolicy(),
+		ctx:    context.Background(),
+	}
+}
+
+// WithPolicy replaces the service's rules and returns the service
+func (s *BorrowerService) WithPolicy(policy BorrowerPolicy) *BorrowerService {
+	s.policy = policy
+	return s
+}
+
+// WithAccess sets the access policy sensitive actions are checked against
+// and returns the service
+func (s *BorrowerService) WithAccess(access *auth.Policy) *BorrowerService {
+	s.access = access
+	return s
+}
+
+// WithContext returns a copy of the service whose changes are made with
+// ctx, and so audited with the actor and request ID it carries
+func (s *BorrowerService) WithContext(ctx context.Context) *BorrowerService {
+	bound := *s
+	bound.repos = s.repos.WithContext(ctx)
+	bound.ctx = ctx
+	return &bound
 }
 
 // GetBorrower retrieves a borrower by ID
@@ -22,17 +75,38 @@ func (s *BorrowerService) GetBorrower(id uuid.UUID) (*models.Borrower, error) {
 	return s.repos.Borrowers().GetByID(id)
 }
 
-// ListBorrowers retrieves all borrowers
-func (s *BorrowerService) ListBorrowers() ([]models.Borrower, error) {
-	return s.repos.Borrowers().GetAll()
+// ListBorrowers retrieves one page of borrowers matching the filter
+func (s *BorrowerService) ListBorrowers(filter repositories.BorrowerFilter, page repositories.PageRequest) (repositories.Page[models.Borrower], error) {
+	return s.repos.Borrowers().List(filter, page)
 }
 
-// CreateBorrower creates a new borrower
-func (s *BorrowerService) CreateBorrower(name, contactInfo string) (*models.Borrower, error) {
-	return s.repos.Borrowers().Create(name, contactInfo)
+// CreateBorrower creates a new borrower from their profile and addresses. KYC starts as pending.
+// A borrower resembling an existing one is rejected with a DuplicateError
+// unless confirmNotDuplicate is set; one sharing a national ID always is.
+func (s *BorrowerService) CreateBorrower(profile BorrowerProfile, addresses []models.BorrowerAddress, confirmNotDuplicate bool) (*models.Borrower, error) {
+	if err := s.validateProfile(profile, time.Now()); err != nil {
+		return nil, err
+	}
+	for i := range addresses {
+		if err := validateAddress(&addresses[i]); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.checkDuplicates(profile, confirmNotDuplicate); err != nil {
+		return nil, err
+	}
+
+	borrower := &models.Borrower{KYCStatus: models.KYCStatusPending, Addresses: addresses}
+	profile.applyTo(borrower)
+	if err := s.repos.Borrowers().Create(borrower); err != nil {
+		return nil, err
+	}
+	return borrower, nil
 }
 
-// GetDelinquentBorrowers retrieves all delinquent borrowers
-func (s *BorrowerService) GetDelinquentBorrowers() ([]models.Borrower, error) {
-	return s.repos.Borrowers().GetDelinquent()
+// GetDelinquentBorrowers retrieves one page of delinquent borrowers
+func (s *BorrowerService) GetDelinquentBorrowers(filter repositories.BorrowerFilter, page repositories.PageRequest) (repositories.Page[models.Borrower], error) {
+	delinquent := true
+	filter.IsDelinquent = &delinquent
+	return s.repos.Borrowers().List(filter, page)
 }
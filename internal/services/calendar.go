@@ -0,0 +1,168 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"loan-billing-system/internal/models"
+	"loan-billing-system/internal/tenant"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrTenantNotFound is returned when the caller's tenant does not exist
+	ErrTenantNotFound = errors.New("tenant not found")
+	// ErrInvalidCalendar is returned when rest days name unknown or repeated weekdays, or leave no working day
+	ErrInvalidCalendar = errors.New("invalid calendar")
+	// ErrCalendarHolidayExists is returned when a holiday is added for a date that already is one
+	ErrCalendarHolidayExists = errors.New("date is already a holiday")
+	// ErrCalendarHolidayNotFound is returned when a calendar holiday does not exist
+	ErrCalendarHolidayNotFound = errors.New("calendar holiday not found")
+	// ErrProductExists is returned when a product is created with a code already in use
+	ErrProductExists = errors.New("loan product already exists")
+	// ErrInvalidProduct is returned when a new product is missing its code or name
+	ErrInvalidProduct = errors.New("invalid loan product")
+)
+
+// TenantCalendar is a tenant's rest days and holidays
+type TenantCalendar struct {
+	Tenant   *models.Tenant
+	Holidays []models.CalendarHoliday
+}
+
+// ListTenants returns every tenant, for jobs that run once per tenant
+func (s *LoanService) ListTenants() ([]models.Tenant, error) {
+	return s.repos.Tenants().List()
+}
+
+// GetCalendar returns the calendar of the caller's tenant
+func (s *LoanService) GetCalendar() (*TenantCalendar, error) {
+	t, err := s.currentTenant()
+	if err != nil {
+		return nil, err
+	}
+	holidays, err := s.repos.Tenants().GetHolidays(t.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &TenantCalendar{Tenant: t, Holidays: holidays}, nil
+}
+
+// UpdateRestDays replaces the weekdays the caller's tenant collects no
+// installments on. Schedules already laid out keep their due dates.
+func (s *LoanService) UpdateRestDays(restDays []string) (*TenantCalendar, error) {
+	t, err := s.currentTenant()
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(restDays))
+	seen := make(map[string]bool, len(restDays))
+	for _, name := range restDays {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if _, ok := models.Weekdays[name]; !ok {
+			return nil, fmt.Errorf("%w: unknown weekday %q", ErrInvalidCalendar, name)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("%w: %s is listed twice", ErrInvalidCalendar, name)
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	if len(names) == len(models.Weekdays) {
+		return nil, fmt.Errorf("%w: at least one weekday must be a working day", ErrInvalidCalendar)
+	}
+
+	t.RestDays = names
+	if err := s.repos.Tenants().Update(t); err != nil {
+		return nil, err
+	}
+	return s.GetCalendar()
+}
+
+// AddCalendarHoliday makes a date a holiday of the caller's tenant
+func (s *LoanService) AddCalendarHoliday(date time.Time, name string) (*models.CalendarHoliday, error) {
+	t, err := s.currentTenant()
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(name) == "" {
+		return nil, fmt.Errorf("%w: holiday name is required", ErrInvalidCalendar)
+	}
+
+	date = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	holidays, err := s.repos.Tenants().GetHolidays(t.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, holiday := range holidays {
+		if holiday.Date.Format(time.DateOnly) == date.Format(time.DateOnly) {
+			return nil, ErrCalendarHolidayExists
+		}
+	}
+
+	holiday := &models.CalendarHoliday{
+		TenantID: t.ID,
+		Date:     date,
+		Name:     strings.TrimSpace(name),
+	}
+	if err := s.repos.Tenants().CreateHoliday(holiday); err != nil {
+		return nil, err
+	}
+	return holiday, nil
+}
+
+// RemoveCalendarHoliday removes a holiday of the caller's tenant
+func (s *LoanService) RemoveCalendarHoliday(id uuid.UUID) error {
+	if err := s.repos.Tenants().DeleteHoliday(id); err != nil {
+		return ErrCalendarHolidayNotFound
+	}
+	return nil
+}
+
+// CreateProduct adds a loan product to the caller's tenant
+func (s *LoanService) CreateProduct(product models.LoanProduct) (*models.LoanProduct, error) {
+	product.Code = strings.TrimSpace(product.Code)
+	if product.Code == "" || strings.TrimSpace(product.Name) == "" {
+		return nil, fmt.Errorf("%w: code and name are required", ErrInvalidProduct)
+	}
+	if product.DefaultAfterDPD > 0 && product.ChargeOffAfterDPD > 0 && product.ChargeOffAfterDPD <= product.DefaultAfterDPD {
+		return nil, ErrInvalidThresholds
+	}
+	if _, err := s.repos.Products().GetByCode(product.Code); err == nil {
+		return nil, ErrProductExists
+	}
+
+	if err := s.repos.Products().Create(&product); err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+// currentTenant returns the tenant the service acts for
+func (s *LoanService) currentTenant() (*models.Tenant, error) {
+	t, err := s.repos.Tenants().GetByID(tenant.IDFrom(s.ctx))
+	if err != nil {
+		return nil, ErrTenantNotFound
+	}
+	return t, nil
+}
+
+// calendar returns the calendar the installments of the service's tenant
+// fall due by. Work done outside any tenant uses a calendar without days off.
+func (s *LoanService) calendar() (models.Calendar, error) {
+	if tenant.IDFrom(s.ctx) == "" {
+		return models.Calendar{}, nil
+	}
+	t, err := s.currentTenant()
+	if err != nil {
+		return models.Calendar{}, err
+	}
+	holidays, err := s.repos.Tenants().GetHolidays(t.ID)
+	if err != nil {
+		return models.Calendar{}, err
+	}
+	return models.NewCalendar(t, holidays), nil
+}
@@ -0,0 +1,219 @@
+package services
+
+import (
+	"errors"
+	"loan-billing-system/internal/models"
+	"loan-billing-system/internal/repositories"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrCollateralNotFound is returned when a piece of collateral does not exist on the loan
+	ErrCollateralNotFound = errors.New("collateral not found")
+	// ErrInvalidCollateral is returned when collateral has an unknown type or a value that is not positive
+	ErrInvalidCollateral = errors.New("collateral type must be vehicle, property, equipment, deposit or other, and its value must be positive")
+	// ErrInvalidLienStatus is returned when a lien status is not pending, registered or released
+	ErrInvalidLienStatus = errors.New("lien status must be pending, registered or released")
+	// ErrLoanNotOpen is returned when collateral is pledged against a loan that is no longer being repaid
+	ErrLoanNotOpen = errors.New("collateral can only be added to an active or defaulted loan")
+	// ErrLoanNotFullyPaid is returned when releasing collateral before its loan has been paid off
+	ErrLoanNotFullyPaid = errors.New("collateral cannot be released until the loan is fully paid")
+	// ErrCollateralReleased is returned when changing collateral that has already been released
+	ErrCollateralReleased = errors.New("collateral has already been released")
+)
+
+// CollateralDetails describe a piece of collateral and its first valuation.
+// A zero ValuedAt means the valuation is made now.
+type CollateralDetails struct {
+	Type        string
+	Description string
+	Value       int64
+	ValuedAt    time.Time
+	ValuedBy    string
+	Notes       string
+}
+
+// Valuation is a new valuation of a piece of collateral. A zero ValuedAt means now.
+type Valuation struct {
+	Value    int64
+	ValuedAt time.Time
+	ValuedBy string
+	Notes    string
+}
+
+// CollateralPosition is a loan's collateral with its total value and loan-to-value ratio
+type CollateralPosition struct {
+	Loan       *models.Loan
+	Collateral []models.Collateral
+	// TotalValue sums the latest valuations of the collateral not yet released
+	TotalValue int64
+	// LoanToValue is the loan's current balance as a percentage of TotalValue; nil without collateral value
+	LoanToValue *float64
+}
+
+// AddCollateral pledges a piece of collateral against a loan with its first valuation
+func (s *LoanService) AddCollateral(loanID uuid.UUID, details CollateralDetails) (*models.Collateral, error) {
+	loan, err := s.repos.Loans().GetByID(loanID)
+	if err != nil {
+		return nil, ErrLoanNotFound
+	}
+	if !isOpen(loan) {
+		return nil, ErrLoanNotOpen
+	}
+
+	switch details.Type {
+	case models.CollateralTypeVehicle, models.CollateralTypeProperty, models.CollateralTypeEquipment,
+		models.CollateralTypeDeposit, models.CollateralTypeOther:
+	default:
+		return nil, ErrInvalidCollateral
+	}
+	if details.Value <= 0 {
+		return nil, ErrInvalidCollateral
+	}
+
+	valuedAt := details.ValuedAt
+	if valuedAt.IsZero() {
+		valuedAt = time.Now()
+	}
+	collateral := &models.Collateral{
+		LoanID:      loanID,
+		Type:        details.Type,
+		Description: details.Description,
+		Value:       details.Value,
+		ValuedAt:    valuedAt,
+		LienStatus:  models.LienStatusPending,
+		Valuations: []models.CollateralValuation{{
+			Value:    details.Value,
+			ValuedAt: valuedAt,
+			ValuedBy: details.ValuedBy,
+			Notes:    details.Notes,
+		}},
+	}
+	if err := s.repos.Collateral().Create(collateral); err != nil {
+		return nil, err
+	}
+
+	return collateral, nil
+}
+
+// RevalueCollateral records a new valuation of a piece of collateral. The
+// collateral takes the new value unless an even later valuation exists.
+func (s *LoanService) RevalueCollateral(loanID, collateralID uuid.UUID, valuation Valuation) (*models.Collateral, error) {
+	collateral, err := s.loanCollateral(loanID, collateralID)
+	if err != nil {
+		return nil, err
+	}
+	if collateral.IsReleased() {
+		return nil, ErrCollateralReleased
+	}
+	if valuation.Value <= 0 {
+		return nil, ErrInvalidCollateral
+	}
+
+	record := models.CollateralValuation{
+		CollateralID: collateralID,
+		Value:        valuation.Value,
+		ValuedAt:     valuation.ValuedAt,
+		ValuedBy:     valuation.ValuedBy,
+		Notes:        valuation.Notes,
+	}
+	if record.ValuedAt.IsZero() {
+		record.ValuedAt = time.Now()
+	}
+
+	err = s.repos.WithTransaction(func(repo repositories.RepositoryManager) error {
+		if err := repo.Collateral().CreateValuation(&record); err != nil {
+			return err
+		}
+		if record.ValuedAt.Before(collateral.ValuedAt) {
+			return nil
+		}
+		collateral.Value = record.Value
+		collateral.ValuedAt = record.ValuedAt
+		return repo.Collateral().Update(collateral)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	collateral.Valuations = append(collateral.Valuations, record)
+	return collateral, nil
+}
+
+// UpdateLienStatus moves a piece of collateral's lien to a new status.
+// Releasing it requires the loan to be fully paid, and a released lien is final.
+func (s *LoanService) UpdateLienStatus(loanID, collateralID uuid.UUID, status string) (*models.Collateral, error) {
+	collateral, err := s.loanCollateral(loanID, collateralID)
+	if err != nil {
+		return nil, err
+	}
+	if collateral.IsReleased() {
+		return nil, ErrCollateralReleased
+	}
+
+	switch status {
+	case models.LienStatusPending, models.LienStatusRegistered:
+	case models.LienStatusReleased:
+		loan, err := s.repos.Loans().GetByID(loanID)
+		if err != nil {
+			return nil, ErrLoanNotFound
+		}
+		if !isFullyPaid(loan) {
+			return nil, ErrLoanNotFullyPaid
+		}
+		now := time.Now()
+		collateral.ReleasedAt = &now
+	default:
+		return nil, ErrInvalidLienStatus
+	}
+
+	collateral.LienStatus = status
+	if err := s.repos.Collateral().Update(collateral); err != nil {
+		return nil, err
+	}
+
+	return collateral, nil
+}
+
+// GetCollateral returns the collateral pledged against a loan with its valuation history and loan-to-value ratio
+func (s *LoanService) GetCollateral(loanID uuid.UUID) (*CollateralPosition, error) {
+	loan, err := s.repos.Loans().GetByID(loanID)
+	if err != nil {
+		return nil, ErrLoanNotFound
+	}
+	collateral, err := s.repos.Collateral().GetByLoanID(loanID)
+	if err != nil {
+		return nil, err
+	}
+
+	position := &CollateralPosition{Loan: loan, Collateral: collateral}
+	for i := range collateral {
+		if !collateral[i].IsReleased() {
+			position.TotalValue += collateral[i].Value
+		}
+	}
+	if position.TotalValue > 0 {
+		ltv := math.Round(float64(loan.CurrentBalance)/float64(position.TotalValue)*10000) / 100
+		position.LoanToValue = &ltv
+	}
+
+	return position, nil
+}
+
+// loanCollateral retrieves a piece of collateral, making sure it is pledged against the given loan
+func (s *LoanService) loanCollateral(loanID, collateralID uuid.UUID) (*models.Collateral, error) {
+	collateral, err := s.repos.Collateral().GetByID(collateralID)
+	if err != nil || collateral.LoanID != loanID {
+		return nil, ErrCollateralNotFound
+	}
+	return collateral, nil
+}
+
+// isFullyPaid reports whether a loan has been repaid. A refinanced loan's
+// debt lives on in the loan that replaced it, so it does not count.
+func isFullyPaid(loan *models.Loan) bool {
+	return loan.Status == models.LoanStatusClosed
+}
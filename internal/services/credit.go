@@ -0,0 +1,147 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"loan-billing-system/internal/auth"
+	"loan-billing-system/internal/models"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Credit rejection reason codes
+const (
+	RejectionCreditLimitExceeded = "credit_limit_exceeded"
+	RejectionTooManyLoans        = "max_concurrent_loans"
+	RejectionBorrowerDelinquent  = "borrower_delinquent"
+	RejectionKYCNotVerified      = "kyc_not_verified"
+)
+
+var (
+	// ErrCreditRejected is wrapped by every CreditRejection
+	ErrCreditRejected = errors.New("loan application rejected")
+	// ErrInvalidCreditLimits is returned when a borrower's credit limit is negative
+	ErrInvalidCreditLimits = errors.New("credit limit must not be negative")
+)
+
+// RejectionReason is one credit rule a loan application failed
+type RejectionReason struct {
+	Code    string
+	Message string
+}
+
+// CreditRejection lists every credit rule a loan application failed
+type CreditRejection struct {
+	Reasons []RejectionReason
+	// Assessment explains the application's score when it was scored
+	Assessment *models.CreditAssessment
+}
+
+// Error joins the rejection messages
+func (r *CreditRejection) Error() string {
+	messages := make([]string, 0, len(r.Reasons))
+	for _, reason := range r.Reasons {
+		messages = append(messages, reason.Message)
+	}
+	return fmt.Sprintf("%s: %s", ErrCreditRejected, strings.Join(messages, "; "))
+}
+
+// Unwrap lets errors.Is match ErrCreditRejected
+func (r *CreditRejection) Unwrap() error {
+	return ErrCreditRejected
+}
+
+// CreditOverride lets a delinquent borrower take a new loan. The caller
+// approves it, and needs the loans:approve permission to.
+type CreditOverride struct {
+	Reason string
+}
+
+// CreditLimits are a borrower's own limits; nil fields fall back to the loan policy
+type CreditLimits struct {
+	CreditLimit        *int64
+	MaxConcurrentLoans *uint
+}
+
+// checkCredit applies the credit rules to a borrower taking out a new loan
+// and returns the ones it fails. Exposure is the current balance of the
+// borrower's open loans, so the new loan counts with its total due. Only
+// delinquency can be overridden; unverified KYC cannot.
+func (s *LoanService) checkCredit(borrower *models.Borrower, loan *models.Loan, override *CreditOverride) ([]RejectionReason, error) {
+	var approvedBy string
+	if override != nil {
+		if err := authorize(s.ctx, s.access, auth.PermLoansApprove); err != nil {
+			return nil, err
+		}
+		approvedBy = actor(s.ctx)
+	}
+
+	var exposure int64
+	var openLoans uint
+	for i := range borrower.Loans {
+		if isOpen(&borrower.Loans[i]) {
+			exposure += borrower.Loans[i].CurrentBalance
+			openLoans++
+		}
+	}
+
+	creditLimit := s.policy.CreditLimit
+	if borrower.CreditLimit != nil {
+		creditLimit = *borrower.CreditLimit
+	}
+	maxLoans := s.policy.MaxConcurrentLoans
+	if borrower.MaxConcurrentLoans != nil {
+		maxLoans = *borrower.MaxConcurrentLoans
+	}
+
+	var reasons []RejectionReason
+	if s.policy.RequireVerifiedKYC {
+		if status := borrower.KYCStatusAt(time.Now()); status != models.KYCStatusVerified {
+			reasons = append(reasons, RejectionReason{
+				Code:    RejectionKYCNotVerified,
+				Message: fmt.Sprintf("borrower's KYC is %s and must be verified", status),
+			})
+		}
+	}
+	if creditLimit > 0 && exposure+loan.CurrentBalance > creditLimit {
+		reasons = append(reasons, RejectionReason{
+			Code:    RejectionCreditLimitExceeded,
+			Message: fmt.Sprintf("exposure of %d plus the new loan's %d exceeds the credit limit of %d", exposure, loan.CurrentBalance, creditLimit),
+		})
+	}
+	if maxLoans > 0 && openLoans >= maxLoans {
+		reasons = append(reasons, RejectionReason{
+			Code:    RejectionTooManyLoans,
+			Message: fmt.Sprintf("borrower already has %d open loans, the maximum is %d", openLoans, maxLoans),
+		})
+	}
+	if borrower.IsDelinquent {
+		if override == nil {
+			reasons = append(reasons, RejectionReason{
+				Code:    RejectionBorrowerDelinquent,
+				Message: "borrower is delinquent",
+			})
+		} else {
+			loan.CreditOverrideBy = approvedBy
+			loan.CreditOverrideReason = override.Reason
+		}
+	}
+
+	return reasons, nil
+}
+
+// SetCreditLimits sets or clears a borrower's own credit limit and maximum number of open loans
+func (s *BorrowerService) SetCreditLimits(id uuid.UUID, limits CreditLimits) (*models.Borrower, error) {
+	if limits.CreditLimit != nil && *limits.CreditLimit < 0 {
+		return nil, ErrInvalidCreditLimits
+	}
+	if _, err := s.repos.Borrowers().GetByID(id); err != nil {
+		return nil, ErrBorrowerNotFound
+	}
+	if err := s.repos.Borrowers().UpdateCreditLimits(id, limits.CreditLimit, limits.MaxConcurrentLoans); err != nil {
+		return nil, err
+	}
+	return s.repos.Borrowers().GetByID(id)
+}
@@ -0,0 +1,283 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"loan-billing-system/internal/auth"
+	"loan-billing-system/internal/models"
+	"loan-billing-system/internal/repositories"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Duplicate match reason codes
+const (
+	MatchNationalID = "national_id"
+	MatchPhone      = "phone"
+	MatchName       = "name"
+)
+
+// nameMatchThreshold is the Jaro-Winkler similarity of two name keys at or
+// above which the names are taken to be the same person's
+const nameMatchThreshold = 0.88
+
+var (
+	// ErrDuplicateBorrower is returned when a borrower with the same national ID already exists
+	ErrDuplicateBorrower = errors.New("borrower already exists")
+	// ErrPossibleDuplicate is returned when a new borrower looks like an existing one and was not confirmed as different
+	ErrPossibleDuplicate = errors.New("borrower may already exist")
+	// ErrInvalidMerge is returned when two borrowers cannot be merged
+	ErrInvalidMerge = errors.New("invalid borrower merge")
+)
+
+// DuplicateCandidate is an existing borrower who may be the same person as another
+type DuplicateCandidate struct {
+	Borrower models.Borrower
+	// Score is how alike the two are, from 0 to 1; a matching national ID scores 1
+	Score float64
+	// Reasons lists the details that matched
+	Reasons []string
+}
+
+// DuplicateError lists the existing borrowers a new borrower may duplicate
+type DuplicateError struct {
+	Candidates []DuplicateCandidate
+	// Blocking is set when a candidate shares the national ID; it cannot be confirmed past
+	Blocking bool
+}
+
+// Error names the candidates
+func (e *DuplicateError) Error() string {
+	names := make([]string, 0, len(e.Candidates))
+	for _, candidate := range e.Candidates {
+		names = append(names, fmt.Sprintf("%s (%s)", candidate.Borrower.Name, candidate.Borrower.ID))
+	}
+	return fmt.Sprintf("%s: %s", e.Unwrap(), strings.Join(names, ", "))
+}
+
+// Unwrap lets errors.Is match ErrDuplicateBorrower or ErrPossibleDuplicate
+func (e *DuplicateError) Unwrap() error {
+	if e.Blocking {
+		return ErrDuplicateBorrower
+	}
+	return ErrPossibleDuplicate
+}
+
+// BorrowerMergeRequest is why two borrowers are merged. The caller is
+// recorded as who merged them.
+type BorrowerMergeRequest struct {
+	Reason string
+}
+
+// checkDuplicates rejects a new borrower matching an existing one. A shared
+// national ID always blocks; phone and name matches block unless confirmed.
+func (s *BorrowerService) checkDuplicates(profile BorrowerProfile, confirmNotDuplicate bool) error {
+	candidates, err := s.findDuplicates(profile, uuid.Nil)
+	if err != nil {
+		return err
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	blocking := slices.ContainsFunc(candidates, func(c DuplicateCandidate) bool {
+		return slices.Contains(c.Reasons, MatchNationalID)
+	})
+	if blocking || !confirmNotDuplicate {
+		return &DuplicateError{Candidates: candidates, Blocking: blocking}
+	}
+	return nil
+}
+
+// FindDuplicates retrieves the other borrowers who may be the same person as a borrower, best match first
+func (s *BorrowerService) FindDuplicates(id uuid.UUID) ([]DuplicateCandidate, error) {
+	borrower, err := s.repos.Borrowers().GetByID(id)
+	if err != nil {
+		return nil, ErrBorrowerNotFound
+	}
+	profile := BorrowerProfile{
+		Name:           borrower.Name,
+		NationalIDType: borrower.NationalIDType,
+		NationalID:     borrower.NationalID,
+		DateOfBirth:    borrower.DateOfBirth,
+		Phone:          borrower.Phone,
+	}
+	return s.findDuplicates(profile, id)
+}
+
+// findDuplicates scores the borrowers sharing a national ID, phone, name or
+// date of birth with a profile. A name matches when it is similar enough and
+// the dates of birth agree or one is unknown; without a date of birth only
+// names normalizing to the same key are found.
+func (s *BorrowerService) findDuplicates(profile BorrowerProfile, excludeID uuid.UUID) ([]DuplicateCandidate, error) {
+	nameKey := models.NameKey(profile.Name)
+	matches, err := s.repos.Borrowers().FindMatches(repositories.BorrowerMatch{
+		ExcludeID:      excludeID,
+		NationalIDType: profile.NationalIDType,
+		NationalID:     profile.NationalID,
+		Phone:          profile.Phone,
+		Name:           profile.Name,
+		DateOfBirth:    profile.DateOfBirth,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []DuplicateCandidate
+	for _, match := range matches {
+		var reasons []string
+		var score float64
+		if profile.NationalID != "" && match.NationalIDType == profile.NationalIDType && match.NationalID == profile.NationalID {
+			reasons = append(reasons, MatchNationalID)
+			score = 1
+		}
+		if profile.Phone != "" && match.Phone == profile.Phone {
+			reasons = append(reasons, MatchPhone)
+			score += 0.5
+		}
+		similarity := jaroWinkler(nameKey, models.NameKey(match.Name))
+		datesAgree := profile.DateOfBirth == nil || match.DateOfBirth == nil || sameDate(profile.DateOfBirth, match.DateOfBirth)
+		if similarity >= nameMatchThreshold && datesAgree {
+			reasons = append(reasons, MatchName)
+			score += similarity / 2
+		}
+		if len(reasons) == 0 {
+			continue
+		}
+		candidates = append(candidates, DuplicateCandidate{Borrower: match, Score: min(score, 1), Reasons: reasons})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].Score != candidates[j].Score {
+			return candidates[i].Score > candidates[j].Score
+		}
+		return candidates[i].Reasons[0] == MatchNationalID && candidates[j].Reasons[0] != MatchNationalID
+	})
+	return candidates, nil
+}
+
+// MergeBorrowers merges a duplicate borrower into another. The duplicate's
+// loans, loan parties, group memberships, addresses, documents and credit
+// assessments move to the borrower kept, who becomes delinquent if either
+// was. The duplicate is deleted and the merge recorded.
+func (s *BorrowerService) MergeBorrowers(targetID, sourceID uuid.UUID, req BorrowerMergeRequest) (*models.BorrowerMerge, error) {
+	if err := authorize(s.ctx, s.access, auth.PermBorrowersMerge); err != nil {
+		return nil, err
+	}
+	if targetID == sourceID {
+		return nil, fmt.Errorf("%w: a borrower cannot be merged into itself", ErrInvalidMerge)
+	}
+	target, err := s.repos.Borrowers().GetByID(targetID)
+	if err != nil {
+		return nil, ErrBorrowerNotFound
+	}
+	source, err := s.repos.Borrowers().GetByID(sourceID)
+	if err != nil {
+		return nil, ErrBorrowerNotFound
+	}
+	if target.AnonymizedAt != nil || source.AnonymizedAt != nil {
+		return nil, ErrAlreadyAnonymized
+	}
+
+	snapshot := *source
+	snapshot.Loans = nil
+	snapshot.Addresses = nil
+	merge := &models.BorrowerMerge{
+		SourceID:       sourceID,
+		TargetID:       targetID,
+		MergedBy:       actor(s.ctx),
+		Reason:         req.Reason,
+		SourceSnapshot: snapshot,
+	}
+
+	err = s.repos.WithTransaction(func(repos repositories.RepositoryManager) error {
+		counts, err := repos.Merges().Reassign(sourceID, targetID)
+		if err != nil {
+			return err
+		}
+		merge.LoansMoved = counts.Loans
+		merge.PartiesMoved = counts.Parties
+		merge.MembersMoved = counts.Members
+
+		if source.IsDelinquent && !target.IsDelinquent {
+			if err := repos.Borrowers().UpdateDelinquencyStatus(targetID, true); err != nil {
+				return err
+			}
+		}
+		source.MergedIntoID = &targetID
+		if err := repos.Borrowers().Update(source); err != nil {
+			return err
+		}
+		if err := repos.Borrowers().Delete(sourceID); err != nil {
+			return err
+		}
+		return repos.Merges().Create(merge)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return merge, nil
+}
+
+// GetMerges retrieves the borrowers merged into a borrower, newest first
+func (s *BorrowerService) GetMerges(id uuid.UUID) ([]models.BorrowerMerge, error) {
+	if _, err := s.repos.Borrowers().GetByID(id); err != nil {
+		return nil, ErrBorrowerNotFound
+	}
+	return s.repos.Merges().GetByTargetID(id)
+}
+
+// jaroWinkler returns the Jaro-Winkler similarity of two strings, from 0 (nothing in common) to 1 (equal)
+func jaroWinkler(a, b string) float64 {
+	s, t := []rune(a), []rune(b)
+	if len(s) == 0 && len(t) == 0 {
+		return 1
+	}
+	if len(s) == 0 || len(t) == 0 {
+		return 0
+	}
+
+	window := max(max(len(s), len(t))/2-1, 0)
+	sMatched := make([]bool, len(s))
+	tMatched := make([]bool, len(t))
+	matches := 0
+	for i := range s {
+		for j := max(0, i-window); j < min(len(t), i+window+1); j++ {
+			if !tMatched[j] && s[i] == t[j] {
+				sMatched[i], tMatched[j] = true, true
+				matches++
+				break
+			}
+		}
+	}
+	if matches == 0 {
+		return 0
+	}
+
+	transpositions := 0
+	j := 0
+	for i := range s {
+		if !sMatched[i] {
+			continue
+		}
+		for !tMatched[j] {
+			j++
+		}
+		if s[i] != t[j] {
+			transpositions++
+		}
+		j++
+	}
+
+	m := float64(matches)
+	jaro := (m/float64(len(s)) + m/float64(len(t)) + (m-float64(transpositions)/2)/m) / 3
+
+	prefix := 0
+	for prefix < min(4, len(s), len(t)) && s[prefix] == t[prefix] {
+		prefix++
+	}
+	return jaro + float64(prefix)*0.1*(1-jaro)
+}
@@ -0,0 +1,150 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"loan-billing-system/internal/models"
+	"loan-billing-system/internal/repositories"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrAlreadyAnonymized is returned when erasing a borrower whose PII is already gone
+	ErrAlreadyAnonymized = errors.New("borrower has already been anonymized")
+)
+
+// ErasureStatus is where a borrower's erasure request stands
+type ErasureStatus struct {
+	Borrower *models.Borrower
+	// Anonymized is set once the borrower's PII has been erased
+	Anonymized bool
+	// OpenLoans counts the open loans the borrower is on; erasure waits for them to close
+	OpenLoans int
+	// EligibleAt is when the retention period ends, known once every loan is closed
+	EligibleAt *time.Time
+}
+
+// ErasureResult summarizes one run of the erasure job
+type ErasureResult struct {
+	Anonymized int
+}
+
+// RequestErasure records a borrower's request to have their data erased and
+// erases it straight away when they are eligible. Otherwise the nightly job
+// erases it once every loan they are on is closed and the retention period
+// has passed since the last loan activity. The caller is recorded as who
+// took the request.
+func (s *BorrowerService) RequestErasure(id uuid.UUID) (*ErasureStatus, error) {
+	borrower, err := s.repos.Borrowers().GetByID(id)
+	if err != nil {
+		return nil, ErrBorrowerNotFound
+	}
+	if borrower.AnonymizedAt != nil {
+		return nil, ErrAlreadyAnonymized
+	}
+
+	now := time.Now()
+	if borrower.ErasureRequestedAt == nil {
+		borrower.ErasureRequestedAt = &now
+		borrower.ErasureRequestedBy = actor(s.ctx)
+		if err := s.repos.Borrowers().Update(borrower); err != nil {
+			return nil, err
+		}
+	}
+
+	status, err := s.erasureStatus(borrower)
+	if err != nil {
+		return nil, err
+	}
+	if status.EligibleAt == nil || now.Before(*status.EligibleAt) {
+		return status, nil
+	}
+
+	if err := s.anonymize(borrower.ID, now); err != nil {
+		return nil, err
+	}
+	if status.Borrower, err = s.repos.Borrowers().GetByID(id); err != nil {
+		return nil, err
+	}
+	status.Anonymized = true
+	return status, nil
+}
+
+// GetErasureStatus reports whether a borrower's PII can be erased yet
+func (s *BorrowerService) GetErasureStatus(id uuid.UUID) (*ErasureStatus, error) {
+	borrower, err := s.repos.Borrowers().GetByID(id)
+	if err != nil {
+		return nil, ErrBorrowerNotFound
+	}
+	return s.erasureStatus(borrower)
+}
+
+// AnonymizeEligible erases the PII of every borrower past the retention
+// period: those who asked for erasure, or everyone when the policy
+// anonymizes after retention. A failure on one borrower does not stop the
+// others; all failures are returned together.
+func (s *BorrowerService) AnonymizeEligible(now time.Time) (ErasureResult, error) {
+	var result ErasureResult
+
+	cutoff := now.AddDate(0, 0, -int(s.policy.RetentionDays))
+	candidates, err := s.repos.Erasures().FindCandidates(cutoff, !s.policy.AnonymizeAfterRetention)
+	if err != nil {
+		return result, err
+	}
+
+	var errs []error
+	for i := range candidates {
+		status, err := s.erasureStatus(&candidates[i])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("borrower %s: %w", candidates[i].ID, err))
+			continue
+		}
+		if status.EligibleAt == nil || now.Before(*status.EligibleAt) {
+			continue
+		}
+		if err := s.anonymize(candidates[i].ID, now); err != nil {
+			errs = append(errs, fmt.Errorf("borrower %s: %w", candidates[i].ID, err))
+			continue
+		}
+		result.Anonymized++
+	}
+	return result, errors.Join(errs...)
+}
+
+// erasureStatus works out when a borrower becomes eligible for erasure. The
+// retention period runs from the last update to any loan they are on, or
+// from when they were created if they never had one.
+func (s *BorrowerService) erasureStatus(borrower *models.Borrower) (*ErasureStatus, error) {
+	status := &ErasureStatus{Borrower: borrower, Anonymized: borrower.AnonymizedAt != nil}
+	if status.Anonymized {
+		return status, nil
+	}
+
+	loans, err := s.repos.Loans().GetByParty(borrower.ID)
+	if err != nil {
+		return nil, err
+	}
+	lastActivity := borrower.CreatedAt
+	for i := range loans {
+		if isOpen(&loans[i]) {
+			status.OpenLoans++
+		}
+		if loans[i].UpdatedAt.After(lastActivity) {
+			lastActivity = loans[i].UpdatedAt
+		}
+	}
+	if status.OpenLoans == 0 {
+		eligibleAt := lastActivity.AddDate(0, 0, int(s.policy.RetentionDays))
+		status.EligibleAt = &eligibleAt
+	}
+	return status, nil
+}
+
+// anonymize erases a borrower's PII in a transaction
+func (s *BorrowerService) anonymize(id uuid.UUID, at time.Time) error {
+	return s.repos.WithTransaction(func(repos repositories.RepositoryManager) error {
+		return repos.Erasures().Anonymize(id, at)
+	})
+}
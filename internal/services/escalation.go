@@ -0,0 +1,220 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"loan-billing-system/internal/auth"
+	"loan-billing-system/internal/models"
+	"loan-billing-system/internal/repositories"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// writeOffRuleManual is logged for write-offs requested directly rather than through a proposal
+const writeOffRuleManual = "manual_write_off"
+
+var (
+	// ErrProductNotFound is returned when a loan product does not exist
+	ErrProductNotFound = errors.New("loan product not found")
+	// ErrInvalidThresholds is returned when a product's charge-off threshold does not come after its default threshold
+	ErrInvalidThresholds = errors.New("charge_off_after_dpd must be greater than default_after_dpd")
+	// ErrChargeOffNotFound is returned when a charge-off proposal does not exist
+	ErrChargeOffNotFound = errors.New("charge-off proposal not found")
+	// ErrChargeOffReviewed is returned when a charge-off proposal has already been reviewed
+	ErrChargeOffReviewed = errors.New("charge-off proposal has already been reviewed")
+)
+
+// EscalationResult summarizes one run of the escalation rules
+type EscalationResult struct {
+	Defaulted          int
+	ChargeOffsProposed int
+}
+
+// ProductThresholds are the escalation thresholds to change on a product. Nil fields are left as they are.
+type ProductThresholds struct {
+	DefaultAfterDPD   *uint
+	ChargeOffAfterDPD *uint
+}
+
+// ApplyEscalationRules applies each product's days-past-due thresholds.
+// Active loans past the default threshold are marked defaulted, and loans
+// past the charge-off threshold are put in the charge-off review queue once.
+// A failure on one loan does not stop the others; all failures are returned
+// together.
+func (s *LoanService) ApplyEscalationRules() (EscalationResult, error) {
+	var result EscalationResult
+
+	products, err := s.repos.Products().List()
+	if err != nil {
+		return result, err
+	}
+
+	var errs []error
+	for _, product := range products {
+		if product.DefaultAfterDPD > 0 {
+			loans, err := s.repos.Loans().GetEscalationCandidates(product.Code, []string{models.LoanStatusActive}, product.DefaultAfterDPD)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			rule := fmt.Sprintf("%s.default_after_dpd=%d", product.Code, product.DefaultAfterDPD)
+			for i := range loans {
+				if err := s.markDefaulted(&loans[i], rule); err != nil {
+					errs = append(errs, fmt.Errorf("loan %s: %w", loans[i].ID, err))
+					continue
+				}
+				result.Defaulted++
+			}
+		}
+
+		if product.ChargeOffAfterDPD > 0 {
+			statuses := []string{models.LoanStatusActive, models.LoanStatusDefaulted}
+			loans, err := s.repos.Loans().GetEscalationCandidates(product.Code, statuses, product.ChargeOffAfterDPD)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			rule := fmt.Sprintf("%s.charge_off_after_dpd=%d", product.Code, product.ChargeOffAfterDPD)
+			for i := range loans {
+				proposed, err := s.proposeChargeOff(&loans[i], rule)
+				if err != nil {
+					errs = append(errs, fmt.Errorf("loan %s: %w", loans[i].ID, err))
+					continue
+				}
+				if proposed {
+					result.ChargeOffsProposed++
+				}
+			}
+		}
+	}
+
+	return result, errors.Join(errs...)
+}
+
+// markDefaulted moves an active loan to defaulted and logs the rule that fired
+func (s *LoanService) markDefaulted(loan *models.Loan, rule string) error {
+	return s.repos.WithTransaction(func(repo repositories.RepositoryManager) error {
+		if err := repo.Loans().UpdateStatus(loan.ID, models.LoanStatusDefaulted); err != nil {
+			return err
+		}
+		return repo.StatusChanges().Create(&models.LoanStatusChange{
+			LoanID:      loan.ID,
+			FromStatus:  loan.Status,
+			ToStatus:    models.LoanStatusDefaulted,
+			Rule:        rule,
+			DaysPastDue: loan.DaysPastDue,
+			ChangedBy:   models.StatusChangedBySystem,
+		})
+	})
+}
+
+// proposeChargeOff queues a loan for charge-off review unless it has been proposed before.
+// A rejected proposal is not raised again; the loan can still be written off by hand.
+func (s *LoanService) proposeChargeOff(loan *models.Loan, rule string) (bool, error) {
+	exists, err := s.repos.ChargeOffs().ExistsForLoan(loan.ID)
+	if err != nil || exists {
+		return false, err
+	}
+
+	err = s.repos.ChargeOffs().Create(&models.ChargeOffProposal{
+		LoanID:      loan.ID,
+		Balance:     loan.CurrentBalance,
+		DaysPastDue: loan.DaysPastDue,
+		Rule:        rule,
+		Status:      models.ChargeOffStatusPending,
+	})
+	return err == nil, err
+}
+
+// ListChargeOffProposals returns the charge-off review queue, optionally narrowed to one status
+func (s *LoanService) ListChargeOffProposals(status string) ([]models.ChargeOffProposal, error) {
+	return s.repos.ChargeOffs().List(status)
+}
+
+// ReviewChargeOff approves or rejects a pending charge-off proposal.
+// Approving it writes the loan off in the same transaction. The caller is
+// recorded as the reviewer.
+func (s *LoanService) ReviewChargeOff(id uuid.UUID, approve bool, notes string) (*models.ChargeOffProposal, error) {
+	if err := authorize(s.ctx, s.access, auth.PermLoansApprove); err != nil {
+		return nil, err
+	}
+	reviewedBy := actor(s.ctx)
+	var proposal *models.ChargeOffProposal
+
+	err := s.repos.WithTransaction(func(repo repositories.RepositoryManager) error {
+		var err error
+		proposal, err = repo.ChargeOffs().GetByID(id)
+		if err != nil {
+			return ErrChargeOffNotFound
+		}
+		if proposal.Status != models.ChargeOffStatusPending {
+			return ErrChargeOffReviewed
+		}
+
+		now := time.Now()
+		proposal.ReviewedBy = reviewedBy
+		proposal.ReviewNotes = notes
+		proposal.ReviewedAt = &now
+		proposal.Status = models.ChargeOffStatusRejected
+
+		if approve {
+			proposal.Status = models.ChargeOffStatusApproved
+			loan, err := repo.Loans().GetByID(proposal.LoanID)
+			if err != nil {
+				return ErrLoanNotFound
+			}
+			reason := notes
+			if reason == "" {
+				reason = "charge-off approved"
+			}
+			if err := writeOff(repo, loan, reason, proposal.Rule, reviewedBy); err != nil {
+				return err
+			}
+		}
+
+		return repo.ChargeOffs().Update(proposal)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return proposal, nil
+}
+
+// GetStatusHistory returns the status changes of a loan, oldest first
+func (s *LoanService) GetStatusHistory(loanID uuid.UUID) ([]models.LoanStatusChange, error) {
+	if _, err := s.repos.Loans().GetByID(loanID); err != nil {
+		return nil, ErrLoanNotFound
+	}
+
+	return s.repos.StatusChanges().GetByLoanID(loanID)
+}
+
+// ListProducts returns every loan product
+func (s *LoanService) ListProducts() ([]models.LoanProduct, error) {
+	return s.repos.Products().List()
+}
+
+// UpdateProductThresholds changes a product's escalation thresholds
+func (s *LoanService) UpdateProductThresholds(code string, thresholds ProductThresholds) (*models.LoanProduct, error) {
+	product, err := s.repos.Products().GetByCode(code)
+	if err != nil {
+		return nil, ErrProductNotFound
+	}
+
+	if thresholds.DefaultAfterDPD != nil {
+		product.DefaultAfterDPD = *thresholds.DefaultAfterDPD
+	}
+	if thresholds.ChargeOffAfterDPD != nil {
+		product.ChargeOffAfterDPD = *thresholds.ChargeOffAfterDPD
+	}
+	if product.DefaultAfterDPD > 0 && product.ChargeOffAfterDPD > 0 && product.ChargeOffAfterDPD <= product.DefaultAfterDPD {
+		return nil, ErrInvalidThresholds
+	}
+
+	if err := s.repos.Products().Update(product); err != nil {
+		return nil, err
+	}
+	return product, nil
+}
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"errors"
+	"loan-billing-system/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrInvalidFee is returned when a fee definition has an unknown type or method, or a negative charge
+	ErrInvalidFee = errors.New("fee type must be deducted, capitalized or installment, method must be flat or percent, and the charge must not be negative")
+	// ErrFeeNotFound is returned when a fee definition does not exist on a product
+	ErrFeeNotFound = errors.New("fee definition not found")
+	// ErrInvalidReportPeriod is returned when a report period does not end after it starts
+	ErrInvalidReportPeriod = errors.New("report period must end after it starts")
+)
+
+// ListProductFees returns the fees charged on a product's loans
+func (s *LoanService) ListProductFees(productCode string) ([]models.FeeDefinition, error) {
+	if _, err := s.repos.Products().GetByCode(productCode); err != nil {
+		return nil, ErrProductNotFound
+	}
+
+	return s.repos.Fees().GetDefinitionsByProductCode(productCode)
+}
+
+// AddProductFee attaches a fee to a product. It applies to loans created after it is added.
+func (s *LoanService) AddProductFee(productCode string, definition models.FeeDefinition) (*models.FeeDefinition, error) {
+	if _, err := s.repos.Products().GetByCode(productCode); err != nil {
+		return nil, ErrProductNotFound
+	}
+
+	switch definition.Type {
+	case models.FeeTypeDeducted, models.FeeTypeCapitalized, models.FeeTypeInstallment:
+	default:
+		return nil, ErrInvalidFee
+	}
+	switch definition.Method {
+	case models.FeeMethodFlat, models.FeeMethodPercent:
+	default:
+		return nil, ErrInvalidFee
+	}
+	if definition.Amount < 0 || definition.Rate < 0 {
+		return nil, ErrInvalidFee
+	}
+
+	definition.ProductCode = productCode
+	if err := s.repos.Fees().CreateDefinition(&definition); err != nil {
+		return nil, err
+	}
+
+	return &definition, nil
+}
+
+// RemoveProductFee detaches a fee from a product. Loans already charged keep their fees.
+func (s *LoanService) RemoveProductFee(productCode string, id uuid.UUID) error {
+	if err := s.repos.Fees().DeleteDefinition(productCode, id); err != nil {
+		return ErrFeeNotFound
+	}
+	return nil
+}
+
+// GetLoanFees returns the fees charged on a loan
+func (s *LoanService) GetLoanFees(loanID uuid.UUID) ([]models.LoanFee, error) {
+	if _, err := s.repos.Loans().GetByID(loanID); err != nil {
+		return nil, ErrLoanNotFound
+	}
+
+	return s.repos.Fees().GetByLoanID(loanID)
+}
+
+// IncomeReport breaks down what was earned over a period. Interest and
+// installment fees are earned as payments are received; upfront fees are
+// earned when the loan is created.
+type IncomeReport struct {
+	From                 time.Time
+	To                   time.Time
+	PrincipalCollected   int64
+	InterestIncome       int64
+	InstallmentFeeIncome int64
+	UpfrontFeeIncome     int64
+}
+
+// FeeIncome returns the total fee income of the report
+func (r *IncomeReport) FeeIncome() int64 {
+	return r.InstallmentFeeIncome + r.UpfrontFeeIncome
+}
+
+// GetIncomeReport reports the principal collected and the interest and fee income earned in [from, to)
+func (s *LoanService) GetIncomeReport(from, to time.Time) (*IncomeReport, error) {
+	if !to.After(from) {
+		return nil, ErrInvalidReportPeriod
+	}
+
+	totals, err := s.repos.Payments().SumAllocations(from, to)
+	if err != nil {
+		return nil, err
+	}
+	upfront, err := s.repos.Fees().SumUpfront(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return &IncomeReport{
+		From:                 from,
+		To:                   to,
+		PrincipalCollected:   totals.Principal,
+		InterestIncome:       totals.Interest,
+		InstallmentFeeIncome: totals.Fees,
+		UpfrontFeeIncome:     upfront,
+	}, nil
+}
@@ -0,0 +1,389 @@
+package services
+
+import (
+	"errors"
+	"loan-billing-system/internal/models"
+	"loan-billing-system/internal/repositories"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrGroupNotFound is returned when a loan group does not exist
+	ErrGroupNotFound = errors.New("loan group not found")
+	// ErrNotGroupMember is returned when a borrower is not a member of the loan group
+	ErrNotGroupMember = errors.New("borrower is not a member of this loan group")
+	// ErrAlreadyGroupMember is returned when a borrower is already a member of the loan group
+	ErrAlreadyGroupMember = errors.New("borrower is already a member of this loan group")
+	// ErrRemoveGroupLeader is returned when removing the leader of a loan group
+	ErrRemoveGroupLeader = errors.New("the group leader cannot be removed; appoint another leader first")
+	// ErrMemberHasOpenLoan is returned when removing a member who still has an open loan through the group
+	ErrMemberHasOpenLoan = errors.New("member still has an open loan through this group")
+	// ErrGroupPaymentMismatch is returned when a group payment does not cover whole installments
+	ErrGroupPaymentMismatch = errors.New("group payment must equal whole installments, applied to the oldest unpaid installments first")
+)
+
+// GroupInstallment is an installment of a member's loan within a group view
+type GroupInstallment struct {
+	LoanID     uuid.UUID
+	BorrowerID uuid.UUID
+	Installment
+}
+
+// GroupScheduleDay gathers the installments of a group's loans that fall due on the same day
+type GroupScheduleDay struct {
+	DueDate      time.Time
+	TotalDue     int64
+	TotalPaid    int64
+	Installments []GroupInstallment
+}
+
+// GroupCollection lists what a group owes as of a date: every unpaid
+// installment of its members' loans that has fallen due, overdue ones included
+type GroupCollection struct {
+	AsOf         time.Time
+	TotalDue     int64
+	Installments []GroupInstallment
+}
+
+// GroupSummary is a loan group with the state of its members' loans. The
+// group is delinquent as soon as any member has an overdue installment.
+type GroupSummary struct {
+	Group          *models.LoanGroup
+	Loans          []models.Loan
+	Outstanding    int64
+	IsDelinquent   bool
+	OverdueMembers []uuid.UUID
+	OverdueAmount  int64
+}
+
+// GroupPaymentResult is a group payment with the member payments it was split into
+type GroupPaymentResult struct {
+	GroupPayment *models.GroupPayment
+	Payments     []models.Payment
+}
+
+// CreateGroup creates a loan group. The leader is made a member if they are not listed.
+func (s *LoanService) CreateGroup(name string, leaderID uuid.UUID, memberIDs []uuid.UUID) (*models.LoanGroup, error) {
+	group := &models.LoanGroup{Name: name, LeaderID: leaderID}
+
+	seen := make(map[uuid.UUID]bool, len(memberIDs)+1)
+	for _, borrowerID := range append([]uuid.UUID{leaderID}, memberIDs...) {
+		if seen[borrowerID] {
+			continue
+		}
+		if _, err := s.repos.Borrowers().GetByID(borrowerID); err != nil {
+			return nil, ErrBorrowerNotFound
+		}
+		seen[borrowerID] = true
+		group.Members = append(group.Members, models.LoanGroupMember{BorrowerID: borrowerID})
+	}
+
+	if err := s.repos.Groups().Create(group); err != nil {
+		return nil, err
+	}
+
+	return group, nil
+}
+
+// GetGroup returns a loan group with its loans, outstanding balance and delinquency
+func (s *LoanService) GetGroup(id uuid.UUID) (*GroupSummary, error) {
+	group, err := s.repos.Groups().GetByID(id)
+	if err != nil {
+		return nil, ErrGroupNotFound
+	}
+	loans, err := s.repos.Loans().GetByGroupID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &GroupSummary{Group: group, Loans: loans}
+	now := time.Now()
+	overdue := make(map[uuid.UUID]bool)
+	for _, loan := range loans {
+		if !isOpen(&loan) {
+			continue
+		}
+		summary.Outstanding += loan.CurrentBalance
+
+		installments, err := s.loanInstallments(loan.ID, now)
+		if err != nil {
+			return nil, err
+		}
+		for _, installment := range installments {
+			if !installment.IsOverdue {
+				continue
+			}
+			summary.OverdueAmount += installment.Schedule.Amount - installment.AmountPaid
+			if !overdue[loan.BorrowerID] {
+				overdue[loan.BorrowerID] = true
+				summary.OverdueMembers = append(summary.OverdueMembers, loan.BorrowerID)
+			}
+		}
+	}
+	summary.IsDelinquent = len(summary.OverdueMembers) > 0
+
+	return summary, nil
+}
+
+// AddGroupMember adds a borrower to a loan group
+func (s *LoanService) AddGroupMember(groupID, borrowerID uuid.UUID) (*models.LoanGroupMember, error) {
+	group, err := s.repos.Groups().GetByID(groupID)
+	if err != nil {
+		return nil, ErrGroupNotFound
+	}
+	if isGroupMember(group, borrowerID) {
+		return nil, ErrAlreadyGroupMember
+	}
+	if _, err := s.repos.Borrowers().GetByID(borrowerID); err != nil {
+		return nil, ErrBorrowerNotFound
+	}
+
+	member := &models.LoanGroupMember{GroupID: groupID, BorrowerID: borrowerID}
+	if err := s.repos.Groups().AddMember(member); err != nil {
+		return nil, err
+	}
+
+	return member, nil
+}
+
+// RemoveGroupMember removes a borrower from a loan group. The leader and
+// members with an open loan through the group cannot be removed.
+func (s *LoanService) RemoveGroupMember(groupID, borrowerID uuid.UUID) error {
+	group, err := s.repos.Groups().GetByID(groupID)
+	if err != nil {
+		return ErrGroupNotFound
+	}
+	if !isGroupMember(group, borrowerID) {
+		return ErrNotGroupMember
+	}
+	if group.LeaderID == borrowerID {
+		return ErrRemoveGroupLeader
+	}
+
+	loans, err := s.repos.Loans().GetByGroupID(groupID)
+	if err != nil {
+		return err
+	}
+	for i := range loans {
+		if loans[i].BorrowerID == borrowerID && isOpen(&loans[i]) {
+			return ErrMemberHasOpenLoan
+		}
+	}
+
+	return s.repos.Groups().RemoveMember(groupID, borrowerID)
+}
+
+// SetGroupLeader appoints a member as the leader of a loan group
+func (s *LoanService) SetGroupLeader(groupID, borrowerID uuid.UUID) (*models.LoanGroup, error) {
+	group, err := s.repos.Groups().GetByID(groupID)
+	if err != nil {
+		return nil, ErrGroupNotFound
+	}
+	if !isGroupMember(group, borrowerID) {
+		return nil, ErrNotGroupMember
+	}
+
+	group.LeaderID = borrowerID
+	if err := s.repos.Groups().Update(group); err != nil {
+		return nil, err
+	}
+
+	return group, nil
+}
+
+// GetGroupSchedule returns the installments of a group's loans, gathered by due date
+func (s *LoanService) GetGroupSchedule(groupID uuid.UUID) ([]GroupScheduleDay, error) {
+	installments, err := s.groupInstallments(groupID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	var days []GroupScheduleDay
+	index := make(map[time.Time]int)
+	for _, installment := range installments {
+		if installment.Schedule.Cancelled {
+			continue
+		}
+		dueDate := truncateToDay(installment.Schedule.DueDate)
+		i, ok := index[dueDate]
+		if !ok {
+			i = len(days)
+			index[dueDate] = i
+			days = append(days, GroupScheduleDay{DueDate: dueDate})
+		}
+		days[i].TotalDue += installment.Schedule.Amount
+		days[i].TotalPaid += installment.AmountPaid
+		days[i].Installments = append(days[i].Installments, installment)
+	}
+
+	return days, nil
+}
+
+// GetGroupCollection returns what a group owes as of the given time
+func (s *LoanService) GetGroupCollection(groupID uuid.UUID, asOf time.Time) (*GroupCollection, error) {
+	installments, err := s.groupInstallments(groupID, asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	collection := &GroupCollection{AsOf: asOf, Installments: []GroupInstallment{}}
+	for _, installment := range installments {
+		if !isCollectable(installment, asOf) {
+			continue
+		}
+		collection.TotalDue += installment.Schedule.Amount - installment.AmountPaid
+		collection.Installments = append(collection.Installments, installment)
+	}
+
+	return collection, nil
+}
+
+// MakeGroupPayment records one payment collected from a group and splits it
+// across the members' loans. The amount is applied to whole installments,
+// oldest due date first; it must add up exactly, or nothing is recorded.
+// The caller is recorded as the collector.
+func (s *LoanService) MakeGroupPayment(groupID uuid.UUID, amount int64) (*GroupPaymentResult, error) {
+	var result *GroupPaymentResult
+
+	err := s.repos.WithTransaction(func(repo repositories.RepositoryManager) error {
+		if _, err := repo.Groups().GetByID(groupID); err != nil {
+			return ErrGroupNotFound
+		}
+		loans, err := repo.Loans().GetByGroupID(groupID)
+		if err != nil {
+			return err
+		}
+
+		// Line up every unpaid installment of the group, oldest first
+		var unpaid []models.Schedule
+		for _, loan := range loans {
+			if !isOpen(&loan) {
+				continue
+			}
+			schedules, err := repo.Schedules().GetUnpaidByLoanID(loan.ID)
+			if err != nil {
+				return err
+			}
+			unpaid = append(unpaid, schedules...)
+		}
+		sort.SliceStable(unpaid, func(i, j int) bool {
+			return unpaid[i].DueDate.Before(unpaid[j].DueDate)
+		})
+
+		// Plan the split before recording anything
+		remaining := amount
+		var plan []models.Schedule
+		for _, schedule := range unpaid {
+			if schedule.Amount > remaining {
+				break
+			}
+			plan = append(plan, schedule)
+			remaining -= schedule.Amount
+		}
+		if remaining != 0 || len(plan) == 0 {
+			return ErrGroupPaymentMismatch
+		}
+
+		groupPayment := &models.GroupPayment{
+			GroupID:     groupID,
+			Amount:      amount,
+			PaymentDate: time.Now(),
+			CollectedBy: actor(s.ctx),
+		}
+		if err := repo.Groups().CreatePayment(groupPayment); err != nil {
+			return err
+		}
+
+		result = &GroupPaymentResult{GroupPayment: groupPayment}
+		for _, schedule := range plan {
+			payment, err := s.recordPayment(repo, schedule.LoanID, schedule.Amount, &groupPayment.ID)
+			if err != nil {
+				return err
+			}
+			result.Payments = append(result.Payments, *payment)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// groupInstallments returns the installments of every open loan of a group, oldest due date first
+func (s *LoanService) groupInstallments(groupID uuid.UUID, currentDate time.Time) ([]GroupInstallment, error) {
+	if _, err := s.repos.Groups().GetByID(groupID); err != nil {
+		return nil, ErrGroupNotFound
+	}
+	loans, err := s.repos.Loans().GetByGroupID(groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	var installments []GroupInstallment
+	for _, loan := range loans {
+		if !isOpen(&loan) {
+			continue
+		}
+		loanInstallments, err := s.loanInstallments(loan.ID, currentDate)
+		if err != nil {
+			return nil, err
+		}
+		for _, installment := range loanInstallments {
+			installments = append(installments, GroupInstallment{
+				LoanID:      loan.ID,
+				BorrowerID:  loan.BorrowerID,
+				Installment: installment,
+			})
+		}
+	}
+	sort.SliceStable(installments, func(i, j int) bool {
+		return installments[i].Schedule.DueDate.Before(installments[j].Schedule.DueDate)
+	})
+
+	return installments, nil
+}
+
+// loanInstallments returns the current schedule of a loan with the payment status of each installment
+func (s *LoanService) loanInstallments(loanID uuid.UUID, currentDate time.Time) ([]Installment, error) {
+	schedules, err := s.repos.Schedules().GetByLoanID(loanID)
+	if err != nil {
+		return nil, err
+	}
+	payments, err := s.repos.Payments().GetByLoanID(loanID)
+	if err != nil {
+		return nil, err
+	}
+	return buildInstallments(schedules, payments, currentDate), nil
+}
+
+// isCollectable reports whether an installment is owed as of the given time
+func isCollectable(installment GroupInstallment, asOf time.Time) bool {
+	schedule := installment.Schedule
+	return !schedule.Paid && !schedule.Cancelled && !schedule.IsDeferred(asOf) && !schedule.DueDate.After(asOf)
+}
+
+// isGroupMember reports whether a borrower is a member of a loan group
+func isGroupMember(group *models.LoanGroup, borrowerID uuid.UUID) bool {
+	for _, member := range group.Members {
+		if member.BorrowerID == borrowerID {
+			return true
+		}
+	}
+	return false
+}
+
+// isOpen reports whether a loan is still being repaid
+func isOpen(loan *models.Loan) bool {
+	return loan.Status == models.LoanStatusActive || loan.Status == models.LoanStatusDefaulted
+}
+
+// truncateToDay returns midnight at the start of the given time's day, in its location
+func truncateToDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
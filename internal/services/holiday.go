@@ -0,0 +1,137 @@
+package services
+
+import (
+	"errors"
+	"loan-billing-system/internal/auth"
+	"loan-billing-system/internal/models"
+	"loan-billing-system/internal/repositories"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrLoanNotDeferrable is returned when a loan's status does not allow a payment holiday
+	ErrLoanNotDeferrable = errors.New("only active loans can be granted a payment holiday")
+	// ErrHolidayInProgress is returned when a loan is already on a payment holiday
+	ErrHolidayInProgress = errors.New("loan is already on a payment holiday")
+	// ErrNothingToDefer is returned when a loan has no unpaid installments left
+	ErrNothingToDefer = errors.New("loan has no unpaid installments to defer")
+)
+
+// HolidayTerms describe a payment holiday to grant on a loan
+type HolidayTerms struct {
+	Installments   uint
+	ReasonCode     string
+	Notes          string
+	AccrueInterest bool
+}
+
+// GrantPaymentHoliday pushes a loan's unpaid installments back by the given
+// number of weeks. Installments moved by the holiday are ignored by the
+// delinquency check until it ends. When interest accrues, the interest on the
+// outstanding principal for the holiday is added to the final installment.
+// Granting a holiday needs approval; the caller is recorded as its approver.
+func (s *LoanService) GrantPaymentHoliday(loanID uuid.UUID, terms HolidayTerms) (*models.PaymentHoliday, error) {
+	if err := authorize(s.ctx, s.access, auth.PermLoansApprove); err != nil {
+		return nil, err
+	}
+
+	var holiday *models.PaymentHoliday
+
+	err := s.repos.WithTransaction(func(repo repositories.RepositoryManager) error {
+		loan, err := repo.Loans().GetByID(loanID)
+		if err != nil {
+			return ErrLoanNotFound
+		}
+		if loan.Status != models.LoanStatusActive {
+			return ErrLoanNotDeferrable
+		}
+
+		now := time.Now()
+		if loan.OnHoliday(now) {
+			return ErrHolidayInProgress
+		}
+
+		unpaidSchedules, err := repo.Schedules().GetUnpaidByLoanID(loanID)
+		if err != nil {
+			return err
+		}
+		if len(unpaidSchedules) == 0 {
+			return ErrNothingToDefer
+		}
+
+		// Move every unpaid installment back, remembering where it was first due
+		shift := int(terms.Installments) * 7
+		holidayEnd := now.AddDate(0, 0, shift)
+		originalTotal := calculateTotalDue(loan.Amount, loan.InterestRate, loan.TermWeeks)
+		var principalOutstanding int64
+		for i := range unpaidSchedules {
+			schedule := &unpaidSchedules[i]
+			principalOutstanding += schedule.Principal(loan.Amount, originalTotal)
+			if schedule.OriginalDueDate == nil {
+				originalDueDate := schedule.DueDate
+				schedule.OriginalDueDate = &originalDueDate
+			}
+			schedule.DueDate = schedule.DueDate.AddDate(0, 0, shift)
+			schedule.DeferredUntil = &holidayEnd
+		}
+
+		var accruedInterest int64
+		if terms.AccrueInterest {
+			accruedInterest = int64(float64(principalOutstanding) * loan.InterestRate / 100 * float64(terms.Installments) / 52)
+			last := &unpaidSchedules[len(unpaidSchedules)-1]
+			last.Amount += accruedInterest
+			last.InterestAmount += accruedInterest
+		}
+
+		if err := repo.Schedules().UpdateBatch(unpaidSchedules); err != nil {
+			return err
+		}
+
+		holiday = &models.PaymentHoliday{
+			LoanID:          loanID,
+			Installments:    terms.Installments,
+			StartDate:       now,
+			EndDate:         holidayEnd,
+			ReasonCode:      terms.ReasonCode,
+			Notes:           terms.Notes,
+			ApprovedBy:      actor(s.ctx),
+			AccruedInterest: accruedInterest,
+		}
+		if err := repo.Holidays().Create(holiday); err != nil {
+			return err
+		}
+
+		// Re-evaluate delinquency now that the deferred installments no longer count
+		schedules, err := repo.Schedules().GetByLoanID(loanID)
+		if err != nil {
+			return err
+		}
+		status := applyCureHold(loan, evaluateDelinquency(schedules, now))
+
+		loan.CurrentBalance += accruedInterest
+		loan.HolidayUntil = &holidayEnd
+		loan.IsDelinquent = status.isDelinquent
+		loan.DaysPastDue = status.daysPastDue
+		if err := repo.Loans().Update(loan); err != nil {
+			return err
+		}
+
+		return s.updatePartyDelinquency(repo, loan, status.isDelinquent)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return holiday, nil
+}
+
+// GetPaymentHolidays returns the payment holidays granted on a loan, oldest first
+func (s *LoanService) GetPaymentHolidays(loanID uuid.UUID) ([]models.PaymentHoliday, error) {
+	if _, err := s.repos.Loans().GetByID(loanID); err != nil {
+		return nil, ErrLoanNotFound
+	}
+
+	return s.repos.Holidays().GetByLoanID(loanID)
+}
@@ -0,0 +1,363 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"loan-billing-system/internal/auth"
+	"loan-billing-system/internal/models"
+	"net/mail"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrInvalidProfile is wrapped by every borrower profile validation error
+	ErrInvalidProfile = errors.New("invalid borrower profile")
+	// ErrInvalidKYCTransition is returned when a borrower's KYC cannot move to the requested status
+	ErrInvalidKYCTransition = errors.New("KYC status cannot change that way")
+	// ErrKYCIncomplete is returned when verifying a borrower whose profile or documents are missing something
+	ErrKYCIncomplete = errors.New("KYC cannot be verified yet")
+	// ErrAddressNotFound is returned when an address does not belong to the borrower
+	ErrAddressNotFound = errors.New("address not found")
+)
+
+// kycTransitions lists the statuses a borrower's KYC may move to from each status
+var kycTransitions = map[string][]string{
+	models.KYCStatusPending:  {models.KYCStatusVerified, models.KYCStatusRejected},
+	models.KYCStatusVerified: {models.KYCStatusPending, models.KYCStatusExpired},
+	models.KYCStatusRejected: {models.KYCStatusPending},
+	models.KYCStatusExpired:  {models.KYCStatusPending},
+}
+
+// nationalIDFormats are the accepted formats of each identity document number
+var nationalIDFormats = map[string]*regexp.Regexp{
+	models.NationalIDTypeNationalID:     regexp.MustCompile(`^[0-9]{6,20}$`),
+	models.NationalIDTypePassport:       regexp.MustCompile(`^[A-Z0-9]{6,12}$`),
+	models.NationalIDTypeDriversLicense: regexp.MustCompile(`^[A-Z0-9-]{5,20}$`),
+	models.NationalIDTypeTaxID:          regexp.MustCompile(`^[0-9.-]{8,20}$`),
+}
+
+var (
+	phonePattern    = regexp.MustCompile(`^\+[1-9][0-9]{7,14}$`)
+	countryPattern  = regexp.MustCompile(`^[A-Z]{2}$`)
+	checksumPattern = regexp.MustCompile(`^[0-9a-f]{64}$`)
+)
+
+// documentContentTypes are the file types accepted as KYC documents
+var documentContentTypes = []string{"application/pdf", "image/jpeg", "image/png"}
+
+// maxDocumentSize is the largest KYC document accepted, in bytes
+const maxDocumentSize = 10 << 20
+
+// BorrowerProfile is who a borrower is: their identity, contact details and income
+type BorrowerProfile struct {
+	Name             string
+	ContactInfo      string
+	NationalIDType   string
+	NationalID       string
+	DateOfBirth      *time.Time
+	Phone            string
+	Email            string
+	EmploymentStatus string
+	Employer         string
+	MonthlyIncome    int64
+}
+
+// applyTo copies the profile onto a borrower
+func (p BorrowerProfile) applyTo(borrower *models.Borrower) {
+	borrower.Name = p.Name
+	borrower.ContactInfo = p.ContactInfo
+	borrower.NationalIDType = p.NationalIDType
+	borrower.NationalID = p.NationalID
+	borrower.DateOfBirth = p.DateOfBirth
+	borrower.Phone = p.Phone
+	borrower.Email = p.Email
+	borrower.EmploymentStatus = p.EmploymentStatus
+	borrower.Employer = p.Employer
+	borrower.MonthlyIncome = p.MonthlyIncome
+}
+
+// KYCReview moves a borrower's KYC to a new status. Rejections need a reason.
+type KYCReview struct {
+	Status string
+	Reason string
+}
+
+// validateProfile checks every field of a borrower profile
+func (s *BorrowerService) validateProfile(profile BorrowerProfile, now time.Time) error {
+	if profile.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidProfile)
+	}
+	if profile.ContactInfo == "" && profile.Phone == "" && profile.Email == "" {
+		return fmt.Errorf("%w: a phone number, email or contact info is required", ErrInvalidProfile)
+	}
+
+	if (profile.NationalIDType == "") != (profile.NationalID == "") {
+		return fmt.Errorf("%w: national ID and its type go together", ErrInvalidProfile)
+	}
+	if profile.NationalIDType != "" {
+		format, ok := nationalIDFormats[profile.NationalIDType]
+		if !ok {
+			return fmt.Errorf("%w: national ID type must be national_id, passport, drivers_license or tax_id", ErrInvalidProfile)
+		}
+		if !format.MatchString(profile.NationalID) {
+			return fmt.Errorf("%w: national ID is not a valid %s number", ErrInvalidProfile, profile.NationalIDType)
+		}
+	}
+
+	if profile.DateOfBirth != nil {
+		if profile.DateOfBirth.After(now) {
+			return fmt.Errorf("%w: date of birth is in the future", ErrInvalidProfile)
+		}
+		if profile.DateOfBirth.AddDate(int(s.policy.MinimumAge), 0, 0).After(now) {
+			return fmt.Errorf("%w: borrower must be at least %d years old", ErrInvalidProfile, s.policy.MinimumAge)
+		}
+	}
+
+	if profile.Phone != "" && !phonePattern.MatchString(profile.Phone) {
+		return fmt.Errorf("%w: phone must be in E.164 format, e.g. +6281234567890", ErrInvalidProfile)
+	}
+	if profile.Email != "" {
+		if address, err := mail.ParseAddress(profile.Email); err != nil || address.Address != profile.Email {
+			return fmt.Errorf("%w: email is not a valid address", ErrInvalidProfile)
+		}
+	}
+
+	switch profile.EmploymentStatus {
+	case "", models.EmploymentUnemployed, models.EmploymentRetired, models.EmploymentStudent:
+	case models.EmploymentEmployed, models.EmploymentSelfEmployed:
+		if profile.Employer == "" {
+			return fmt.Errorf("%w: employer is required when %s", ErrInvalidProfile, profile.EmploymentStatus)
+		}
+	default:
+		return fmt.Errorf("%w: employment status must be employed, self_employed, unemployed, retired or student", ErrInvalidProfile)
+	}
+	if profile.MonthlyIncome < 0 {
+		return fmt.Errorf("%w: monthly income must not be negative", ErrInvalidProfile)
+	}
+
+	return nil
+}
+
+// validateAddress checks an address's type, required lines and country code
+func validateAddress(address *models.BorrowerAddress) error {
+	switch address.Type {
+	case models.AddressTypeResidential, models.AddressTypeMailing, models.AddressTypeWork:
+	default:
+		return fmt.Errorf("%w: address type must be residential, mailing or work", ErrInvalidProfile)
+	}
+	if address.Line1 == "" || address.City == "" {
+		return fmt.Errorf("%w: address line 1 and city are required", ErrInvalidProfile)
+	}
+	if !countryPattern.MatchString(address.Country) {
+		return fmt.Errorf("%w: country must be an ISO 3166-1 alpha-2 code", ErrInvalidProfile)
+	}
+	return nil
+}
+
+// UpdateProfile replaces a borrower's profile. Changing who a verified
+// borrower is (name, national ID or date of birth) sends their KYC back to pending.
+func (s *BorrowerService) UpdateProfile(id uuid.UUID, profile BorrowerProfile) (*models.Borrower, error) {
+	borrower, err := s.repos.Borrowers().GetByID(id)
+	if err != nil {
+		return nil, ErrBorrowerNotFound
+	}
+	if borrower.AnonymizedAt != nil {
+		return nil, ErrAlreadyAnonymized
+	}
+	if err := s.validateProfile(profile, time.Now()); err != nil {
+		return nil, err
+	}
+
+	identityChanged := borrower.Name != profile.Name ||
+		borrower.NationalIDType != profile.NationalIDType ||
+		borrower.NationalID != profile.NationalID ||
+		!sameDate(borrower.DateOfBirth, profile.DateOfBirth)
+	if identityChanged && borrower.KYCStatus == models.KYCStatusVerified {
+		resetKYC(borrower)
+	}
+
+	profile.applyTo(borrower)
+	if err := s.repos.Borrowers().Update(borrower); err != nil {
+		return nil, err
+	}
+	return borrower, nil
+}
+
+// AddAddress adds an address to a borrower
+func (s *BorrowerService) AddAddress(id uuid.UUID, address models.BorrowerAddress) (*models.BorrowerAddress, error) {
+	borrower, err := s.repos.Borrowers().GetByID(id)
+	if err != nil {
+		return nil, ErrBorrowerNotFound
+	}
+	if borrower.AnonymizedAt != nil {
+		return nil, ErrAlreadyAnonymized
+	}
+	if err := validateAddress(&address); err != nil {
+		return nil, err
+	}
+
+	address.BorrowerID = id
+	if err := s.repos.KYC().CreateAddress(&address); err != nil {
+		return nil, err
+	}
+	return &address, nil
+}
+
+// RemoveAddress removes one of a borrower's addresses
+func (s *BorrowerService) RemoveAddress(id, addressID uuid.UUID) error {
+	if _, err := s.repos.Borrowers().GetByID(id); err != nil {
+		return ErrBorrowerNotFound
+	}
+	if err := s.repos.KYC().DeleteAddress(id, addressID); err != nil {
+		return ErrAddressNotFound
+	}
+	return nil
+}
+
+// AddDocument records the metadata of a document supporting a borrower's KYC
+func (s *BorrowerService) AddDocument(id uuid.UUID, document models.BorrowerDocument) (*models.BorrowerDocument, error) {
+	borrower, err := s.repos.Borrowers().GetByID(id)
+	if err != nil {
+		return nil, ErrBorrowerNotFound
+	}
+	if borrower.AnonymizedAt != nil {
+		return nil, ErrAlreadyAnonymized
+	}
+
+	switch document.Type {
+	case models.DocumentTypeNationalID, models.DocumentTypePassport, models.DocumentTypeDriversLicense,
+		models.DocumentTypeProofOfAddress, models.DocumentTypePayslip, models.DocumentTypeBankStatement, models.DocumentTypeOther:
+	default:
+		return nil, fmt.Errorf("%w: unknown document type %q", ErrInvalidProfile, document.Type)
+	}
+	if !slices.Contains(documentContentTypes, document.ContentType) {
+		return nil, fmt.Errorf("%w: documents must be PDF, JPEG or PNG", ErrInvalidProfile)
+	}
+	if document.SizeBytes <= 0 || document.SizeBytes > maxDocumentSize {
+		return nil, fmt.Errorf("%w: documents must be between 1 byte and 10 MB", ErrInvalidProfile)
+	}
+	if !checksumPattern.MatchString(document.Checksum) {
+		return nil, fmt.Errorf("%w: checksum must be a hex-encoded SHA-256", ErrInvalidProfile)
+	}
+	if document.FileName == "" || document.StorageKey == "" {
+		return nil, fmt.Errorf("%w: file name and storage key are required", ErrInvalidProfile)
+	}
+
+	document.BorrowerID = id
+	if err := s.repos.KYC().CreateDocument(&document); err != nil {
+		return nil, err
+	}
+	return &document, nil
+}
+
+// GetDocuments returns the documents supporting a borrower's KYC, oldest first
+func (s *BorrowerService) GetDocuments(id uuid.UUID) ([]models.BorrowerDocument, error) {
+	if _, err := s.repos.Borrowers().GetByID(id); err != nil {
+		return nil, ErrBorrowerNotFound
+	}
+	return s.repos.KYC().GetDocuments(id)
+}
+
+// ReviewKYC moves a borrower's KYC to a new status. A lapsed verification
+// counts as expired. Verifying needs a national ID, a date of birth, an
+// address and an identity document that has not expired; the verification
+// lasts KYCValidityDays, or until that document expires if sooner. The
+// caller is recorded as the reviewer.
+func (s *BorrowerService) ReviewKYC(id uuid.UUID, review KYCReview) (*models.Borrower, error) {
+	if err := authorize(s.ctx, s.access, auth.PermBorrowersKYC); err != nil {
+		return nil, err
+	}
+	borrower, err := s.repos.Borrowers().GetByID(id)
+	if err != nil {
+		return nil, ErrBorrowerNotFound
+	}
+
+	now := time.Now()
+	current := borrower.KYCStatusAt(now)
+	if !slices.Contains(kycTransitions[current], review.Status) {
+		return nil, fmt.Errorf("%w: from %s to %s", ErrInvalidKYCTransition, current, review.Status)
+	}
+
+	switch review.Status {
+	case models.KYCStatusVerified:
+		expiresAt, err := s.verificationExpiry(borrower, now)
+		if err != nil {
+			return nil, err
+		}
+		borrower.KYCExpiresAt = expiresAt
+		borrower.KYCRejectionReason = ""
+	case models.KYCStatusRejected:
+		if review.Reason == "" {
+			return nil, fmt.Errorf("%w: a rejection needs a reason", ErrInvalidKYCTransition)
+		}
+		borrower.KYCRejectionReason = review.Reason
+	case models.KYCStatusPending:
+		resetKYC(borrower)
+	}
+
+	borrower.KYCStatus = review.Status
+	borrower.KYCReviewedBy = actor(s.ctx)
+	borrower.KYCReviewedAt = &now
+	if err := s.repos.Borrowers().Update(borrower); err != nil {
+		return nil, err
+	}
+	return borrower, nil
+}
+
+// verificationExpiry checks that a borrower can be verified and returns when the verification will lapse
+func (s *BorrowerService) verificationExpiry(borrower *models.Borrower, now time.Time) (*time.Time, error) {
+	if borrower.NationalID == "" || borrower.DateOfBirth == nil {
+		return nil, fmt.Errorf("%w: national ID and date of birth are required", ErrKYCIncomplete)
+	}
+	if len(borrower.Addresses) == 0 {
+		return nil, fmt.Errorf("%w: an address is required", ErrKYCIncomplete)
+	}
+
+	documents, err := s.repos.KYC().GetDocuments(borrower.ID)
+	if err != nil {
+		return nil, err
+	}
+	var identity *models.BorrowerDocument
+	for i := range documents {
+		document := &documents[i]
+		if !document.IsIdentity() || (document.ExpiresAt != nil && !now.Before(*document.ExpiresAt)) {
+			continue
+		}
+		// Keep the identity document that stays valid the longest
+		if identity == nil || (identity.ExpiresAt != nil && (document.ExpiresAt == nil || document.ExpiresAt.After(*identity.ExpiresAt))) {
+			identity = document
+		}
+	}
+	if identity == nil {
+		return nil, fmt.Errorf("%w: a valid identity document is required", ErrKYCIncomplete)
+	}
+
+	var expiresAt *time.Time
+	if s.policy.KYCValidityDays > 0 {
+		lapse := now.AddDate(0, 0, int(s.policy.KYCValidityDays))
+		expiresAt = &lapse
+	}
+	if identity.ExpiresAt != nil && (expiresAt == nil || identity.ExpiresAt.Before(*expiresAt)) {
+		expiresAt = identity.ExpiresAt
+	}
+	return expiresAt, nil
+}
+
+// resetKYC sends a borrower's KYC back to pending, clearing the last review's outcome
+func resetKYC(borrower *models.Borrower) {
+	borrower.KYCStatus = models.KYCStatusPending
+	borrower.KYCExpiresAt = nil
+	borrower.KYCRejectionReason = ""
+}
+
+// sameDate reports whether two optional dates fall on the same calendar day
+func sameDate(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Format(time.DateOnly) == b.Format(time.DateOnly)
+}
@@ -1,92 +1,345 @@
 package services
 
 import (
+	"context"
 	"errors"
+	"loan-billing-system/internal/auth"
 	"loan-billing-system/internal/models"
 	"loan-billing-system/internal/repositories"
+	"loan-billing-system/internal/scoring"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+// ErrBorrowerNotFound is returned when a loan refers to a borrower that does not exist
+var ErrBorrowerNotFound = errors.New("borrower not found")
+
+// ErrLoanWrittenOff is returned when a repayment is made on a written-off loan
+var ErrLoanWrittenOff = errors.New("loan is written off; record a recovery instead")
+
+// ErrFeesExceedAmount is returned when a product's deducted fees leave nothing to disburse
+var ErrFeesExceedAmount = errors.New("deducted fees must be less than the loan amount")
+
+// LoanSummary pairs a loan with its next unpaid installment
+type LoanSummary struct {
+	Loan    models.Loan
+	NextDue *models.Schedule
+}
+
+// LoanPolicy holds the configurable rules applied by the loan service
+type LoanPolicy struct {
+	// RestructureCurePayments is how many payments a restructured loan must
+	// receive before its delinquency flag may clear; 0 clears it immediately
+	RestructureCurePayments uint
+	// GuarantorsShareDelinquency marks a loan's guarantors delinquent along
+	// with its borrowers; co-borrowers always are
+	GuarantorsShareDelinquency bool
+	// CreditLimit caps a borrower's open balances, new loan included; 0 means no limit
+	CreditLimit int64
+	// MaxConcurrentLoans caps how many open loans a borrower may hold; 0 means no limit
+	MaxConcurrentLoans uint
+	// RequireVerifiedKYC refuses new loans to borrowers whose KYC is not verified
+	RequireVerifiedKYC bool
+}
+
+// DefaultLoanPolicy returns the rules used when no policy is configured
+func DefaultLoanPolicy() LoanPolicy {
+	return LoanPolicy{
+		RestructureCurePayments: 3,
+	}
+}
+
 // LoanService handles loan business logic
 type LoanService struct {
-	repos repositories.RepositoryManager
+	repos  repositories.RepositoryManager
+	policy LoanPolicy
+	scorer scoring.Scorer
+	access *auth.Policy
+	ctx    context.Context
 }
 
 // NewLoanService creates a new loan service
 func NewLoanService(repos repositories.RepositoryManager) *LoanService {
 	return &LoanService{
-		repos: repos,
+		repos:  repos,
+		policy: DefaultLoanPolicy(),
+		ctx:    context.Background(),
 	}
 }
 
+// WithPolicy replaces the service's rules and returns the service
+func (s *LoanService) WithPolicy(policy LoanPolicy) *LoanService {
+	s.policy = policy
+	return s
+}
+
+// WithAccess sets the access policy sensitive actions are checked against
+// and returns the service
+func (s *LoanService) WithAccess(access *auth.Policy) *LoanService {
+	s.access = access
+	return s
+}
+
+// WithContext returns a copy of the service whose changes are made with
+// ctx, and so audited with the actor and request ID it carries
+func (s *LoanService) WithContext(ctx context.Context) *LoanService {
+	bound := *s
+	bound.repos = s.repos.WithContext(ctx)
+	bound.ctx = ctx
+	return &bound
+}
+
 // GetLoan retrieves a loan by ID
 func (s *LoanService) GetLoan(id uuid.UUID) (*models.Loan, error) {
 	return s.repos.Loans().GetByID(id)
 }
 
-// CreateLoan creates a new loan and generates payment schedules
+// LoanApplication is a request for a new loan under a product
+type LoanApplication struct {
+	BorrowerID   uuid.UUID
+	ProductCode  string
+	Amount       int64
+	InterestRate float64
+	TermWeeks    uint
+	// Parties are the loan's co-borrowers and guarantors; only BorrowerID and Role are used
+	Parties []models.LoanParty
+	// GroupID is the loan group the borrower takes the loan through, if any
+	GroupID *uuid.UUID
+	// Override lets a delinquent borrower through the credit checks
+	Override *CreditOverride
+}
+
+// CreateLoan creates a new loan under the default product and generates payment schedules
 func (s *LoanService) CreateLoan(borrowerID uuid.UUID, amount int64, interestRate float64, termWeeks uint) (*models.Loan, error) {
+	return s.ApplyForLoan(LoanApplication{
+		BorrowerID:   borrowerID,
+		ProductCode:  models.DefaultProductCode,
+		Amount:       amount,
+		InterestRate: interestRate,
+		TermWeeks:    termWeeks,
+	})
+}
+
+// ApplyForLoan creates a new loan under the application's product, charging
+// the product's fees, adds its co-borrowers and guarantors, and generates
+// payment schedules. Applications failing the credit checks are rejected with
+// a CreditRejection.
+func (s *LoanService) ApplyForLoan(application LoanApplication) (*models.Loan, error) {
 	// Check if borrower exists
-	_, err := s.repos.Borrowers().GetByID(borrowerID)
+	borrower, err := s.repos.Borrowers().GetByID(application.BorrowerID)
 	if err != nil {
-		return nil, errors.New("borrower not found")
+		return nil, ErrBorrowerNotFound
+	}
+	if borrower.AnonymizedAt != nil {
+		return nil, ErrAlreadyAnonymized
 	}
 
-	// Calculate total with interest
-	totalDue := calculateTotalDue(amount, interestRate, termWeeks)
+	if application.ProductCode == "" {
+		application.ProductCode = models.DefaultProductCode
+	}
+	if _, err := s.repos.Products().GetByCode(application.ProductCode); err != nil {
+		return nil, ErrProductNotFound
+	}
+	feeDefinitions, err := s.repos.Fees().GetDefinitionsByProductCode(application.ProductCode)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validateParties(application.BorrowerID, nil, application.Parties); err != nil {
+		return nil, err
+	}
+	if application.GroupID != nil {
+		group, err := s.repos.Groups().GetByID(*application.GroupID)
+		if err != nil {
+			return nil, ErrGroupNotFound
+		}
+		if !isGroupMember(group, application.BorrowerID) {
+			return nil, ErrNotGroupMember
+		}
+	}
 
-	// Create new loan
-	loan := models.Loan{
-		BorrowerID:     borrowerID,
-		Amount:         amount,
-		InterestRate:   interestRate,
-		TermWeeks:      termWeeks,
-		StartDate:      time.Now(),
-		Status:         "active",
-		CurrentBalance: totalDue,
+	calendar, err := s.calendar()
+	if err != nil {
+		return nil, err
 	}
 
-	// Save the loan
-	if err := s.repos.Loans().Create(&loan); err != nil {
+	loan, schedules, fees, err := newLoan(application.BorrowerID, application.ProductCode, application.Amount, application.InterestRate, application.TermWeeks, feeDefinitions, time.Now(), calendar)
+	if err != nil {
+		return nil, err
+	}
+	loan.GroupID = application.GroupID
+	reasons, err := s.checkCredit(borrower, &loan, application.Override)
+	if err != nil {
+		return nil, err
+	}
+	assessment, scoreReasons, err := s.assessCredit(borrower, &loan, application.Override)
+	if err != nil {
 		return nil, err
 	}
+	if reasons = append(reasons, scoreReasons...); len(reasons) > 0 {
+		return nil, &CreditRejection{Reasons: reasons, Assessment: assessment}
+	}
+
+	err = s.repos.WithTransaction(func(repo repositories.RepositoryManager) error {
+		// Save the loan
+		if err := repo.Loans().Create(&loan); err != nil {
+			return err
+		}
 
-	// Generate weekly payment schedule
-	if err := s.generateLoanSchedule(&loan); err != nil {
+		// Save the weekly payment schedule and the fees charged
+		for i := range schedules {
+			schedules[i].LoanID = loan.ID
+		}
+		if err := repo.Schedules().CreateBatch(schedules); err != nil {
+			return err
+		}
+		for i := range fees {
+			fees[i].LoanID = loan.ID
+		}
+		if err := repo.Fees().CreateBatch(fees); err != nil {
+			return err
+		}
+
+		if assessment != nil {
+			assessment.LoanID = loan.ID
+			if err := repo.Assessments().Create(assessment); err != nil {
+				return err
+			}
+		}
+
+		parties := make([]models.LoanParty, 0, len(application.Parties))
+		for _, party := range application.Parties {
+			parties = append(parties, models.LoanParty{LoanID: loan.ID, BorrowerID: party.BorrowerID, Role: party.Role})
+		}
+		return repo.Parties().CreateBatch(parties)
+	})
+	if err != nil {
 		return nil, err
 	}
 
 	return &loan, nil
 }
 
+// newLoan prices a new loan and lays out its weekly schedule without saving
+// either. Deducted fees reduce what is paid out, capitalized fees are added to
+// the principal and accrue interest, and installment fees are added to every
+// installment. The APR is computed on what the borrower actually receives.
+func newLoan(borrowerID uuid.UUID, productCode string, amount int64, interestRate float64, termWeeks uint, feeDefinitions []models.FeeDefinition, startDate time.Time, calendar models.Calendar) (models.Loan, []models.Schedule, []models.LoanFee, error) {
+	var deducted, capitalized, perInstallment int64
+	fees := make([]models.LoanFee, 0, len(feeDefinitions))
+	for _, definition := range feeDefinitions {
+		charge := definition.Charge(amount)
+		total := charge
+		switch definition.Type {
+		case models.FeeTypeDeducted:
+			deducted += charge
+		case models.FeeTypeCapitalized:
+			capitalized += charge
+		case models.FeeTypeInstallment:
+			perInstallment += charge
+			total = charge * int64(termWeeks)
+		}
+		fees = append(fees, models.LoanFee{
+			FeeDefinitionID: definition.ID,
+			Name:            definition.Name,
+			Type:            definition.Type,
+			Amount:          total,
+		})
+	}
+	if deducted >= amount {
+		return models.Loan{}, nil, nil, ErrFeesExceedAmount
+	}
+
+	// Calculate total with interest on the principal, capitalized fees included
+	principal := amount + capitalized
+	totalDue := calculateTotalDue(principal, interestRate, termWeeks)
+
+	loan := models.Loan{
+		BorrowerID:      borrowerID,
+		ProductCode:     productCode,
+		Amount:          principal,
+		DisbursedAmount: amount - deducted,
+		InterestRate:    interestRate,
+		TermWeeks:       termWeeks,
+		StartDate:       startDate,
+		Status:          models.LoanStatusActive,
+		CurrentBalance:  totalDue + perInstallment*int64(termWeeks),
+		ScheduleVersion: 1,
+	}
+
+	// Lay out the weekly payment schedule and disclose the cost it implies
+	schedules := buildSchedule(&loan, calendar)
+	for i := range schedules {
+		schedules[i].FeeAmount = perInstallment
+		schedules[i].Amount += perInstallment
+	}
+	disclosure, err := discloseCost(loan.DisbursedAmount, loan.StartDate, schedules)
+	if err != nil {
+		return models.Loan{}, nil, nil, err
+	}
+	loan.APR = disclosure.APR
+	loan.EffectiveRate = disclosure.EffectiveRate
+
+	return loan, schedules, fees, nil
+}
+
 // Helper function to calculate total due with interest
 func calculateTotalDue(principal int64, interestRate float64, termWeeks uint) int64 {
 	interestFactor := interestRate / 100 * float64(termWeeks) / 52
 	return int64(float64(principal) * (1 + interestFactor))
 }
 
-// generateLoanSchedule creates payment schedules for a loan
-func (s *LoanService) generateLoanSchedule(loan *models.Loan) error {
-	weeklyPayment := loan.CalculateWeeklyPayment()
+// buildSchedule lays out the weekly installments of a new loan without saving them
+func buildSchedule(loan *models.Loan, calendar models.Calendar) []models.Schedule {
+	return layoutSchedule(loan.Amount, loan.InterestRate, loan.TermWeeks, loan.StartDate, 1, calendar)
+}
 
-	// Create 50 weekly schedules
+// layoutSchedule splits principal plus flat interest into weekly installments
+// starting one week after startDate. An installment falling on a rest day or
+// holiday of the calendar is due on the next working day instead. The
+// rounding remainders are added to the final installment so that the
+// installments add up to the total due.
+func layoutSchedule(principal int64, interestRate float64, termWeeks uint, startDate time.Time, version uint, calendar models.Calendar) []models.Schedule {
+	totalDue := calculateTotalDue(principal, interestRate, termWeeks)
+	weeklyPayment := totalDue / int64(termWeeks)
+	weeklyPrincipal := principal / int64(termWeeks)
+
+	// Create one schedule per week of the term
 	var schedules []models.Schedule
-	for week := uint(1); week <= loan.TermWeeks; week++ {
-		dueDate := loan.StartDate.AddDate(0, 0, int(week)*7)
+	for week := uint(1); week <= termWeeks; week++ {
+		dueDate := calendar.NextWorkingDay(startDate.AddDate(0, 0, int(week)*7))
+		amount, principalAmount := weeklyPayment, weeklyPrincipal
+		if week == termWeeks {
+			amount = totalDue - weeklyPayment*int64(termWeeks-1)
+			principalAmount = principal - weeklyPrincipal*int64(termWeeks-1)
+		}
 		schedule := models.Schedule{
-			LoanID:     loan.ID,
-			WeekNumber: week,
-			DueDate:    dueDate,
-			Amount:     weeklyPayment,
-			Paid:       false,
+			Version:         version,
+			WeekNumber:      week,
+			DueDate:         dueDate,
+			Amount:          amount,
+			PrincipalAmount: principalAmount,
+			InterestAmount:  amount - principalAmount,
+			Paid:            false,
 		}
 		schedules = append(schedules, schedule)
 	}
 
-	// Save all schedules to database
-	return s.repos.Schedules().CreateBatch(schedules)
+	return schedules
+}
+
+// allocatePayment splits a payment on an installment into principal, interest
+// and fees. A payment settling less than the full installment, such as a
+// refinance payoff of an installment not yet due, covers its fees first and
+// then principal.
+func allocatePayment(payment *models.Payment, loan *models.Loan, schedule models.Schedule) {
+	if payment.Amount < schedule.Amount {
+		payment.Fees = min(payment.Amount, schedule.FeeAmount)
+		payment.Principal = payment.Amount - payment.Fees
+		return
+	}
+	totalDue := calculateTotalDue(loan.Amount, loan.InterestRate, loan.TermWeeks)
+	payment.Principal, payment.Interest, payment.Fees = schedule.Allocation(loan.Amount, totalDue)
 }
 
 // GetOutstanding returns the current outstanding balance for a loan
@@ -114,128 +367,221 @@ func (s *LoanService) IsDelinquent(loanID uuid.UUID) (bool, error) {
 		return false, err
 	}
 
-	// Get current date
-	currentDate := time.Now()
-
-	// Count consecutive unpaid schedules that are past due
-	var consecutiveMissed int
-	maxConsecutiveMissed := 0
+	status := applyCureHold(loan, evaluateDelinquency(schedules, time.Now()))
 
-	for i := 0; i < len(schedules); i++ {
-		if !schedules[i].Paid && schedules[i].DueDate.Before(currentDate) {
-			consecutiveMissed++
-			if consecutiveMissed > maxConsecutiveMissed {
-				maxConsecutiveMissed = consecutiveMissed
-			}
-		} else {
-			consecutiveMissed = 0
-		}
+	// Keep the loan's delinquency snapshot fresh for listings and reports
+	if err := s.repos.Loans().UpdateDelinquency(loanID, status.isDelinquent, status.daysPastDue); err != nil {
+		return status.isDelinquent, err
 	}
 
-	isDelinquent := maxConsecutiveMissed >= 2
-
-	// Update borrower's delinquent status if needed
-	if isDelinquent {
-		if err := s.repos.Borrowers().UpdateDelinquencyStatus(loan.BorrowerID, true); err != nil {
-			return isDelinquent, err
+	// Update the delinquent status of the loan's parties if needed
+	if status.isDelinquent {
+		if err := s.updatePartyDelinquency(s.repos, loan, true); err != nil {
+			return status.isDelinquent, err
 		}
 	}
 
-	return isDelinquent, nil
+	return status.isDelinquent, nil
 }
 
 // MakePayment records a payment for a loan
 func (s *LoanService) MakePayment(loanID uuid.UUID, amount int64) error {
 	return s.repos.WithTransaction(func(repo repositories.RepositoryManager) error {
-		// Get the loan
-		loan, err := repo.Loans().GetByID(loanID)
-		if err != nil {
-			return err
-		}
+		_, err := s.recordPayment(repo, loanID, amount, nil)
+		return err
+	})
+}
 
-		// Find the earliest unpaid schedule
-		unpaidSchedules, err := repo.Schedules().GetUnpaidByLoanID(loanID)
-		if err != nil || len(unpaidSchedules) == 0 {
-			return errors.New("no unpaid schedules found")
-		}
-		schedule := unpaidSchedules[0]
+// recordPayment pays a loan's earliest unpaid installment and refreshes the
+// loan's balance, status and delinquency. Payments split from a group payment
+// carry its ID.
+func (s *LoanService) recordPayment(repo repositories.RepositoryManager, loanID uuid.UUID, amount int64, groupPaymentID *uuid.UUID) (*models.Payment, error) {
+	// Get the loan
+	loan, err := repo.Loans().GetByID(loanID)
+	if err != nil {
+		return nil, err
+	}
+	if loan.Status == models.LoanStatusWrittenOff {
+		return nil, ErrLoanWrittenOff
+	}
 
-		// Check if payment amount matches the schedule amount
-		if amount != schedule.Amount {
-			return errors.New("payment amount must match the scheduled amount")
-		}
+	// Find the earliest unpaid schedule
+	unpaidSchedules, err := repo.Schedules().GetUnpaidByLoanID(loanID)
+	if err != nil || len(unpaidSchedules) == 0 {
+		return nil, errors.New("no unpaid schedules found")
+	}
+	schedule := unpaidSchedules[0]
 
-		// Record the payment
-		paymentDate := time.Now()
-		payment := models.Payment{
-			LoanID:      loanID,
-			ScheduleID:  schedule.ID,
-			Amount:      amount,
-			PaymentDate: paymentDate,
-		}
-		if err := repo.Payments().Create(&payment); err != nil {
-			return err
-		}
+	// Check if payment amount matches the schedule amount
+	if amount != schedule.Amount {
+		return nil, errors.New("payment amount must match the scheduled amount")
+	}
 
-		// Update the schedule as paid
-		if err := repo.Schedules().UpdatePaidStatus(schedule.ID, true); err != nil {
-			return err
-		}
+	// Record the payment
+	paymentDate := time.Now()
+	payment := models.Payment{
+		LoanID:      loanID,
+		ScheduleID:  schedule.ID,
+		Amount:      amount,
+		PaymentDate: paymentDate,
+		Source:      models.PaymentSourceBorrower,
+	}
+	if groupPaymentID != nil {
+		payment.Source = models.PaymentSourceGroup
+		payment.GroupPaymentID = groupPaymentID
+	}
+	allocatePayment(&payment, loan, schedule)
+	if err := repo.Payments().Create(&payment); err != nil {
+		return nil, err
+	}
 
-		// Update the current balance
-		newBalance := loan.CurrentBalance - amount
-		if err := repo.Loans().UpdateBalance(loanID, newBalance); err != nil {
-			return err
-		}
+	// Update the schedule as paid
+	if err := repo.Schedules().UpdatePaidStatus(schedule.ID, true); err != nil {
+		return nil, err
+	}
 
-		// Update the last payment date
-		if err := repo.Loans().UpdateLastPaymentDate(loanID, paymentDate); err != nil {
-			return err
-		}
+	// Update the current balance
+	newBalance := loan.CurrentBalance - amount
+	if err := repo.Loans().UpdateBalance(loanID, newBalance); err != nil {
+		return nil, err
+	}
 
-		// Check if all schedules are paid
-		unpaidCount, err := repo.Schedules().CountUnpaidByLoanID(loanID)
-		if err != nil {
-			return err
-		}
+	// Update the last payment date
+	if err := repo.Loans().UpdateLastPaymentDate(loanID, paymentDate); err != nil {
+		return nil, err
+	}
 
-		// If all schedules are paid, update loan status to closed
-		if unpaidCount == 0 {
-			if err := repo.Loans().UpdateStatus(loanID, "closed"); err != nil {
-				return err
-			}
+	// Count the payment towards a restructured loan's cure period
+	if loan.CurePaymentsRemaining > 0 {
+		loan.CurePaymentsRemaining--
+		if err := repo.Loans().UpdateCurePaymentsRemaining(loanID, loan.CurePaymentsRemaining); err != nil {
+			return nil, err
 		}
+	}
 
-		// Re-check delinquency status
-		schedules, err := repo.Schedules().GetByLoanID(loanID)
-		if err != nil {
-			return err
+	// Check if all schedules are paid
+	unpaidCount, err := repo.Schedules().CountUnpaidByLoanID(loanID)
+	if err != nil {
+		return nil, err
+	}
+
+	// If all schedules are paid, update loan status to closed
+	if unpaidCount == 0 {
+		if err := repo.Loans().UpdateStatus(loanID, models.LoanStatusClosed); err != nil {
+			return nil, err
 		}
+	}
 
-		// Count consecutive unpaid schedules that are past due
-		currentDate := time.Now()
-		var consecutiveMissed int
-		maxConsecutiveMissed := 0
+	// Re-check delinquency status
+	schedules, err := repo.Schedules().GetByLoanID(loanID)
+	if err != nil {
+		return nil, err
+	}
 
-		for i := 0; i < len(schedules); i++ {
-			if !schedules[i].Paid && schedules[i].DueDate.Before(currentDate) {
-				consecutiveMissed++
-				if consecutiveMissed > maxConsecutiveMissed {
-					maxConsecutiveMissed = consecutiveMissed
-				}
-			} else {
-				consecutiveMissed = 0
-			}
-		}
+	status := applyCureHold(loan, evaluateDelinquency(schedules, time.Now()))
+	if err := repo.Loans().UpdateDelinquency(loanID, status.isDelinquent, status.daysPastDue); err != nil {
+		return nil, err
+	}
 
-		isDelinquent := maxConsecutiveMissed >= 2
+	// Update the delinquent status of the loan's parties
+	if err := s.updatePartyDelinquency(repo, loan, status.isDelinquent); err != nil {
+		return nil, err
+	}
 
-		// Update borrower's delinquent status
-		return repo.Borrowers().UpdateDelinquencyStatus(loan.BorrowerID, isDelinquent)
-	})
+	return &payment, nil
 }
 
 // GetPotentialDelinquentLoans returns loans that haven't been paid recently
 func (s *LoanService) GetPotentialDelinquentLoans() ([]models.Loan, error) {
 	return s.repos.Loans().GetPotentialDelinquent()
 }
+
+// ListLoans retrieves one page of loans matching the filter along with their next installment
+func (s *LoanService) ListLoans(filter repositories.LoanFilter, page repositories.PageRequest) (repositories.Page[LoanSummary], error) {
+	loans, err := s.repos.Loans().List(filter, page)
+	if err != nil {
+		return repositories.Page[LoanSummary]{}, err
+	}
+
+	loanIDs := make([]uuid.UUID, 0, len(loans.Items))
+	for _, loan := range loans.Items {
+		loanIDs = append(loanIDs, loan.ID)
+	}
+
+	nextDue, err := s.repos.Schedules().GetNextUnpaidByLoanIDs(loanIDs)
+	if err != nil {
+		return repositories.Page[LoanSummary]{}, err
+	}
+
+	result := repositories.Page[LoanSummary]{
+		Items:      make([]LoanSummary, 0, len(loans.Items)),
+		NextCursor: loans.NextCursor,
+		Limit:      loans.Limit,
+	}
+	for _, loan := range loans.Items {
+		summary := LoanSummary{Loan: loan}
+		if schedule, ok := nextDue[loan.ID]; ok {
+			summary.NextDue = &schedule
+		}
+		result.Items = append(result.Items, summary)
+	}
+
+	return result, nil
+}
+
+// ListBorrowerLoans retrieves one page of a borrower's loans
+func (s *LoanService) ListBorrowerLoans(borrowerID uuid.UUID, filter repositories.LoanFilter, page repositories.PageRequest) (repositories.Page[LoanSummary], error) {
+	if _, err := s.repos.Borrowers().GetByID(borrowerID); err != nil {
+		return repositories.Page[LoanSummary]{}, ErrBorrowerNotFound
+	}
+
+	filter.BorrowerID = &borrowerID
+	return s.ListLoans(filter, page)
+}
+
+// delinquencyStatus is the outcome of evaluating a loan's schedules
+type delinquencyStatus struct {
+	isDelinquent bool
+	daysPastDue  uint
+}
+
+// evaluateDelinquency checks a loan's schedules for missed installments.
+// A loan is delinquent when 2 or more consecutive installments are past due,
+// and its days past due are counted from the oldest unpaid past-due installment.
+// Installments deferred by a payment holiday are skipped until the holiday ends.
+func evaluateDelinquency(schedules []models.Schedule, currentDate time.Time) delinquencyStatus {
+	var consecutiveMissed int
+	maxConsecutiveMissed := 0
+	var oldestMissed *time.Time
+
+	for i := 0; i < len(schedules); i++ {
+		if schedules[i].IsDeferred(currentDate) {
+			continue
+		}
+		if !schedules[i].Paid && schedules[i].DueDate.Before(currentDate) {
+			if oldestMissed == nil {
+				oldestMissed = &schedules[i].DueDate
+			}
+			consecutiveMissed++
+			if consecutiveMissed > maxConsecutiveMissed {
+				maxConsecutiveMissed = consecutiveMissed
+			}
+		} else {
+			consecutiveMissed = 0
+		}
+	}
+
+	status := delinquencyStatus{isDelinquent: maxConsecutiveMissed >= 2}
+	if oldestMissed != nil {
+		status.daysPastDue = uint(currentDate.Sub(*oldestMissed) / (24 * time.Hour))
+	}
+	return status
+}
+
+// applyCureHold keeps a delinquent restructured loan flagged until its cure period is over
+func applyCureHold(loan *models.Loan, status delinquencyStatus) delinquencyStatus {
+	if loan.CurePaymentsRemaining > 0 && loan.IsDelinquent {
+		status.isDelinquent = true
+	}
+	return status
+}
@@ -0,0 +1,219 @@
+package services
+
+import (
+	"errors"
+	"loan-billing-system/internal/models"
+	"loan-billing-system/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrInvalidPartyRole is returned when a loan party is given a role other than co-borrower or guarantor
+	ErrInvalidPartyRole = errors.New("party role must be co_borrower or guarantor")
+	// ErrPartyExists is returned when a borrower is already a party to the loan
+	ErrPartyExists = errors.New("borrower is already a party to this loan")
+	// ErrPartyNotFound is returned when a borrower is not an additional party to the loan
+	ErrPartyNotFound = errors.New("borrower is not a co-borrower or guarantor of this loan")
+)
+
+// BorrowerLoan is a loan a borrower is on, with the role they hold on it
+type BorrowerLoan struct {
+	Loan models.Loan
+	Role string
+}
+
+// BorrowerExposure is a borrower with every loan they are a party to. Only
+// the balances of open (active or defaulted) loans count towards exposure;
+// loans the borrower guarantees are kept apart as contingent exposure.
+type BorrowerExposure struct {
+	Borrower           *models.Borrower
+	Loans              []BorrowerLoan
+	DirectExposure     int64
+	ContingentExposure int64
+}
+
+// TotalExposure returns the borrower's direct and contingent exposure together
+func (e *BorrowerExposure) TotalExposure() int64 {
+	return e.DirectExposure + e.ContingentExposure
+}
+
+// AddLoanParty adds a co-borrower or guarantor to a loan
+func (s *LoanService) AddLoanParty(loanID, borrowerID uuid.UUID, role string) (*models.LoanParty, error) {
+	loan, err := s.repos.Loans().GetByID(loanID)
+	if err != nil {
+		return nil, ErrLoanNotFound
+	}
+	existing, err := s.repos.Parties().GetByLoanID(loanID)
+	if err != nil {
+		return nil, err
+	}
+
+	party := models.LoanParty{LoanID: loanID, BorrowerID: borrowerID, Role: role}
+	if err := s.validateParties(loan.BorrowerID, existing, []models.LoanParty{party}); err != nil {
+		return nil, err
+	}
+	if err := s.repos.Parties().Create(&party); err != nil {
+		return nil, err
+	}
+
+	return &party, nil
+}
+
+// RemoveLoanParty removes a co-borrower or guarantor from a loan
+func (s *LoanService) RemoveLoanParty(loanID, borrowerID uuid.UUID) error {
+	if _, err := s.repos.Loans().GetByID(loanID); err != nil {
+		return ErrLoanNotFound
+	}
+	if err := s.repos.Parties().Delete(loanID, borrowerID); err != nil {
+		return ErrPartyNotFound
+	}
+	return nil
+}
+
+// GetLoanParties returns every party to a loan, the primary borrower first
+func (s *LoanService) GetLoanParties(loanID uuid.UUID) ([]models.LoanParty, error) {
+	loan, err := s.repos.Loans().GetByID(loanID)
+	if err != nil {
+		return nil, ErrLoanNotFound
+	}
+	parties, err := s.repos.Parties().GetByLoanID(loanID)
+	if err != nil {
+		return nil, err
+	}
+
+	primary := models.LoanParty{
+		LoanID:     loan.ID,
+		BorrowerID: loan.BorrowerID,
+		Role:       models.LoanPartyRolePrimary,
+		CreatedAt:  loan.CreatedAt,
+	}
+	return append([]models.LoanParty{primary}, parties...), nil
+}
+
+// validateParties checks that parties being added to a loan exist, hold an
+// additional role and are not on the loan already
+func (s *LoanService) validateParties(primaryID uuid.UUID, existing, added []models.LoanParty) error {
+	seen := map[uuid.UUID]bool{primaryID: true}
+	for _, party := range existing {
+		seen[party.BorrowerID] = true
+	}
+
+	for _, party := range added {
+		if party.Role != models.LoanPartyRoleCoBorrower && party.Role != models.LoanPartyRoleGuarantor {
+			return ErrInvalidPartyRole
+		}
+		if seen[party.BorrowerID] {
+			return ErrPartyExists
+		}
+		borrower, err := s.repos.Borrowers().GetByID(party.BorrowerID)
+		if err != nil {
+			return ErrBorrowerNotFound
+		}
+		if borrower.AnonymizedAt != nil {
+			return ErrAlreadyAnonymized
+		}
+		seen[party.BorrowerID] = true
+	}
+	return nil
+}
+
+// updatePartyDelinquency brings the delinquency flag of a loan's primary
+// borrower and co-borrowers, and of its guarantors when the policy says so,
+// in line with the loan. A delinquent loan flags them all; a loan that is
+// not only clears the flag of those who share no other delinquent loan.
+func (s *LoanService) updatePartyDelinquency(repo repositories.RepositoryManager, loan *models.Loan, isDelinquent bool) error {
+	borrowerIDs := []uuid.UUID{loan.BorrowerID}
+	parties, err := repo.Parties().GetByLoanID(loan.ID)
+	if err != nil {
+		return err
+	}
+	for _, party := range parties {
+		if party.Role == models.LoanPartyRoleGuarantor && !s.policy.GuarantorsShareDelinquency {
+			continue
+		}
+		borrowerIDs = append(borrowerIDs, party.BorrowerID)
+	}
+
+	for _, borrowerID := range borrowerIDs {
+		delinquent := isDelinquent
+		if !delinquent {
+			if delinquent, err = s.sharesDelinquency(repo, borrowerID, loan.ID); err != nil {
+				return err
+			}
+		}
+		if err := repo.Borrowers().UpdateDelinquencyStatus(borrowerID, delinquent); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sharesDelinquency reports whether a borrower is on an open delinquent loan
+// other than the one given, in a role that shares its delinquency
+func (s *LoanService) sharesDelinquency(repo repositories.RepositoryManager, borrowerID, exceptLoanID uuid.UUID) (bool, error) {
+	loans, err := repo.Loans().GetByParty(borrowerID)
+	if err != nil {
+		return false, err
+	}
+	for i := range loans {
+		loan := &loans[i]
+		if loan.ID == exceptLoanID || !isOpen(loan) || !loan.IsDelinquent {
+			continue
+		}
+		if loan.BorrowerID == borrowerID || s.policy.GuarantorsShareDelinquency {
+			return true, nil
+		}
+		parties, err := repo.Parties().GetByLoanID(loan.ID)
+		if err != nil {
+			return false, err
+		}
+		for _, party := range parties {
+			if party.BorrowerID == borrowerID && party.Role == models.LoanPartyRoleCoBorrower {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// GetBorrowerExposure returns a borrower with every loan they are a party to and their total exposure
+func (s *BorrowerService) GetBorrowerExposure(id uuid.UUID) (*BorrowerExposure, error) {
+	borrower, err := s.repos.Borrowers().GetByID(id)
+	if err != nil {
+		return nil, ErrBorrowerNotFound
+	}
+
+	loans, err := s.repos.Loans().GetByParty(id)
+	if err != nil {
+		return nil, err
+	}
+	parties, err := s.repos.Parties().GetByBorrowerID(id)
+	if err != nil {
+		return nil, err
+	}
+	roles := make(map[uuid.UUID]string, len(parties))
+	for _, party := range parties {
+		roles[party.LoanID] = party.Role
+	}
+
+	exposure := &BorrowerExposure{Borrower: borrower, Loans: make([]BorrowerLoan, 0, len(loans))}
+	for _, loan := range loans {
+		role := models.LoanPartyRolePrimary
+		if loan.BorrowerID != id {
+			role = roles[loan.ID]
+		}
+		exposure.Loans = append(exposure.Loans, BorrowerLoan{Loan: loan, Role: role})
+
+		if !isOpen(&loan) {
+			continue
+		}
+		if role == models.LoanPartyRoleGuarantor {
+			exposure.ContingentExposure += loan.CurrentBalance
+		} else {
+			exposure.DirectExposure += loan.CurrentBalance
+		}
+	}
+
+	return exposure, nil
+}
@@ -0,0 +1,94 @@
+package services
+
+import (
+	"loan-billing-system/internal/finance"
+	"loan-billing-system/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// weeksPerYear is used to annualize weekly rates
+const weeksPerYear = 52
+
+// LoanQuote is a preview of a loan's cost and repayment schedule
+type LoanQuote struct {
+	ProductCode         string
+	Amount              int64 // Requested, before fees
+	Principal           int64 // Amount interest is charged on, capitalized fees included
+	DisbursedAmount     int64 // Cash paid out, net of deducted fees
+	InterestRate        float64
+	TermWeeks           uint
+	StartDate           time.Time
+	TotalDue            int64
+	TotalInterest       int64
+	TotalFees           int64
+	WeeklyPayment       int64
+	APR                 float64 // Percent per year, like InterestRate
+	EffectiveAnnualRate float64 // Percent per year, like InterestRate
+	Fees                []models.LoanFee
+	Schedule            []models.Schedule
+}
+
+// QuoteLoan prices a loan under a product exactly as ApplyForLoan would,
+// product fees included, and returns the resulting cost and schedule without
+// saving anything. An empty product code quotes the standard product.
+func (s *LoanService) QuoteLoan(productCode string, amount int64, interestRate float64, termWeeks uint) (*LoanQuote, error) {
+	if productCode == "" {
+		productCode = models.DefaultProductCode
+	}
+	if _, err := s.repos.Products().GetByCode(productCode); err != nil {
+		return nil, ErrProductNotFound
+	}
+	feeDefinitions, err := s.repos.Fees().GetDefinitionsByProductCode(productCode)
+	if err != nil {
+		return nil, err
+	}
+
+	calendar, err := s.calendar()
+	if err != nil {
+		return nil, err
+	}
+
+	loan, schedule, fees, err := newLoan(uuid.Nil, productCode, amount, interestRate, termWeeks, feeDefinitions, time.Now(), calendar)
+	if err != nil {
+		return nil, err
+	}
+
+	var totalFees int64
+	for _, fee := range fees {
+		totalFees += fee.Amount
+	}
+
+	return &LoanQuote{
+		ProductCode:         productCode,
+		Amount:              amount,
+		Principal:           loan.Amount,
+		DisbursedAmount:     loan.DisbursedAmount,
+		InterestRate:        interestRate,
+		TermWeeks:           termWeeks,
+		StartDate:           loan.StartDate,
+		TotalDue:            loan.CurrentBalance,
+		TotalInterest:       loan.CalculateTotalDue() - loan.Amount,
+		TotalFees:           totalFees,
+		WeeklyPayment:       loan.CalculateWeeklyPayment() + schedule[0].FeeAmount,
+		APR:                 loan.APR,
+		EffectiveAnnualRate: loan.EffectiveRate,
+		Fees:                fees,
+		Schedule:            schedule,
+	}, nil
+}
+
+// discloseCost computes the APR and effective rate of a loan from its actual
+// cash flows: amountFinanced paid out on disbursedAt, then the schedule
+func discloseCost(amountFinanced int64, disbursedAt time.Time, schedule []models.Schedule) (finance.Disclosure, error) {
+	payments := make([]finance.ScheduledPayment, 0, len(schedule))
+	for _, installment := range schedule {
+		payments = append(payments, finance.ScheduledPayment{
+			Date:   installment.DueDate,
+			Amount: float64(installment.Amount),
+		})
+	}
+
+	return finance.Disclose(float64(amountFinanced), disbursedAt, payments, weeksPerYear)
+}
@@ -0,0 +1,228 @@
+package services
+
+import (
+	"errors"
+	"loan-billing-system/internal/models"
+	"loan-billing-system/internal/repositories"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrLoanNotRefinanceable is returned when a loan is not active and current
+	ErrLoanNotRefinanceable = errors.New("only active loans that are not delinquent can be refinanced")
+	// ErrRefinanceTooSmall is returned when the new loan does not exceed the old loan's payoff amount
+	ErrRefinanceTooSmall = errors.New("new loan amount must exceed the payoff amount of the loan being refinanced")
+)
+
+// RefinanceTerms are the terms of the loan replacing a refinanced loan.
+// A nil interest rate keeps the old loan's rate.
+type RefinanceTerms struct {
+	Amount       int64
+	InterestRate *float64
+	TermWeeks    uint
+}
+
+// RefinanceResult is the outcome of a refinancing
+type RefinanceResult struct {
+	OldLoan      *models.Loan
+	NewLoan      *models.Loan
+	PayoffAmount int64
+}
+
+// RefinanceLoan replaces a loan with a larger one in a single transaction.
+// The new loan's disbursement first settles the old loan's payoff amount
+// (arrears in full plus principal and installment fees not yet due); only the
+// difference is paid out to the borrower. The settlement is recorded as
+// refinance payments on the old loan, which is then closed as refinanced and
+// linked to the new one. Its parties and unreleased collateral move to the new
+// loan, which still owes the debt.
+// The new loan must pass the same credit checks and scoring as an application,
+// counting the borrower's other loans but not the one it settles; a new loan
+// failing them is rejected with a CreditRejection.
+func (s *LoanService) RefinanceLoan(loanID uuid.UUID, terms RefinanceTerms) (*RefinanceResult, error) {
+	var result *RefinanceResult
+
+	calendar, err := s.calendar()
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repos.WithTransaction(func(repo repositories.RepositoryManager) error {
+		oldLoan, err := repo.Loans().GetByID(loanID)
+		if err != nil {
+			return ErrLoanNotFound
+		}
+		if oldLoan.Status != models.LoanStatusActive || oldLoan.IsDelinquent {
+			return ErrLoanNotRefinanceable
+		}
+
+		unpaidSchedules, err := repo.Schedules().GetUnpaidByLoanID(loanID)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		outstanding := splitOutstanding(oldLoan, unpaidSchedules, now)
+		payoff := outstanding.payoff()
+		if terms.Amount <= payoff {
+			return ErrRefinanceTooSmall
+		}
+
+		interestRate := oldLoan.InterestRate
+		if terms.InterestRate != nil {
+			interestRate = *terms.InterestRate
+		}
+
+		feeDefinitions, err := repo.Fees().GetDefinitionsByProductCode(oldLoan.ProductCode)
+		if err != nil {
+			return err
+		}
+
+		// Price the new loan on the full amount: the payoff is financed too
+		newLoan, schedules, fees, err := newLoan(oldLoan.BorrowerID, oldLoan.ProductCode, terms.Amount, interestRate, terms.TermWeeks, feeDefinitions, now, calendar)
+		if err != nil {
+			return err
+		}
+		if newLoan.DisbursedAmount <= payoff {
+			return ErrRefinanceTooSmall
+		}
+		newLoan.DisbursedAmount -= payoff
+		newLoan.RefinancedFromID = &oldLoan.ID
+		newLoan.GroupID = oldLoan.GroupID
+
+		borrower, err := repo.Borrowers().GetByID(oldLoan.BorrowerID)
+		if err != nil {
+			return ErrBorrowerNotFound
+		}
+		borrower = settledBorrower(borrower, loanID)
+		reasons, err := s.checkCredit(borrower, &newLoan, nil)
+		if err != nil {
+			return err
+		}
+		assessment, scoreReasons, err := s.assessCredit(borrower, &newLoan, nil)
+		if err != nil {
+			return err
+		}
+		if reasons = append(reasons, scoreReasons...); len(reasons) > 0 {
+			return &CreditRejection{Reasons: reasons, Assessment: assessment}
+		}
+
+		if err := repo.Loans().Create(&newLoan); err != nil {
+			return err
+		}
+		if assessment != nil {
+			assessment.LoanID = newLoan.ID
+			if err := repo.Assessments().Create(assessment); err != nil {
+				return err
+			}
+		}
+		for i := range schedules {
+			schedules[i].LoanID = newLoan.ID
+		}
+		if err := repo.Schedules().CreateBatch(schedules); err != nil {
+			return err
+		}
+		for i := range fees {
+			fees[i].LoanID = newLoan.ID
+		}
+		if err := repo.Fees().CreateBatch(fees); err != nil {
+			return err
+		}
+
+		// The co-borrowers and guarantors stay on the new loan
+		oldParties, err := repo.Parties().GetByLoanID(loanID)
+		if err != nil {
+			return err
+		}
+		parties := make([]models.LoanParty, 0, len(oldParties))
+		for _, party := range oldParties {
+			parties = append(parties, models.LoanParty{LoanID: newLoan.ID, BorrowerID: party.BorrowerID, Role: party.Role})
+		}
+		if err := repo.Parties().CreateBatch(parties); err != nil {
+			return err
+		}
+
+		// So does the collateral still securing it, with its valuation history
+		collateral, err := repo.Collateral().GetByLoanID(loanID)
+		if err != nil {
+			return err
+		}
+		for i := range collateral {
+			if collateral[i].IsReleased() {
+				continue
+			}
+			collateral[i].LoanID = newLoan.ID
+			if err := repo.Collateral().Update(&collateral[i]); err != nil {
+				return err
+			}
+		}
+
+		// Settle every unpaid installment of the old loan out of the disbursement
+		for _, schedule := range unpaidSchedules {
+			payment := models.Payment{
+				LoanID:      loanID,
+				ScheduleID:  schedule.ID,
+				Amount:      outstanding.settled[schedule.ID],
+				PaymentDate: now,
+				Source:      models.PaymentSourceRefinance,
+			}
+			allocatePayment(&payment, oldLoan, schedule)
+			if err := repo.Payments().Create(&payment); err != nil {
+				return err
+			}
+			if err := repo.Schedules().UpdatePaidStatus(schedule.ID, true); err != nil {
+				return err
+			}
+		}
+
+		fromStatus := oldLoan.Status
+		oldLoan.Status = models.LoanStatusRefinanced
+		oldLoan.CurrentBalance = 0
+		oldLoan.LastPaymentDate = &now
+		oldLoan.RefinancedByID = &newLoan.ID
+		if err := repo.Loans().Update(oldLoan); err != nil {
+			return err
+		}
+
+		if err := repo.StatusChanges().Create(&models.LoanStatusChange{
+			LoanID:      loanID,
+			FromStatus:  fromStatus,
+			ToStatus:    models.LoanStatusRefinanced,
+			Rule:        "refinanced_by=" + newLoan.ID.String(),
+			DaysPastDue: oldLoan.DaysPastDue,
+			ChangedBy:   actor(s.ctx),
+		}); err != nil {
+			return err
+		}
+
+		result = &RefinanceResult{
+			OldLoan:      oldLoan,
+			NewLoan:      &newLoan,
+			PayoffAmount: payoff,
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// settledBorrower returns a copy of the borrower with one of their loans
+// settled, as it will be once refinanced: it keeps its repayment history but no
+// longer counts towards their exposure or open loans
+func settledBorrower(borrower *models.Borrower, loanID uuid.UUID) *models.Borrower {
+	settled := *borrower
+	settled.Loans = make([]models.Loan, len(borrower.Loans))
+	copy(settled.Loans, borrower.Loans)
+	for i := range settled.Loans {
+		if settled.Loans[i].ID == loanID {
+			settled.Loans[i].Status = models.LoanStatusRefinanced
+			settled.Loans[i].CurrentBalance = 0
+		}
+	}
+	return &settled
+}
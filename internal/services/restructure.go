@@ -0,0 +1,213 @@
+package services
+
+import (
+	"errors"
+	"loan-billing-system/internal/models"
+	"loan-billing-system/internal/repositories"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrLoanNotRestructurable is returned when a loan's status does not allow restructuring
+	ErrLoanNotRestructurable = errors.New("only active loans can be restructured")
+	// ErrNothingToRestructure is returned when a loan has no unpaid installments left
+	ErrNothingToRestructure = errors.New("loan has no unpaid installments to restructure")
+)
+
+// RestructureTerms are the new terms of a restructured loan. Nil fields keep
+// the loan's current interest rate and the number of installments left.
+type RestructureTerms struct {
+	InterestRate *float64
+	TermWeeks    *uint
+	Reason       string
+}
+
+// RestructureLoan capitalizes a loan's arrears and replaces its remaining
+// schedule with a new version. Overdue installments are capitalized in full,
+// while installments not yet due only carry their principal over, so interest
+// that has not been earned yet is not charged twice. Their installment fees
+// are still owed and are spread over the new installments, without interest.
+// The unpaid rows of the old version are cancelled rather than deleted to keep
+// them for history.
+func (s *LoanService) RestructureLoan(loanID uuid.UUID, terms RestructureTerms) (*models.LoanRestructure, error) {
+	var restructure *models.LoanRestructure
+
+	calendar, err := s.calendar()
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repos.WithTransaction(func(repo repositories.RepositoryManager) error {
+		loan, err := repo.Loans().GetByID(loanID)
+		if err != nil {
+			return ErrLoanNotFound
+		}
+		if loan.Status != models.LoanStatusActive {
+			return ErrLoanNotRestructurable
+		}
+
+		unpaidSchedules, err := repo.Schedules().GetUnpaidByLoanID(loanID)
+		if err != nil {
+			return err
+		}
+		if len(unpaidSchedules) == 0 {
+			return ErrNothingToRestructure
+		}
+
+		// Split what is left into arrears, and principal and fees not yet due
+		now := time.Now()
+		outstanding := splitOutstanding(loan, unpaidSchedules, now)
+		arrears := outstanding.arrears
+		newPrincipal := arrears + outstanding.principalNotDue
+
+		interestRate := loan.InterestRate
+		if terms.InterestRate != nil {
+			interestRate = *terms.InterestRate
+		}
+		termWeeks := uint(len(unpaidSchedules))
+		if terms.TermWeeks != nil {
+			termWeeks = *terms.TermWeeks
+		}
+
+		// Lay out the new schedule version
+		newVersion := loan.ScheduleVersion + 1
+		schedules := layoutSchedule(newPrincipal, interestRate, termWeeks, now, newVersion, calendar)
+		for i := range schedules {
+			schedules[i].LoanID = loanID
+		}
+		spreadFees(schedules, outstanding.feesNotDue)
+		newBalance := calculateTotalDue(newPrincipal, interestRate, termWeeks) + outstanding.feesNotDue
+
+		disclosure, err := discloseCost(newPrincipal, now, schedules)
+		if err != nil {
+			return err
+		}
+
+		if err := repo.Schedules().CancelUnpaidByLoanID(loanID); err != nil {
+			return err
+		}
+		if err := repo.Schedules().CreateBatch(schedules); err != nil {
+			return err
+		}
+
+		restructure = &models.LoanRestructure{
+			LoanID:               loanID,
+			FromVersion:          loan.ScheduleVersion,
+			ToVersion:            newVersion,
+			PreviousInterestRate: loan.InterestRate,
+			NewInterestRate:      interestRate,
+			PreviousTermWeeks:    loan.TermWeeks,
+			NewTermWeeks:         termWeeks,
+			PreviousPrincipal:    loan.Amount,
+			PreviousBalance:      loan.CurrentBalance,
+			ArrearsCapitalized:   arrears,
+			FeesCarried:          outstanding.feesNotDue,
+			NewPrincipal:         newPrincipal,
+			NewBalance:           newBalance,
+			Reason:               terms.Reason,
+		}
+		if err := repo.Restructures().Create(restructure); err != nil {
+			return err
+		}
+
+		// The loan now carries the new principal, rate and term, so that its
+		// total due matches the new schedule; the old ones stay on the record
+		loan.Amount = newPrincipal
+		loan.InterestRate = interestRate
+		loan.TermWeeks = termWeeks
+		loan.APR = disclosure.APR
+		loan.EffectiveRate = disclosure.EffectiveRate
+		loan.CurrentBalance = newBalance
+		loan.ScheduleVersion = newVersion
+		loan.IsRestructured = true
+		loan.RestructuredAt = &now
+		loan.DaysPastDue = 0
+
+		// Reset delinquency right away, or once the cure period has been served
+		resetDelinquency := s.policy.RestructureCurePayments == 0 || !loan.IsDelinquent
+		if resetDelinquency {
+			loan.IsDelinquent = false
+			loan.CurePaymentsRemaining = 0
+		} else {
+			loan.CurePaymentsRemaining = s.policy.RestructureCurePayments
+		}
+
+		if err := repo.Loans().Update(loan); err != nil {
+			return err
+		}
+
+		if resetDelinquency {
+			return s.updatePartyDelinquency(repo, loan, false)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return restructure, nil
+}
+
+// outstandingSplit divides what is left on a loan into what has fallen due,
+// and the principal and installment fees not yet due
+type outstandingSplit struct {
+	arrears         int64
+	principalNotDue int64
+	feesNotDue      int64
+	// settled maps each unpaid installment to the part of it the split counts
+	settled map[uuid.UUID]int64
+}
+
+// payoff returns the amount that settles the loan: arrears in full plus
+// principal and installment fees not yet due
+func (o outstandingSplit) payoff() int64 {
+	return o.arrears + o.principalNotDue + o.feesNotDue
+}
+
+// splitOutstanding splits a loan's unpaid installments into arrears, and
+// principal and installment fees not yet due. Interest on installments not yet
+// due has not been earned, so it is left out.
+func splitOutstanding(loan *models.Loan, unpaidSchedules []models.Schedule, now time.Time) outstandingSplit {
+	originalTotal := calculateTotalDue(loan.Amount, loan.InterestRate, loan.TermWeeks)
+	split := outstandingSplit{settled: make(map[uuid.UUID]int64, len(unpaidSchedules))}
+	for _, schedule := range unpaidSchedules {
+		if schedule.DueDate.Before(now) {
+			split.arrears += schedule.Amount
+			split.settled[schedule.ID] = schedule.Amount
+		} else {
+			principal := schedule.Principal(loan.Amount, originalTotal)
+			split.principalNotDue += principal
+			split.feesNotDue += schedule.FeeAmount
+			split.settled[schedule.ID] = principal + schedule.FeeAmount
+		}
+	}
+	return split
+}
+
+// spreadFees adds installment fees to a new schedule, evenly, with the
+// rounding remainder on the final installment
+func spreadFees(schedules []models.Schedule, fees int64) {
+	if fees == 0 || len(schedules) == 0 {
+		return
+	}
+	perInstallment := fees / int64(len(schedules))
+	for i := range schedules {
+		fee := perInstallment
+		if i == len(schedules)-1 {
+			fee = fees - perInstallment*int64(len(schedules)-1)
+		}
+		schedules[i].FeeAmount += fee
+		schedules[i].Amount += fee
+	}
+}
+
+// GetRestructures returns the restructuring history of a loan, oldest first
+func (s *LoanService) GetRestructures(loanID uuid.UUID) ([]models.LoanRestructure, error) {
+	if _, err := s.repos.Loans().GetByID(loanID); err != nil {
+		return nil, ErrLoanNotFound
+	}
+
+	return s.repos.Restructures().GetByLoanID(loanID)
+}
@@ -0,0 +1,99 @@
+package services
+
+import (
+	"errors"
+	"loan-billing-system/internal/auth"
+	"loan-billing-system/internal/models"
+	"loan-billing-system/internal/repositories"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrPaymentAlreadyReversed is returned when a payment has already been reversed
+	ErrPaymentAlreadyReversed = errors.New("payment has already been reversed")
+	// ErrPaymentNotReversible is returned when a payment is not a borrower's repayment of an active or closed loan
+	ErrPaymentNotReversible = errors.New("only borrower repayments of active or closed loans can be reversed")
+)
+
+// ReversePayment undoes a repayment taken in error, such as one that bounced.
+// The payment is kept, marked as reversed by the caller, and no longer counts:
+// its installment is unpaid again, the loan's balance goes back up, a loan the
+// payment closed is reopened and delinquency is re-evaluated. Payments split
+// from a group payment or settled by a refinancing cannot be reversed.
+func (s *LoanService) ReversePayment(paymentID uuid.UUID, reason string) (*models.Payment, error) {
+	if err := authorize(s.ctx, s.access, auth.PermPaymentsReverse); err != nil {
+		return nil, err
+	}
+
+	var payment *models.Payment
+	err := s.repos.WithTransaction(func(repo repositories.RepositoryManager) error {
+		var err error
+		payment, err = repo.Payments().GetByID(paymentID)
+		if err != nil {
+			return ErrPaymentNotFound
+		}
+		if payment.ReversedAt != nil {
+			return ErrPaymentAlreadyReversed
+		}
+		if payment.Source != models.PaymentSourceBorrower {
+			return ErrPaymentNotReversible
+		}
+
+		loan, err := repo.Loans().GetByID(payment.LoanID)
+		if err != nil {
+			return ErrLoanNotFound
+		}
+		if loan.Status != models.LoanStatusActive && loan.Status != models.LoanStatusClosed {
+			return ErrPaymentNotReversible
+		}
+
+		now := time.Now()
+		payment.ReversedAt = &now
+		payment.ReversedBy = actor(s.ctx)
+		payment.ReversalReason = reason
+		if err := repo.Payments().Update(payment); err != nil {
+			return err
+		}
+
+		if err := repo.Schedules().UpdatePaidStatus(payment.ScheduleID, false); err != nil {
+			return err
+		}
+		if err := repo.Loans().UpdateBalance(loan.ID, loan.CurrentBalance+payment.Amount); err != nil {
+			return err
+		}
+
+		if loan.Status == models.LoanStatusClosed {
+			if err := repo.Loans().UpdateStatus(loan.ID, models.LoanStatusActive); err != nil {
+				return err
+			}
+			if err := repo.StatusChanges().Create(&models.LoanStatusChange{
+				LoanID:      loan.ID,
+				FromStatus:  models.LoanStatusClosed,
+				ToStatus:    models.LoanStatusActive,
+				Rule:        "payment_reversed=" + payment.ID.String(),
+				DaysPastDue: loan.DaysPastDue,
+				ChangedBy:   payment.ReversedBy,
+			}); err != nil {
+				return err
+			}
+		}
+
+		// The installment is owed again, and may be overdue
+		schedules, err := repo.Schedules().GetByLoanID(loan.ID)
+		if err != nil {
+			return err
+		}
+		status := applyCureHold(loan, evaluateDelinquency(schedules, now))
+		if err := repo.Loans().UpdateDelinquency(loan.ID, status.isDelinquent, status.daysPastDue); err != nil {
+			return err
+		}
+		return s.updatePartyDelinquency(repo, loan, status.isDelinquent)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return payment, nil
+}
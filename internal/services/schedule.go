@@ -0,0 +1,125 @@
+package services
+
+import (
+	"errors"
+	"loan-billing-system/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrLoanNotFound is returned when a loan does not exist
+var ErrLoanNotFound = errors.New("loan not found")
+
+// ErrPaymentNotFound is returned when a payment does not exist
+var ErrPaymentNotFound = errors.New("payment not found")
+
+// Installment statuses reported on repayment schedules
+const (
+	InstallmentStatusPaid      = "paid"
+	InstallmentStatusPartial   = "partial"
+	InstallmentStatusOverdue   = "overdue"
+	InstallmentStatusUpcoming  = "upcoming"
+	InstallmentStatusDeferred  = "deferred"
+	InstallmentStatusCancelled = "cancelled"
+)
+
+// Installment is a schedule row together with the payments applied to it
+type Installment struct {
+	Schedule   models.Schedule
+	AmountPaid int64
+	PaidDate   *time.Time
+	Status     string
+	IsOverdue  bool
+}
+
+// GetSchedule returns a loan's repayment schedule with the payment status of each installment.
+// Version 0 returns the current schedule; any other version returns that version in full,
+// including installments cancelled by a later restructuring.
+func (s *LoanService) GetSchedule(loanID uuid.UUID, version uint) ([]Installment, error) {
+	if _, err := s.repos.Loans().GetByID(loanID); err != nil {
+		return nil, ErrLoanNotFound
+	}
+
+	var schedules []models.Schedule
+	var err error
+	if version == 0 {
+		schedules, err = s.repos.Schedules().GetByLoanID(loanID)
+	} else {
+		schedules, err = s.repos.Schedules().GetByLoanIDAndVersion(loanID, version)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	payments, err := s.repos.Payments().GetByLoanID(loanID)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildInstallments(schedules, payments, time.Now()), nil
+}
+
+// GetPayments returns the payment history of a loan, oldest first
+func (s *LoanService) GetPayments(loanID uuid.UUID) ([]models.Payment, error) {
+	if _, err := s.repos.Loans().GetByID(loanID); err != nil {
+		return nil, ErrLoanNotFound
+	}
+
+	return s.repos.Payments().GetByLoanID(loanID)
+}
+
+// GetPayment retrieves a single payment by ID
+func (s *LoanService) GetPayment(id uuid.UUID) (*models.Payment, error) {
+	payment, err := s.repos.Payments().GetByID(id)
+	if err != nil {
+		return nil, ErrPaymentNotFound
+	}
+	return payment, nil
+}
+
+// buildInstallments matches payments to their schedule rows and derives each installment's status
+func buildInstallments(schedules []models.Schedule, payments []models.Payment, currentDate time.Time) []Installment {
+	paidBySchedule := make(map[uuid.UUID]int64)
+	lastPaidAt := make(map[uuid.UUID]time.Time)
+	for _, payment := range payments {
+		if payment.ReversedAt != nil {
+			continue
+		}
+		paidBySchedule[payment.ScheduleID] += payment.Amount
+		if payment.PaymentDate.After(lastPaidAt[payment.ScheduleID]) {
+			lastPaidAt[payment.ScheduleID] = payment.PaymentDate
+		}
+	}
+
+	installments := make([]Installment, 0, len(schedules))
+	for _, schedule := range schedules {
+		installment := Installment{
+			Schedule:   schedule,
+			AmountPaid: paidBySchedule[schedule.ID],
+			IsOverdue:  !schedule.Paid && !schedule.Cancelled && !schedule.IsDeferred(currentDate) && schedule.DueDate.Before(currentDate),
+		}
+		if paidAt, ok := lastPaidAt[schedule.ID]; ok {
+			installment.PaidDate = &paidAt
+		}
+
+		switch {
+		case schedule.Cancelled:
+			installment.Status = InstallmentStatusCancelled
+		case schedule.Paid:
+			installment.Status = InstallmentStatusPaid
+		case installment.AmountPaid > 0:
+			installment.Status = InstallmentStatusPartial
+		case installment.IsOverdue:
+			installment.Status = InstallmentStatusOverdue
+		case schedule.IsDeferred(currentDate):
+			installment.Status = InstallmentStatusDeferred
+		default:
+			installment.Status = InstallmentStatusUpcoming
+		}
+
+		installments = append(installments, installment)
+	}
+
+	return installments
+}
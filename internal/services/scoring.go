@@ -0,0 +1,148 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"loan-billing-system/internal/models"
+	"loan-billing-system/internal/scoring"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Scoring rejection reason codes
+const (
+	RejectionScoreDeclined = "score_declined"
+	RejectionScoreReferred = "score_referred"
+)
+
+// ErrAssessmentNotFound is returned when a loan was created without being scored
+var ErrAssessmentNotFound = errors.New("loan has no credit assessment")
+
+// WithScorer sets the scorer that assesses loan applications and returns the
+// service. Without a scorer, applications are not scored.
+func (s *LoanService) WithScorer(scorer scoring.Scorer) *LoanService {
+	s.scorer = scorer
+	return s
+}
+
+// assessCredit scores a loan application and returns the assessment to keep
+// with the loan and the reasons to reject it, if any. Declined applications
+// are always rejected; referred ones go through only with a credit override.
+func (s *LoanService) assessCredit(borrower *models.Borrower, loan *models.Loan, override *CreditOverride) (*models.CreditAssessment, []RejectionReason, error) {
+	if s.scorer == nil {
+		return nil, nil, nil
+	}
+
+	applicant, err := s.applicantProfile(borrower, loan, time.Now())
+	if err != nil {
+		return nil, nil, err
+	}
+	result := s.scorer.Score(applicant)
+
+	assessment := &models.CreditAssessment{
+		BorrowerID:       borrower.ID,
+		ScorecardVersion: result.ScorecardVersion,
+		Score:            result.Score,
+		Grade:            result.Grade,
+		Decision:         result.Decision,
+		Contributions:    result.Contributions,
+	}
+
+	var reasons []RejectionReason
+	switch result.Decision {
+	case scoring.DecisionDecline:
+		reasons = append(reasons, RejectionReason{
+			Code:    RejectionScoreDeclined,
+			Message: fmt.Sprintf("scored %d (grade %s) on scorecard %s and was declined", result.Score, result.Grade, result.ScorecardVersion),
+		})
+	case scoring.DecisionRefer:
+		if override == nil {
+			reasons = append(reasons, RejectionReason{
+				Code:    RejectionScoreReferred,
+				Message: fmt.Sprintf("scored %d (grade %s) on scorecard %s and needs an approved override", result.Score, result.Grade, result.ScorecardVersion),
+			})
+		} else {
+			assessment.ReferralApprovedBy = actor(s.ctx)
+			loan.CreditOverrideBy = assessment.ReferralApprovedBy
+			loan.CreditOverrideReason = override.Reason
+		}
+	}
+
+	return assessment, reasons, nil
+}
+
+// applicantProfile gathers what the scorecard needs to know about a borrower
+// applying for a loan from their loans, installments and payments. An
+// installment is on time when it was paid in full by the end of its due date.
+func (s *LoanService) applicantProfile(borrower *models.Borrower, loan *models.Loan, now time.Time) (scoring.Applicant, error) {
+	applicant := scoring.Applicant{
+		IsDelinquent:    borrower.IsDelinquent,
+		RequestedAmount: loan.Amount,
+		TermWeeks:       loan.TermWeeks,
+		TenureDays:      int(now.Sub(borrower.CreatedAt).Hours() / 24),
+	}
+	today := truncateToDay(now)
+
+	for i := range borrower.Loans {
+		existing := &borrower.Loans[i]
+		if isOpen(existing) {
+			applicant.Exposure += existing.CurrentBalance
+			applicant.OpenLoans++
+		}
+		if existing.Status == models.LoanStatusDefaulted || existing.Status == models.LoanStatusWrittenOff {
+			applicant.PriorDefaults++
+		}
+
+		schedules, err := s.repos.Schedules().GetByLoanID(existing.ID)
+		if err != nil {
+			return applicant, err
+		}
+		payments, err := s.repos.Payments().GetByLoanID(existing.ID)
+		if err != nil {
+			return applicant, err
+		}
+		// Payments come oldest first, so the last one seen settled the installment
+		settledOn := make(map[uuid.UUID]time.Time, len(payments))
+		for _, payment := range payments {
+			if payment.ReversedAt != nil {
+				continue
+			}
+			settledOn[payment.ScheduleID] = payment.PaymentDate
+		}
+
+		for _, schedule := range schedules {
+			dueDate := truncateToDay(schedule.DueDate)
+			if schedule.Cancelled || !dueDate.Before(today) {
+				continue
+			}
+			applicant.InstallmentsDue++
+
+			lateUntil := today
+			if schedule.Paid {
+				lateUntil = truncateToDay(settledOn[schedule.ID])
+			}
+			daysLate := int(lateUntil.Sub(dueDate).Hours() / 24)
+			if daysLate <= 0 {
+				applicant.InstallmentsOnTime++
+			}
+			if daysLate > applicant.MaxDaysLate {
+				applicant.MaxDaysLate = daysLate
+			}
+		}
+	}
+
+	return applicant, nil
+}
+
+// GetCreditAssessment returns the credit assessment a loan received when it was created
+func (s *LoanService) GetCreditAssessment(loanID uuid.UUID) (*models.CreditAssessment, error) {
+	if _, err := s.repos.Loans().GetByID(loanID); err != nil {
+		return nil, ErrLoanNotFound
+	}
+	assessment, err := s.repos.Assessments().GetByLoanID(loanID)
+	if err != nil {
+		return nil, ErrAssessmentNotFound
+	}
+	return assessment, nil
+}
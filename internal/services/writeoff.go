@@ -0,0 +1,134 @@
+package services
+
+import (
+	"errors"
+	"loan-billing-system/internal/auth"
+	"loan-billing-system/internal/models"
+	"loan-billing-system/internal/repositories"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrLoanNotWriteOffable is returned when a loan's status does not allow a write-off
+	ErrLoanNotWriteOffable = errors.New("only active or defaulted loans can be written off")
+	// ErrLoanNotWrittenOff is returned when a recovery is recorded on a loan that was not written off
+	ErrLoanNotWrittenOff = errors.New("recoveries can only be recorded on written-off loans")
+	// ErrRecoveryExceedsWrittenOff is returned when a recovery is larger than the written-off balance left
+	ErrRecoveryExceedsWrittenOff = errors.New("recovery exceeds the written-off balance left to recover")
+)
+
+// RecoveryDetails describe money collected on a written-off loan
+type RecoveryDetails struct {
+	Amount    int64
+	Reference string
+	Notes     string
+}
+
+// WriteOffLoan moves a loan's remaining balance off the books. The unpaid
+// installments are cancelled, the balance is moved to the written-off bucket
+// and the loan leaves the active portfolio, so nightly delinquency checks no
+// longer pick it up. The write-off is recorded as made by the caller.
+func (s *LoanService) WriteOffLoan(loanID uuid.UUID, reason string) (*models.Loan, error) {
+	if err := authorize(s.ctx, s.access, auth.PermLoansWriteOff); err != nil {
+		return nil, err
+	}
+	var loan *models.Loan
+
+	err := s.repos.WithTransaction(func(repo repositories.RepositoryManager) error {
+		var err error
+		loan, err = repo.Loans().GetByID(loanID)
+		if err != nil {
+			return ErrLoanNotFound
+		}
+
+		return writeOff(repo, loan, reason, writeOffRuleManual, actor(s.ctx))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return loan, nil
+}
+
+// writeOff moves a loan's balance to the written-off bucket and logs the status change
+func writeOff(repo repositories.RepositoryManager, loan *models.Loan, reason, rule, changedBy string) error {
+	if loan.Status != models.LoanStatusActive && loan.Status != models.LoanStatusDefaulted {
+		return ErrLoanNotWriteOffable
+	}
+
+	if err := repo.Schedules().CancelUnpaidByLoanID(loan.ID); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	fromStatus := loan.Status
+	loan.Status = models.LoanStatusWrittenOff
+	loan.WrittenOffAmount = loan.CurrentBalance
+	loan.WrittenOffAt = &now
+	loan.WriteOffReason = reason
+	loan.CurrentBalance = 0
+	loan.CurePaymentsRemaining = 0
+	loan.HolidayUntil = nil
+
+	if err := repo.Loans().Update(loan); err != nil {
+		return err
+	}
+
+	return repo.StatusChanges().Create(&models.LoanStatusChange{
+		LoanID:      loan.ID,
+		FromStatus:  fromStatus,
+		ToStatus:    loan.Status,
+		Rule:        rule,
+		DaysPastDue: loan.DaysPastDue,
+		ChangedBy:   changedBy,
+	})
+}
+
+// RecordRecovery records money collected on a written-off loan. Recoveries
+// are tracked apart from payments and never reopen the loan.
+func (s *LoanService) RecordRecovery(loanID uuid.UUID, details RecoveryDetails) (*models.Recovery, error) {
+	var recovery *models.Recovery
+
+	err := s.repos.WithTransaction(func(repo repositories.RepositoryManager) error {
+		loan, err := repo.Loans().GetByID(loanID)
+		if err != nil {
+			return ErrLoanNotFound
+		}
+		if loan.Status != models.LoanStatusWrittenOff {
+			return ErrLoanNotWrittenOff
+		}
+		if details.Amount > loan.OutstandingWrittenOff() {
+			return ErrRecoveryExceedsWrittenOff
+		}
+
+		recovery = &models.Recovery{
+			LoanID:       loanID,
+			Amount:       details.Amount,
+			RecoveryDate: time.Now(),
+			Reference:    details.Reference,
+			Notes:        details.Notes,
+		}
+		if err := repo.Recoveries().Create(recovery); err != nil {
+			return err
+		}
+
+		loan.RecoveredAmount += details.Amount
+		return repo.Loans().Update(loan)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return recovery, nil
+}
+
+// GetRecoveries returns the recoveries collected on a loan, oldest first
+func (s *LoanService) GetRecoveries(loanID uuid.UUID) ([]models.Recovery, error) {
+	if _, err := s.repos.Loans().GetByID(loanID); err != nil {
+		return nil, ErrLoanNotFound
+	}
+
+	return s.repos.Recoveries().GetByLoanID(loanID)
+}
@@ -0,0 +1,38 @@
+// Package tenant keeps the lending entities served by one deployment apart.
+// Every API request acts for the tenant of its principal, and a GORM plugin
+// scopes every statement on a tenant-owned model to that tenant, so that no
+// query can read or change another tenant's rows.
+package tenant
+
+import "context"
+
+type contextKey int
+
+const (
+	idKey contextKey = iota
+	unscopedKey
+)
+
+// WithID returns a context whose statements act for tenant id
+func WithID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, idKey, id)
+}
+
+// IDFrom returns the tenant of a context, or "" when there is none
+func IDFrom(ctx context.Context) string {
+	id, _ := ctx.Value(idKey).(string)
+	return id
+}
+
+// Unscoped returns a context whose statements read every tenant's rows.
+// Rows created with it still belong to its tenant. Only use it for data
+// that spans tenants, such as the audit chain.
+func Unscoped(ctx context.Context) context.Context {
+	return context.WithValue(ctx, unscopedKey, true)
+}
+
+// IsUnscoped reports whether statements run with ctx read every tenant's rows
+func IsUnscoped(ctx context.Context) bool {
+	unscoped, _ := ctx.Value(unscopedKey).(bool)
+	return unscoped
+}
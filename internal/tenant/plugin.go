@@ -0,0 +1,179 @@
+package tenant
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+var (
+	// ErrNoTenant is returned when a tenant-owned row is created without a tenant
+	ErrNoTenant = errors.New("row has no tenant")
+	// ErrCrossTenant is returned when a row is created for another tenant than the caller's
+	ErrCrossTenant = errors.New("row belongs to another tenant")
+)
+
+// fieldName is the field that makes a model tenant-owned
+const fieldName = "TenantID"
+
+// Plugin is a GORM plugin that scopes every statement on a tenant-owned
+// model, one with a TenantID field, to the tenant of the statement's
+// context: queries only read that tenant's rows, updates and deletes only
+// change them, and created rows are given the tenant. Statements without a
+// tenant come from inside the system, such as migrations and command line
+// tools, and see every tenant; the rows they create must name their tenant
+// unless the field is tagged `tenant:"optional"`. Raw SQL is not scoped.
+type Plugin struct{}
+
+// Name returns the name of the plugin
+func (Plugin) Name() string {
+	return "tenant"
+}
+
+// Initialize registers the plugin's callbacks
+func (Plugin) Initialize(db *gorm.DB) error {
+	if err := db.Callback().Create().Before("gorm:before_create").Register("tenant:create", assign); err != nil {
+		return err
+	}
+	if err := db.Callback().Query().Before("gorm:query").Register("tenant:query", scope); err != nil {
+		return err
+	}
+	if err := db.Callback().Row().Before("gorm:row").Register("tenant:row", scope); err != nil {
+		return err
+	}
+	if err := db.Callback().Update().Before("gorm:before_update").Register("tenant:update", scopeChange); err != nil {
+		return err
+	}
+	return db.Callback().Delete().Before("gorm:before_delete").Register("tenant:delete", scopeChange)
+}
+
+// tenantField returns the tenant field of the statement's model, or nil
+// when the model is not tenant-owned
+func tenantField(db *gorm.DB) *schema.Field {
+	if db.Error != nil || db.Statement.Schema == nil {
+		return nil
+	}
+	return db.Statement.Schema.LookUpField(fieldName)
+}
+
+// assign gives created rows the statement's tenant, and refuses rows of
+// another tenant or of none
+func assign(db *gorm.DB) {
+	field := tenantField(db)
+	if field == nil {
+		return
+	}
+	ctx := db.Statement.Context
+	id := IDFrom(ctx)
+
+	set := func(row reflect.Value) {
+		row = reflect.Indirect(row)
+		if row.Kind() != reflect.Struct {
+			return
+		}
+		value, zero := field.ValueOf(ctx, row)
+		switch {
+		case zero && id != "":
+			if err := field.Set(ctx, row, id); err != nil {
+				db.AddError(err)
+			}
+		case zero && field.Tag.Get("tenant") != "optional":
+			db.AddError(fmt.Errorf("%w: %s", ErrNoTenant, db.Statement.Schema.Table))
+		case !zero && id != "" && value != id && !IsUnscoped(ctx):
+			db.AddError(fmt.Errorf("%w: %s of tenant %v", ErrCrossTenant, db.Statement.Schema.Table, value))
+		}
+	}
+
+	rows := reflect.Indirect(db.Statement.ReflectValue)
+	switch rows.Kind() {
+	case reflect.Struct:
+		set(rows)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rows.Len(); i++ {
+			set(rows.Index(i))
+		}
+	}
+
+	// An upsert, such as Save falling back to a create, must not take over
+	// another tenant's row with the same key
+	if id == "" || IsUnscoped(ctx) {
+		return
+	}
+	if c, ok := db.Statement.Clauses["ON CONFLICT"]; ok {
+		if onConflict, ok := c.Expression.(clause.OnConflict); ok && !onConflict.DoNothing {
+			onConflict.Where.Exprs = append(onConflict.Where.Exprs, owned(field, id))
+			c.Expression = onConflict
+			db.Statement.Clauses["ON CONFLICT"] = c
+		}
+	}
+}
+
+// scope limits a query to the rows of the statement's tenant
+func scope(db *gorm.DB) {
+	field := tenantField(db)
+	if field == nil {
+		return
+	}
+	ctx := db.Statement.Context
+	if id := IDFrom(ctx); id != "" && !IsUnscoped(ctx) {
+		restrict(db.Statement, owned(field, id))
+	}
+}
+
+// scopeChange limits an update or delete to the rows of the statement's
+// tenant. Statements without conditions are left alone, so that GORM still
+// refuses them unless global updates are allowed.
+func scopeChange(db *gorm.DB) {
+	if db.Error != nil || db.Statement.Schema == nil {
+		return
+	}
+	if _, ok := db.Statement.Clauses["WHERE"]; !ok && !db.AllowGlobalUpdate && !hasPrimaryKey(db.Statement) {
+		return
+	}
+	scope(db)
+}
+
+// owned matches the rows of a tenant
+func owned(field *schema.Field, id string) clause.Expression {
+	return clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName}, Value: id}
+}
+
+// restrict adds a condition to the statement's WHERE clause. The existing
+// conditions are grouped first, so that one joined by OR cannot escape it.
+func restrict(stmt *gorm.Statement, condition clause.Expression) {
+	exprs := []clause.Expression{condition}
+	c, ok := stmt.Clauses["WHERE"]
+	if ok {
+		if where, ok := c.Expression.(clause.Where); ok && len(where.Exprs) > 0 {
+			exprs = append(exprs, clause.And(where.Exprs...))
+		}
+	}
+	c.Name = "WHERE"
+	c.Expression = clause.Where{Exprs: exprs}
+	stmt.Clauses["WHERE"] = c
+}
+
+// hasPrimaryKey reports whether a statement was given a model with any part
+// of its primary key set
+func hasPrimaryKey(stmt *gorm.Statement) bool {
+	if len(stmt.Schema.PrimaryFields) == 0 {
+		return false
+	}
+	value := reflect.Indirect(stmt.ReflectValue)
+	switch value.Kind() {
+	case reflect.Struct:
+		for _, field := range stmt.Schema.PrimaryFields {
+			if _, zero := field.ValueOf(stmt.Context, value); !zero {
+				return true
+			}
+		}
+		return false
+	case reflect.Slice, reflect.Array:
+		return value.Len() > 0
+	}
+	return false
+}
@@ -0,0 +1,326 @@
+package api_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"loan-billing-system/internal/api"
+	"loan-billing-system/internal/auth"
+	"loan-billing-system/internal/models"
+	"loan-billing-system/internal/repositories"
+	"loan-billing-system/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// routePermissions are the permissions every API route must require, any one
+// of which lets the caller through. A route added to SetupRoutes fails the
+// suite until it is listed here.
+var routePermissions = map[string][]auth.Permission{
+	"POST /api/borrowers":                            {auth.PermBorrowersWrite},
+	"GET /api/borrowers":                             {auth.PermBorrowersRead},
+	"GET /api/borrowers/:id":                         {auth.PermBorrowersRead, auth.PermSelfRead},
+	"GET /api/borrowers/delinquent":                  {auth.PermBorrowersRead},
+	"GET /api/borrowers/:id/loans":                   {auth.PermLoansRead, auth.PermSelfRead},
+	"PUT /api/borrowers/:id/credit-limits":           {auth.PermBorrowersWrite},
+	"PUT /api/borrowers/:id/profile":                 {auth.PermBorrowersWrite},
+	"POST /api/borrowers/:id/addresses":              {auth.PermBorrowersWrite},
+	"DELETE /api/borrowers/:id/addresses/:addressId": {auth.PermBorrowersWrite},
+	"POST /api/borrowers/:id/documents":              {auth.PermBorrowersWrite},
+	"GET /api/borrowers/:id/documents":               {auth.PermBorrowersRead},
+	"POST /api/borrowers/:id/kyc":                    {auth.PermBorrowersKYC},
+	"GET /api/borrowers/:id/duplicates":              {auth.PermBorrowersRead},
+	"POST /api/borrowers/:id/merge":                  {auth.PermBorrowersMerge},
+	"GET /api/borrowers/:id/merges":                  {auth.PermBorrowersRead},
+	"POST /api/borrowers/:id/erasure":                {auth.PermBorrowersWrite},
+	"GET /api/borrowers/:id/erasure":                 {auth.PermBorrowersRead},
+
+	"POST /api/loans":                                         {auth.PermLoansWrite},
+	"GET /api/loans":                                          {auth.PermLoansRead},
+	"POST /api/loans/quote":                                   {auth.PermLoansRead},
+	"GET /api/loans/:id":                                      {auth.PermLoansRead, auth.PermSelfRead},
+	"GET /api/loans/:id/outstanding":                          {auth.PermLoansRead, auth.PermSelfRead},
+	"GET /api/loans/:id/delinquent":                           {auth.PermLoansRead, auth.PermSelfRead},
+	"POST /api/loans/:id/payment":                             {auth.PermPaymentsWrite, auth.PermSelfPay},
+	"GET /api/loans/:id/schedule":                             {auth.PermLoansRead, auth.PermSelfRead},
+	"GET /api/loans/:id/payments":                             {auth.PermLoansRead, auth.PermSelfRead},
+	"POST /api/loans/:id/restructure":                         {auth.PermLoansWrite},
+	"GET /api/loans/:id/restructures":                         {auth.PermLoansRead},
+	"POST /api/loans/:id/holiday":                             {auth.PermLoansApprove},
+	"GET /api/loans/:id/holidays":                             {auth.PermLoansRead},
+	"POST /api/loans/:id/write-off":                           {auth.PermLoansWriteOff},
+	"POST /api/loans/:id/recoveries":                          {auth.PermPaymentsWrite},
+	"GET /api/loans/:id/recoveries":                           {auth.PermLoansRead},
+	"GET /api/loans/:id/status-history":                       {auth.PermLoansRead},
+	"POST /api/loans/:id/refinance":                           {auth.PermLoansWrite},
+	"GET /api/loans/:id/fees":                                 {auth.PermLoansRead},
+	"POST /api/loans/:id/parties":                             {auth.PermLoansWrite},
+	"GET /api/loans/:id/parties":                              {auth.PermLoansRead},
+	"DELETE /api/loans/:id/parties/:borrowerId":               {auth.PermLoansWrite},
+	"GET /api/loans/:id/credit-assessment":                    {auth.PermLoansRead},
+	"POST /api/loans/:id/collateral":                          {auth.PermLoansWrite},
+	"GET /api/loans/:id/collateral":                           {auth.PermLoansRead},
+	"POST /api/loans/:id/collateral/:collateralId/valuations": {auth.PermLoansWrite},
+	"PUT /api/loans/:id/collateral/:collateralId/lien":        {auth.PermLoansWrite},
+
+	"GET /api/payments/:id":          {auth.PermLoansRead},
+	"POST /api/payments/:id/reverse": {auth.PermPaymentsReverse},
+
+	"POST /api/groups":                           {auth.PermLoansWrite},
+	"GET /api/groups/:id":                        {auth.PermLoansRead},
+	"POST /api/groups/:id/members":               {auth.PermLoansWrite},
+	"DELETE /api/groups/:id/members/:borrowerId": {auth.PermLoansWrite},
+	"PUT /api/groups/:id/leader":                 {auth.PermLoansWrite},
+	"GET /api/groups/:id/schedule":               {auth.PermLoansRead},
+	"GET /api/groups/:id/collections":            {auth.PermLoansRead},
+	"POST /api/groups/:id/payments":              {auth.PermPaymentsWrite},
+
+	"GET /api/products":                      {auth.PermLoansRead},
+	"POST /api/products":                     {auth.PermProductsWrite},
+	"PATCH /api/products/:code":              {auth.PermProductsWrite},
+	"GET /api/products/:code/fees":           {auth.PermLoansRead},
+	"POST /api/products/:code/fees":          {auth.PermProductsWrite},
+	"DELETE /api/products/:code/fees/:feeId": {auth.PermProductsWrite},
+	"GET /api/calendar":                      {auth.PermLoansRead},
+	"PUT /api/calendar":                      {auth.PermProductsWrite},
+	"POST /api/calendar/holidays":            {auth.PermProductsWrite},
+	"DELETE /api/calendar/holidays/:id":      {auth.PermProductsWrite},
+
+	"GET /api/charge-offs":              {auth.PermLoansRead},
+	"POST /api/charge-offs/:id/approve": {auth.PermLoansApprove},
+	"POST /api/charge-offs/:id/reject":  {auth.PermLoansApprove},
+
+	"GET /api/reports/income": {auth.PermReportsRead},
+
+	"GET /api/audit":        {auth.PermAuditRead},
+	"GET /api/audit/verify": {auth.PermAuditRead},
+
+	"POST /api/api-keys":       {auth.PermAPIKeysManage},
+	"GET /api/api-keys":        {auth.PermAPIKeysManage},
+	"DELETE /api/api-keys/:id": {auth.PermAPIKeysManage},
+}
+
+// RoutesTestSuite defines the test suite for route access control
+type RoutesTestSuite struct {
+	suite.Suite
+	db   *gorm.DB
+	echo *echo.Echo
+	key  ed25519.PrivateKey
+	kid  string
+}
+
+// SetupSuite sets up the API over a database holding only loans, with one
+// staff role per permission, a role granting nothing and the borrower role
+func (s *RoutesTestSuite) SetupSuite() {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	s.Require().NoError(err)
+	s.db = db
+
+	// Create tables manually for SQLite compatibility
+	db.Exec(`CREATE TABLE borrowers (
+		id TEXT PRIMARY KEY,
+		tenant_id TEXT NOT NULL DEFAULT 'default',
+		name TEXT,
+		created_at DATETIME,
+		updated_at DATETIME,
+		deleted_at DATETIME
+	)`)
+
+	db.Exec(`CREATE TABLE loans (
+		id TEXT PRIMARY KEY,
+		tenant_id TEXT NOT NULL DEFAULT 'default',
+		borrower_id TEXT NOT NULL,
+		amount INTEGER NOT NULL,
+		interest_rate REAL NOT NULL,
+		term_weeks INTEGER NOT NULL,
+		start_date DATETIME NOT NULL,
+		status TEXT NOT NULL DEFAULT 'active',
+		current_balance INTEGER NOT NULL DEFAULT 0,
+		created_at DATETIME,
+		updated_at DATETIME,
+		deleted_at DATETIME
+	)`)
+
+	db.Exec(`CREATE TABLE schedules (
+		id TEXT PRIMARY KEY,
+		tenant_id TEXT NOT NULL DEFAULT 'default',
+		loan_id TEXT NOT NULL,
+		week_number INTEGER NOT NULL,
+		due_date DATETIME NOT NULL,
+		amount INTEGER NOT NULL,
+		paid BOOLEAN DEFAULT false,
+		created_at DATETIME,
+		updated_at DATETIME,
+		deleted_at DATETIME
+	)`)
+
+	db.Exec(`CREATE TABLE loan_parties (
+		id TEXT PRIMARY KEY,
+		tenant_id TEXT NOT NULL DEFAULT 'default',
+		loan_id TEXT NOT NULL,
+		borrower_id TEXT NOT NULL,
+		role TEXT NOT NULL,
+		created_at DATETIME
+	)`)
+
+	pub, key, err := ed25519.GenerateKey(rand.Reader)
+	s.Require().NoError(err)
+	jwk, err := auth.NewJWK(pub)
+	s.Require().NoError(err)
+	data, err := json.Marshal(auth.JWKS{Keys: []auth.JWK{jwk}})
+	s.Require().NoError(err)
+	keys, err := auth.ParseJWKS(data)
+	s.Require().NoError(err)
+	s.key, s.kid = key, jwk.Kid
+
+	access := &auth.Policy{Roles: map[string][]auth.Permission{
+		"nobody":          {},
+		auth.RoleBorrower: {auth.PermSelfRead, auth.PermSelfPay},
+	}}
+	for _, permission := range auth.Permissions {
+		if !permission.SelfService() {
+			access.Roles[string(permission)] = []auth.Permission{permission}
+		}
+	}
+	s.Require().NoError(access.Validate())
+
+	repos := repositories.NewGormRepositoryManager(db)
+	s.echo = echo.New()
+	api.SetupRoutes(s.echo, db,
+		services.NewBorrowerService(repos).WithAccess(access),
+		services.NewLoanService(repos).WithAccess(access),
+		services.NewAuditService(repos),
+		services.NewAuthService(repos, auth.NewVerifier(keys, "", "")).WithAccess(access),
+		access)
+}
+
+// staffToken signs a token for a member of staff holding one role
+func (s *RoutesTestSuite) staffToken(role string) string {
+	token, err := auth.Sign(s.key, s.kid, auth.Claims{Subject: "jane", ExpiresAt: time.Now().Add(time.Hour).Unix(), Roles: []string{role}})
+	s.Require().NoError(err)
+	return token
+}
+
+// borrowerToken signs a self-service token for a borrower. The roles it
+// claims must not matter.
+func (s *RoutesTestSuite) borrowerToken(borrowerID uuid.UUID) string {
+	token, err := auth.Sign(s.key, s.kid, auth.Claims{Subject: "budi", ExpiresAt: time.Now().Add(time.Hour).Unix(),
+		Roles: []string{string(auth.PermLoansRead)}, BorrowerID: borrowerID.String()})
+	s.Require().NoError(err)
+	return token
+}
+
+// request calls a route with a bearer token, or with no credentials when
+// token is empty. Path parameters are filled in with unknown IDs.
+func (s *RoutesTestSuite) request(method, path, token string) int {
+	path = strings.NewReplacer(":id", uuid.NewString(), ":addressId", uuid.NewString(), ":borrowerId", uuid.NewString(),
+		":collateralId", uuid.NewString(), ":feeId", uuid.NewString(), ":code", "STANDARD").Replace(path)
+	req := httptest.NewRequest(method, path, strings.NewReader("{}"))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	if token != "" {
+		req.Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	s.echo.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+// TestEveryRouteRequiresItsPermission tests each route: refused without
+// credentials, forbidden without the permission, let through with it.
+// Borrowers only get through to the routes that take a self-service permission.
+func (s *RoutesTestSuite) TestEveryRouteRequiresItsPermission() {
+	borrower := s.borrowerToken(uuid.New())
+	registered := map[string]bool{}
+	for _, route := range s.echo.Routes() {
+		if route.Method == echo.RouteNotFound || !strings.HasPrefix(route.Path, "/api/") {
+			continue
+		}
+		name := route.Method + " " + route.Path
+		registered[name] = true
+		permissions, ok := routePermissions[name]
+		if !s.True(ok, "%s has no expected permission", name) {
+			continue
+		}
+
+		s.Equal(http.StatusUnauthorized, s.request(route.Method, route.Path, ""), name)
+		s.Equal(http.StatusForbidden, s.request(route.Method, route.Path, s.staffToken("nobody")), name)
+		selfService := false
+		for _, permission := range auth.Permissions {
+			if permission.SelfService() {
+				selfService = selfService || slices.Contains(permissions, permission)
+				continue
+			}
+			code := s.request(route.Method, route.Path, s.staffToken(string(permission)))
+			if slices.Contains(permissions, permission) {
+				s.NotContains([]int{http.StatusUnauthorized, http.StatusForbidden}, code, "%s as %s", name, permission)
+			} else {
+				s.Equal(http.StatusForbidden, code, "%s as %s", name, permission)
+			}
+		}
+
+		code := s.request(route.Method, route.Path, borrower)
+		if selfService {
+			s.NotContains([]int{http.StatusUnauthorized, http.StatusForbidden}, code, "%s as a borrower", name)
+		} else {
+			s.Equal(http.StatusForbidden, code, "%s as a borrower", name)
+		}
+	}
+
+	for name := range routePermissions {
+		s.True(registered[name], "%s is not registered", name)
+	}
+}
+
+// TestBorrowersOnlyReachTheirOwnLoans tests that a borrower sees and repays
+// the loans they are a party to only; other borrowers and their loans are not found
+func (s *RoutesTestSuite) TestBorrowersOnlyReachTheirOwnLoans() {
+	own, other := uuid.New(), uuid.New()
+	ownLoan, otherLoan := uuid.New(), uuid.New()
+	for borrowerID, loanID := range map[uuid.UUID]uuid.UUID{own: ownLoan, other: otherLoan} {
+		s.db.Exec("INSERT INTO loans (id, borrower_id, amount, interest_rate, term_weeks, start_date, current_balance) VALUES (?, ?, ?, ?, ?, ?, ?)",
+			loanID.String(), borrowerID.String(), 5000000, 10.0, 50, time.Now(), 5500000)
+	}
+	token := s.borrowerToken(own)
+
+	s.Equal(http.StatusOK, s.request(http.MethodGet, "/api/loans/"+ownLoan.String(), token))
+	s.Equal(http.StatusOK, s.request(http.MethodGet, "/api/loans/"+otherLoan.String(), s.staffToken(string(auth.PermLoansRead))))
+
+	for _, path := range []string{"", "/outstanding", "/delinquent", "/schedule", "/payments"} {
+		s.Equal(http.StatusNotFound, s.request(http.MethodGet, "/api/loans/"+otherLoan.String()+path, token), path)
+	}
+	s.Equal(http.StatusNotFound, s.request(http.MethodPost, "/api/loans/"+otherLoan.String()+"/payment", token))
+	s.Equal(http.StatusNotFound, s.request(http.MethodGet, "/api/borrowers/"+other.String(), token))
+	s.Equal(http.StatusNotFound, s.request(http.MethodGet, "/api/borrowers/"+other.String()+"/loans", token))
+
+	// A guarantor reaches the loans they stand behind, but not the primary borrower's profile
+	guarantorID := uuid.New()
+	guarantor := s.borrowerToken(guarantorID)
+	s.Equal(http.StatusNotFound, s.request(http.MethodGet, "/api/loans/"+otherLoan.String(), guarantor))
+	s.db.Exec("INSERT INTO loan_parties (id, loan_id, borrower_id, role) VALUES (?, ?, ?, ?)",
+		uuid.NewString(), otherLoan.String(), guarantorID.String(), models.LoanPartyRoleGuarantor)
+	for _, path := range []string{"", "/outstanding"} {
+		s.Equal(http.StatusOK, s.request(http.MethodGet, "/api/loans/"+otherLoan.String()+path, guarantor), path)
+	}
+	s.Equal(http.StatusNotFound, s.request(http.MethodGet, "/api/borrowers/"+other.String(), guarantor))
+
+	// Listing across borrowers needs staff permissions
+	s.Equal(http.StatusForbidden, s.request(http.MethodGet, "/api/loans", token))
+	s.Equal(http.StatusForbidden, s.request(http.MethodGet, "/api/borrowers", token))
+}
+
+// TestRoutesSuite runs the routes test suite
+func TestRoutesSuite(t *testing.T) {
+	suite.Run(t, new(RoutesTestSuite))
+}
@@ -0,0 +1,232 @@
+package audit_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"loan-billing-system/internal/audit"
+	"loan-billing-system/internal/models"
+	"loan-billing-system/internal/pii"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// PluginTestSuite defines the test suite for the audit plugin
+type PluginTestSuite struct {
+	suite.Suite
+	DB  *gorm.DB
+	ctx context.Context
+}
+
+// SetupSuite prepares an in-memory database with the audit plugin before any tests run
+func (s *PluginTestSuite) SetupSuite() {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		s.T().Fatal(err)
+	}
+
+	// Keep a single connection so every query sees the same in-memory database
+	sqlDB, err := db.DB()
+	if err != nil {
+		s.T().Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+
+	if err := db.Use(audit.Plugin{}); err != nil {
+		s.T().Fatal(err)
+	}
+
+	// Borrower PII is encrypted, so a vault must be in use
+	key := make([]byte, 32)
+	keys, err := pii.NewLocalKeyProvider("test", map[string][]byte{"test": key}, key)
+	if err != nil {
+		s.T().Fatal(err)
+	}
+	pii.Use(pii.NewVault(keys))
+
+	// Create tables manually for SQLite compatibility
+	db.Exec(`CREATE TABLE audit_entries (
+		id TEXT PRIMARY KEY,
+		tenant_id TEXT NOT NULL DEFAULT '',
+		sequence INTEGER NOT NULL UNIQUE,
+		actor TEXT NOT NULL,
+		action TEXT NOT NULL,
+		entity_type TEXT NOT NULL,
+		entity_id TEXT NOT NULL,
+		changes TEXT NOT NULL,
+		request_id TEXT,
+		prev_hash TEXT NOT NULL,
+		hash TEXT NOT NULL,
+		created_at DATETIME NOT NULL
+	)`)
+
+	db.Exec(`CREATE TABLE loan_products (
+		code TEXT NOT NULL,
+		tenant_id TEXT NOT NULL DEFAULT 'default',
+		name TEXT NOT NULL,
+		default_after_dpd INTEGER NOT NULL DEFAULT 0,
+		charge_off_after_dpd INTEGER NOT NULL DEFAULT 0,
+		created_at DATETIME,
+		updated_at DATETIME,
+		PRIMARY KEY (tenant_id, code)
+	)`)
+
+	db.Exec(`CREATE TABLE borrowers (
+		id TEXT PRIMARY KEY,
+		tenant_id TEXT NOT NULL DEFAULT 'default',
+		name TEXT NOT NULL,
+		name_index TEXT,
+		contact_info TEXT,
+		is_delinquent BOOLEAN DEFAULT false,
+		national_id_type TEXT,
+		national_id TEXT,
+		national_id_index TEXT,
+		date_of_birth TEXT,
+		date_of_birth_index TEXT,
+		phone TEXT,
+		phone_index TEXT,
+		email TEXT,
+		employment_status TEXT,
+		employer TEXT,
+		monthly_income TEXT,
+		kyc_status TEXT NOT NULL DEFAULT 'pending',
+		kyc_reviewed_by TEXT,
+		kyc_reviewed_at DATETIME,
+		kyc_rejection_reason TEXT,
+		kyc_expires_at DATETIME,
+		credit_limit INTEGER,
+		max_concurrent_loans INTEGER,
+		merged_into_id TEXT,
+		erasure_requested_at DATETIME,
+		erasure_requested_by TEXT,
+		anonymized_at DATETIME,
+		created_at DATETIME,
+		updated_at DATETIME,
+		deleted_at DATETIME
+	)`)
+
+	s.DB = db
+	s.ctx = audit.WithRequestID(audit.WithActor(context.Background(), "ops.admin"), "req-1")
+}
+
+// SetupTest clears the tables before each test. The audit log refuses
+// deletes, so it is emptied with raw SQL.
+func (s *PluginTestSuite) SetupTest() {
+	s.DB.Exec("DELETE FROM audit_entries")
+	s.DB.Exec("DELETE FROM loan_products")
+	s.DB.Exec("DELETE FROM borrowers")
+}
+
+// entries returns the audit log in order
+func (s *PluginTestSuite) entries() []models.AuditEntry {
+	var entries []models.AuditEntry
+	s.Require().NoError(s.DB.Order("sequence").Find(&entries).Error)
+	return entries
+}
+
+// changes decodes the changes of an audit entry
+func (s *PluginTestSuite) changes(entry models.AuditEntry) map[string]audit.Change {
+	var changes map[string]audit.Change
+	s.Require().NoError(json.Unmarshal([]byte(entry.Changes), &changes))
+	return changes
+}
+
+// TestRecordsChanges tests that creates, updates and deletes are recorded with their actor, request and diff
+func (s *PluginTestSuite) TestRecordsChanges() {
+	db := s.DB.WithContext(s.ctx)
+	product := models.LoanProduct{Code: "WEEKLY", Name: "Weekly loan", DefaultAfterDPD: 90}
+	s.Require().NoError(db.Create(&product).Error)
+	s.Require().NoError(db.Model(&models.LoanProduct{}).Where("code = ?", "WEEKLY").Update("default_after_dpd", 60).Error)
+	// Saving without changing anything is not recorded
+	s.Require().NoError(db.Model(&models.LoanProduct{}).Where("code = ?", "WEEKLY").Update("default_after_dpd", 60).Error)
+	product.Name = "Weekly microloan"
+	product.DefaultAfterDPD = 60
+	s.Require().NoError(db.Save(&product).Error)
+	s.Require().NoError(s.DB.Delete(&product).Error)
+
+	entries := s.entries()
+	s.Require().Len(entries, 4)
+
+	s.Equal(models.AuditActionCreate, entries[0].Action)
+	s.Equal("loan_products", entries[0].EntityType)
+	s.Equal("WEEKLY", entries[0].EntityID)
+	s.Equal("ops.admin", entries[0].Actor)
+	s.Equal("req-1", entries[0].RequestID)
+	created := s.changes(entries[0])
+	s.Equal("Weekly loan", created["name"].New)
+	s.Nil(created["name"].Old)
+	s.NotContains(created, "updated_at")
+
+	s.Equal(models.AuditActionUpdate, entries[1].Action)
+	s.Equal(map[string]audit.Change{"default_after_dpd": {Old: float64(90), New: float64(60)}}, s.changes(entries[1]))
+	s.Equal(map[string]audit.Change{"name": {Old: "Weekly loan", New: "Weekly microloan"}}, s.changes(entries[2]))
+
+	// Changes made without an actor are the system's
+	s.Equal(models.AuditActionDelete, entries[3].Action)
+	s.Equal(audit.SystemActor, entries[3].Actor)
+	s.Equal("Weekly microloan", s.changes(entries[3])["name"].Old)
+
+	sequence, hash, err := audit.Verify(0, "", entries)
+	s.Require().NoError(err)
+	s.Equal(int64(4), sequence)
+	s.Equal(entries[3].Hash, hash)
+}
+
+// TestRolledBackWithChange tests that entries are written in the transaction of the change
+func (s *PluginTestSuite) TestRolledBackWithChange() {
+	failure := errors.New("payment failed")
+	err := s.DB.WithContext(s.ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&models.LoanProduct{Code: "WEEKLY", Name: "Weekly loan"}).Error; err != nil {
+			return err
+		}
+		return failure
+	})
+	s.ErrorIs(err, failure)
+	s.Empty(s.entries())
+}
+
+// TestRedactsPersonalData tests that PII never reaches the audit log and that re-encryption is not a change
+func (s *PluginTestSuite) TestRedactsPersonalData() {
+	db := s.DB.WithContext(s.ctx)
+	borrower := models.Borrower{ID: uuid.New(), Name: "Siti Rahma", Phone: "+6281234567890", MonthlyIncome: 8000000}
+	s.Require().NoError(db.Create(&borrower).Error)
+	// Every save re-encrypts the PII columns with fresh data keys
+	s.Require().NoError(db.Save(&borrower).Error)
+	borrower.Name = "Siti Rahmawati"
+	borrower.IsDelinquent = true
+	s.Require().NoError(db.Save(&borrower).Error)
+
+	entries := s.entries()
+	s.Require().Len(entries, 2)
+	for _, entry := range entries {
+		s.NotContains(entry.Changes, "Siti")
+		s.NotContains(entry.Changes, "6281234567890")
+		s.NotContains(entry.Changes, "8000000")
+		s.NotContains(entry.Changes, "_index")
+	}
+	s.Equal("[redacted]", s.changes(entries[0])["monthly_income"].New)
+	s.Equal(map[string]audit.Change{
+		"name":          {Old: "[redacted]", New: "[redacted]"},
+		"is_delinquent": {Old: false, New: true},
+	}, s.changes(entries[1]))
+}
+
+// TestEntriesAreImmutable tests that audit entries cannot be updated or deleted through GORM
+func (s *PluginTestSuite) TestEntriesAreImmutable() {
+	s.Require().NoError(s.DB.Create(&models.LoanProduct{Code: "WEEKLY", Name: "Weekly loan"}).Error)
+	entry := s.entries()[0]
+
+	err := s.DB.Model(&entry).Update("actor", "someone.else").Error
+	s.ErrorIs(err, audit.ErrImmutable)
+	err = s.DB.Delete(&entry).Error
+	s.ErrorIs(err, audit.ErrImmutable)
+	s.Equal("system", s.entries()[0].Actor)
+}
+
+func TestPluginSuite(t *testing.T) {
+	suite.Run(t, new(PluginTestSuite))
+}
@@ -0,0 +1,172 @@
+package auth_test
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"loan-billing-system/internal/auth"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// JWTTestSuite defines the test suite for staff token verification
+type JWTTestSuite struct {
+	suite.Suite
+	signers map[string]crypto.Signer
+	kids    map[string]string
+	keys    *auth.KeySet
+	now     time.Time
+}
+
+// SetupSuite creates one key of each supported type and a key set holding them
+func (s *JWTTestSuite) SetupSuite() {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	s.Require().NoError(err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	s.Require().NoError(err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	s.Require().NoError(err)
+
+	s.signers = map[string]crypto.Signer{auth.AlgRS256: rsaKey, auth.AlgES256: ecKey, auth.AlgEdDSA: edKey}
+	s.kids = map[string]string{}
+	var jwks auth.JWKS
+	for alg, signer := range s.signers {
+		jwk, err := auth.NewJWK(signer.Public())
+		s.Require().NoError(err)
+		s.Equal(alg, jwk.Alg)
+		s.kids[alg] = jwk.Kid
+		jwks.Keys = append(jwks.Keys, jwk)
+	}
+	data, err := json.Marshal(jwks)
+	s.Require().NoError(err)
+	s.keys, err = auth.ParseJWKS(data)
+	s.Require().NoError(err)
+	s.now = time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+}
+
+// claims returns valid claims for a token issued now
+func (s *JWTTestSuite) claims() auth.Claims {
+	return auth.Claims{
+		Issuer:    "https://id.example.com",
+		Subject:   "jane",
+		Audience:  auth.Audience{"loan-billing"},
+		ExpiresAt: s.now.Add(time.Hour).Unix(),
+		IssuedAt:  s.now.Unix(),
+		Roles:     []string{"credit_officer"},
+	}
+}
+
+// sign signs claims with the key of an algorithm
+func (s *JWTTestSuite) sign(alg string, claims auth.Claims) string {
+	token, err := auth.Sign(s.signers[alg], s.kids[alg], claims)
+	s.Require().NoError(err)
+	return token
+}
+
+// TestVerify tests that tokens signed with each key type verify
+func (s *JWTTestSuite) TestVerify() {
+	verifier := auth.NewVerifier(s.keys, "https://id.example.com", "loan-billing")
+	for _, alg := range []string{auth.AlgRS256, auth.AlgES256, auth.AlgEdDSA} {
+		claims, err := verifier.Verify(s.sign(alg, s.claims()), s.now)
+		s.Require().NoError(err, alg)
+		s.Equal("jane", claims.Subject)
+		s.Equal([]string{"credit_officer"}, claims.Roles)
+	}
+}
+
+// TestRejectsInvalidTokens tests that bad signatures, expired tokens and tokens for someone else are rejected
+func (s *JWTTestSuite) TestRejectsInvalidTokens() {
+	verifier := auth.NewVerifier(s.keys, "https://id.example.com", "loan-billing")
+	token := s.sign(auth.AlgES256, s.claims())
+
+	expired := s.claims()
+	expired.ExpiresAt = s.now.Add(-2 * time.Minute).Unix()
+	wrongAudience := s.claims()
+	wrongAudience.Audience = auth.Audience{"another-api"}
+	wrongIssuer := s.claims()
+	wrongIssuer.Issuer = "https://evil.example.com"
+	noExpiry := s.claims()
+	noExpiry.ExpiresAt = 0
+
+	// The payload of one token with the signature of another
+	parts := strings.Split(token, ".")
+	other := strings.Split(s.sign(auth.AlgES256, wrongAudience), ".")
+	tampered := parts[0] + "." + other[1] + "." + parts[2]
+
+	// A token naming an RSA key but claiming to be signed with another algorithm
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"ES256","kid":"` + s.kids[auth.AlgRS256] + `"}`))
+	rsaParts := strings.Split(s.sign(auth.AlgRS256, s.claims()), ".")
+	wrongAlg := header + "." + rsaParts[1] + "." + rsaParts[2]
+
+	// A token signed by a key outside the key set
+	outsider, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	s.Require().NoError(err)
+	unknown, err := auth.Sign(outsider, s.kids[auth.AlgES256], s.claims())
+	s.Require().NoError(err)
+
+	for name, token := range map[string]string{
+		"expired":        s.sign(auth.AlgES256, expired),
+		"wrong audience": s.sign(auth.AlgES256, wrongAudience),
+		"wrong issuer":   s.sign(auth.AlgES256, wrongIssuer),
+		"no expiry":      s.sign(auth.AlgES256, noExpiry),
+		"tampered":       tampered,
+		"wrong alg":      wrongAlg,
+		"unknown signer": unknown,
+		"malformed":      "not-a-token",
+	} {
+		_, err := verifier.Verify(token, s.now)
+		s.ErrorIs(err, auth.ErrInvalidToken, name)
+	}
+
+	// Within the allowed clock skew an expired token is still accepted
+	skewed := s.claims()
+	skewed.ExpiresAt = s.now.Add(-30 * time.Second).Unix()
+	_, err = verifier.Verify(s.sign(auth.AlgES256, skewed), s.now)
+	s.NoError(err)
+}
+
+// TestParseJWKSRejectsWeakKeys tests that key sets with small RSA keys or missing key IDs are refused
+func (s *JWTTestSuite) TestParseJWKSRejectsWeakKeys() {
+	weak, err := rsa.GenerateKey(rand.Reader, 1024)
+	s.Require().NoError(err)
+	jwk := auth.JWK{Kty: "RSA", Kid: "weak",
+		N: base64.RawURLEncoding.EncodeToString(weak.N.Bytes()),
+		E: base64.RawURLEncoding.EncodeToString([]byte{1, 0, 1})}
+	data, err := json.Marshal(auth.JWKS{Keys: []auth.JWK{jwk}})
+	s.Require().NoError(err)
+	_, err = auth.ParseJWKS(data)
+	s.ErrorIs(err, auth.ErrUnsupportedKey)
+
+	_, err = auth.ParseJWKS([]byte(`{"keys":[{"kty":"OKP","crv":"Ed25519","x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}]}`))
+	s.Error(err)
+}
+
+// TestAPIKeys tests the API key format and hashing
+func (s *JWTTestSuite) TestAPIKeys() {
+	key, prefix, err := auth.GenerateAPIKey()
+	s.Require().NoError(err)
+	s.True(auth.IsAPIKey(key))
+	parsed, err := auth.ParseAPIKey(key)
+	s.Require().NoError(err)
+	s.Equal(prefix, parsed)
+
+	hash := auth.HashAPIKey(key)
+	s.True(auth.MatchAPIKey(key, hash))
+	s.False(auth.MatchAPIKey(key+"x", hash))
+
+	_, err = auth.ParseAPIKey("lbk_short_secret")
+	s.ErrorIs(err, auth.ErrInvalidAPIKey)
+}
+
+// TestJWTTestSuite runs the JWT test suite
+func TestJWTTestSuite(t *testing.T) {
+	suite.Run(t, new(JWTTestSuite))
+}
@@ -0,0 +1,96 @@
+package auth_test
+
+import (
+	"context"
+	"loan-billing-system/internal/auth"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+// PolicyTestSuite defines the test suite for the access policy
+type PolicyTestSuite struct {
+	suite.Suite
+	policy *auth.Policy
+}
+
+// SetupTest loads the built-in policy
+func (s *PolicyTestSuite) SetupTest() {
+	var err error
+	s.policy, err = auth.DefaultPolicy()
+	s.Require().NoError(err)
+}
+
+// staff returns a member of staff holding roles
+func staff(roles ...string) *auth.Principal {
+	return &auth.Principal{Type: auth.PrincipalStaff, Subject: "jane", Roles: roles}
+}
+
+// TestDefaultPolicy tests the separation of duties of the built-in roles
+func (s *PolicyTestSuite) TestDefaultPolicy() {
+	for _, role := range []string{"viewer", "loan_officer", "approver", "collector", "admin"} {
+		s.True(s.policy.HasRole(role), role)
+		s.True(s.policy.Allows(staff(role), auth.PermLoansRead), role)
+	}
+
+	s.False(s.policy.Allows(staff("viewer"), auth.PermLoansWrite))
+	s.True(s.policy.Allows(staff("loan_officer"), auth.PermLoansWrite))
+	s.False(s.policy.Allows(staff("loan_officer"), auth.PermLoansApprove))
+	s.True(s.policy.Allows(staff("approver"), auth.PermLoansApprove))
+	s.False(s.policy.Allows(staff("approver"), auth.PermLoansWriteOff))
+	s.True(s.policy.Allows(staff("collector"), auth.PermPaymentsWrite))
+	s.False(s.policy.Allows(staff("collector"), auth.PermLoansWrite))
+	for _, permission := range auth.Permissions {
+		s.Equal(!permission.SelfService(), s.policy.Allows(staff("admin"), permission), permission)
+	}
+
+	// Roles add up, and roles the policy does not define grant nothing
+	s.True(s.policy.Allows(staff("collector", "approver"), auth.PermLoansApprove))
+	s.False(s.policy.Allows(staff("superuser"), auth.PermLoansRead))
+	s.False(s.policy.Allows(nil, auth.PermLoansRead))
+}
+
+// TestBorrowersOnlyGetSelfService tests that borrowers are granted nothing
+// beyond their own data, whatever roles they hold, and that staff never act as a borrower
+func (s *PolicyTestSuite) TestBorrowersOnlyGetSelfService() {
+	borrowerID := uuid.New()
+	borrower := &auth.Principal{Type: auth.PrincipalBorrower, Subject: "budi", Roles: []string{auth.RoleBorrower, "admin"}, BorrowerID: &borrowerID}
+	for _, permission := range auth.Permissions {
+		s.Equal(permission.SelfService(), s.policy.Allows(borrower, permission), permission)
+	}
+
+	s.True(borrower.OwnsBorrower(borrowerID))
+	s.False(borrower.OwnsBorrower(uuid.New()))
+	s.True(staff("viewer").OwnsBorrower(uuid.New()))
+
+	ctx := auth.WithPrincipal(context.Background(), borrower)
+	s.NoError(s.policy.Authorize(ctx, auth.PermLoansRead, auth.PermSelfRead))
+	s.ErrorIs(s.policy.Authorize(ctx, auth.PermLoansRead), auth.ErrForbidden)
+}
+
+// TestAuthorize tests that callers without the permission are forbidden and internal calls are not checked
+func (s *PolicyTestSuite) TestAuthorize() {
+	s.ErrorIs(s.policy.Authorize(auth.WithPrincipal(context.Background(), staff("viewer")), auth.PermLoansWriteOff), auth.ErrForbidden)
+	s.NoError(s.policy.Authorize(auth.WithPrincipal(context.Background(), staff("admin")), auth.PermLoansWriteOff))
+	s.NoError(s.policy.Authorize(context.Background(), auth.PermLoansWriteOff))
+}
+
+// TestParsePolicyRejectsTypos tests that unknown permissions and fields are refused
+func (s *PolicyTestSuite) TestParsePolicyRejectsTypos() {
+	policy, err := auth.ParsePolicy([]byte(`{"roles":{"teller":["payments:write","loans:read"]}}`), "json")
+	s.Require().NoError(err)
+	s.True(policy.Allows(staff("teller"), auth.PermPaymentsWrite))
+
+	_, err = auth.ParsePolicy([]byte("roles:\n  teller:\n    - payment:write\n"), "yaml")
+	s.ErrorIs(err, auth.ErrInvalidPolicy)
+	_, err = auth.ParsePolicy([]byte("role:\n  teller:\n    - payments:write\n"), "yaml")
+	s.ErrorIs(err, auth.ErrInvalidPolicy)
+	_, err = auth.ParsePolicy([]byte(`{"roles":{}}`), "json")
+	s.ErrorIs(err, auth.ErrInvalidPolicy)
+}
+
+// TestPolicyTestSuite runs the access policy test suite
+func TestPolicyTestSuite(t *testing.T) {
+	suite.Run(t, new(PolicyTestSuite))
+}
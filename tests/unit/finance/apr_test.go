@@ -0,0 +1,102 @@
+package finance_test
+
+import (
+	"loan-billing-system/internal/finance"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// APRTestSuite checks the cost-of-credit calculator against published worked examples
+type APRTestSuite struct {
+	suite.Suite
+}
+
+// TestRegulationZMonthlyLoan tests the US Regulation Z actuarial APR of an amortizing loan:
+// $5,000 financed and repaid with 36 monthly payments of $166.07 discloses a 12.00% APR
+func (s *APRTestSuite) TestRegulationZMonthlyLoan() {
+	payments := make([]float64, 36)
+	for i := range payments {
+		payments[i] = 166.07
+	}
+
+	apr, err := finance.AnnualPercentageRate(5000, payments, 12)
+
+	assert.NoError(s.T(), err)
+	assert.InDelta(s.T(), 12.00, apr, 0.005)
+}
+
+// TestRegulationZFeeFinanced tests that fees withheld from the amount financed raise the APR:
+// the same payments on $4,900 actually received disclose roughly 13.4%
+func (s *APRTestSuite) TestRegulationZFeeFinanced() {
+	payments := make([]float64, 36)
+	for i := range payments {
+		payments[i] = 166.07
+	}
+
+	apr, err := finance.AnnualPercentageRate(4900, payments, 12)
+
+	assert.NoError(s.T(), err)
+	assert.InDelta(s.T(), 13.40, apr, 0.05)
+}
+
+// TestConsumerCreditDirectiveSingleRepayment tests the EU APRC of a credit of 1,000
+// repaid with a single payment of 1,200 one year later, which is exactly 20%
+func (s *APRTestSuite) TestConsumerCreditDirectiveSingleRepayment() {
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	aprc, err := finance.AnnualPercentageRateOfCharge([]finance.CashFlow{
+		{Date: start, Amount: -1000},
+		{Date: start.AddDate(0, 0, 365), Amount: 1200},
+	})
+
+	assert.NoError(s.T(), err)
+	assert.InDelta(s.T(), 20.00, aprc, 0.0001)
+}
+
+// TestConsumerCreditDirectiveTwoRepayments tests the EU APRC of a credit of 1,000
+// repaid with 600 after one year and 600 after two years, which is 13.07%
+func (s *APRTestSuite) TestConsumerCreditDirectiveTwoRepayments() {
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	aprc, err := finance.AnnualPercentageRateOfCharge([]finance.CashFlow{
+		{Date: start, Amount: -1000},
+		{Date: start.AddDate(0, 0, 365), Amount: 600},
+		{Date: start.AddDate(0, 0, 730), Amount: 600},
+	})
+
+	assert.NoError(s.T(), err)
+	assert.InDelta(s.T(), 13.07, aprc, 0.005)
+}
+
+// TestDiscloseStandardLoan tests the standard product: 5,000,000 at 10% flat over 50 weeks
+// costs far more than its nominal rate once the declining balance is accounted for
+func (s *APRTestSuite) TestDiscloseStandardLoan() {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	payments := make([]finance.ScheduledPayment, 50)
+	for i := range payments {
+		payments[i] = finance.ScheduledPayment{Date: start.AddDate(0, 0, (i+1)*7), Amount: 109615}
+	}
+	payments[49].Amount = 109634
+
+	disclosure, err := finance.Disclose(5000000, start, payments, 52)
+
+	assert.NoError(s.T(), err)
+	assert.InDelta(s.T(), 19.04, disclosure.APR, 0.01)
+	assert.Greater(s.T(), disclosure.EffectiveRate, disclosure.APR)
+}
+
+// TestRejectsInvalidCashFlows tests that cash flows without a rate are refused
+func (s *APRTestSuite) TestRejectsInvalidCashFlows() {
+	_, err := finance.AnnualPercentageRate(0, []float64{100}, 12)
+	assert.ErrorIs(s.T(), err, finance.ErrInvalidCashFlows)
+
+	_, err = finance.IRR([]float64{100, 100})
+	assert.ErrorIs(s.T(), err, finance.ErrNoIRR)
+}
+
+func TestAPRSuite(t *testing.T) {
+	suite.Run(t, new(APRTestSuite))
+}
@@ -0,0 +1,102 @@
+package pii_test
+
+import (
+	"loan-billing-system/internal/pii"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// VaultTestSuite defines the test suite for PII encryption
+type VaultTestSuite struct {
+	suite.Suite
+	keyFile string
+}
+
+// SetupTest creates a key file with one key
+func (s *VaultTestSuite) SetupTest() {
+	s.keyFile = filepath.Join(s.T().TempDir(), "keys.json")
+	_, err := pii.GenerateKey(s.keyFile)
+	s.Require().NoError(err)
+}
+
+// loadVault loads a vault from the suite's key file
+func (s *VaultTestSuite) loadVault() *pii.Vault {
+	keys, err := pii.LoadKeyFile(s.keyFile)
+	s.Require().NoError(err)
+	return pii.NewVault(keys)
+}
+
+// TestEncryptDecrypt tests that values round-trip and every encryption differs
+func (s *VaultTestSuite) TestEncryptDecrypt() {
+	vault := s.loadVault()
+
+	first, err := vault.Encrypt("3171234567890001")
+	s.Require().NoError(err)
+	second, err := vault.Encrypt("3171234567890001")
+	s.Require().NoError(err)
+	s.True(pii.IsEncrypted(first))
+	s.NotContains(first, "3171234567890001")
+	s.NotEqual(first, second)
+
+	plaintext, err := vault.Decrypt(first)
+	s.Require().NoError(err)
+	s.Equal("3171234567890001", plaintext)
+
+	// Legacy plaintext reads back unchanged
+	plaintext, err = vault.Decrypt("Siti Rahma")
+	s.Require().NoError(err)
+	s.Equal("Siti Rahma", plaintext)
+
+	// Tampering is detected
+	flip := map[byte]string{'A': "B"}[first[len(first)-10]]
+	if flip == "" {
+		flip = "A"
+	}
+	_, err = vault.Decrypt(first[:len(first)-10] + flip + first[len(first)-9:])
+	s.ErrorIs(err, pii.ErrCorrupt)
+}
+
+// TestKeyRotation tests that old values stay readable after a new key is made current
+func (s *VaultTestSuite) TestKeyRotation() {
+	oldVault := s.loadVault()
+	oldValue, err := oldVault.Encrypt("+6281234567890")
+	s.Require().NoError(err)
+	oldKey, err := pii.KeyID(oldValue)
+	s.Require().NoError(err)
+	oldIndex := oldVault.BlindIndex("phone", "+6281234567890")
+
+	newKey, err := pii.GenerateKey(s.keyFile)
+	s.Require().NoError(err)
+	newVault := s.loadVault()
+	s.Equal(newKey, newVault.CurrentKeyID())
+	s.NotEqual(oldKey, newKey)
+
+	plaintext, err := newVault.Decrypt(oldValue)
+	s.Require().NoError(err)
+	s.Equal("+6281234567890", plaintext)
+
+	newValue, err := newVault.Encrypt(plaintext)
+	s.Require().NoError(err)
+	id, err := pii.KeyID(newValue)
+	s.Require().NoError(err)
+	s.Equal(newKey, id)
+
+	// Blind indexes survive rotation
+	s.Equal(oldIndex, newVault.BlindIndex("phone", "+6281234567890"))
+	s.NotEqual(oldIndex, newVault.BlindIndex("name", "+6281234567890"))
+
+	// Dropping the old key makes its values unreadable
+	data, err := os.ReadFile(s.keyFile)
+	s.Require().NoError(err)
+	s.Require().NoError(os.WriteFile(s.keyFile, []byte(strings.Replace(string(data), `"`+oldKey+`"`, `"retired"`, 1)), 0o600))
+	_, err = s.loadVault().Decrypt(oldValue)
+	s.ErrorIs(err, pii.ErrUnknownKey)
+}
+
+func TestVaultSuite(t *testing.T) {
+	suite.Run(t, new(VaultTestSuite))
+}
@@ -0,0 +1,416 @@
+package repositories_test
+
+import (
+	"loan-billing-system/internal/models"
+	"loan-billing-system/internal/pii"
+	"loan-billing-system/internal/repositories"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// BorrowerRepositoryTestSuite defines the test suite for borrower listings
+type BorrowerRepositoryTestSuite struct {
+	suite.Suite
+	DB   *gorm.DB
+	Repo *repositories.GormBorrowerRepository
+}
+
+// SetupSuite prepares an in-memory database before any tests run
+func (s *BorrowerRepositoryTestSuite) SetupSuite() {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		s.T().Fatal(err)
+	}
+
+	// Keep a single connection so every query sees the same in-memory database
+	sqlDB, err := db.DB()
+	if err != nil {
+		s.T().Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+
+	// Borrower PII is encrypted, so a vault must be in use
+	key := make([]byte, 32)
+	keys, err := pii.NewLocalKeyProvider("test", map[string][]byte{"test": key}, key)
+	if err != nil {
+		s.T().Fatal(err)
+	}
+	pii.Use(pii.NewVault(keys))
+
+	// Create tables manually for SQLite compatibility
+	db.Exec(`CREATE TABLE borrowers (
+		id TEXT PRIMARY KEY,
+		tenant_id TEXT NOT NULL DEFAULT 'default',
+		name TEXT NOT NULL,
+		name_index TEXT,
+		contact_info TEXT,
+		is_delinquent BOOLEAN DEFAULT false,
+		national_id_type TEXT,
+		national_id TEXT,
+		national_id_index TEXT,
+		date_of_birth TEXT,
+		date_of_birth_index TEXT,
+		phone TEXT,
+		phone_index TEXT,
+		email TEXT,
+		employment_status TEXT,
+		employer TEXT,
+		monthly_income TEXT,
+		kyc_status TEXT NOT NULL DEFAULT 'pending',
+		kyc_reviewed_by TEXT,
+		kyc_reviewed_at DATETIME,
+		kyc_rejection_reason TEXT,
+		kyc_expires_at DATETIME,
+		credit_limit INTEGER,
+		max_concurrent_loans INTEGER,
+		merged_into_id TEXT,
+		erasure_requested_at DATETIME,
+		erasure_requested_by TEXT,
+		anonymized_at DATETIME,
+		created_at DATETIME,
+		updated_at DATETIME,
+		deleted_at DATETIME
+	)`)
+
+	db.Exec(`CREATE TABLE loans (
+		id TEXT PRIMARY KEY,
+		tenant_id TEXT NOT NULL DEFAULT 'default',
+		borrower_id TEXT NOT NULL,
+		amount INTEGER NOT NULL,
+		interest_rate REAL NOT NULL,
+		term_weeks INTEGER NOT NULL,
+		start_date DATETIME NOT NULL,
+		status TEXT NOT NULL DEFAULT 'active',
+		current_balance INTEGER NOT NULL DEFAULT 0,
+		last_payment_date DATETIME,
+		created_at DATETIME,
+		updated_at DATETIME,
+		deleted_at DATETIME
+	)`)
+
+	db.Exec(`CREATE TABLE loan_parties (
+		id TEXT PRIMARY KEY,
+		tenant_id TEXT NOT NULL DEFAULT 'default',
+		loan_id TEXT NOT NULL,
+		borrower_id TEXT NOT NULL,
+		role TEXT NOT NULL,
+		created_at DATETIME
+	)`)
+
+	db.Exec(`CREATE TABLE borrower_addresses (
+		id TEXT PRIMARY KEY,
+		tenant_id TEXT NOT NULL DEFAULT 'default',
+		borrower_id TEXT NOT NULL,
+		type TEXT NOT NULL,
+		line1 TEXT NOT NULL,
+		line2 TEXT,
+		city TEXT NOT NULL,
+		region TEXT,
+		postal_code TEXT,
+		country TEXT NOT NULL,
+		created_at DATETIME,
+		updated_at DATETIME,
+		deleted_at DATETIME
+	)`)
+
+	db.Exec(`CREATE TABLE borrower_documents (
+		id TEXT PRIMARY KEY,
+		tenant_id TEXT NOT NULL DEFAULT 'default',
+		borrower_id TEXT NOT NULL,
+		type TEXT NOT NULL,
+		file_name TEXT NOT NULL,
+		content_type TEXT NOT NULL,
+		size_bytes INTEGER NOT NULL,
+		storage_key TEXT NOT NULL,
+		checksum TEXT NOT NULL,
+		expires_at DATE,
+		uploaded_by TEXT,
+		created_at DATETIME
+	)`)
+
+	db.Exec(`CREATE TABLE borrower_merges (
+		id TEXT PRIMARY KEY,
+		tenant_id TEXT NOT NULL DEFAULT 'default',
+		source_id TEXT NOT NULL,
+		target_id TEXT NOT NULL,
+		merged_by TEXT NOT NULL,
+		reason TEXT,
+		loans_moved INTEGER NOT NULL DEFAULT 0,
+		parties_moved INTEGER NOT NULL DEFAULT 0,
+		members_moved INTEGER NOT NULL DEFAULT 0,
+		source_snapshot TEXT,
+		created_at DATETIME
+	)`)
+
+	s.DB = db
+	s.Repo = repositories.NewGormBorrowerRepository(db)
+}
+
+// TearDownTest cleans up after each test
+func (s *BorrowerRepositoryTestSuite) TearDownTest() {
+	s.DB.Exec("DELETE FROM loans")
+	s.DB.Exec("DELETE FROM loan_parties")
+	s.DB.Exec("DELETE FROM borrower_addresses")
+	s.DB.Exec("DELETE FROM borrower_documents")
+	s.DB.Exec("DELETE FROM borrower_merges")
+	s.DB.Exec("DELETE FROM borrowers")
+}
+
+// seedBorrowers inserts n borrowers created one hour apart, oldest first
+func (s *BorrowerRepositoryTestSuite) seedBorrowers(n int) []models.Borrower {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var borrowers []models.Borrower
+	for i := 0; i < n; i++ {
+		borrower := models.Borrower{
+			ID:           uuid.New(),
+			Name:         string(rune('A' + i)),
+			IsDelinquent: i%2 == 0,
+			CreatedAt:    base.Add(time.Duration(i) * time.Hour),
+		}
+		if err := s.DB.Create(&borrower).Error; err != nil {
+			s.T().Fatal(err)
+		}
+		borrowers = append(borrowers, borrower)
+	}
+	return borrowers
+}
+
+// TestListWalksAllPages tests that following next_cursor visits every row exactly once
+func (s *BorrowerRepositoryTestSuite) TestListWalksAllPages() {
+	seeded := s.seedBorrowers(5)
+
+	var seen []uuid.UUID
+	page := repositories.PageRequest{Limit: 2}
+	for {
+		result, err := s.Repo.List(repositories.BorrowerFilter{}, page)
+		assert.NoError(s.T(), err)
+		for _, b := range result.Items {
+			seen = append(seen, b.ID)
+		}
+		if !result.HasMore() {
+			break
+		}
+		page.Cursor = result.NextCursor
+	}
+
+	var expected []uuid.UUID
+	for _, b := range seeded {
+		expected = append(expected, b.ID)
+	}
+	assert.Equal(s.T(), expected, seen)
+}
+
+// TestListDescending tests sorting in descending order across pages
+func (s *BorrowerRepositoryTestSuite) TestListDescending() {
+	seeded := s.seedBorrowers(3)
+
+	first, err := s.Repo.List(repositories.BorrowerFilter{}, repositories.PageRequest{Limit: 2, SortBy: "created_at", Desc: true})
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), first.Items, 2)
+	assert.Equal(s.T(), seeded[2].ID, first.Items[0].ID)
+	assert.Equal(s.T(), seeded[1].ID, first.Items[1].ID)
+
+	second, err := s.Repo.List(repositories.BorrowerFilter{}, repositories.PageRequest{Limit: 2, SortBy: "created_at", Desc: true, Cursor: first.NextCursor})
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), second.Items, 1)
+	assert.Equal(s.T(), seeded[0].ID, second.Items[0].ID)
+	assert.False(s.T(), second.HasMore())
+}
+
+// TestListFilters tests the delinquency, creation range and loan status filters
+func (s *BorrowerRepositoryTestSuite) TestListFilters() {
+	seeded := s.seedBorrowers(4)
+
+	delinquent := true
+	result, err := s.Repo.List(repositories.BorrowerFilter{IsDelinquent: &delinquent}, repositories.PageRequest{})
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), result.Items, 2)
+
+	from := seeded[1].CreatedAt
+	to := seeded[3].CreatedAt
+	result, err = s.Repo.List(repositories.BorrowerFilter{CreatedFrom: &from, CreatedTo: &to}, repositories.PageRequest{})
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), result.Items, 2)
+	assert.Equal(s.T(), seeded[1].ID, result.Items[0].ID)
+
+	s.DB.Exec("INSERT INTO loans (id, borrower_id, amount, interest_rate, term_weeks, start_date, status) VALUES (?, ?, ?, ?, ?, ?, ?)",
+		uuid.New().String(), seeded[3].ID.String(), 5000000, 10.0, 50, time.Now(), "active")
+	// A loan row of another tenant does not count, even one naming this tenant's borrower
+	s.DB.Exec("INSERT INTO loans (id, tenant_id, borrower_id, amount, interest_rate, term_weeks, start_date, status) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+		uuid.New().String(), "other", seeded[2].ID.String(), 5000000, 10.0, 50, time.Now(), "active")
+
+	result, err = s.Repo.List(repositories.BorrowerFilter{LoanStatus: "active"}, repositories.PageRequest{})
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), result.Items, 1)
+	assert.Equal(s.T(), seeded[3].ID, result.Items[0].ID)
+}
+
+// TestListRejectsBadInput tests invalid cursors and sort fields
+func (s *BorrowerRepositoryTestSuite) TestListRejectsBadInput() {
+	s.seedBorrowers(3)
+
+	_, err := s.Repo.List(repositories.BorrowerFilter{}, repositories.PageRequest{Cursor: "not-a-cursor"})
+	assert.ErrorIs(s.T(), err, repositories.ErrInvalidCursor)
+
+	_, err = s.Repo.List(repositories.BorrowerFilter{}, repositories.PageRequest{SortBy: "contact_info"})
+	assert.ErrorIs(s.T(), err, repositories.ErrInvalidSort)
+
+	// Names are encrypted, so they cannot be sorted on
+	_, err = s.Repo.List(repositories.BorrowerFilter{}, repositories.PageRequest{SortBy: "name"})
+	assert.ErrorIs(s.T(), err, repositories.ErrInvalidSort)
+
+	// A cursor issued for one sort order cannot be replayed against another
+	first, err := s.Repo.List(repositories.BorrowerFilter{}, repositories.PageRequest{Limit: 1})
+	assert.NoError(s.T(), err)
+	_, err = s.Repo.List(repositories.BorrowerFilter{}, repositories.PageRequest{Limit: 1, Desc: true, Cursor: first.NextCursor})
+	assert.ErrorIs(s.T(), err, repositories.ErrInvalidCursor)
+}
+
+// TestPIIEncryptedAtRest tests that PII is stored encrypted and still found through its blind indexes
+func (s *BorrowerRepositoryTestSuite) TestPIIEncryptedAtRest() {
+	dateOfBirth := time.Date(1990, 5, 17, 0, 0, 0, 0, time.UTC)
+	borrower := &models.Borrower{
+		ID:             uuid.New(),
+		Name:           "Siti Rahmá",
+		ContactInfo:    "Jl. Sudirman 1, Jakarta",
+		NationalIDType: models.NationalIDTypeNationalID,
+		NationalID:     "3171234567890001",
+		DateOfBirth:    &dateOfBirth,
+		Phone:          "+6281234567890",
+		MonthlyIncome:  8000000,
+		Addresses:      []models.BorrowerAddress{{ID: uuid.New(), Type: models.AddressTypeResidential, Line1: "Jl. Sudirman 1", City: "Jakarta", Region: "DKI Jakarta", Country: "ID"}},
+	}
+	assert.NoError(s.T(), s.Repo.Create(borrower))
+	kyc := repositories.NewGormKYCRepository(s.DB)
+	s.Require().NoError(kyc.CreateDocument(&models.BorrowerDocument{ID: uuid.New(), BorrowerID: borrower.ID, Type: models.DocumentTypeNationalID, FileName: "ktp-siti-rahma.jpg", ContentType: "image/jpeg", SizeBytes: 1024, StorageKey: "kyc/1", Checksum: "abc"}))
+
+	var stored struct {
+		Name          string
+		ContactInfo   string
+		NationalID    string
+		DateOfBirth   string
+		Phone         string
+		MonthlyIncome string
+	}
+	s.DB.Raw("SELECT name, contact_info, national_id, date_of_birth, phone, monthly_income FROM borrowers WHERE id = ?", borrower.ID).Scan(&stored)
+	var address struct{ City, Region string }
+	s.DB.Raw("SELECT city, region FROM borrower_addresses WHERE borrower_id = ?", borrower.ID).Scan(&address)
+	var fileName string
+	s.DB.Raw("SELECT file_name FROM borrower_documents WHERE borrower_id = ?", borrower.ID).Scan(&fileName)
+	for _, value := range []string{stored.Name, stored.ContactInfo, stored.NationalID, stored.DateOfBirth, stored.Phone, stored.MonthlyIncome, address.City, address.Region, fileName} {
+		assert.True(s.T(), pii.IsEncrypted(value), value)
+	}
+	assert.NotContains(s.T(), stored.NationalID, "3171234567890001")
+
+	// Encrypted values read back as they were written
+	read, err := s.Repo.GetByID(borrower.ID)
+	s.Require().NoError(err)
+	s.True(dateOfBirth.Equal(*read.DateOfBirth))
+	s.Equal(int64(8000000), read.MonthlyIncome)
+	s.Equal("Jakarta", read.Addresses[0].City)
+	documents, err := kyc.GetDocuments(borrower.ID)
+	s.Require().NoError(err)
+	s.Equal("ktp-siti-rahma.jpg", documents[0].FileName)
+
+	// Dates of birth match through their blind index
+	found, err := s.Repo.FindMatches(repositories.BorrowerMatch{DateOfBirth: &dateOfBirth})
+	s.Require().NoError(err)
+	s.Len(found, 1)
+	otherDate := dateOfBirth.AddDate(0, 0, 1)
+	found, err = s.Repo.FindMatches(repositories.BorrowerMatch{DateOfBirth: &otherDate})
+	s.Require().NoError(err)
+	s.Empty(found)
+
+	// A date of birth left in plaintext by the old date column still reads
+	s.DB.Exec("UPDATE borrowers SET date_of_birth = ? WHERE id = ?", "1990-05-17", borrower.ID)
+	read, err = s.Repo.GetByID(borrower.ID)
+	s.Require().NoError(err)
+	s.True(dateOfBirth.Equal(*read.DateOfBirth))
+
+	found, err = s.Repo.FindMatches(repositories.BorrowerMatch{NationalIDType: models.NationalIDTypeNationalID, NationalID: "3171234567890001"})
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), found, 1)
+	assert.Equal(s.T(), "Siti Rahmá", found[0].Name)
+	assert.Equal(s.T(), "+6281234567890", found[0].Phone)
+
+	// Names match on their normalized form
+	found, err = s.Repo.FindMatches(repositories.BorrowerMatch{Name: "rahma, siti"})
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), found, 1)
+
+	found, err = s.Repo.FindMatches(repositories.BorrowerMatch{Phone: "+6281234567899"})
+	assert.NoError(s.T(), err)
+	assert.Empty(s.T(), found)
+}
+
+// TestAnonymize tests that erasure clears a borrower's PII and that of their merged duplicates but keeps their loans
+func (s *BorrowerRepositoryTestSuite) TestAnonymize() {
+	erasures := repositories.NewGormErasureRepository(s.DB)
+	cutoff := time.Now().AddDate(0, 0, -30)
+	borrower := &models.Borrower{
+		ID:             uuid.New(),
+		Name:           "Siti Rahma",
+		NationalIDType: models.NationalIDTypeNationalID,
+		NationalID:     "3171234567890001",
+		Phone:          "+6281234567890",
+		CreatedAt:      cutoff.AddDate(-1, 0, 0),
+		Addresses:      []models.BorrowerAddress{{ID: uuid.New(), Type: models.AddressTypeResidential, Line1: "Jl. Sudirman 1", City: "Jakarta", Country: "ID"}},
+	}
+	s.Require().NoError(s.Repo.Create(borrower))
+	duplicate := &models.Borrower{ID: uuid.New(), Name: "Siti Rahmah", Phone: "+6281234567899", MergedIntoID: &borrower.ID, CreatedAt: borrower.CreatedAt}
+	s.Require().NoError(s.Repo.Create(duplicate))
+	s.Require().NoError(s.Repo.Delete(duplicate.ID))
+	s.Require().NoError(repositories.NewGormMergeRepository(s.DB).Create(&models.BorrowerMerge{ID: uuid.New(), SourceID: duplicate.ID, TargetID: borrower.ID, MergedBy: "ops.admin", SourceSnapshot: *duplicate}))
+	loanID := uuid.New()
+	s.DB.Exec("INSERT INTO loans (id, borrower_id, amount, interest_rate, term_weeks, start_date, status, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+		loanID.String(), borrower.ID.String(), 5000000, 10.0, 50, time.Now(), models.LoanStatusActive, cutoff.AddDate(0, -1, 0))
+
+	// Open loans and erasure requests gate the candidates
+	candidates, err := erasures.FindCandidates(cutoff, false)
+	s.Require().NoError(err)
+	s.Empty(candidates)
+	s.DB.Exec("UPDATE loans SET status = ? WHERE id = ?", models.LoanStatusClosed, loanID.String())
+	candidates, err = erasures.FindCandidates(cutoff, false)
+	s.Require().NoError(err)
+	s.Len(candidates, 1)
+	candidates, err = erasures.FindCandidates(cutoff, true)
+	s.Require().NoError(err)
+	s.Empty(candidates)
+
+	s.Require().NoError(erasures.Anonymize(borrower.ID, time.Now()))
+
+	anonymized, err := s.Repo.GetByID(borrower.ID)
+	s.Require().NoError(err)
+	s.Equal(models.AnonymizedName, anonymized.Name)
+	s.Empty(anonymized.NationalID)
+	s.Empty(anonymized.Phone)
+	s.NotNil(anonymized.AnonymizedAt)
+	s.Empty(anonymized.Addresses)
+	s.Len(anonymized.Loans, 1)
+
+	var merged models.Borrower
+	s.Require().NoError(s.DB.Unscoped().First(&merged, duplicate.ID).Error)
+	s.Equal(models.AnonymizedName, merged.Name)
+	s.Empty(merged.Phone)
+	s.Empty(merged.PhoneIndex)
+
+	var addresses, snapshots int64
+	s.DB.Raw("SELECT COUNT(*) FROM borrower_addresses").Scan(&addresses)
+	s.DB.Raw("SELECT COUNT(*) FROM borrower_merges WHERE source_snapshot IS NOT NULL").Scan(&snapshots)
+	s.Zero(addresses)
+	s.Zero(snapshots)
+
+	found, err := s.Repo.FindMatches(repositories.BorrowerMatch{Name: "Siti Rahma", Phone: "+6281234567890"})
+	s.Require().NoError(err)
+	s.Empty(found)
+}
+
+func TestBorrowerRepositorySuite(t *testing.T) {
+	suite.Run(t, new(BorrowerRepositoryTestSuite))
+}
@@ -1,4 +1,4 @@
-package scheduler_test
+package repositories_test
 
 import (
 	"loan-billing-system/internal/models"
@@ -103,6 +103,7 @@ func (s *SchedulerTestSuite) SetupSuite() {
 	// Create tables manually for SQLite compatibility
 	db.Exec(`CREATE TABLE borrowers (
 		id TEXT PRIMARY KEY,
+		tenant_id TEXT NOT NULL DEFAULT 'default',
 		name TEXT NOT NULL,
 		contact_info TEXT,
 		is_delinquent BOOLEAN DEFAULT false,
@@ -113,6 +114,7 @@ func (s *SchedulerTestSuite) SetupSuite() {
 
 	db.Exec(`CREATE TABLE loans (
 		id TEXT PRIMARY KEY,
+		tenant_id TEXT NOT NULL DEFAULT 'default',
 		borrower_id TEXT NOT NULL,
 		amount INTEGER NOT NULL,
 		interest_rate REAL NOT NULL,
@@ -127,6 +129,7 @@ func (s *SchedulerTestSuite) SetupSuite() {
 
 	db.Exec(`CREATE TABLE schedules (
 		id TEXT PRIMARY KEY,
+		tenant_id TEXT NOT NULL DEFAULT 'default',
 		loan_id TEXT NOT NULL,
 		week_number INTEGER NOT NULL,
 		due_date DATETIME NOT NULL,
@@ -140,6 +143,7 @@ func (s *SchedulerTestSuite) SetupSuite() {
 
 	db.Exec(`CREATE TABLE payments (
 		id TEXT PRIMARY KEY,
+		tenant_id TEXT NOT NULL DEFAULT 'default',
 		loan_id TEXT NOT NULL,
 		schedule_id TEXT NOT NULL,
 		amount INTEGER NOT NULL,
@@ -0,0 +1,136 @@
+package scoring_test
+
+import (
+	"loan-billing-system/internal/scoring"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// testScorecardYAML scores repayment record and past defaults
+const testScorecardYAML = `
+version: test-1
+base_score: 500
+approve_from: 550
+decline_below: 450
+grades:
+  - name: C
+    min_score: 0
+  - name: A
+    min_score: 560
+  - name: B
+    min_score: 500
+rules:
+  - name: Repayment record
+    factor: on_time_ratio
+    missing:
+      points: 10
+      reason: No history
+    bands:
+      - min: 0.9
+        points: 70
+        reason: Pays on time
+      - max: 0.9
+        points: -80
+        reason: Pays late
+  - name: Past defaults
+    factor: prior_defaults
+    bands:
+      - max: 1
+        points: 0
+        reason: No defaults
+      - min: 1
+        points: 0
+        reason: Has defaulted
+        knockout: true
+`
+
+// ScorecardTestSuite checks scorecard loading and scoring
+type ScorecardTestSuite struct {
+	suite.Suite
+	scorecard *scoring.Scorecard
+}
+
+// SetupTest parses the test scorecard before each test
+func (s *ScorecardTestSuite) SetupTest() {
+	scorecard, err := scoring.Parse([]byte(testScorecardYAML), "yaml")
+	s.Require().NoError(err)
+	s.scorecard = scorecard
+}
+
+// TestScoreBandsGradesAndDecisions tests that points, grade and decision follow the bands and thresholds
+func (s *ScorecardTestSuite) TestScoreBandsGradesAndDecisions() {
+	// Good payer: 500 + 70
+	result := s.scorecard.Score(scoring.Applicant{InstallmentsDue: 10, InstallmentsOnTime: 10})
+	assert.Equal(s.T(), "test-1", result.ScorecardVersion)
+	assert.Equal(s.T(), 570, result.Score)
+	assert.Equal(s.T(), "A", result.Grade)
+	assert.Equal(s.T(), scoring.DecisionApprove, result.Decision)
+	assert.Equal(s.T(), "Pays on time", result.Contributions[0].Reason)
+	assert.InDelta(s.T(), 1.0, *result.Contributions[0].Value, 0.0001)
+
+	// No history scores the missing band and is referred
+	result = s.scorecard.Score(scoring.Applicant{})
+	assert.Equal(s.T(), 510, result.Score)
+	assert.Equal(s.T(), "B", result.Grade)
+	assert.Equal(s.T(), scoring.DecisionRefer, result.Decision)
+	assert.Nil(s.T(), result.Contributions[0].Value)
+
+	// Late payer falls below the decline threshold
+	result = s.scorecard.Score(scoring.Applicant{InstallmentsDue: 10, InstallmentsOnTime: 5})
+	assert.Equal(s.T(), 420, result.Score)
+	assert.Equal(s.T(), "C", result.Grade)
+	assert.Equal(s.T(), scoring.DecisionDecline, result.Decision)
+
+	// A knockout declines whatever the score
+	result = s.scorecard.Score(scoring.Applicant{InstallmentsDue: 10, InstallmentsOnTime: 10, PriorDefaults: 1})
+	assert.Equal(s.T(), 570, result.Score)
+	assert.Equal(s.T(), scoring.DecisionDecline, result.Decision)
+	assert.True(s.T(), result.Contributions[1].Knockout)
+}
+
+// TestLoadJSONFile tests that a JSON scorecard file is loaded by its extension
+func (s *ScorecardTestSuite) TestLoadJSONFile() {
+	path := filepath.Join(s.T().TempDir(), "scorecard.json")
+	content := `{"version": "json-1", "base_score": 600, "approve_from": 600, "decline_below": 500,
+		"grades": [{"name": "A", "min_score": 0}],
+		"rules": [{"name": "Tenure", "factor": "tenure_days", "bands": [{"min": 365, "points": 20, "reason": "Long-standing customer"}]}]}`
+	s.Require().NoError(os.WriteFile(path, []byte(content), 0o600))
+
+	scorecard, err := scoring.Load(path)
+
+	s.Require().NoError(err)
+	assert.Equal(s.T(), "json-1", scorecard.Version)
+	assert.Equal(s.T(), 620, scorecard.Score(scoring.Applicant{TenureDays: 400}).Score)
+	assert.Equal(s.T(), 600, scorecard.Score(scoring.Applicant{TenureDays: 10}).Score)
+}
+
+// TestInvalidScorecards tests that typos and unknown factors are rejected rather than ignored
+func (s *ScorecardTestSuite) TestInvalidScorecards() {
+	_, err := scoring.Parse([]byte(`{"version": "x", "grades": [{"name": "A"}], "rules": [{"name": "r", "factor": "shoe_size", "bands": [{"points": 1}]}]}`), "json")
+	assert.ErrorIs(s.T(), err, scoring.ErrInvalidScorecard)
+
+	_, err = scoring.Parse([]byte("version: x\nbase_scor: 10\ngrades:\n  - name: A\n"), "yaml")
+	assert.ErrorIs(s.T(), err, scoring.ErrInvalidScorecard)
+
+	_, err = scoring.Parse([]byte("version: x\napprove_from: 500\ndecline_below: 600\ngrades:\n  - name: A\n"), "yaml")
+	assert.ErrorIs(s.T(), err, scoring.ErrInvalidScorecard)
+
+	_, err = scoring.Parse([]byte("version = 'x'"), "toml")
+	assert.ErrorIs(s.T(), err, scoring.ErrInvalidScorecard)
+}
+
+// TestDefaultScorecard tests that the built-in scorecard loads and approves a first-time borrower
+func (s *ScorecardTestSuite) TestDefaultScorecard() {
+	scorecard, err := scoring.Default()
+
+	s.Require().NoError(err)
+	assert.Equal(s.T(), scoring.DecisionApprove, scorecard.Score(scoring.Applicant{RequestedAmount: 5000000, TermWeeks: 50}).Decision)
+}
+
+func TestScorecardSuite(t *testing.T) {
+	suite.Run(t, new(ScorecardTestSuite))
+}
@@ -1,10 +1,16 @@
 package services_test
 
 import (
+	"context"
 	"errors"
+	"loan-billing-system/internal/audit"
+	"loan-billing-system/internal/auth"
 	"loan-billing-system/internal/models"
 	"loan-billing-system/internal/repositories"
+	"loan-billing-system/internal/scoring"
 	"loan-billing-system/internal/services"
+	"loan-billing-system/internal/tenant"
+	"strings"
 	"testing"
 	"time"
 
@@ -17,10 +23,27 @@ import (
 // Mock repositories
 type MockRepoManager struct {
 	mock.Mock
-	borrowerRepo *MockBorrowerRepo
-	loanRepo     *MockLoanRepo
-	scheduleRepo *MockScheduleRepo
-	paymentRepo  *MockPaymentRepo
+	borrowerRepo   *MockBorrowerRepo
+	loanRepo       *MockLoanRepo
+	scheduleRepo   *MockScheduleRepo
+	paymentRepo    *MockPaymentRepo
+	restructRepo   *MockRestructureRepo
+	holidayRepo    *MockHolidayRepo
+	recoveryRepo   *MockRecoveryRepo
+	productRepo    *MockProductRepo
+	changeRepo     *MockStatusChangeRepo
+	chargeRepo     *MockChargeOffRepo
+	feeRepo        *MockFeeRepo
+	partyRepo      *MockLoanPartyRepo
+	groupRepo      *MockGroupRepo
+	collateralRepo *MockCollateralRepo
+	assessmentRepo *MockAssessmentRepo
+	kycRepo        *MockKYCRepo
+	mergeRepo      *MockMergeRepo
+	erasureRepo    *MockErasureRepo
+	auditRepo      *MockAuditRepo
+	apiKeyRepo     *MockAPIKeyRepo
+	tenantRepo     *MockTenantRepo
 }
 
 func (m *MockRepoManager) Borrowers() repositories.BorrowerRepository {
@@ -39,6 +62,78 @@ func (m *MockRepoManager) Payments() repositories.PaymentRepository {
 	return m.paymentRepo
 }
 
+func (m *MockRepoManager) Restructures() repositories.RestructureRepository {
+	return m.restructRepo
+}
+
+func (m *MockRepoManager) Holidays() repositories.HolidayRepository {
+	return m.holidayRepo
+}
+
+func (m *MockRepoManager) Recoveries() repositories.RecoveryRepository {
+	return m.recoveryRepo
+}
+
+func (m *MockRepoManager) Products() repositories.ProductRepository {
+	return m.productRepo
+}
+
+func (m *MockRepoManager) StatusChanges() repositories.StatusChangeRepository {
+	return m.changeRepo
+}
+
+func (m *MockRepoManager) ChargeOffs() repositories.ChargeOffRepository {
+	return m.chargeRepo
+}
+
+func (m *MockRepoManager) Fees() repositories.FeeRepository {
+	return m.feeRepo
+}
+
+func (m *MockRepoManager) Parties() repositories.LoanPartyRepository {
+	return m.partyRepo
+}
+
+func (m *MockRepoManager) Groups() repositories.GroupRepository {
+	return m.groupRepo
+}
+
+func (m *MockRepoManager) Collateral() repositories.CollateralRepository {
+	return m.collateralRepo
+}
+
+func (m *MockRepoManager) Assessments() repositories.AssessmentRepository {
+	return m.assessmentRepo
+}
+
+func (m *MockRepoManager) KYC() repositories.KYCRepository {
+	return m.kycRepo
+}
+
+func (m *MockRepoManager) Merges() repositories.MergeRepository {
+	return m.mergeRepo
+}
+
+func (m *MockRepoManager) Erasures() repositories.ErasureRepository {
+	return m.erasureRepo
+}
+
+func (m *MockRepoManager) Audit() repositories.AuditRepository {
+	return m.auditRepo
+}
+
+func (m *MockRepoManager) APIKeys() repositories.APIKeyRepository {
+	return m.apiKeyRepo
+}
+
+func (m *MockRepoManager) Tenants() repositories.TenantRepository {
+	return m.tenantRepo
+}
+
+func (m *MockRepoManager) WithContext(ctx context.Context) repositories.RepositoryManager {
+	return m
+}
+
 func (m *MockRepoManager) WithTransaction(fn func(repo repositories.RepositoryManager) error) error {
 	args := m.Called(fn)
 	if args.Get(0) == nil {
@@ -60,19 +155,14 @@ func (m *MockBorrowerRepo) GetByID(id uuid.UUID) (*models.Borrower, error) {
 	return args.Get(0).(*models.Borrower), args.Error(1)
 }
 
-func (m *MockBorrowerRepo) GetAll() ([]models.Borrower, error) {
-	args := m.Called()
-	return args.Get(0).([]models.Borrower), args.Error(1)
-}
-
-func (m *MockBorrowerRepo) GetDelinquent() ([]models.Borrower, error) {
-	args := m.Called()
-	return args.Get(0).([]models.Borrower), args.Error(1)
+func (m *MockBorrowerRepo) List(filter repositories.BorrowerFilter, page repositories.PageRequest) (repositories.Page[models.Borrower], error) {
+	args := m.Called(filter, page)
+	return args.Get(0).(repositories.Page[models.Borrower]), args.Error(1)
 }
 
-func (m *MockBorrowerRepo) Create(name, contactInfo string) (*models.Borrower, error) {
-	args := m.Called(name, contactInfo)
-	return args.Get(0).(*models.Borrower), args.Error(1)
+func (m *MockBorrowerRepo) Create(borrower *models.Borrower) error {
+	args := m.Called(borrower)
+	return args.Error(0)
 }
 
 func (m *MockBorrowerRepo) Update(borrower *models.Borrower) error {
@@ -85,6 +175,21 @@ func (m *MockBorrowerRepo) UpdateDelinquencyStatus(id uuid.UUID, isDelinquent bo
 	return args.Error(0)
 }
 
+func (m *MockBorrowerRepo) UpdateCreditLimits(id uuid.UUID, creditLimit *int64, maxConcurrentLoans *uint) error {
+	args := m.Called(id, creditLimit, maxConcurrentLoans)
+	return args.Error(0)
+}
+
+func (m *MockBorrowerRepo) FindMatches(match repositories.BorrowerMatch) ([]models.Borrower, error) {
+	args := m.Called(match)
+	return args.Get(0).([]models.Borrower), args.Error(1)
+}
+
+func (m *MockBorrowerRepo) Delete(id uuid.UUID) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
 type MockLoanRepo struct {
 	mock.Mock
 }
@@ -102,11 +207,26 @@ func (m *MockLoanRepo) GetByBorrowerID(borrowerID uuid.UUID) ([]models.Loan, err
 	return args.Get(0).([]models.Loan), args.Error(1)
 }
 
+func (m *MockLoanRepo) GetByParty(borrowerID uuid.UUID) ([]models.Loan, error) {
+	args := m.Called(borrowerID)
+	return args.Get(0).([]models.Loan), args.Error(1)
+}
+
+func (m *MockLoanRepo) GetByGroupID(groupID uuid.UUID) ([]models.Loan, error) {
+	args := m.Called(groupID)
+	return args.Get(0).([]models.Loan), args.Error(1)
+}
+
 func (m *MockLoanRepo) GetAllActive() ([]models.Loan, error) {
 	args := m.Called()
 	return args.Get(0).([]models.Loan), args.Error(1)
 }
 
+func (m *MockLoanRepo) List(filter repositories.LoanFilter, page repositories.PageRequest) (repositories.Page[models.Loan], error) {
+	args := m.Called(filter, page)
+	return args.Get(0).(repositories.Page[models.Loan]), args.Error(1)
+}
+
 func (m *MockLoanRepo) Create(loan *models.Loan) error {
 	args := m.Called(loan)
 	return args.Error(0)
@@ -127,6 +247,16 @@ func (m *MockLoanRepo) UpdateBalance(id uuid.UUID, balance int64) error {
 	return args.Error(0)
 }
 
+func (m *MockLoanRepo) UpdateDelinquency(id uuid.UUID, isDelinquent bool, daysPastDue uint) error {
+	args := m.Called(id, isDelinquent, daysPastDue)
+	return args.Error(0)
+}
+
+func (m *MockLoanRepo) UpdateCurePaymentsRemaining(id uuid.UUID, remaining uint) error {
+	args := m.Called(id, remaining)
+	return args.Error(0)
+}
+
 func (m *MockLoanRepo) UpdateLastPaymentDate(id uuid.UUID, date time.Time) error {
 	args := m.Called(id, date)
 	return args.Error(0)
@@ -137,6 +267,11 @@ func (m *MockLoanRepo) GetPotentialDelinquent() ([]models.Loan, error) {
 	return args.Get(0).([]models.Loan), args.Error(1)
 }
 
+func (m *MockLoanRepo) GetEscalationCandidates(productCode string, statuses []string, minDaysPastDue uint) ([]models.Loan, error) {
+	args := m.Called(productCode, statuses, minDaysPastDue)
+	return args.Get(0).([]models.Loan), args.Error(1)
+}
+
 type MockScheduleRepo struct {
 	mock.Mock
 }
@@ -159,6 +294,21 @@ func (m *MockScheduleRepo) GetUnpaidByLoanID(loanID uuid.UUID) ([]models.Schedul
 	return args.Get(0).([]models.Schedule), args.Error(1)
 }
 
+func (m *MockScheduleRepo) GetNextUnpaidByLoanIDs(loanIDs []uuid.UUID) (map[uuid.UUID]models.Schedule, error) {
+	args := m.Called(loanIDs)
+	return args.Get(0).(map[uuid.UUID]models.Schedule), args.Error(1)
+}
+
+func (m *MockScheduleRepo) GetByLoanIDAndVersion(loanID uuid.UUID, version uint) ([]models.Schedule, error) {
+	args := m.Called(loanID, version)
+	return args.Get(0).([]models.Schedule), args.Error(1)
+}
+
+func (m *MockScheduleRepo) CancelUnpaidByLoanID(loanID uuid.UUID) error {
+	args := m.Called(loanID)
+	return args.Error(0)
+}
+
 func (m *MockScheduleRepo) Create(schedule *models.Schedule) error {
 	args := m.Called(schedule)
 	return args.Error(0)
@@ -169,6 +319,11 @@ func (m *MockScheduleRepo) CreateBatch(schedules []models.Schedule) error {
 	return args.Error(0)
 }
 
+func (m *MockScheduleRepo) UpdateBatch(schedules []models.Schedule) error {
+	args := m.Called(schedules)
+	return args.Error(0)
+}
+
 func (m *MockScheduleRepo) UpdatePaidStatus(id uuid.UUID, paid bool) error {
 	args := m.Called(id, paid)
 	return args.Error(0)
@@ -201,183 +356,2171 @@ func (m *MockPaymentRepo) Create(payment *models.Payment) error {
 	return args.Error(0)
 }
 
-// LoanServiceTestSuite defines the test suite for loan service
-type LoanServiceTestSuite struct {
-	suite.Suite
-	service      *services.LoanService
-	repoManager  *MockRepoManager
-	borrowerRepo *MockBorrowerRepo
-	loanRepo     *MockLoanRepo
-	scheduleRepo *MockScheduleRepo
-	paymentRepo  *MockPaymentRepo
+func (m *MockPaymentRepo) Update(payment *models.Payment) error {
+	args := m.Called(payment)
+	return args.Error(0)
 }
 
-// SetupTest prepares the test suite before each test
-func (s *LoanServiceTestSuite) SetupTest() {
-	s.borrowerRepo = new(MockBorrowerRepo)
-	s.loanRepo = new(MockLoanRepo)
-	s.scheduleRepo = new(MockScheduleRepo)
-	s.paymentRepo = new(MockPaymentRepo)
-
-	s.repoManager = &MockRepoManager{
-		borrowerRepo: s.borrowerRepo,
-		loanRepo:     s.loanRepo,
-		scheduleRepo: s.scheduleRepo,
-		paymentRepo:  s.paymentRepo,
-	}
-
-	s.service = services.NewLoanService(s.repoManager)
+func (m *MockPaymentRepo) SumAllocations(from, to time.Time) (repositories.PaymentTotals, error) {
+	args := m.Called(from, to)
+	return args.Get(0).(repositories.PaymentTotals), args.Error(1)
 }
 
-// TestCreateLoan tests the loan creation functionality
-func (s *LoanServiceTestSuite) TestCreateLoan() {
-	// Prepare test data
-	borrowerID := uuid.New()
-	borrower := &models.Borrower{
-		ID:           borrowerID,
-		Name:         "Test Borrower",
-		ContactInfo:  "test@example.com",
-		IsDelinquent: false,
-	}
+type MockRestructureRepo struct {
+	mock.Mock
+}
 
-	// Setup expectations
-	s.borrowerRepo.On("GetByID", borrowerID).Return(borrower, nil)
-	s.loanRepo.On("Create", mock.AnythingOfType("*models.Loan")).Run(func(args mock.Arguments) {
-		loan := args.Get(0).(*models.Loan)
-		loan.ID = uuid.New() // Simulate database assigning an ID
-	}).Return(nil)
-	s.scheduleRepo.On("CreateBatch", mock.AnythingOfType("[]models.Schedule")).Return(nil)
+func (m *MockRestructureRepo) GetByLoanID(loanID uuid.UUID) ([]models.LoanRestructure, error) {
+	args := m.Called(loanID)
+	return args.Get(0).([]models.LoanRestructure), args.Error(1)
+}
 
-	// Call the service
-	loan, err := s.service.CreateLoan(borrowerID, 5000000, 10.0, 50)
+func (m *MockRestructureRepo) Create(restructure *models.LoanRestructure) error {
+	args := m.Called(restructure)
+	return args.Error(0)
+}
 
-	// Assert results
-	assert.NoError(s.T(), err)
-	assert.NotNil(s.T(), loan)
-	assert.Equal(s.T(), borrowerID, loan.BorrowerID)
-	assert.Equal(s.T(), int64(5000000), loan.Amount)
-	assert.Equal(s.T(), 10.0, loan.InterestRate)
-	assert.Equal(s.T(), uint(50), loan.TermWeeks)
-	assert.Equal(s.T(), "active", loan.Status)
-	assert.Equal(s.T(), int64(5480769), loan.CurrentBalance)
+type MockHolidayRepo struct {
+	mock.Mock
+}
 
-	// Verify mock expectations
-	s.borrowerRepo.AssertExpectations(s.T())
-	s.loanRepo.AssertExpectations(s.T())
-	s.scheduleRepo.AssertExpectations(s.T())
+func (m *MockHolidayRepo) GetByLoanID(loanID uuid.UUID) ([]models.PaymentHoliday, error) {
+	args := m.Called(loanID)
+	return args.Get(0).([]models.PaymentHoliday), args.Error(1)
 }
 
-// TestCreateLoanBorrowerNotFound tests loan creation with non-existent borrower
-func (s *LoanServiceTestSuite) TestCreateLoanBorrowerNotFound() {
-	// Prepare test data
-	borrowerID := uuid.New()
+func (m *MockHolidayRepo) Create(holiday *models.PaymentHoliday) error {
+	args := m.Called(holiday)
+	return args.Error(0)
+}
 
-	// Setup expectations
-	s.borrowerRepo.On("GetByID", borrowerID).Return(nil, errors.New("borrower not found"))
+type MockRecoveryRepo struct {
+	mock.Mock
+}
 
-	// Call the service
-	loan, err := s.service.CreateLoan(borrowerID, 5000000, 10.0, 50)
+func (m *MockRecoveryRepo) GetByLoanID(loanID uuid.UUID) ([]models.Recovery, error) {
+	args := m.Called(loanID)
+	return args.Get(0).([]models.Recovery), args.Error(1)
+}
 
-	// Assert results
-	assert.Error(s.T(), err)
-	assert.Nil(s.T(), loan)
-	assert.Contains(s.T(), err.Error(), "borrower not found")
+func (m *MockRecoveryRepo) Create(recovery *models.Recovery) error {
+	args := m.Called(recovery)
+	return args.Error(0)
+}
 
-	// Verify mock expectations
-	s.borrowerRepo.AssertExpectations(s.T())
+type MockProductRepo struct {
+	mock.Mock
 }
 
-// TestIsDelinquent tests the delinquency checking
-func (s *LoanServiceTestSuite) TestIsDelinquent() {
-	// Prepare test data
-	loanID := uuid.New()
-	borrowerID := uuid.New()
-	loan := &models.Loan{
-		ID:         loanID,
-		BorrowerID: borrowerID,
-		Amount:     5000000,
-		Status:     "active",
+func (m *MockProductRepo) GetByCode(code string) (*models.LoanProduct, error) {
+	args := m.Called(code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
 	}
+	return args.Get(0).(*models.LoanProduct), args.Error(1)
+}
 
-	// Create schedules with 2 missed payments
-	now := time.Now()
-	schedules := []models.Schedule{
-		{ID: uuid.New(), LoanID: loanID, WeekNumber: 1, DueDate: now.AddDate(0, 0, -21), Amount: 109615, Paid: true},
-		{ID: uuid.New(), LoanID: loanID, WeekNumber: 2, DueDate: now.AddDate(0, 0, -14), Amount: 109615, Paid: false}, // Unpaid and past due
-		{ID: uuid.New(), LoanID: loanID, WeekNumber: 3, DueDate: now.AddDate(0, 0, -7), Amount: 109615, Paid: false},  // Unpaid and past due
-		{ID: uuid.New(), LoanID: loanID, WeekNumber: 4, DueDate: now.AddDate(0, 0, 0), Amount: 109615, Paid: false},   // Due today (not late yet)
-	}
+func (m *MockProductRepo) List() ([]models.LoanProduct, error) {
+	args := m.Called()
+	return args.Get(0).([]models.LoanProduct), args.Error(1)
+}
 
-	// Setup expectations
-	s.loanRepo.On("GetByID", loanID).Return(loan, nil)
-	s.scheduleRepo.On("GetByLoanID", loanID).Return(schedules, nil)
-	s.borrowerRepo.On("UpdateDelinquencyStatus", borrowerID, true).Return(nil)
+func (m *MockProductRepo) Create(product *models.LoanProduct) error {
+	args := m.Called(product)
+	return args.Error(0)
+}
 
-	// Call the service
-	isDelinquent, err := s.service.IsDelinquent(loanID)
+func (m *MockProductRepo) Update(product *models.LoanProduct) error {
+	args := m.Called(product)
+	return args.Error(0)
+}
 
-	// Assert results
-	assert.NoError(s.T(), err)
-	assert.True(s.T(), isDelinquent)
+type MockStatusChangeRepo struct {
+	mock.Mock
+}
 
-	// Verify mock expectations
-	s.loanRepo.AssertExpectations(s.T())
-	s.scheduleRepo.AssertExpectations(s.T())
-	s.borrowerRepo.AssertExpectations(s.T())
+func (m *MockStatusChangeRepo) GetByLoanID(loanID uuid.UUID) ([]models.LoanStatusChange, error) {
+	args := m.Called(loanID)
+	return args.Get(0).([]models.LoanStatusChange), args.Error(1)
 }
 
-// TestMakePayment tests the payment processing
-func (s *LoanServiceTestSuite) TestMakePayment() {
-	// Prepare test data
-	loanID := uuid.New()
-	borrowerID := uuid.New()
-	scheduleID := uuid.New()
+func (m *MockStatusChangeRepo) Create(change *models.LoanStatusChange) error {
+	args := m.Called(change)
+	return args.Error(0)
+}
 
-	loan := &models.Loan{
-		ID:             loanID,
-		BorrowerID:     borrowerID,
-		Amount:         5000000,
-		Status:         "active",
-		CurrentBalance: 5480769,
-	}
+type MockChargeOffRepo struct {
+	mock.Mock
+}
 
-	unpaidSchedule := &models.Schedule{
-		ID:         scheduleID,
-		LoanID:     loanID,
-		WeekNumber: 1,
-		DueDate:    time.Now().AddDate(0, 0, -7),
-		Amount:     109615,
-		Paid:       false,
+func (m *MockChargeOffRepo) GetByID(id uuid.UUID) (*models.ChargeOffProposal, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
 	}
+	return args.Get(0).(*models.ChargeOffProposal), args.Error(1)
+}
 
-	// Setup expectations for transaction
-	s.repoManager.On("WithTransaction", mock.AnythingOfType("func(repositories.RepositoryManager) error")).Return(nil)
+func (m *MockChargeOffRepo) List(status string) ([]models.ChargeOffProposal, error) {
+	args := m.Called(status)
+	return args.Get(0).([]models.ChargeOffProposal), args.Error(1)
+}
 
-	// Setup expectations inside the transaction
-	s.loanRepo.On("GetByID", loanID).Return(loan, nil)
-	s.scheduleRepo.On("GetUnpaidByLoanID", loanID).Return([]models.Schedule{*unpaidSchedule}, nil)
-	s.paymentRepo.On("Create", mock.AnythingOfType("*models.Payment")).Return(nil)
-	s.scheduleRepo.On("UpdatePaidStatus", scheduleID, true).Return(nil)
-	s.loanRepo.On("UpdateBalance", loanID, int64(5371154)).Return(nil)
-	s.loanRepo.On("UpdateLastPaymentDate", loanID, mock.AnythingOfType("time.Time")).Return(nil)
-	s.scheduleRepo.On("CountUnpaidByLoanID", loanID).Return(int64(5), nil)
-	s.scheduleRepo.On("GetByLoanID", loanID).Return([]models.Schedule{*unpaidSchedule}, nil)
-	s.borrowerRepo.On("UpdateDelinquencyStatus", borrowerID, false).Return(nil)
+func (m *MockChargeOffRepo) ExistsForLoan(loanID uuid.UUID) (bool, error) {
+	args := m.Called(loanID)
+	return args.Bool(0), args.Error(1)
+}
 
-	// Call the service
-	err := s.service.MakePayment(loanID, 109615)
+func (m *MockChargeOffRepo) Create(proposal *models.ChargeOffProposal) error {
+	args := m.Called(proposal)
+	return args.Error(0)
+}
+
+func (m *MockChargeOffRepo) Update(proposal *models.ChargeOffProposal) error {
+	args := m.Called(proposal)
+	return args.Error(0)
+}
+
+type MockFeeRepo struct {
+	mock.Mock
+}
+
+func (m *MockFeeRepo) GetDefinitionsByProductCode(productCode string) ([]models.FeeDefinition, error) {
+	args := m.Called(productCode)
+	return args.Get(0).([]models.FeeDefinition), args.Error(1)
+}
+
+func (m *MockFeeRepo) CreateDefinition(definition *models.FeeDefinition) error {
+	args := m.Called(definition)
+	return args.Error(0)
+}
+
+func (m *MockFeeRepo) DeleteDefinition(productCode string, id uuid.UUID) error {
+	args := m.Called(productCode, id)
+	return args.Error(0)
+}
+
+func (m *MockFeeRepo) GetByLoanID(loanID uuid.UUID) ([]models.LoanFee, error) {
+	args := m.Called(loanID)
+	return args.Get(0).([]models.LoanFee), args.Error(1)
+}
+
+func (m *MockFeeRepo) CreateBatch(fees []models.LoanFee) error {
+	args := m.Called(fees)
+	return args.Error(0)
+}
+
+func (m *MockFeeRepo) SumUpfront(from, to time.Time) (int64, error) {
+	args := m.Called(from, to)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+type MockLoanPartyRepo struct {
+	mock.Mock
+}
+
+func (m *MockLoanPartyRepo) GetByLoanID(loanID uuid.UUID) ([]models.LoanParty, error) {
+	args := m.Called(loanID)
+	return args.Get(0).([]models.LoanParty), args.Error(1)
+}
+
+func (m *MockLoanPartyRepo) GetByBorrowerID(borrowerID uuid.UUID) ([]models.LoanParty, error) {
+	args := m.Called(borrowerID)
+	return args.Get(0).([]models.LoanParty), args.Error(1)
+}
+
+func (m *MockLoanPartyRepo) Create(party *models.LoanParty) error {
+	args := m.Called(party)
+	return args.Error(0)
+}
+
+func (m *MockLoanPartyRepo) CreateBatch(parties []models.LoanParty) error {
+	args := m.Called(parties)
+	return args.Error(0)
+}
+
+func (m *MockLoanPartyRepo) Delete(loanID, borrowerID uuid.UUID) error {
+	args := m.Called(loanID, borrowerID)
+	return args.Error(0)
+}
+
+type MockGroupRepo struct {
+	mock.Mock
+}
+
+func (m *MockGroupRepo) GetByID(id uuid.UUID) (*models.LoanGroup, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.LoanGroup), args.Error(1)
+}
+
+func (m *MockGroupRepo) Create(group *models.LoanGroup) error {
+	args := m.Called(group)
+	return args.Error(0)
+}
+
+func (m *MockGroupRepo) Update(group *models.LoanGroup) error {
+	args := m.Called(group)
+	return args.Error(0)
+}
+
+func (m *MockGroupRepo) AddMember(member *models.LoanGroupMember) error {
+	args := m.Called(member)
+	return args.Error(0)
+}
+
+func (m *MockGroupRepo) RemoveMember(groupID, borrowerID uuid.UUID) error {
+	args := m.Called(groupID, borrowerID)
+	return args.Error(0)
+}
+
+func (m *MockGroupRepo) CreatePayment(payment *models.GroupPayment) error {
+	args := m.Called(payment)
+	return args.Error(0)
+}
+
+type MockCollateralRepo struct {
+	mock.Mock
+}
+
+func (m *MockCollateralRepo) GetByID(id uuid.UUID) (*models.Collateral, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Collateral), args.Error(1)
+}
+
+func (m *MockCollateralRepo) GetByLoanID(loanID uuid.UUID) ([]models.Collateral, error) {
+	args := m.Called(loanID)
+	return args.Get(0).([]models.Collateral), args.Error(1)
+}
+
+func (m *MockCollateralRepo) Create(collateral *models.Collateral) error {
+	args := m.Called(collateral)
+	return args.Error(0)
+}
+
+func (m *MockCollateralRepo) Update(collateral *models.Collateral) error {
+	args := m.Called(collateral)
+	return args.Error(0)
+}
+
+func (m *MockCollateralRepo) CreateValuation(valuation *models.CollateralValuation) error {
+	args := m.Called(valuation)
+	return args.Error(0)
+}
+
+type MockAssessmentRepo struct {
+	mock.Mock
+}
+
+func (m *MockAssessmentRepo) GetByLoanID(loanID uuid.UUID) (*models.CreditAssessment, error) {
+	args := m.Called(loanID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CreditAssessment), args.Error(1)
+}
+
+func (m *MockAssessmentRepo) Create(assessment *models.CreditAssessment) error {
+	args := m.Called(assessment)
+	return args.Error(0)
+}
+
+type MockKYCRepo struct {
+	mock.Mock
+}
+
+func (m *MockKYCRepo) CreateAddress(address *models.BorrowerAddress) error {
+	args := m.Called(address)
+	return args.Error(0)
+}
+
+func (m *MockKYCRepo) DeleteAddress(borrowerID, id uuid.UUID) error {
+	args := m.Called(borrowerID, id)
+	return args.Error(0)
+}
+
+func (m *MockKYCRepo) GetDocuments(borrowerID uuid.UUID) ([]models.BorrowerDocument, error) {
+	args := m.Called(borrowerID)
+	return args.Get(0).([]models.BorrowerDocument), args.Error(1)
+}
+
+func (m *MockKYCRepo) CreateDocument(document *models.BorrowerDocument) error {
+	args := m.Called(document)
+	return args.Error(0)
+}
+
+type MockMergeRepo struct {
+	mock.Mock
+}
+
+func (m *MockMergeRepo) Create(merge *models.BorrowerMerge) error {
+	args := m.Called(merge)
+	return args.Error(0)
+}
+
+func (m *MockMergeRepo) GetByTargetID(targetID uuid.UUID) ([]models.BorrowerMerge, error) {
+	args := m.Called(targetID)
+	return args.Get(0).([]models.BorrowerMerge), args.Error(1)
+}
+
+func (m *MockMergeRepo) Reassign(sourceID, targetID uuid.UUID) (repositories.MergeCounts, error) {
+	args := m.Called(sourceID, targetID)
+	return args.Get(0).(repositories.MergeCounts), args.Error(1)
+}
+
+type MockErasureRepo struct {
+	mock.Mock
+}
+
+func (m *MockErasureRepo) FindCandidates(cutoff time.Time, requestedOnly bool) ([]models.Borrower, error) {
+	args := m.Called(cutoff, requestedOnly)
+	return args.Get(0).([]models.Borrower), args.Error(1)
+}
+
+func (m *MockErasureRepo) Anonymize(borrowerID uuid.UUID, at time.Time) error {
+	args := m.Called(borrowerID, at)
+	return args.Error(0)
+}
+
+type MockAuditRepo struct {
+	mock.Mock
+}
+
+func (m *MockAuditRepo) List(filter repositories.AuditFilter, page repositories.PageRequest) (repositories.Page[models.AuditEntry], error) {
+	args := m.Called(filter, page)
+	return args.Get(0).(repositories.Page[models.AuditEntry]), args.Error(1)
+}
+
+func (m *MockAuditRepo) GetChain(afterSequence int64, limit int) ([]models.AuditEntry, error) {
+	args := m.Called(afterSequence, limit)
+	return args.Get(0).([]models.AuditEntry), args.Error(1)
+}
+
+type MockAPIKeyRepo struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyRepo) Create(key *models.APIKey) error {
+	args := m.Called(key)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepo) GetByID(id uuid.UUID) (*models.APIKey, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepo) GetByPrefix(prefix string) (*models.APIKey, error) {
+	args := m.Called(prefix)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepo) List() ([]models.APIKey, error) {
+	args := m.Called()
+	return args.Get(0).([]models.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepo) Revoke(id uuid.UUID, revokedBy string, at time.Time) error {
+	args := m.Called(id, revokedBy, at)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepo) TouchLastUsed(id uuid.UUID, at time.Time) error {
+	args := m.Called(id, at)
+	return args.Error(0)
+}
+
+type MockTenantRepo struct {
+	mock.Mock
+}
+
+func (m *MockTenantRepo) GetByID(id string) (*models.Tenant, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Tenant), args.Error(1)
+}
+
+func (m *MockTenantRepo) List() ([]models.Tenant, error) {
+	args := m.Called()
+	return args.Get(0).([]models.Tenant), args.Error(1)
+}
+
+func (m *MockTenantRepo) Update(tenant *models.Tenant) error {
+	args := m.Called(tenant)
+	return args.Error(0)
+}
+
+func (m *MockTenantRepo) GetHolidays(tenantID string) ([]models.CalendarHoliday, error) {
+	args := m.Called(tenantID)
+	return args.Get(0).([]models.CalendarHoliday), args.Error(1)
+}
+
+func (m *MockTenantRepo) CreateHoliday(holiday *models.CalendarHoliday) error {
+	args := m.Called(holiday)
+	return args.Error(0)
+}
+
+func (m *MockTenantRepo) DeleteHoliday(id uuid.UUID) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+// LoanServiceTestSuite defines the test suite for loan service
+type LoanServiceTestSuite struct {
+	suite.Suite
+	service        *services.LoanService
+	repoManager    *MockRepoManager
+	borrowerRepo   *MockBorrowerRepo
+	loanRepo       *MockLoanRepo
+	scheduleRepo   *MockScheduleRepo
+	paymentRepo    *MockPaymentRepo
+	restructRepo   *MockRestructureRepo
+	holidayRepo    *MockHolidayRepo
+	recoveryRepo   *MockRecoveryRepo
+	productRepo    *MockProductRepo
+	changeRepo     *MockStatusChangeRepo
+	chargeRepo     *MockChargeOffRepo
+	feeRepo        *MockFeeRepo
+	partyRepo      *MockLoanPartyRepo
+	groupRepo      *MockGroupRepo
+	collateralRepo *MockCollateralRepo
+	assessmentRepo *MockAssessmentRepo
+	kycRepo        *MockKYCRepo
+	mergeRepo      *MockMergeRepo
+	erasureRepo    *MockErasureRepo
+	auditRepo      *MockAuditRepo
+	apiKeyRepo     *MockAPIKeyRepo
+	tenantRepo     *MockTenantRepo
+}
+
+// SetupTest prepares the test suite before each test
+func (s *LoanServiceTestSuite) SetupTest() {
+	s.borrowerRepo = new(MockBorrowerRepo)
+	s.loanRepo = new(MockLoanRepo)
+	s.scheduleRepo = new(MockScheduleRepo)
+	s.paymentRepo = new(MockPaymentRepo)
+	s.restructRepo = new(MockRestructureRepo)
+	s.holidayRepo = new(MockHolidayRepo)
+	s.recoveryRepo = new(MockRecoveryRepo)
+	s.productRepo = new(MockProductRepo)
+	s.changeRepo = new(MockStatusChangeRepo)
+	s.chargeRepo = new(MockChargeOffRepo)
+	s.feeRepo = new(MockFeeRepo)
+	s.partyRepo = new(MockLoanPartyRepo)
+	s.groupRepo = new(MockGroupRepo)
+	s.collateralRepo = new(MockCollateralRepo)
+	s.assessmentRepo = new(MockAssessmentRepo)
+	s.kycRepo = new(MockKYCRepo)
+	s.mergeRepo = new(MockMergeRepo)
+	s.erasureRepo = new(MockErasureRepo)
+	s.auditRepo = new(MockAuditRepo)
+	s.apiKeyRepo = new(MockAPIKeyRepo)
+	s.tenantRepo = new(MockTenantRepo)
+
+	s.repoManager = &MockRepoManager{
+		borrowerRepo:   s.borrowerRepo,
+		loanRepo:       s.loanRepo,
+		scheduleRepo:   s.scheduleRepo,
+		paymentRepo:    s.paymentRepo,
+		restructRepo:   s.restructRepo,
+		holidayRepo:    s.holidayRepo,
+		recoveryRepo:   s.recoveryRepo,
+		productRepo:    s.productRepo,
+		changeRepo:     s.changeRepo,
+		chargeRepo:     s.chargeRepo,
+		feeRepo:        s.feeRepo,
+		partyRepo:      s.partyRepo,
+		groupRepo:      s.groupRepo,
+		collateralRepo: s.collateralRepo,
+		assessmentRepo: s.assessmentRepo,
+		kycRepo:        s.kycRepo,
+		mergeRepo:      s.mergeRepo,
+		erasureRepo:    s.erasureRepo,
+		auditRepo:      s.auditRepo,
+		apiKeyRepo:     s.apiKeyRepo,
+		tenantRepo:     s.tenantRepo,
+	}
+
+	s.service = services.NewLoanService(s.repoManager)
+}
+
+// TestCreateLoan tests the loan creation functionality
+func (s *LoanServiceTestSuite) TestCreateLoan() {
+	// Prepare test data
+	borrowerID := uuid.New()
+	borrower := &models.Borrower{
+		ID:           borrowerID,
+		Name:         "Test Borrower",
+		ContactInfo:  "test@example.com",
+		IsDelinquent: false,
+	}
+
+	// Setup expectations
+	s.borrowerRepo.On("GetByID", borrowerID).Return(borrower, nil)
+	s.productRepo.On("GetByCode", "standard").Return(&models.LoanProduct{Code: "standard"}, nil)
+	s.feeRepo.On("GetDefinitionsByProductCode", "standard").Return([]models.FeeDefinition{}, nil)
+	s.repoManager.On("WithTransaction", mock.AnythingOfType("func(repositories.RepositoryManager) error")).Return(nil)
+	s.loanRepo.On("Create", mock.AnythingOfType("*models.Loan")).Run(func(args mock.Arguments) {
+		loan := args.Get(0).(*models.Loan)
+		loan.ID = uuid.New() // Simulate database assigning an ID
+	}).Return(nil)
+	s.scheduleRepo.On("CreateBatch", mock.AnythingOfType("[]models.Schedule")).Return(nil)
+	s.feeRepo.On("CreateBatch", []models.LoanFee{}).Return(nil)
+	s.partyRepo.On("CreateBatch", []models.LoanParty{}).Return(nil)
+
+	// Call the service
+	loan, err := s.service.CreateLoan(borrowerID, 5000000, 10.0, 50)
+
+	// Assert results
+	assert.NoError(s.T(), err)
+	assert.NotNil(s.T(), loan)
+	assert.Equal(s.T(), borrowerID, loan.BorrowerID)
+	assert.Equal(s.T(), int64(5000000), loan.Amount)
+	assert.Equal(s.T(), 10.0, loan.InterestRate)
+	assert.Equal(s.T(), uint(50), loan.TermWeeks)
+	assert.Equal(s.T(), "active", loan.Status)
+	assert.Equal(s.T(), int64(5480769), loan.CurrentBalance)
+	assert.InDelta(s.T(), 19.04, loan.APR, 0.01)
+	assert.Greater(s.T(), loan.EffectiveRate, loan.APR)
+
+	// Verify mock expectations
+	s.borrowerRepo.AssertExpectations(s.T())
+	s.loanRepo.AssertExpectations(s.T())
+	s.scheduleRepo.AssertExpectations(s.T())
+}
+
+// TestCreateLoanBorrowerNotFound tests loan creation with non-existent borrower
+func (s *LoanServiceTestSuite) TestCreateLoanBorrowerNotFound() {
+	// Prepare test data
+	borrowerID := uuid.New()
+
+	// Setup expectations
+	s.borrowerRepo.On("GetByID", borrowerID).Return(nil, errors.New("borrower not found"))
+
+	// Call the service
+	loan, err := s.service.CreateLoan(borrowerID, 5000000, 10.0, 50)
+
+	// Assert results
+	assert.Error(s.T(), err)
+	assert.Nil(s.T(), loan)
+	assert.Contains(s.T(), err.Error(), "borrower not found")
+
+	// Verify mock expectations
+	s.borrowerRepo.AssertExpectations(s.T())
+}
+
+// TestIsDelinquent tests the delinquency checking
+func (s *LoanServiceTestSuite) TestIsDelinquent() {
+	// Prepare test data
+	loanID := uuid.New()
+	borrowerID := uuid.New()
+	loan := &models.Loan{
+		ID:         loanID,
+		BorrowerID: borrowerID,
+		Amount:     5000000,
+		Status:     "active",
+	}
+
+	// Create schedules with 2 missed payments
+	now := time.Now()
+	schedules := []models.Schedule{
+		{ID: uuid.New(), LoanID: loanID, WeekNumber: 1, DueDate: now.AddDate(0, 0, -21), Amount: 109615, Paid: true},
+		{ID: uuid.New(), LoanID: loanID, WeekNumber: 2, DueDate: now.AddDate(0, 0, -14), Amount: 109615, Paid: false}, // Unpaid and past due
+		{ID: uuid.New(), LoanID: loanID, WeekNumber: 3, DueDate: now.AddDate(0, 0, -7), Amount: 109615, Paid: false},  // Unpaid and past due
+		{ID: uuid.New(), LoanID: loanID, WeekNumber: 4, DueDate: now.AddDate(0, 0, 0), Amount: 109615, Paid: false},   // Due today (not late yet)
+	}
+
+	// Setup expectations
+	s.loanRepo.On("GetByID", loanID).Return(loan, nil)
+	s.scheduleRepo.On("GetByLoanID", loanID).Return(schedules, nil)
+	s.loanRepo.On("UpdateDelinquency", loanID, true, uint(14)).Return(nil)
+	s.borrowerRepo.On("UpdateDelinquencyStatus", borrowerID, true).Return(nil)
+
+	// Delinquency spreads to co-borrowers, but not to guarantors by default
+	coBorrowerID, guarantorID := uuid.New(), uuid.New()
+	s.partyRepo.On("GetByLoanID", loanID).Return([]models.LoanParty{
+		{LoanID: loanID, BorrowerID: coBorrowerID, Role: models.LoanPartyRoleCoBorrower},
+		{LoanID: loanID, BorrowerID: guarantorID, Role: models.LoanPartyRoleGuarantor},
+	}, nil)
+	s.borrowerRepo.On("UpdateDelinquencyStatus", coBorrowerID, true).Return(nil)
+
+	// Call the service
+	isDelinquent, err := s.service.IsDelinquent(loanID)
+
+	// Assert results
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), isDelinquent)
+
+	// Verify mock expectations
+	s.loanRepo.AssertExpectations(s.T())
+	s.scheduleRepo.AssertExpectations(s.T())
+	s.borrowerRepo.AssertExpectations(s.T())
+	s.borrowerRepo.AssertNotCalled(s.T(), "UpdateDelinquencyStatus", guarantorID, mock.Anything)
+}
+
+// TestMakePayment tests the payment processing
+func (s *LoanServiceTestSuite) TestMakePayment() {
+	// Prepare test data
+	loanID := uuid.New()
+	borrowerID := uuid.New()
+	scheduleID := uuid.New()
+
+	loan := &models.Loan{
+		ID:             loanID,
+		BorrowerID:     borrowerID,
+		Amount:         5000000,
+		Status:         "active",
+		CurrentBalance: 5480769,
+	}
+
+	unpaidSchedule := &models.Schedule{
+		ID:         scheduleID,
+		LoanID:     loanID,
+		WeekNumber: 1,
+		DueDate:    time.Now().AddDate(0, 0, -7),
+		Amount:     109615,
+		Paid:       false,
+	}
+
+	// Setup expectations for transaction
+	s.repoManager.On("WithTransaction", mock.AnythingOfType("func(repositories.RepositoryManager) error")).Return(nil)
+
+	// Setup expectations inside the transaction
+	s.loanRepo.On("GetByID", loanID).Return(loan, nil)
+	s.scheduleRepo.On("GetUnpaidByLoanID", loanID).Return([]models.Schedule{*unpaidSchedule}, nil)
+	s.paymentRepo.On("Create", mock.AnythingOfType("*models.Payment")).Return(nil)
+	s.scheduleRepo.On("UpdatePaidStatus", scheduleID, true).Return(nil)
+	s.loanRepo.On("UpdateBalance", loanID, int64(5371154)).Return(nil)
+	s.loanRepo.On("UpdateLastPaymentDate", loanID, mock.AnythingOfType("time.Time")).Return(nil)
+	s.scheduleRepo.On("CountUnpaidByLoanID", loanID).Return(int64(5), nil)
+	s.scheduleRepo.On("GetByLoanID", loanID).Return([]models.Schedule{*unpaidSchedule}, nil)
+	s.loanRepo.On("UpdateDelinquency", loanID, false, uint(7)).Return(nil)
+	s.loanRepo.On("GetByParty", borrowerID).Return([]models.Loan{*loan}, nil)
+	s.borrowerRepo.On("UpdateDelinquencyStatus", borrowerID, false).Return(nil)
+	s.partyRepo.On("GetByLoanID", loanID).Return([]models.LoanParty{}, nil)
+
+	// Call the service
+	err := s.service.MakePayment(loanID, 109615)
+
+	// Assert results
+	assert.NoError(s.T(), err)
+
+	// Verify mock expectations
+	s.repoManager.AssertExpectations(s.T())
+	s.loanRepo.AssertExpectations(s.T())
+	s.scheduleRepo.AssertExpectations(s.T())
+	s.paymentRepo.AssertExpectations(s.T())
+	s.borrowerRepo.AssertExpectations(s.T())
+}
+
+// TestPaymentKeepsBorrowerDelinquentOnAnotherLoan tests that paying a current loan does not clear the flag of a borrower in arrears elsewhere
+func (s *LoanServiceTestSuite) TestPaymentKeepsBorrowerDelinquentOnAnotherLoan() {
+	// Prepare test data: a current loan, and another loan in arrears
+	borrowerID, coBorrowerID := uuid.New(), uuid.New()
+	current := &models.Loan{ID: uuid.New(), BorrowerID: borrowerID, Amount: 5000000, Status: models.LoanStatusActive, CurrentBalance: 5480769}
+	overdue := models.Loan{ID: uuid.New(), BorrowerID: borrowerID, Amount: 1000000, Status: models.LoanStatusActive, IsDelinquent: true}
+	installment := models.Schedule{ID: uuid.New(), LoanID: current.ID, WeekNumber: 1, DueDate: time.Now().AddDate(0, 0, 7), Amount: 109615}
+
+	s.repoManager.On("WithTransaction", mock.AnythingOfType("func(repositories.RepositoryManager) error")).Return(nil)
+	s.loanRepo.On("GetByID", current.ID).Return(current, nil)
+	s.scheduleRepo.On("GetUnpaidByLoanID", current.ID).Return([]models.Schedule{installment}, nil)
+	s.paymentRepo.On("Create", mock.AnythingOfType("*models.Payment")).Return(nil)
+	s.scheduleRepo.On("UpdatePaidStatus", installment.ID, true).Return(nil)
+	s.loanRepo.On("UpdateBalance", current.ID, int64(5371154)).Return(nil)
+	s.loanRepo.On("UpdateLastPaymentDate", current.ID, mock.AnythingOfType("time.Time")).Return(nil)
+	s.scheduleRepo.On("CountUnpaidByLoanID", current.ID).Return(int64(49), nil)
+	s.scheduleRepo.On("GetByLoanID", current.ID).Return([]models.Schedule{}, nil)
+	s.loanRepo.On("UpdateDelinquency", current.ID, false, uint(0)).Return(nil)
+
+	// The co-borrower of the current loan is not on the overdue one
+	s.partyRepo.On("GetByLoanID", current.ID).Return([]models.LoanParty{
+		{LoanID: current.ID, BorrowerID: coBorrowerID, Role: models.LoanPartyRoleCoBorrower},
+	}, nil)
+	s.loanRepo.On("GetByParty", borrowerID).Return([]models.Loan{*current, overdue}, nil)
+	s.loanRepo.On("GetByParty", coBorrowerID).Return([]models.Loan{*current}, nil)
+	s.borrowerRepo.On("UpdateDelinquencyStatus", borrowerID, true).Return(nil)
+	s.borrowerRepo.On("UpdateDelinquencyStatus", coBorrowerID, false).Return(nil)
+
+	// Call the service
+	err := s.service.MakePayment(current.ID, 109615)
+
+	// Assert results
+	assert.NoError(s.T(), err)
+	s.borrowerRepo.AssertCalled(s.T(), "UpdateDelinquencyStatus", borrowerID, true)
+	s.borrowerRepo.AssertNotCalled(s.T(), "UpdateDelinquencyStatus", borrowerID, false)
+	s.borrowerRepo.AssertCalled(s.T(), "UpdateDelinquencyStatus", coBorrowerID, false)
+}
+
+// TestReversePayment tests that reversing a payment unpays its installment, restores the balance and reopens the loan it closed
+func (s *LoanServiceTestSuite) TestReversePayment() {
+	// Prepare test data: a loan closed by its last payment
+	borrowerID := uuid.New()
+	loan := &models.Loan{ID: uuid.New(), BorrowerID: borrowerID, Amount: 100000, Status: models.LoanStatusClosed}
+	schedule := models.Schedule{ID: uuid.New(), LoanID: loan.ID, WeekNumber: 1, DueDate: time.Now().AddDate(0, 0, 7), Amount: 101923}
+	payment := &models.Payment{ID: uuid.New(), LoanID: loan.ID, ScheduleID: schedule.ID, Amount: 101923, Source: models.PaymentSourceBorrower}
+	groupPayment := &models.Payment{ID: uuid.New(), LoanID: loan.ID, ScheduleID: schedule.ID, Amount: 101923, Source: models.PaymentSourceGroup}
+
+	// Setup expectations
+	s.repoManager.On("WithTransaction", mock.AnythingOfType("func(repositories.RepositoryManager) error")).Return(nil)
+	s.paymentRepo.On("GetByID", payment.ID).Return(payment, nil)
+	s.paymentRepo.On("GetByID", groupPayment.ID).Return(groupPayment, nil)
+	s.loanRepo.On("GetByID", loan.ID).Return(loan, nil)
+	s.paymentRepo.On("Update", payment).Return(nil).Once()
+	s.scheduleRepo.On("UpdatePaidStatus", schedule.ID, false).Return(nil).Once()
+	s.loanRepo.On("UpdateBalance", loan.ID, int64(101923)).Return(nil).Once()
+	s.loanRepo.On("UpdateStatus", loan.ID, models.LoanStatusActive).Return(nil).Once()
+	s.changeRepo.On("Create", mock.MatchedBy(func(change *models.LoanStatusChange) bool {
+		return change.FromStatus == models.LoanStatusClosed && change.ToStatus == models.LoanStatusActive &&
+			change.Rule == "payment_reversed="+payment.ID.String() && change.ChangedBy == "staff:admin"
+	})).Return(nil).Once()
+	s.scheduleRepo.On("GetByLoanID", loan.ID).Return([]models.Schedule{schedule}, nil)
+	s.loanRepo.On("UpdateDelinquency", loan.ID, false, uint(0)).Return(nil)
+	s.loanRepo.On("GetByParty", borrowerID).Return([]models.Loan{*loan}, nil)
+	s.borrowerRepo.On("UpdateDelinquencyStatus", borrowerID, false).Return(nil)
+	s.partyRepo.On("GetByLoanID", loan.ID).Return([]models.LoanParty{}, nil)
+
+	// Call the service, as the caller
+	admin := s.service.WithContext(auth.WithPrincipal(context.Background(), &auth.Principal{Type: auth.PrincipalStaff, Subject: "admin"}))
+	reversed, err := admin.ReversePayment(payment.ID, "Cheque bounced")
+
+	// Assert results
+	s.Require().NoError(err)
+	s.NotNil(reversed.ReversedAt)
+	s.Equal("staff:admin", reversed.ReversedBy)
+	s.Equal("Cheque bounced", reversed.ReversalReason)
+
+	// A payment is reversed once, and group payments not at all
+	_, err = admin.ReversePayment(payment.ID, "Again")
+	s.ErrorIs(err, services.ErrPaymentAlreadyReversed)
+	_, err = admin.ReversePayment(groupPayment.ID, "Bounced")
+	s.ErrorIs(err, services.ErrPaymentNotReversible)
+
+	// Verify mock expectations
+	s.paymentRepo.AssertExpectations(s.T())
+	s.scheduleRepo.AssertExpectations(s.T())
+	s.loanRepo.AssertExpectations(s.T())
+	s.changeRepo.AssertExpectations(s.T())
+}
+
+// TestListLoans tests that loan listings are enriched with the next unpaid installment
+func (s *LoanServiceTestSuite) TestListLoans() {
+	// Prepare test data
+	paidUpLoan := models.Loan{ID: uuid.New(), Status: "closed"}
+	activeLoan := models.Loan{ID: uuid.New(), Status: "active", CurrentBalance: 4500000}
+	nextDue := models.Schedule{ID: uuid.New(), LoanID: activeLoan.ID, WeekNumber: 6, Amount: 109615}
+
+	filter := repositories.LoanFilter{Status: "active"}
+	page := repositories.PageRequest{Limit: 2}
+
+	// Setup expectations
+	s.loanRepo.On("List", filter, page).Return(repositories.Page[models.Loan]{
+		Items:      []models.Loan{paidUpLoan, activeLoan},
+		NextCursor: "next",
+		Limit:      2,
+	}, nil)
+	s.scheduleRepo.On("GetNextUnpaidByLoanIDs", []uuid.UUID{paidUpLoan.ID, activeLoan.ID}).
+		Return(map[uuid.UUID]models.Schedule{activeLoan.ID: nextDue}, nil)
+
+	// Call the service
+	result, err := s.service.ListLoans(filter, page)
+
+	// Assert results
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "next", result.NextCursor)
+	assert.Len(s.T(), result.Items, 2)
+	assert.Nil(s.T(), result.Items[0].NextDue)
+	assert.Equal(s.T(), nextDue.ID, result.Items[1].NextDue.ID)
+
+	// Verify mock expectations
+	s.loanRepo.AssertExpectations(s.T())
+	s.scheduleRepo.AssertExpectations(s.T())
+}
+
+// TestGetSchedule tests that installments report paid, partial, overdue and upcoming status
+func (s *LoanServiceTestSuite) TestGetSchedule() {
+	// Prepare test data
+	loanID := uuid.New()
+	now := time.Now()
+	schedules := []models.Schedule{
+		{ID: uuid.New(), LoanID: loanID, WeekNumber: 1, DueDate: now.AddDate(0, 0, -21), Amount: 109615, Paid: true},
+		{ID: uuid.New(), LoanID: loanID, WeekNumber: 2, DueDate: now.AddDate(0, 0, -14), Amount: 109615, Paid: false},
+		{ID: uuid.New(), LoanID: loanID, WeekNumber: 3, DueDate: now.AddDate(0, 0, -7), Amount: 109615, Paid: false},
+		{ID: uuid.New(), LoanID: loanID, WeekNumber: 4, DueDate: now.AddDate(0, 0, 7), Amount: 109615, Paid: false},
+	}
+	paidAt := now.AddDate(0, 0, -20)
+	payments := []models.Payment{
+		{ID: uuid.New(), LoanID: loanID, ScheduleID: schedules[0].ID, Amount: 109615, PaymentDate: paidAt},
+		{ID: uuid.New(), LoanID: loanID, ScheduleID: schedules[1].ID, Amount: 50000, PaymentDate: now.AddDate(0, 0, -13)},
+	}
+
+	// Setup expectations
+	s.loanRepo.On("GetByID", loanID).Return(&models.Loan{ID: loanID}, nil)
+	s.scheduleRepo.On("GetByLoanID", loanID).Return(schedules, nil)
+	s.paymentRepo.On("GetByLoanID", loanID).Return(payments, nil)
+
+	// Call the service
+	installments, err := s.service.GetSchedule(loanID, 0)
+
+	// Assert results
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), installments, 4)
+	assert.Equal(s.T(), services.InstallmentStatusPaid, installments[0].Status)
+	assert.Equal(s.T(), paidAt, *installments[0].PaidDate)
+	assert.Equal(s.T(), services.InstallmentStatusPartial, installments[1].Status)
+	assert.Equal(s.T(), int64(50000), installments[1].AmountPaid)
+	assert.True(s.T(), installments[1].IsOverdue)
+	assert.Equal(s.T(), services.InstallmentStatusOverdue, installments[2].Status)
+	assert.Equal(s.T(), services.InstallmentStatusUpcoming, installments[3].Status)
+	assert.Nil(s.T(), installments[3].PaidDate)
+
+	// Verify mock expectations
+	s.loanRepo.AssertExpectations(s.T())
+	s.scheduleRepo.AssertExpectations(s.T())
+	s.paymentRepo.AssertExpectations(s.T())
+}
+
+// TestQuoteLoan tests that quoting mirrors loan creation, product fees included, without persisting anything
+func (s *LoanServiceTestSuite) TestQuoteLoan() {
+	s.productRepo.On("GetByCode", models.DefaultProductCode).Return(&models.LoanProduct{Code: models.DefaultProductCode}, nil)
+	s.feeRepo.On("GetDefinitionsByProductCode", models.DefaultProductCode).Return([]models.FeeDefinition{}, nil)
+
+	// Call the service
+	quote, err := s.service.QuoteLoan("", 5000000, 10.0, 50)
+
+	// Assert results
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), models.DefaultProductCode, quote.ProductCode)
+	assert.Equal(s.T(), int64(5000000), quote.DisbursedAmount)
+	assert.Equal(s.T(), int64(5480769), quote.TotalDue)
+	assert.Equal(s.T(), int64(480769), quote.TotalInterest)
+	assert.Equal(s.T(), int64(109615), quote.WeeklyPayment)
+	assert.Len(s.T(), quote.Schedule, 50)
+	assert.Equal(s.T(), int64(109634), quote.Schedule[49].Amount) // Carries the rounding remainder
+
+	var scheduled int64
+	for _, installment := range quote.Schedule {
+		scheduled += installment.Amount
+	}
+	assert.Equal(s.T(), quote.TotalDue, scheduled)
+
+	// Flat interest costs more than its nominal rate once compounded
+	assert.Greater(s.T(), quote.EffectiveAnnualRate, quote.InterestRate)
+
+	// A product's fees are priced in as they would be on the loan
+	s.productRepo.On("GetByCode", "premium").Return(&models.LoanProduct{Code: "premium"}, nil)
+	s.feeRepo.On("GetDefinitionsByProductCode", "premium").Return([]models.FeeDefinition{
+		{ID: uuid.New(), Name: "Origination", Type: models.FeeTypeDeducted, Method: models.FeeMethodFlat, Amount: 100000},
+		{ID: uuid.New(), Name: "Processing", Type: models.FeeTypeCapitalized, Method: models.FeeMethodPercent, Rate: 1},
+		{ID: uuid.New(), Name: "Service", Type: models.FeeTypeInstallment, Method: models.FeeMethodFlat, Amount: 1000},
+	}, nil)
+	premium, err := s.service.QuoteLoan("premium", 5000000, 10.0, 50)
+	s.Require().NoError(err)
+	assert.Equal(s.T(), int64(5050000), premium.Principal)
+	assert.Equal(s.T(), int64(4900000), premium.DisbursedAmount)
+	assert.Equal(s.T(), int64(5585576), premium.TotalDue)
+	assert.Equal(s.T(), int64(485576), premium.TotalInterest)
+	assert.Equal(s.T(), int64(200000), premium.TotalFees)
+	assert.Equal(s.T(), int64(111711), premium.WeeklyPayment)
+	assert.Len(s.T(), premium.Fees, 3)
+	assert.Greater(s.T(), premium.APR, 19.04)
+	assert.Greater(s.T(), premium.APR, quote.APR)
+
+	s.productRepo.On("GetByCode", "missing").Return(nil, errors.New("record not found"))
+	_, err = s.service.QuoteLoan("missing", 5000000, 10.0, 50)
+	assert.ErrorIs(s.T(), err, services.ErrProductNotFound)
+
+	// Verify nothing was persisted
+	s.loanRepo.AssertNotCalled(s.T(), "Create", mock.Anything)
+	s.scheduleRepo.AssertNotCalled(s.T(), "CreateBatch", mock.Anything)
+	s.feeRepo.AssertNotCalled(s.T(), "CreateBatch", mock.Anything)
+}
+
+// TestRestructureLoan tests that arrears are capitalized into a new schedule version
+func (s *LoanServiceTestSuite) TestRestructureLoan() {
+	// Prepare test data
+	loanID := uuid.New()
+	now := time.Now()
+	loan := &models.Loan{
+		ID:              loanID,
+		BorrowerID:      uuid.New(),
+		Amount:          5000000,
+		InterestRate:    10.0,
+		TermWeeks:       50,
+		Status:          "active",
+		CurrentBalance:  438460,
+		IsDelinquent:    true,
+		ScheduleVersion: 1,
+	}
+
+	// Two overdue installments and two not yet due
+	unpaidSchedules := []models.Schedule{
+		{ID: uuid.New(), LoanID: loanID, Version: 1, WeekNumber: 47, DueDate: now.AddDate(0, 0, -14), Amount: 109615},
+		{ID: uuid.New(), LoanID: loanID, Version: 1, WeekNumber: 48, DueDate: now.AddDate(0, 0, -7), Amount: 109615},
+		{ID: uuid.New(), LoanID: loanID, Version: 1, WeekNumber: 49, DueDate: now.AddDate(0, 0, 7), Amount: 109615, PrincipalAmount: 100000, InterestAmount: 9615},
+		{ID: uuid.New(), LoanID: loanID, Version: 1, WeekNumber: 50, DueDate: now.AddDate(0, 0, 14), Amount: 109615, PrincipalAmount: 100000, InterestAmount: 9615},
+	}
+
+	// Setup expectations
+	s.repoManager.On("WithTransaction", mock.AnythingOfType("func(repositories.RepositoryManager) error")).Return(nil)
+	s.loanRepo.On("GetByID", loanID).Return(loan, nil)
+	s.scheduleRepo.On("GetUnpaidByLoanID", loanID).Return(unpaidSchedules, nil)
+	s.scheduleRepo.On("CancelUnpaidByLoanID", loanID).Return(nil)
+
+	var newSchedules []models.Schedule
+	s.scheduleRepo.On("CreateBatch", mock.AnythingOfType("[]models.Schedule")).Run(func(args mock.Arguments) {
+		newSchedules = args.Get(0).([]models.Schedule)
+	}).Return(nil)
+	s.restructRepo.On("Create", mock.AnythingOfType("*models.LoanRestructure")).Return(nil)
+	s.loanRepo.On("Update", loan).Return(nil)
+
+	// Call the service
+	restructure, err := s.service.RestructureLoan(loanID, services.RestructureTerms{Reason: "hardship"})
+
+	// Assert results
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), int64(219230), restructure.ArrearsCapitalized)
+	assert.Equal(s.T(), int64(419230), restructure.NewPrincipal)
+	assert.Equal(s.T(), uint(1), restructure.FromVersion)
+	assert.Equal(s.T(), uint(2), restructure.ToVersion)
+	assert.Equal(s.T(), uint(4), restructure.NewTermWeeks)
+
+	assert.Len(s.T(), newSchedules, 4)
+	var total int64
+	for _, schedule := range newSchedules {
+		assert.Equal(s.T(), uint(2), schedule.Version)
+		assert.Equal(s.T(), loanID, schedule.LoanID)
+		total += schedule.Amount
+	}
+	assert.Equal(s.T(), restructure.NewBalance, total)
+	assert.Equal(s.T(), restructure.NewBalance, loan.CurrentBalance)
+
+	// The loan's amount, terms and total due follow the new schedule
+	assert.Equal(s.T(), int64(5000000), restructure.PreviousPrincipal)
+	assert.Equal(s.T(), restructure.NewPrincipal, loan.Amount)
+	assert.Equal(s.T(), uint(4), loan.TermWeeks)
+	assert.Equal(s.T(), total, loan.CalculateTotalDue())
+
+	// A delinquent loan stays delinquent until the cure payments are made
+	assert.True(s.T(), loan.IsRestructured)
+	assert.True(s.T(), loan.IsDelinquent)
+	assert.Equal(s.T(), uint(3), loan.CurePaymentsRemaining)
+	assert.Equal(s.T(), uint(0), loan.DaysPastDue)
+
+	// Verify mock expectations
+	s.repoManager.AssertExpectations(s.T())
+	s.loanRepo.AssertExpectations(s.T())
+	s.scheduleRepo.AssertExpectations(s.T())
+	s.restructRepo.AssertExpectations(s.T())
+	s.borrowerRepo.AssertNotCalled(s.T(), "UpdateDelinquencyStatus", mock.Anything, mock.Anything)
+}
+
+// TestRestructureLoanCarriesFees tests that installment fees not yet due are spread over the new schedule rather than dropped
+func (s *LoanServiceTestSuite) TestRestructureLoanCarriesFees() {
+	// Prepare test data: installments carrying a 1000 service fee each
+	loanID := uuid.New()
+	now := time.Now()
+	loan := &models.Loan{ID: loanID, BorrowerID: uuid.New(), Amount: 5000000, InterestRate: 10.0, TermWeeks: 50, Status: models.LoanStatusActive, CurrentBalance: 442460, ScheduleVersion: 1}
+	unpaidSchedules := []models.Schedule{
+		{ID: uuid.New(), LoanID: loanID, WeekNumber: 47, DueDate: now.AddDate(0, 0, -14), Amount: 110615, PrincipalAmount: 100000, InterestAmount: 9615, FeeAmount: 1000},
+		{ID: uuid.New(), LoanID: loanID, WeekNumber: 48, DueDate: now.AddDate(0, 0, -7), Amount: 110615, PrincipalAmount: 100000, InterestAmount: 9615, FeeAmount: 1000},
+		{ID: uuid.New(), LoanID: loanID, WeekNumber: 49, DueDate: now.AddDate(0, 0, 7), Amount: 110615, PrincipalAmount: 100000, InterestAmount: 9615, FeeAmount: 1000},
+		{ID: uuid.New(), LoanID: loanID, WeekNumber: 50, DueDate: now.AddDate(0, 0, 14), Amount: 110615, PrincipalAmount: 100000, InterestAmount: 9615, FeeAmount: 1000},
+	}
+	var newSchedules []models.Schedule
+
+	// Setup expectations
+	s.repoManager.On("WithTransaction", mock.AnythingOfType("func(repositories.RepositoryManager) error")).Return(nil)
+	s.loanRepo.On("GetByID", loanID).Return(loan, nil)
+	s.scheduleRepo.On("GetUnpaidByLoanID", loanID).Return(unpaidSchedules, nil)
+	s.scheduleRepo.On("CancelUnpaidByLoanID", loanID).Return(nil)
+	s.scheduleRepo.On("CreateBatch", mock.AnythingOfType("[]models.Schedule")).Run(func(args mock.Arguments) {
+		newSchedules = args.Get(0).([]models.Schedule)
+	}).Return(nil)
+	s.restructRepo.On("Create", mock.AnythingOfType("*models.LoanRestructure")).Return(nil)
+	s.loanRepo.On("Update", loan).Return(nil)
+	s.loanRepo.On("GetByParty", loan.BorrowerID).Return([]models.Loan{*loan}, nil)
+	s.borrowerRepo.On("UpdateDelinquencyStatus", loan.BorrowerID, false).Return(nil)
+	s.partyRepo.On("GetByLoanID", loanID).Return([]models.LoanParty{}, nil)
+
+	// Call the service
+	restructure, err := s.service.RestructureLoan(loanID, services.RestructureTerms{Reason: "hardship"})
+
+	// Overdue fees are capitalized with the arrears; the two fees not yet due carry over as fees
+	s.Require().NoError(err)
+	s.Equal(int64(221230), restructure.ArrearsCapitalized)
+	s.Equal(int64(421230), restructure.NewPrincipal)
+	s.Equal(int64(2000), restructure.FeesCarried)
+
+	s.Require().Len(newSchedules, 4)
+	var total, fees int64
+	for _, schedule := range newSchedules {
+		s.Equal(int64(500), schedule.FeeAmount)
+		total += schedule.Amount
+		fees += schedule.FeeAmount
+	}
+	s.Equal(int64(2000), fees)
+	s.Equal(restructure.NewBalance, total)
+	s.Equal(total, loan.CurrentBalance)
+	s.Equal(loan.CalculateTotalDue()+fees, total)
+}
+
+// TestRefinanceLoanSettlesFees tests that the payoff settles the installment fees not yet due along with their principal
+func (s *LoanServiceTestSuite) TestRefinanceLoanSettlesFees() {
+	// Prepare test data: one overdue and one upcoming installment, each with a 1000 fee
+	loanID := uuid.New()
+	now := time.Now()
+	oldLoan := &models.Loan{ID: loanID, BorrowerID: uuid.New(), ProductCode: "standard", Amount: 5000000, InterestRate: 10.0, TermWeeks: 50, Status: models.LoanStatusActive, CurrentBalance: 221230}
+	overdue := models.Schedule{ID: uuid.New(), LoanID: loanID, WeekNumber: 49, DueDate: now.AddDate(0, 0, -1), Amount: 110615, PrincipalAmount: 100000, InterestAmount: 9615, FeeAmount: 1000}
+	upcoming := models.Schedule{ID: uuid.New(), LoanID: loanID, WeekNumber: 50, DueDate: now.AddDate(0, 0, 6), Amount: 110615, PrincipalAmount: 100000, InterestAmount: 9615, FeeAmount: 1000}
+	payments := map[uuid.UUID]*models.Payment{}
+
+	// Setup expectations
+	s.repoManager.On("WithTransaction", mock.AnythingOfType("func(repositories.RepositoryManager) error")).Return(nil)
+	s.loanRepo.On("GetByID", loanID).Return(oldLoan, nil)
+	s.borrowerRepo.On("GetByID", oldLoan.BorrowerID).Return(&models.Borrower{ID: oldLoan.BorrowerID}, nil)
+	s.scheduleRepo.On("GetUnpaidByLoanID", loanID).Return([]models.Schedule{overdue, upcoming}, nil)
+	s.feeRepo.On("GetDefinitionsByProductCode", "standard").Return([]models.FeeDefinition{}, nil)
+	s.partyRepo.On("GetByLoanID", loanID).Return([]models.LoanParty{}, nil)
+	s.loanRepo.On("Create", mock.AnythingOfType("*models.Loan")).Return(nil)
+	s.scheduleRepo.On("CreateBatch", mock.AnythingOfType("[]models.Schedule")).Return(nil)
+	s.feeRepo.On("CreateBatch", []models.LoanFee{}).Return(nil)
+	s.partyRepo.On("CreateBatch", []models.LoanParty{}).Return(nil)
+	s.collateralRepo.On("GetByLoanID", loanID).Return([]models.Collateral{}, nil)
+	s.paymentRepo.On("Create", mock.AnythingOfType("*models.Payment")).Run(func(args mock.Arguments) {
+		payment := args.Get(0).(*models.Payment)
+		payments[payment.ScheduleID] = payment
+	}).Return(nil)
+	s.scheduleRepo.On("UpdatePaidStatus", mock.AnythingOfType("uuid.UUID"), true).Return(nil)
+	s.loanRepo.On("Update", oldLoan).Return(nil)
+	s.changeRepo.On("Create", mock.AnythingOfType("*models.LoanStatusChange")).Return(nil)
+
+	// Call the service
+	result, err := s.service.RefinanceLoan(loanID, services.RefinanceTerms{Amount: 1000000, TermWeeks: 10})
+
+	// Assert results
+	s.Require().NoError(err)
+	s.Equal(int64(211615), result.PayoffAmount)
+	s.Equal(int64(1000000-211615), result.NewLoan.DisbursedAmount)
+	s.Equal(int64(1000), payments[overdue.ID].Fees)
+	s.Equal(int64(101000), payments[upcoming.ID].Amount)
+	s.Equal(int64(100000), payments[upcoming.ID].Principal)
+	s.Equal(int64(1000), payments[upcoming.ID].Fees)
+}
+
+// TestGrantPaymentHoliday tests that a holiday defers the unpaid installments and clears delinquency
+func (s *LoanServiceTestSuite) TestGrantPaymentHoliday() {
+	// Prepare test data
+	loanID := uuid.New()
+	borrowerID := uuid.New()
+	now := time.Now()
+	loan := &models.Loan{
+		ID:              loanID,
+		BorrowerID:      borrowerID,
+		Amount:          5000000,
+		InterestRate:    10.0,
+		TermWeeks:       50,
+		Status:          "active",
+		CurrentBalance:  328845,
+		IsDelinquent:    true,
+		DaysPastDue:     14,
+		ScheduleVersion: 1,
+	}
+
+	// Two overdue installments and one not yet due
+	schedules := []models.Schedule{
+		{ID: uuid.New(), LoanID: loanID, WeekNumber: 48, DueDate: now.AddDate(0, 0, -14), Amount: 109615, PrincipalAmount: 100000, InterestAmount: 9615},
+		{ID: uuid.New(), LoanID: loanID, WeekNumber: 49, DueDate: now.AddDate(0, 0, -7), Amount: 109615, PrincipalAmount: 100000, InterestAmount: 9615},
+		{ID: uuid.New(), LoanID: loanID, WeekNumber: 50, DueDate: now.AddDate(0, 0, 7), Amount: 109615, PrincipalAmount: 100000, InterestAmount: 9615},
+	}
+	originalDueDate := schedules[0].DueDate
+
+	// Setup expectations
+	s.repoManager.On("WithTransaction", mock.AnythingOfType("func(repositories.RepositoryManager) error")).Return(nil)
+	s.loanRepo.On("GetByID", loanID).Return(loan, nil)
+	s.scheduleRepo.On("GetUnpaidByLoanID", loanID).Return(schedules, nil)
+	s.scheduleRepo.On("UpdateBatch", mock.AnythingOfType("[]models.Schedule")).Return(nil)
+	s.scheduleRepo.On("GetByLoanID", loanID).Return(schedules, nil)
+	s.holidayRepo.On("Create", mock.AnythingOfType("*models.PaymentHoliday")).Return(nil)
+	s.loanRepo.On("Update", loan).Return(nil)
+	s.loanRepo.On("GetByParty", borrowerID).Return([]models.Loan{*loan}, nil)
+	s.borrowerRepo.On("UpdateDelinquencyStatus", borrowerID, false).Return(nil)
+	s.partyRepo.On("GetByLoanID", loanID).Return([]models.LoanParty{}, nil)
+
+	// Call the service, as the approver
+	approver := auth.WithPrincipal(context.Background(), &auth.Principal{Type: auth.PrincipalStaff, Subject: "ops-lead"})
+	holiday, err := s.service.WithContext(approver).GrantPaymentHoliday(loanID, services.HolidayTerms{
+		Installments:   4,
+		ReasonCode:     models.HolidayReasonMedical,
+		AccrueInterest: true,
+	})
 
 	// Assert results
 	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), uint(4), holiday.Installments)
+	assert.Equal(s.T(), "staff:ops-lead", holiday.ApprovedBy)
+	assert.Equal(s.T(), int64(2307), holiday.AccruedInterest)
+
+	assert.Equal(s.T(), originalDueDate, *schedules[0].OriginalDueDate)
+	assert.Equal(s.T(), originalDueDate.AddDate(0, 0, 28), schedules[0].DueDate)
+	assert.True(s.T(), schedules[0].IsDeferred(now))
+	assert.Equal(s.T(), int64(109615+2307), schedules[2].Amount)
+
+	assert.True(s.T(), loan.OnHoliday(now))
+	assert.False(s.T(), loan.IsDelinquent)
+	assert.Equal(s.T(), uint(0), loan.DaysPastDue)
+	assert.Equal(s.T(), int64(328845+2307), loan.CurrentBalance)
 
 	// Verify mock expectations
 	s.repoManager.AssertExpectations(s.T())
 	s.loanRepo.AssertExpectations(s.T())
 	s.scheduleRepo.AssertExpectations(s.T())
-	s.paymentRepo.AssertExpectations(s.T())
+	s.holidayRepo.AssertExpectations(s.T())
 	s.borrowerRepo.AssertExpectations(s.T())
 }
 
+// TestWriteOffAndRecover tests that a written-off loan only accepts recoveries up to the written-off balance
+func (s *LoanServiceTestSuite) TestWriteOffAndRecover() {
+	// Prepare test data
+	loanID := uuid.New()
+	loan := &models.Loan{
+		ID:             loanID,
+		BorrowerID:     uuid.New(),
+		Amount:         5000000,
+		Status:         "active",
+		CurrentBalance: 1200000,
+	}
+
+	// Setup expectations
+	s.repoManager.On("WithTransaction", mock.AnythingOfType("func(repositories.RepositoryManager) error")).Return(nil)
+	s.loanRepo.On("GetByID", loanID).Return(loan, nil)
+	s.scheduleRepo.On("CancelUnpaidByLoanID", loanID).Return(nil)
+	s.loanRepo.On("Update", loan).Return(nil)
+	s.recoveryRepo.On("Create", mock.AnythingOfType("*models.Recovery")).Return(nil)
+	s.changeRepo.On("Create", mock.MatchedBy(func(change *models.LoanStatusChange) bool {
+		return change.FromStatus == "active" && change.ToStatus == models.LoanStatusWrittenOff && change.ChangedBy == "staff:collections"
+	})).Return(nil).Once()
+
+	// Write the loan off, as the caller
+	collections := auth.WithPrincipal(context.Background(), &auth.Principal{Type: auth.PrincipalStaff, Subject: "collections"})
+	writtenOff, err := s.service.WithContext(collections).WriteOffLoan(loanID, "borrower deceased")
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), models.LoanStatusWrittenOff, writtenOff.Status)
+	assert.Equal(s.T(), int64(1200000), writtenOff.WrittenOffAmount)
+	assert.Equal(s.T(), int64(0), writtenOff.CurrentBalance)
+	assert.NotNil(s.T(), writtenOff.WrittenOffAt)
+
+	// Repayments are refused, recoveries are tracked separately
+	assert.ErrorIs(s.T(), s.service.MakePayment(loanID, 109615), services.ErrLoanWrittenOff)
+
+	recovery, err := s.service.RecordRecovery(loanID, services.RecoveryDetails{Amount: 200000, Reference: "agency-17"})
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), int64(200000), recovery.Amount)
+	assert.Equal(s.T(), int64(200000), loan.RecoveredAmount)
+	assert.Equal(s.T(), int64(0), loan.CurrentBalance)
+
+	_, err = s.service.RecordRecovery(loanID, services.RecoveryDetails{Amount: 1000001})
+	assert.ErrorIs(s.T(), err, services.ErrRecoveryExceedsWrittenOff)
+
+	// A second write-off is refused
+	_, err = s.service.WithContext(collections).WriteOffLoan(loanID, "again")
+	assert.ErrorIs(s.T(), err, services.ErrLoanNotWriteOffable)
+
+	// Verify mock expectations
+	s.loanRepo.AssertExpectations(s.T())
+	s.scheduleRepo.AssertExpectations(s.T())
+	s.recoveryRepo.AssertNumberOfCalls(s.T(), "Create", 1)
+	s.changeRepo.AssertExpectations(s.T())
+}
+
+// TestApplyEscalationRules tests that product thresholds default loans and queue charge-offs once
+func (s *LoanServiceTestSuite) TestApplyEscalationRules() {
+	// Prepare test data
+	product := models.DefaultProduct()
+	overdueLoan := models.Loan{ID: uuid.New(), Status: "active", DaysPastDue: 95, CurrentBalance: 2000000}
+	longOverdueLoan := models.Loan{ID: uuid.New(), Status: "defaulted", DaysPastDue: 190, CurrentBalance: 1500000}
+	alreadyProposedLoan := models.Loan{ID: uuid.New(), Status: "defaulted", DaysPastDue: 250}
+
+	// Setup expectations
+	s.repoManager.On("WithTransaction", mock.AnythingOfType("func(repositories.RepositoryManager) error")).Return(nil)
+	s.productRepo.On("List").Return([]models.LoanProduct{product}, nil)
+	s.loanRepo.On("GetEscalationCandidates", "standard", []string{"active"}, uint(90)).
+		Return([]models.Loan{overdueLoan}, nil)
+	s.loanRepo.On("GetEscalationCandidates", "standard", []string{"active", "defaulted"}, uint(180)).
+		Return([]models.Loan{longOverdueLoan, alreadyProposedLoan}, nil)
+	s.loanRepo.On("UpdateStatus", overdueLoan.ID, models.LoanStatusDefaulted).Return(nil)
+	s.changeRepo.On("Create", mock.MatchedBy(func(change *models.LoanStatusChange) bool {
+		return change.LoanID == overdueLoan.ID &&
+			change.ToStatus == models.LoanStatusDefaulted &&
+			change.Rule == "standard.default_after_dpd=90" &&
+			change.ChangedBy == models.StatusChangedBySystem
+	})).Return(nil)
+	s.chargeRepo.On("ExistsForLoan", longOverdueLoan.ID).Return(false, nil)
+	s.chargeRepo.On("ExistsForLoan", alreadyProposedLoan.ID).Return(true, nil)
+	s.chargeRepo.On("Create", mock.MatchedBy(func(proposal *models.ChargeOffProposal) bool {
+		return proposal.LoanID == longOverdueLoan.ID &&
+			proposal.Status == models.ChargeOffStatusPending &&
+			proposal.Rule == "standard.charge_off_after_dpd=180" &&
+			proposal.Balance == 1500000
+	})).Return(nil)
+
+	// Call the service
+	result, err := s.service.ApplyEscalationRules()
+
+	// Assert results
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), 1, result.Defaulted)
+	assert.Equal(s.T(), 1, result.ChargeOffsProposed)
+
+	// Verify mock expectations
+	s.productRepo.AssertExpectations(s.T())
+	s.loanRepo.AssertExpectations(s.T())
+	s.changeRepo.AssertExpectations(s.T())
+	s.chargeRepo.AssertExpectations(s.T())
+}
+
+// TestRefinanceLoan tests that the new loan settles the old loan's payoff and disburses the difference
+func (s *LoanServiceTestSuite) TestRefinanceLoan() {
+	// Prepare test data
+	loanID := uuid.New()
+	now := time.Now()
+	oldLoan := &models.Loan{
+		ID:             loanID,
+		BorrowerID:     uuid.New(),
+		ProductCode:    "standard",
+		Amount:         5000000,
+		InterestRate:   10.0,
+		TermWeeks:      50,
+		Status:         "active",
+		CurrentBalance: 328845,
+	}
+	unpaidSchedules := []models.Schedule{
+		{ID: uuid.New(), LoanID: loanID, WeekNumber: 48, DueDate: now.AddDate(0, 0, -1), Amount: 109615, PrincipalAmount: 100000, InterestAmount: 9615},
+		{ID: uuid.New(), LoanID: loanID, WeekNumber: 49, DueDate: now.AddDate(0, 0, 6), Amount: 109615, PrincipalAmount: 100000, InterestAmount: 9615},
+		{ID: uuid.New(), LoanID: loanID, WeekNumber: 50, DueDate: now.AddDate(0, 0, 13), Amount: 109615, PrincipalAmount: 100000, InterestAmount: 9615},
+	}
+	newLoanID := uuid.New()
+	// The limit fits the new loan's total due only once the old loan is settled
+	creditLimit := int64(1100000)
+	borrower := &models.Borrower{ID: oldLoan.BorrowerID, CreditLimit: &creditLimit, Loans: []models.Loan{*oldLoan}}
+
+	// Setup expectations
+	s.repoManager.On("WithTransaction", mock.AnythingOfType("func(repositories.RepositoryManager) error")).Return(nil)
+	s.loanRepo.On("GetByID", loanID).Return(oldLoan, nil)
+	s.borrowerRepo.On("GetByID", oldLoan.BorrowerID).Return(borrower, nil)
+	s.scheduleRepo.On("GetUnpaidByLoanID", loanID).Return(unpaidSchedules, nil)
+	s.feeRepo.On("GetDefinitionsByProductCode", "standard").Return([]models.FeeDefinition{}, nil)
+	s.partyRepo.On("GetByLoanID", loanID).Return([]models.LoanParty{}, nil)
+	s.loanRepo.On("Create", mock.AnythingOfType("*models.Loan")).Run(func(args mock.Arguments) {
+		args.Get(0).(*models.Loan).ID = newLoanID
+	}).Return(nil)
+	s.scheduleRepo.On("CreateBatch", mock.AnythingOfType("[]models.Schedule")).Return(nil)
+	s.feeRepo.On("CreateBatch", []models.LoanFee{}).Return(nil)
+	s.partyRepo.On("CreateBatch", []models.LoanParty{}).Return(nil)
+	s.collateralRepo.On("GetByLoanID", loanID).Return([]models.Collateral{}, nil)
+	s.paymentRepo.On("Create", mock.MatchedBy(func(payment *models.Payment) bool {
+		return payment.Source == models.PaymentSourceRefinance
+	})).Return(nil).Times(3)
+	s.scheduleRepo.On("UpdatePaidStatus", mock.AnythingOfType("uuid.UUID"), true).Return(nil).Times(3)
+	s.loanRepo.On("Update", oldLoan).Return(nil)
+	s.changeRepo.On("Create", mock.MatchedBy(func(change *models.LoanStatusChange) bool {
+		return change.LoanID == loanID && change.ToStatus == models.LoanStatusRefinanced && change.ChangedBy == "staff:branch-7"
+	})).Return(nil)
+
+	// Call the service, as the caller
+	branch := auth.WithPrincipal(context.Background(), &auth.Principal{Type: auth.PrincipalStaff, Subject: "branch-7"})
+	result, err := s.service.WithContext(branch).RefinanceLoan(loanID, services.RefinanceTerms{Amount: 1000000, TermWeeks: 10})
+
+	// Assert results
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), int64(309615), result.PayoffAmount)
+	assert.Equal(s.T(), int64(1000000), result.NewLoan.Amount)
+	assert.Equal(s.T(), int64(690385), result.NewLoan.DisbursedAmount)
+	assert.Equal(s.T(), loanID, *result.NewLoan.RefinancedFromID)
+	assert.Equal(s.T(), models.LoanStatusRefinanced, result.OldLoan.Status)
+	assert.Equal(s.T(), int64(0), result.OldLoan.CurrentBalance)
+	assert.Equal(s.T(), newLoanID, *result.OldLoan.RefinancedByID)
+
+	// A top-up that does not cover the payoff is refused
+	oldLoan.Status = "active"
+	_, err = s.service.WithContext(branch).RefinanceLoan(loanID, services.RefinanceTerms{Amount: 300000, TermWeeks: 10})
+	assert.ErrorIs(s.T(), err, services.ErrRefinanceTooSmall)
+
+	// The borrower's other loans still count towards the credit checks
+	borrower.Loans = append(borrower.Loans, models.Loan{ID: uuid.New(), Status: models.LoanStatusActive, CurrentBalance: 200000})
+	_, err = s.service.WithContext(branch).RefinanceLoan(loanID, services.RefinanceTerms{Amount: 1000000, TermWeeks: 10})
+	var rejection *services.CreditRejection
+	s.Require().ErrorAs(err, &rejection)
+	s.Equal(services.RejectionCreditLimitExceeded, rejection.Reasons[0].Code)
+	s.loanRepo.AssertNumberOfCalls(s.T(), "Create", 1)
+
+	// Verify mock expectations
+	s.loanRepo.AssertExpectations(s.T())
+	s.scheduleRepo.AssertExpectations(s.T())
+	s.paymentRepo.AssertExpectations(s.T())
+	s.changeRepo.AssertExpectations(s.T())
+}
+
+// TestRefinanceLoanMovesCollateral tests that a refinanced loan's collateral secures the new loan and stays pledged until it is paid
+func (s *LoanServiceTestSuite) TestRefinanceLoanMovesCollateral() {
+	// Prepare test data: a secured loan with one lien still registered
+	loanID := uuid.New()
+	oldLoan := &models.Loan{ID: loanID, BorrowerID: uuid.New(), ProductCode: "standard", Amount: 5000000, InterestRate: 10.0, TermWeeks: 50, Status: models.LoanStatusActive, CurrentBalance: 109615}
+	unpaidSchedules := []models.Schedule{
+		{ID: uuid.New(), LoanID: loanID, WeekNumber: 50, DueDate: time.Now().AddDate(0, 0, 6), Amount: 109615, PrincipalAmount: 100000, InterestAmount: 9615},
+	}
+	vehicle := models.Collateral{ID: uuid.New(), LoanID: loanID, Type: models.CollateralTypeVehicle, Value: 5000000, LienStatus: models.LienStatusRegistered}
+	deposit := models.Collateral{ID: uuid.New(), LoanID: loanID, Type: models.CollateralTypeDeposit, Value: 1000000, LienStatus: models.LienStatusReleased}
+	newLoanID := uuid.New()
+	var newLoan *models.Loan
+
+	// Setup expectations
+	s.repoManager.On("WithTransaction", mock.AnythingOfType("func(repositories.RepositoryManager) error")).Return(nil)
+	s.loanRepo.On("GetByID", loanID).Return(oldLoan, nil)
+	s.borrowerRepo.On("GetByID", oldLoan.BorrowerID).Return(&models.Borrower{ID: oldLoan.BorrowerID}, nil)
+	s.scheduleRepo.On("GetUnpaidByLoanID", loanID).Return(unpaidSchedules, nil)
+	s.feeRepo.On("GetDefinitionsByProductCode", "standard").Return([]models.FeeDefinition{}, nil)
+	s.partyRepo.On("GetByLoanID", loanID).Return([]models.LoanParty{}, nil)
+	s.loanRepo.On("Create", mock.AnythingOfType("*models.Loan")).Run(func(args mock.Arguments) {
+		newLoan = args.Get(0).(*models.Loan)
+		newLoan.ID = newLoanID
+	}).Return(nil)
+	s.scheduleRepo.On("CreateBatch", mock.AnythingOfType("[]models.Schedule")).Return(nil)
+	s.feeRepo.On("CreateBatch", []models.LoanFee{}).Return(nil)
+	s.partyRepo.On("CreateBatch", []models.LoanParty{}).Return(nil)
+	s.collateralRepo.On("GetByLoanID", loanID).Return([]models.Collateral{vehicle, deposit}, nil)
+	s.collateralRepo.On("Update", mock.MatchedBy(func(collateral *models.Collateral) bool {
+		return collateral.ID == vehicle.ID && collateral.LoanID == newLoanID
+	})).Return(nil).Once()
+	s.paymentRepo.On("Create", mock.AnythingOfType("*models.Payment")).Return(nil)
+	s.scheduleRepo.On("UpdatePaidStatus", mock.AnythingOfType("uuid.UUID"), true).Return(nil)
+	s.loanRepo.On("Update", oldLoan).Return(nil)
+	s.changeRepo.On("Create", mock.AnythingOfType("*models.LoanStatusChange")).Return(nil)
+
+	// Call the service
+	_, err := s.service.RefinanceLoan(loanID, services.RefinanceTerms{Amount: 1000000, TermWeeks: 10})
+	s.Require().NoError(err)
+	s.collateralRepo.AssertExpectations(s.T())
+
+	// Collateral left on the settled loan cannot be released either: its debt moved to the new loan
+	s.collateralRepo.On("GetByID", vehicle.ID).Return(&vehicle, nil)
+	_, err = s.service.UpdateLienStatus(loanID, vehicle.ID, models.LienStatusReleased)
+	s.ErrorIs(err, services.ErrLoanNotFullyPaid)
+
+	// Nor can it be through the new loan until that loan is paid
+	moved := vehicle
+	moved.LoanID = newLoanID
+	s.collateralRepo.On("GetByID", moved.ID).Unset()
+	s.collateralRepo.On("GetByID", moved.ID).Return(&moved, nil)
+	s.loanRepo.On("GetByID", newLoanID).Return(newLoan, nil)
+	_, err = s.service.UpdateLienStatus(newLoanID, moved.ID, models.LienStatusReleased)
+	s.ErrorIs(err, services.ErrLoanNotFullyPaid)
+	s.collateralRepo.AssertNumberOfCalls(s.T(), "Update", 1)
+}
+
+// TestApplyForLoanWithFees tests that product fees change the disbursement, schedule, balance and APR
+func (s *LoanServiceTestSuite) TestApplyForLoanWithFees() {
+	// Prepare test data
+	borrowerID := uuid.New()
+	definitions := []models.FeeDefinition{
+		{ID: uuid.New(), Name: "Origination", Type: models.FeeTypeDeducted, Method: models.FeeMethodFlat, Amount: 100000},
+		{ID: uuid.New(), Name: "Processing", Type: models.FeeTypeCapitalized, Method: models.FeeMethodPercent, Rate: 1},
+		{ID: uuid.New(), Name: "Service", Type: models.FeeTypeInstallment, Method: models.FeeMethodFlat, Amount: 1000},
+	}
+	var schedules []models.Schedule
+	var fees []models.LoanFee
+
+	// Setup expectations
+	s.borrowerRepo.On("GetByID", borrowerID).Return(&models.Borrower{ID: borrowerID}, nil)
+	s.productRepo.On("GetByCode", "premium").Return(&models.LoanProduct{Code: "premium"}, nil)
+	s.feeRepo.On("GetDefinitionsByProductCode", "premium").Return(definitions, nil)
+	s.repoManager.On("WithTransaction", mock.AnythingOfType("func(repositories.RepositoryManager) error")).Return(nil)
+	s.loanRepo.On("Create", mock.AnythingOfType("*models.Loan")).Run(func(args mock.Arguments) {
+		args.Get(0).(*models.Loan).ID = uuid.New()
+	}).Return(nil)
+	s.scheduleRepo.On("CreateBatch", mock.AnythingOfType("[]models.Schedule")).Run(func(args mock.Arguments) {
+		schedules = args.Get(0).([]models.Schedule)
+	}).Return(nil)
+	s.feeRepo.On("CreateBatch", mock.AnythingOfType("[]models.LoanFee")).Run(func(args mock.Arguments) {
+		fees = args.Get(0).([]models.LoanFee)
+	}).Return(nil)
+	s.partyRepo.On("CreateBatch", []models.LoanParty{}).Return(nil)
+
+	// Call the service
+	loan, err := s.service.ApplyForLoan(services.LoanApplication{
+		BorrowerID:   borrowerID,
+		ProductCode:  "premium",
+		Amount:       5000000,
+		InterestRate: 10.0,
+		TermWeeks:    50,
+	})
+
+	// Assert results
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), int64(5050000), loan.Amount)
+	assert.Equal(s.T(), int64(4900000), loan.DisbursedAmount)
+	assert.Equal(s.T(), int64(5585576), loan.CurrentBalance)
+	assert.Greater(s.T(), loan.APR, 19.04)
+
+	var scheduled int64
+	for _, schedule := range schedules {
+		assert.Equal(s.T(), int64(1000), schedule.FeeAmount)
+		scheduled += schedule.Amount
+	}
+	assert.Equal(s.T(), loan.CurrentBalance, scheduled)
+
+	principal, interest, fee := schedules[0].Allocation(loan.Amount, 0)
+	assert.Equal(s.T(), int64(101000), principal)
+	assert.Equal(s.T(), int64(1000), fee)
+	assert.Equal(s.T(), schedules[0].Amount, principal+interest+fee)
+
+	assert.Len(s.T(), fees, 3)
+	assert.Equal(s.T(), int64(50000), fees[2].Amount)
+
+	// Deducted fees that swallow the loan are refused
+	_, err = s.service.ApplyForLoan(services.LoanApplication{
+		BorrowerID:   borrowerID,
+		ProductCode:  "premium",
+		Amount:       100000,
+		InterestRate: 10.0,
+		TermWeeks:    50,
+	})
+	assert.ErrorIs(s.T(), err, services.ErrFeesExceedAmount)
+
+	// Verify mock expectations
+	s.loanRepo.AssertExpectations(s.T())
+	s.scheduleRepo.AssertExpectations(s.T())
+	s.feeRepo.AssertExpectations(s.T())
+}
+
+// TestGetBorrowerExposure tests that a borrower's loans include those they co-borrow or guarantee
+func (s *LoanServiceTestSuite) TestGetBorrowerExposure() {
+	// Prepare test data
+	borrowerID := uuid.New()
+	ownLoan := models.Loan{ID: uuid.New(), BorrowerID: borrowerID, Status: models.LoanStatusActive, CurrentBalance: 300000}
+	coBorrowedLoan := models.Loan{ID: uuid.New(), BorrowerID: uuid.New(), Status: models.LoanStatusDefaulted, CurrentBalance: 200000}
+	guaranteedLoan := models.Loan{ID: uuid.New(), BorrowerID: uuid.New(), Status: models.LoanStatusActive, CurrentBalance: 100000}
+	closedLoan := models.Loan{ID: uuid.New(), BorrowerID: borrowerID, Status: models.LoanStatusClosed, CurrentBalance: 0}
+
+	// Setup expectations
+	s.borrowerRepo.On("GetByID", borrowerID).Return(&models.Borrower{ID: borrowerID}, nil)
+	s.loanRepo.On("GetByParty", borrowerID).Return([]models.Loan{ownLoan, coBorrowedLoan, guaranteedLoan, closedLoan}, nil)
+	s.partyRepo.On("GetByBorrowerID", borrowerID).Return([]models.LoanParty{
+		{LoanID: coBorrowedLoan.ID, BorrowerID: borrowerID, Role: models.LoanPartyRoleCoBorrower},
+		{LoanID: guaranteedLoan.ID, BorrowerID: borrowerID, Role: models.LoanPartyRoleGuarantor},
+	}, nil)
+
+	// Call the service
+	exposure, err := services.NewBorrowerService(s.repoManager).GetBorrowerExposure(borrowerID)
+
+	// Assert results
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), exposure.Loans, 4)
+	assert.Equal(s.T(), models.LoanPartyRolePrimary, exposure.Loans[0].Role)
+	assert.Equal(s.T(), models.LoanPartyRoleCoBorrower, exposure.Loans[1].Role)
+	assert.Equal(s.T(), models.LoanPartyRoleGuarantor, exposure.Loans[2].Role)
+	assert.Equal(s.T(), int64(500000), exposure.DirectExposure)
+	assert.Equal(s.T(), int64(100000), exposure.ContingentExposure)
+	assert.Equal(s.T(), int64(600000), exposure.TotalExposure())
+
+	// Verify mock expectations
+	s.loanRepo.AssertExpectations(s.T())
+	s.partyRepo.AssertExpectations(s.T())
+}
+
+// TestMakeGroupPayment tests that a group payment is split across members' oldest installments
+func (s *LoanServiceTestSuite) TestMakeGroupPayment() {
+	// Prepare test data
+	groupID := uuid.New()
+	now := time.Now()
+	loanA := &models.Loan{ID: uuid.New(), BorrowerID: uuid.New(), Status: "active", CurrentBalance: 2000, GroupID: &groupID}
+	loanB := &models.Loan{ID: uuid.New(), BorrowerID: uuid.New(), Status: "active", CurrentBalance: 3000, GroupID: &groupID}
+	schedulesA := []models.Schedule{
+		{ID: uuid.New(), LoanID: loanA.ID, WeekNumber: 1, DueDate: now.AddDate(0, 0, -7), Amount: 1000, PrincipalAmount: 900, InterestAmount: 100},
+		{ID: uuid.New(), LoanID: loanA.ID, WeekNumber: 2, DueDate: now.AddDate(0, 0, 7), Amount: 1000, PrincipalAmount: 900, InterestAmount: 100},
+	}
+	schedulesB := []models.Schedule{
+		{ID: uuid.New(), LoanID: loanB.ID, WeekNumber: 1, DueDate: now.AddDate(0, 0, -3), Amount: 1500, PrincipalAmount: 1400, InterestAmount: 100},
+		{ID: uuid.New(), LoanID: loanB.ID, WeekNumber: 2, DueDate: now.AddDate(0, 0, 11), Amount: 1500, PrincipalAmount: 1400, InterestAmount: 100},
+	}
+
+	// Setup expectations
+	s.repoManager.On("WithTransaction", mock.AnythingOfType("func(repositories.RepositoryManager) error")).Return(nil)
+	s.groupRepo.On("GetByID", groupID).Return(&models.LoanGroup{ID: groupID}, nil)
+	s.loanRepo.On("GetByGroupID", groupID).Return([]models.Loan{*loanA, *loanB}, nil)
+	s.groupRepo.On("CreatePayment", mock.AnythingOfType("*models.GroupPayment")).Run(func(args mock.Arguments) {
+		args.Get(0).(*models.GroupPayment).ID = uuid.New()
+	}).Return(nil)
+	for _, loan := range []*models.Loan{loanA, loanB} {
+		schedules := schedulesA
+		if loan == loanB {
+			schedules = schedulesB
+		}
+		s.loanRepo.On("GetByID", loan.ID).Return(loan, nil)
+		s.scheduleRepo.On("GetUnpaidByLoanID", loan.ID).Return(schedules, nil)
+		s.scheduleRepo.On("UpdatePaidStatus", schedules[0].ID, true).Return(nil)
+		s.loanRepo.On("UpdateBalance", loan.ID, loan.CurrentBalance-schedules[0].Amount).Return(nil)
+		s.loanRepo.On("UpdateLastPaymentDate", loan.ID, mock.AnythingOfType("time.Time")).Return(nil)
+		s.scheduleRepo.On("CountUnpaidByLoanID", loan.ID).Return(int64(1), nil)
+		s.scheduleRepo.On("GetByLoanID", loan.ID).Return(schedules[1:], nil)
+		s.loanRepo.On("UpdateDelinquency", loan.ID, false, uint(0)).Return(nil)
+		s.loanRepo.On("GetByParty", loan.BorrowerID).Return([]models.Loan{*loan}, nil)
+		s.borrowerRepo.On("UpdateDelinquencyStatus", loan.BorrowerID, false).Return(nil)
+		s.partyRepo.On("GetByLoanID", loan.ID).Return([]models.LoanParty{}, nil)
+	}
+	s.paymentRepo.On("Create", mock.MatchedBy(func(payment *models.Payment) bool {
+		return payment.Source == models.PaymentSourceGroup && payment.GroupPaymentID != nil
+	})).Return(nil).Times(2)
+
+	// Call the service, as the field officer collecting: the oldest installment of each member adds up to the payment
+	collector := s.service.WithContext(auth.WithPrincipal(context.Background(), &auth.Principal{Type: auth.PrincipalStaff, Subject: "field.officer"}))
+	result, err := collector.MakeGroupPayment(groupID, 2500)
+
+	// Assert results
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), result.Payments, 2)
+	assert.Equal(s.T(), schedulesA[0].ID, result.Payments[0].ScheduleID)
+	assert.Equal(s.T(), schedulesB[0].ID, result.Payments[1].ScheduleID)
+	assert.Equal(s.T(), int64(1400), result.Payments[1].Principal)
+	assert.Equal(s.T(), result.GroupPayment.ID, *result.Payments[0].GroupPaymentID)
+	assert.Equal(s.T(), "staff:field.officer", result.GroupPayment.CollectedBy)
+
+	// An amount that does not add up to whole installments is refused
+	_, err = collector.MakeGroupPayment(groupID, 2000)
+	assert.ErrorIs(s.T(), err, services.ErrGroupPaymentMismatch)
+
+	// Verify mock expectations
+	s.loanRepo.AssertExpectations(s.T())
+	s.scheduleRepo.AssertExpectations(s.T())
+	s.paymentRepo.AssertExpectations(s.T())
+	s.groupRepo.AssertNumberOfCalls(s.T(), "CreatePayment", 1)
+}
+
+// TestCollateralLoanToValueAndRelease tests the loan-to-value ratio and that collateral is only released once the loan is paid
+func (s *LoanServiceTestSuite) TestCollateralLoanToValueAndRelease() {
+	loanID := uuid.New()
+	loan := &models.Loan{ID: loanID, Status: models.LoanStatusActive, CurrentBalance: 4000000}
+	vehicle := models.Collateral{ID: uuid.New(), LoanID: loanID, Type: models.CollateralTypeVehicle, Value: 5000000, LienStatus: models.LienStatusRegistered}
+	released := models.Collateral{ID: uuid.New(), LoanID: loanID, Type: models.CollateralTypeDeposit, Value: 3000000, LienStatus: models.LienStatusReleased}
+
+	s.loanRepo.On("GetByID", loanID).Return(loan, nil)
+	s.collateralRepo.On("GetByLoanID", loanID).Return([]models.Collateral{vehicle, released}, nil)
+	s.collateralRepo.On("GetByID", vehicle.ID).Return(&vehicle, nil)
+	s.collateralRepo.On("Update", mock.AnythingOfType("*models.Collateral")).Return(nil)
+
+	// Released collateral no longer secures the loan
+	position, err := s.service.GetCollateral(loanID)
+	s.NoError(err)
+	s.Equal(int64(5000000), position.TotalValue)
+	s.Require().NotNil(position.LoanToValue)
+	s.Equal(80.0, *position.LoanToValue)
+
+	// The lien stays while the loan is being repaid
+	_, err = s.service.UpdateLienStatus(loanID, vehicle.ID, models.LienStatusReleased)
+	s.ErrorIs(err, services.ErrLoanNotFullyPaid)
+	s.collateralRepo.AssertNotCalled(s.T(), "Update", mock.Anything)
+
+	loan.Status = models.LoanStatusClosed
+	collateral, err := s.service.UpdateLienStatus(loanID, vehicle.ID, models.LienStatusReleased)
+	s.NoError(err)
+	s.Equal(models.LienStatusReleased, collateral.LienStatus)
+	s.NotNil(collateral.ReleasedAt)
+}
+
+// TestApplyForLoanCreditChecks tests that failed credit rules are reported together and only delinquency can be overridden
+func (s *LoanServiceTestSuite) TestApplyForLoanCreditChecks() {
+	access, err := auth.DefaultPolicy()
+	s.Require().NoError(err)
+	s.service.WithPolicy(services.LoanPolicy{CreditLimit: 10000000, MaxConcurrentLoans: 1}).WithAccess(access)
+	borrowerID := uuid.New()
+	creditLimit := int64(5000000)
+	borrower := &models.Borrower{
+		ID:           borrowerID,
+		IsDelinquent: true,
+		CreditLimit:  &creditLimit,
+		Loans: []models.Loan{
+			{Status: models.LoanStatusActive, CurrentBalance: 4000000},
+			{Status: models.LoanStatusClosed, CurrentBalance: 0},
+		},
+	}
+	application := services.LoanApplication{BorrowerID: borrowerID, Amount: 1000000, InterestRate: 10.0, TermWeeks: 50}
+
+	s.borrowerRepo.On("GetByID", borrowerID).Return(borrower, nil)
+	s.productRepo.On("GetByCode", models.DefaultProductCode).Return(&models.LoanProduct{Code: models.DefaultProductCode}, nil)
+	s.feeRepo.On("GetDefinitionsByProductCode", models.DefaultProductCode).Return([]models.FeeDefinition{}, nil)
+
+	// Every failed rule is reported
+	_, err = s.service.ApplyForLoan(application)
+	var rejection *services.CreditRejection
+	s.Require().ErrorAs(err, &rejection)
+	s.ErrorIs(err, services.ErrCreditRejected)
+	codes := make([]string, 0, len(rejection.Reasons))
+	for _, reason := range rejection.Reasons {
+		codes = append(codes, reason.Code)
+	}
+	s.Equal([]string{services.RejectionCreditLimitExceeded, services.RejectionTooManyLoans, services.RejectionBorrowerDelinquent}, codes)
+
+	// Only callers with loans:approve may override, and the approver is always the caller
+	application.Override = &services.CreditOverride{Reason: "Hardship cleared"}
+	teller := auth.WithPrincipal(context.Background(), &auth.Principal{Type: auth.PrincipalStaff, Subject: "teller", Roles: []string{"loan_officer"}})
+	_, err = s.service.WithContext(teller).ApplyForLoan(application)
+	s.ErrorIs(err, auth.ErrForbidden)
+
+	// An override does not lift the credit limit or the loan cap
+	riskOfficer := s.service.WithContext(auth.WithPrincipal(context.Background(), &auth.Principal{Type: auth.PrincipalStaff, Subject: "risk.officer", Roles: []string{"approver"}}))
+	_, err = riskOfficer.ApplyForLoan(application)
+	s.Require().ErrorAs(err, &rejection)
+	s.Len(rejection.Reasons, 2)
+	s.repoManager.AssertNotCalled(s.T(), "WithTransaction", mock.Anything)
+
+	// Within limits, the override lets the delinquent borrower through and is recorded
+	borrower.CreditLimit = nil
+	borrower.Loans = borrower.Loans[1:]
+	s.repoManager.On("WithTransaction", mock.AnythingOfType("func(repositories.RepositoryManager) error")).Return(nil)
+	s.loanRepo.On("Create", mock.AnythingOfType("*models.Loan")).Return(nil)
+	s.scheduleRepo.On("CreateBatch", mock.AnythingOfType("[]models.Schedule")).Return(nil)
+	s.feeRepo.On("CreateBatch", mock.AnythingOfType("[]models.LoanFee")).Return(nil)
+	s.partyRepo.On("CreateBatch", []models.LoanParty{}).Return(nil)
+
+	loan, err := riskOfficer.ApplyForLoan(application)
+	s.NoError(err)
+	s.Equal("staff:risk.officer", loan.CreditOverrideBy)
+	s.Equal("Hardship cleared", loan.CreditOverrideReason)
+}
+
+// TestApplyForLoanScoring tests that an application is scored on the borrower's repayment history and that referrals need an override
+func (s *LoanServiceTestSuite) TestApplyForLoanScoring() {
+	scorecard, err := scoring.Parse([]byte(`
+version: test-1
+base_score: 500
+approve_from: 550
+decline_below: 450
+grades:
+  - name: B
+    min_score: 500
+  - name: C
+    min_score: 0
+rules:
+  - name: Repayment record
+    factor: on_time_ratio
+    bands:
+      - min: 0.9
+        points: 70
+        reason: Pays on time
+      - min: 0.5
+        max: 0.9
+        points: 0
+        reason: Sometimes late
+      - max: 0.5
+        points: -100
+        reason: Usually late
+`), "yaml")
+	s.Require().NoError(err)
+	s.service.WithScorer(scorecard)
+
+	// A repaid loan with one of four installments paid 20 days late
+	borrowerID := uuid.New()
+	previousLoanID := uuid.New()
+	start := time.Now().AddDate(0, -3, 0)
+	var schedules []models.Schedule
+	var payments []models.Payment
+	for week := 1; week <= 4; week++ {
+		schedule := models.Schedule{ID: uuid.New(), LoanID: previousLoanID, DueDate: start.AddDate(0, 0, 7*week), Paid: true}
+		paidOn := schedule.DueDate
+		if week == 2 {
+			paidOn = paidOn.AddDate(0, 0, 20)
+		}
+		schedules = append(schedules, schedule)
+		payments = append(payments, models.Payment{LoanID: previousLoanID, ScheduleID: schedule.ID, PaymentDate: paidOn})
+	}
+	borrower := &models.Borrower{
+		ID:        borrowerID,
+		CreatedAt: start,
+		Loans:     []models.Loan{{ID: previousLoanID, Status: models.LoanStatusClosed}},
+	}
+	application := services.LoanApplication{BorrowerID: borrowerID, Amount: 1000000, InterestRate: 10.0, TermWeeks: 50}
+
+	s.borrowerRepo.On("GetByID", borrowerID).Return(borrower, nil)
+	s.productRepo.On("GetByCode", models.DefaultProductCode).Return(&models.LoanProduct{Code: models.DefaultProductCode}, nil)
+	s.feeRepo.On("GetDefinitionsByProductCode", models.DefaultProductCode).Return([]models.FeeDefinition{}, nil)
+	s.scheduleRepo.On("GetByLoanID", previousLoanID).Return(schedules, nil)
+	s.paymentRepo.On("GetByLoanID", previousLoanID).Return(payments, nil)
+
+	// Three of four on time is referred, and a referral needs an override
+	_, err = s.service.ApplyForLoan(application)
+	var rejection *services.CreditRejection
+	s.Require().ErrorAs(err, &rejection)
+	s.Equal(services.RejectionScoreReferred, rejection.Reasons[0].Code)
+	s.Require().NotNil(rejection.Assessment)
+	s.Equal(500, rejection.Assessment.Score)
+	s.Equal("B", rejection.Assessment.Grade)
+	s.Equal(scoring.DecisionRefer, rejection.Assessment.Decision)
+	s.Equal("Sometimes late", rejection.Assessment.Contributions[0].Reason)
+	s.InDelta(0.75, *rejection.Assessment.Contributions[0].Value, 0.0001)
+
+	// With an override the loan is created and its assessment kept
+	var saved *models.CreditAssessment
+	loanID := uuid.New()
+	s.repoManager.On("WithTransaction", mock.AnythingOfType("func(repositories.RepositoryManager) error")).Return(nil)
+	s.loanRepo.On("Create", mock.AnythingOfType("*models.Loan")).Run(func(args mock.Arguments) {
+		args.Get(0).(*models.Loan).ID = loanID
+	}).Return(nil)
+	s.scheduleRepo.On("CreateBatch", mock.AnythingOfType("[]models.Schedule")).Return(nil)
+	s.feeRepo.On("CreateBatch", mock.AnythingOfType("[]models.LoanFee")).Return(nil)
+	s.partyRepo.On("CreateBatch", []models.LoanParty{}).Return(nil)
+	s.assessmentRepo.On("Create", mock.AnythingOfType("*models.CreditAssessment")).Run(func(args mock.Arguments) {
+		saved = args.Get(0).(*models.CreditAssessment)
+	}).Return(nil)
+
+	application.Override = &services.CreditOverride{Reason: "Late payment explained"}
+	riskOfficer := auth.WithPrincipal(context.Background(), &auth.Principal{Type: auth.PrincipalStaff, Subject: "risk.officer"})
+	loan, err := s.service.WithContext(riskOfficer).ApplyForLoan(application)
+
+	s.NoError(err)
+	s.Equal("staff:risk.officer", loan.CreditOverrideBy)
+	s.Require().NotNil(saved)
+	s.Equal(loanID, saved.LoanID)
+	s.Equal("test-1", saved.ScorecardVersion)
+	s.Equal("staff:risk.officer", saved.ReferralApprovedBy)
+}
+
+// TestReviewKYC tests that verification needs a valid identity document, lapses with it and is reset by identity changes
+func (s *LoanServiceTestSuite) TestReviewKYC() {
+	borrowerService := services.NewBorrowerService(s.repoManager)
+	borrowerID := uuid.New()
+	dateOfBirth := time.Date(1990, 4, 21, 0, 0, 0, 0, time.UTC)
+	borrower := &models.Borrower{
+		ID:             borrowerID,
+		Name:           "Siti Rahma",
+		Phone:          "+6281234567890",
+		NationalIDType: models.NationalIDTypeNationalID,
+		NationalID:     "3171234567890001",
+		DateOfBirth:    &dateOfBirth,
+		KYCStatus:      models.KYCStatusPending,
+		Addresses:      []models.BorrowerAddress{{Type: models.AddressTypeResidential, Line1: "Jl. Sudirman 1", City: "Jakarta", Country: "ID"}},
+	}
+	passportExpiry := time.Now().AddDate(0, 2, 0)
+	expiredLicense := time.Now().AddDate(0, -1, 0)
+
+	s.borrowerRepo.On("GetByID", borrowerID).Return(borrower, nil)
+	s.borrowerRepo.On("Update", borrower).Return(nil)
+	s.kycRepo.On("GetDocuments", borrowerID).Return([]models.BorrowerDocument{
+		{Type: models.DocumentTypePayslip},
+		{Type: models.DocumentTypeDriversLicense, ExpiresAt: &expiredLicense},
+	}, nil).Once()
+
+	// Payslips and expired licences do not prove identity
+	_, err := borrowerService.ReviewKYC(borrowerID, services.KYCReview{Status: models.KYCStatusVerified})
+	s.ErrorIs(err, services.ErrKYCIncomplete)
+
+	// A passport does, and the verification lapses with it
+	s.kycRepo.On("GetDocuments", borrowerID).Return([]models.BorrowerDocument{
+		{Type: models.DocumentTypePassport, ExpiresAt: &passportExpiry},
+	}, nil)
+	analyst := auth.WithPrincipal(context.Background(), &auth.Principal{Type: auth.PrincipalStaff, Subject: "kyc.analyst"})
+	verified, err := borrowerService.WithContext(analyst).ReviewKYC(borrowerID, services.KYCReview{Status: models.KYCStatusVerified})
+	s.Require().NoError(err)
+	s.Equal(models.KYCStatusVerified, verified.KYCStatus)
+	s.Equal("staff:kyc.analyst", verified.KYCReviewedBy)
+	s.Equal(passportExpiry, *verified.KYCExpiresAt)
+	s.Equal(models.KYCStatusExpired, verified.KYCStatusAt(passportExpiry))
+
+	// Verified borrowers cannot be rejected outright
+	_, err = borrowerService.ReviewKYC(borrowerID, services.KYCReview{Status: models.KYCStatusRejected, Reason: "Mismatch"})
+	s.ErrorIs(err, services.ErrInvalidKYCTransition)
+
+	// Contact changes keep the verification, identity changes reset it
+	profile := services.BorrowerProfile{
+		Name:           "Siti Rahma",
+		Phone:          "+6281234567899",
+		NationalIDType: models.NationalIDTypeNationalID,
+		NationalID:     "3171234567890001",
+		DateOfBirth:    &dateOfBirth,
+	}
+	updated, err := borrowerService.UpdateProfile(borrowerID, profile)
+	s.Require().NoError(err)
+	s.Equal(models.KYCStatusVerified, updated.KYCStatus)
+
+	profile.NationalID = "3171234567890002"
+	updated, err = borrowerService.UpdateProfile(borrowerID, profile)
+	s.Require().NoError(err)
+	s.Equal(models.KYCStatusPending, updated.KYCStatus)
+	s.Nil(updated.KYCExpiresAt)
+
+	// Profiles are validated field by field
+	profile.Phone = "0812-3456"
+	_, err = borrowerService.UpdateProfile(borrowerID, profile)
+	s.ErrorIs(err, services.ErrInvalidProfile)
+}
+
+// TestBorrowerDuplicatesAndMerge tests that duplicates are caught on create and merged with their loans
+func (s *LoanServiceTestSuite) TestBorrowerDuplicatesAndMerge() {
+	borrowerService := services.NewBorrowerService(s.repoManager)
+	dateOfBirth := time.Date(1990, 4, 21, 0, 0, 0, 0, time.UTC)
+	otherDateOfBirth := time.Date(1985, 1, 2, 0, 0, 0, 0, time.UTC)
+	existing := models.Borrower{
+		ID:             uuid.New(),
+		Name:           "Siti Rahma",
+		Phone:          "+6281234567890",
+		NationalIDType: models.NationalIDTypeNationalID,
+		NationalID:     "3171234567890001",
+		DateOfBirth:    &dateOfBirth,
+		IsDelinquent:   true,
+	}
+	namesake := models.Borrower{ID: uuid.New(), Name: "Siti Rahma", DateOfBirth: &otherDateOfBirth}
+	s.borrowerRepo.On("FindMatches", mock.Anything).Return([]models.Borrower{namesake, existing}, nil)
+
+	// A similar name with the same birthday needs confirming; a namesake born elsewhere is ignored
+	profile := services.BorrowerProfile{Name: "Rahma, Sity", Phone: "+6281234567899", DateOfBirth: &dateOfBirth}
+	_, err := borrowerService.CreateBorrower(profile, nil, false)
+	var duplicate *services.DuplicateError
+	s.Require().ErrorAs(err, &duplicate)
+	s.ErrorIs(err, services.ErrPossibleDuplicate)
+	s.Require().Len(duplicate.Candidates, 1)
+	s.Equal(existing.ID, duplicate.Candidates[0].Borrower.ID)
+	s.Equal([]string{services.MatchName}, duplicate.Candidates[0].Reasons)
+
+	s.borrowerRepo.On("Create", mock.AnythingOfType("*models.Borrower")).Return(nil).Once()
+	created, err := borrowerService.CreateBorrower(profile, nil, true)
+	s.Require().NoError(err)
+	s.Equal("Rahma, Sity", created.Name)
+
+	// A shared national ID cannot be confirmed past
+	profile.NationalIDType = models.NationalIDTypeNationalID
+	profile.NationalID = "3171234567890001"
+	_, err = borrowerService.CreateBorrower(profile, nil, true)
+	s.ErrorIs(err, services.ErrDuplicateBorrower)
+
+	// Merging moves the duplicate's loans and carries over its delinquency
+	target := &models.Borrower{ID: uuid.New(), Name: "Siti Rahma"}
+	source := &existing
+	s.borrowerRepo.On("GetByID", target.ID).Return(target, nil)
+	s.borrowerRepo.On("GetByID", source.ID).Return(source, nil)
+	s.repoManager.On("WithTransaction", mock.AnythingOfType("func(repositories.RepositoryManager) error")).Return(nil)
+	s.mergeRepo.On("Reassign", source.ID, target.ID).Return(repositories.MergeCounts{Loans: 2, Parties: 1}, nil)
+	s.borrowerRepo.On("UpdateDelinquencyStatus", target.ID, true).Return(nil)
+	s.borrowerRepo.On("Update", source).Return(nil)
+	s.borrowerRepo.On("Delete", source.ID).Return(nil)
+	s.mergeRepo.On("Create", mock.AnythingOfType("*models.BorrowerMerge")).Return(nil)
+
+	_, err = borrowerService.MergeBorrowers(target.ID, target.ID, services.BorrowerMergeRequest{})
+	s.ErrorIs(err, services.ErrInvalidMerge)
+
+	admin := auth.WithPrincipal(context.Background(), &auth.Principal{Type: auth.PrincipalStaff, Subject: "ops.admin"})
+	merge, err := borrowerService.WithContext(admin).MergeBorrowers(target.ID, source.ID, services.BorrowerMergeRequest{Reason: "Same KTP"})
+	s.Require().NoError(err)
+	s.Equal("staff:ops.admin", merge.MergedBy)
+	s.Equal(int64(2), merge.LoansMoved)
+	s.Equal(int64(1), merge.PartiesMoved)
+	s.Equal("3171234567890001", merge.SourceSnapshot.NationalID)
+	s.Equal(target.ID, *source.MergedIntoID)
+	s.borrowerRepo.AssertCalled(s.T(), "UpdateDelinquencyStatus", target.ID, true)
+	s.borrowerRepo.AssertCalled(s.T(), "Delete", source.ID)
+}
+
+// TestBorrowerErasure tests that erasure waits for open loans and the retention period
+func (s *LoanServiceTestSuite) TestBorrowerErasure() {
+	borrowerService := services.NewBorrowerService(s.repoManager).WithPolicy(services.BorrowerPolicy{RetentionDays: 30})
+	now := time.Now()
+	waiting := &models.Borrower{ID: uuid.New(), Name: "Siti Rahma", CreatedAt: now.AddDate(-2, 0, 0)}
+	ready := &models.Borrower{ID: uuid.New(), Name: "Budi Santoso", CreatedAt: now.AddDate(-2, 0, 0)}
+	paidOff := models.Loan{ID: uuid.New(), Status: models.LoanStatusClosed, UpdatedAt: now.AddDate(0, 0, -10)}
+	open := models.Loan{ID: uuid.New(), Status: models.LoanStatusActive, UpdatedAt: now.AddDate(0, 0, -90)}
+
+	s.borrowerRepo.On("GetByID", waiting.ID).Return(waiting, nil)
+	s.borrowerRepo.On("GetByID", ready.ID).Return(ready, nil)
+	s.borrowerRepo.On("Update", mock.AnythingOfType("*models.Borrower")).Return(nil)
+	s.loanRepo.On("GetByParty", waiting.ID).Return([]models.Loan{paidOff, open}, nil).Once()
+	s.repoManager.On("WithTransaction", mock.AnythingOfType("func(repositories.RepositoryManager) error")).Return(nil)
+
+	// An open loan holds erasure back with no date yet
+	dpo := auth.WithPrincipal(context.Background(), &auth.Principal{Type: auth.PrincipalStaff, Subject: "dpo"})
+	status, err := borrowerService.WithContext(dpo).RequestErasure(waiting.ID)
+	s.Require().NoError(err)
+	s.Equal("staff:dpo", waiting.ErasureRequestedBy)
+	s.False(status.Anonymized)
+	s.Equal(1, status.OpenLoans)
+	s.Nil(status.EligibleAt)
+	s.NotNil(waiting.ErasureRequestedAt)
+
+	// Once it closes, the retention period runs from the last loan activity
+	s.loanRepo.On("GetByParty", waiting.ID).Return([]models.Loan{paidOff}, nil)
+	status, err = borrowerService.GetErasureStatus(waiting.ID)
+	s.Require().NoError(err)
+	s.Equal(paidOff.UpdatedAt.AddDate(0, 0, 30), *status.EligibleAt)
+
+	// A borrower past retention is anonymized straight away, keeping their loans
+	s.loanRepo.On("GetByParty", ready.ID).Return([]models.Loan{{ID: uuid.New(), Status: models.LoanStatusClosed, UpdatedAt: now.AddDate(0, -3, 0)}}, nil)
+	s.erasureRepo.On("Anonymize", ready.ID, mock.AnythingOfType("time.Time")).Run(func(args mock.Arguments) {
+		at := args.Get(1).(time.Time)
+		ready.Name = models.AnonymizedName
+		ready.AnonymizedAt = &at
+	}).Return(nil).Once()
+	status, err = borrowerService.WithContext(dpo).RequestErasure(ready.ID)
+	s.Require().NoError(err)
+	s.True(status.Anonymized)
+	s.Equal(models.AnonymizedName, status.Borrower.Name)
+
+	_, err = borrowerService.WithContext(dpo).RequestErasure(ready.ID)
+	s.ErrorIs(err, services.ErrAlreadyAnonymized)
+	_, err = borrowerService.UpdateProfile(ready.ID, services.BorrowerProfile{Name: "Budi Santoso", Phone: "+6281234567890"})
+	s.ErrorIs(err, services.ErrAlreadyAnonymized)
+
+	// The nightly job skips requests still inside the retention period
+	s.erasureRepo.On("FindCandidates", mock.AnythingOfType("time.Time"), true).Return([]models.Borrower{*waiting}, nil)
+	result, err := borrowerService.AnonymizeEligible(now)
+	s.Require().NoError(err)
+	s.Equal(0, result.Anonymized)
+	s.erasureRepo.On("Anonymize", waiting.ID, mock.AnythingOfType("time.Time")).Return(nil)
+	result, err = borrowerService.AnonymizeEligible(now.AddDate(0, 0, 21))
+	s.Require().NoError(err)
+	s.Equal(1, result.Anonymized)
+}
+
+// TestVerifyAuditLog tests that the audit chain check catches altered entries and rewritten logs
+func (s *LoanServiceTestSuite) TestVerifyAuditLog() {
+	auditService := services.NewAuditService(s.repoManager)
+	chain := func() []models.AuditEntry {
+		entries := make([]models.AuditEntry, 3)
+		prevHash := ""
+		for i := range entries {
+			entries[i] = models.AuditEntry{
+				ID:         uuid.New(),
+				Sequence:   int64(i + 1),
+				Actor:      "ops.admin",
+				Action:     models.AuditActionUpdate,
+				EntityType: "loans",
+				EntityID:   uuid.NewString(),
+				Changes:    `{"status":{"old":"active","new":"defaulted"}}`,
+				PrevHash:   prevHash,
+				CreatedAt:  time.Now(),
+			}
+			entries[i].Hash = audit.Hash(&entries[i])
+			prevHash = entries[i].Hash
+		}
+		return entries
+	}
+
+	// An intact chain reports its head as the next anchor
+	entries := chain()
+	s.auditRepo.On("GetChain", int64(0), 1000).Return(entries, nil).Once()
+	result, err := auditService.VerifyAuditLog(nil)
+	s.Require().NoError(err)
+	s.True(result.Valid)
+	s.Equal(int64(3), result.Checked)
+	s.Equal(int64(3), result.HeadSequence)
+	s.Equal(entries[2].Hash, result.HeadHash)
+
+	// Editing an entry breaks the chain at that entry
+	tampered := chain()
+	tampered[1].Changes = `{"status":{"old":"active","new":"closed"}}`
+	s.auditRepo.On("GetChain", int64(0), 1000).Return(tampered, nil).Once()
+	result, err = auditService.VerifyAuditLog(nil)
+	s.Require().NoError(err)
+	s.False(result.Valid)
+	s.Equal(int64(1), result.Checked)
+	s.Contains(result.Problem, "entry 2 has been altered")
+
+	// Removing an entry leaves a gap
+	s.auditRepo.On("GetChain", int64(0), 1000).Return([]models.AuditEntry{entries[0], entries[2]}, nil).Once()
+	result, err = auditService.VerifyAuditLog(nil)
+	s.Require().NoError(err)
+	s.False(result.Valid)
+	s.Contains(result.Problem, "expected entry 2")
+
+	// A chain recomputed from scratch is consistent but no longer matches an earlier anchor
+	anchor := &services.AuditAnchor{Sequence: 2, Hash: entries[1].Hash}
+	s.auditRepo.On("GetChain", int64(0), 1000).Return(chain(), nil).Once()
+	result, err = auditService.VerifyAuditLog(anchor)
+	s.Require().NoError(err)
+	s.False(result.Valid)
+	s.Contains(result.Problem, "rewritten")
+
+	s.auditRepo.On("GetChain", int64(0), 1000).Return(entries, nil).Once()
+	result, err = auditService.VerifyAuditLog(anchor)
+	s.Require().NoError(err)
+	s.True(result.Valid)
+
+	// A log cut short before the anchor is truncated
+	s.auditRepo.On("GetChain", int64(0), 1000).Return(entries[:1], nil).Once()
+	result, err = auditService.VerifyAuditLog(anchor)
+	s.Require().NoError(err)
+	s.False(result.Valid)
+	s.Contains(result.Problem, "truncated")
+}
+
+// TestAuthenticateAPIKey tests that only active keys matching their stored hash authenticate
+func (s *LoanServiceTestSuite) TestAuthenticateAPIKey() {
+	service := services.NewAuthService(s.repoManager, nil)
+	key, prefix, err := auth.GenerateAPIKey()
+	s.Require().NoError(err)
+	stored := &models.APIKey{ID: uuid.New(), TenantID: "alpha", Name: "Partner", Prefix: prefix, Hash: auth.HashAPIKey(key), Roles: []string{"payments"}}
+
+	s.apiKeyRepo.On("GetByPrefix", prefix).Return(stored, nil)
+	s.apiKeyRepo.On("TouchLastUsed", stored.ID, mock.Anything).Return(nil).Once()
+
+	principal, err := service.AuthenticateAPIKey(key)
+	s.Require().NoError(err)
+	s.Equal(auth.PrincipalAPIKey, principal.Type)
+	s.Equal("api_key:"+prefix, principal.Actor())
+	s.True(principal.HasRole("payments"))
+	s.Equal("alpha", principal.Tenant) // Keys act for the tenant they were issued in
+
+	// Used moments ago, so last use is not written again
+	recently := time.Now().Add(-10 * time.Second)
+	stored.LastUsedAt = &recently
+	_, err = service.AuthenticateAPIKey(key)
+	s.NoError(err)
+	s.apiKeyRepo.AssertNumberOfCalls(s.T(), "TouchLastUsed", 1)
+
+	// Right prefix, wrong secret
+	_, err = service.AuthenticateAPIKey("lbk_" + prefix + "_forged")
+	s.ErrorIs(err, auth.ErrInvalidAPIKey)
+
+	revoked := time.Now().Add(-time.Hour)
+	stored.RevokedAt = &revoked
+	_, err = service.AuthenticateAPIKey(key)
+	s.ErrorIs(err, auth.ErrInvalidAPIKey)
+
+	stored.RevokedAt = nil
+	stored.ExpiresAt = &revoked
+	_, err = service.AuthenticateAPIKey(key)
+	s.ErrorIs(err, auth.ErrInvalidAPIKey)
+}
+
+// TestCreateAPIKey tests that staff can issue keys, stored only as a hash, and partners cannot
+func (s *LoanServiceTestSuite) TestCreateAPIKey() {
+	service := services.NewAuthService(s.repoManager, nil)
+	staff := auth.WithPrincipal(context.Background(), &auth.Principal{Type: auth.PrincipalStaff, Subject: "jane"})
+	partner := auth.WithPrincipal(context.Background(), &auth.Principal{Type: auth.PrincipalAPIKey, Subject: "0123456789ab"})
+
+	_, _, err := service.CreateAPIKey(services.APIKeyRequest{Name: "Partner"})
+	s.ErrorIs(err, auth.ErrForbidden)
+	_, _, err = service.WithContext(partner).CreateAPIKey(services.APIKeyRequest{Name: "Partner"})
+	s.ErrorIs(err, auth.ErrForbidden)
+
+	past := time.Now().Add(-time.Hour)
+	_, _, err = service.WithContext(staff).CreateAPIKey(services.APIKeyRequest{Name: "Partner", ExpiresAt: &past})
+	s.ErrorIs(err, services.ErrInvalidAPIKeyRequest)
+
+	s.apiKeyRepo.On("Create", mock.AnythingOfType("*models.APIKey")).Return(nil).Once()
+	record, key, err := service.WithContext(staff).CreateAPIKey(services.APIKeyRequest{Name: " Partner "})
+	s.Require().NoError(err)
+	s.Equal("Partner", record.Name)
+	s.Equal("staff:jane", record.CreatedBy)
+	s.NotEqual(key, record.Hash)
+	s.True(auth.MatchAPIKey(key, record.Hash))
+	prefix, err := auth.ParseAPIKey(key)
+	s.Require().NoError(err)
+	s.Equal(prefix, record.Prefix)
+}
+
+// TestQuoteLoanFollowsTenantCalendar tests that installments falling on a rest day or holiday of the tenant are due on the next working day
+func (s *LoanServiceTestSuite) TestQuoteLoanFollowsTenantCalendar() {
+	// Every installment falls on the weekday of the start date, which is a rest day
+	today := time.Now().Weekday()
+	restDay := strings.ToLower(today.String())
+	secondDue := time.Now().AddDate(0, 0, 14)
+	holiday := time.Date(secondDue.Year(), secondDue.Month(), secondDue.Day()+1, 0, 0, 0, 0, time.UTC)
+	s.tenantRepo.On("GetByID", "alpha").Return(&models.Tenant{ID: "alpha", RestDays: []string{restDay}}, nil)
+	s.tenantRepo.On("GetHolidays", "alpha").Return([]models.CalendarHoliday{{TenantID: "alpha", Date: holiday, Name: "Founding Day"}}, nil)
+	s.productRepo.On("GetByCode", models.DefaultProductCode).Return(&models.LoanProduct{Code: models.DefaultProductCode}, nil)
+	s.feeRepo.On("GetDefinitionsByProductCode", models.DefaultProductCode).Return([]models.FeeDefinition{}, nil)
+
+	// Call the service as the tenant
+	quote, err := s.service.WithContext(tenant.WithID(context.Background(), "alpha")).QuoteLoan("", 5000000, 10.0, 4)
+
+	// Assert results
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), quote.Schedule, 4)
+	for _, installment := range quote.Schedule {
+		assert.NotEqual(s.T(), today, installment.DueDate.Weekday())
+	}
+	assert.Equal(s.T(), quote.StartDate.AddDate(0, 0, 8), quote.Schedule[0].DueDate)
+	assert.Equal(s.T(), quote.StartDate.AddDate(0, 0, 16), quote.Schedule[1].DueDate) // Past the rest day and the holiday after it
+	assert.Equal(s.T(), quote.StartDate.AddDate(0, 0, 22), quote.Schedule[2].DueDate)
+
+	// Callers of an unknown tenant cannot lay out schedules
+	s.tenantRepo.On("GetByID", "ghost").Return(nil, errors.New("record not found"))
+	_, err = s.service.WithContext(tenant.WithID(context.Background(), "ghost")).QuoteLoan("", 5000000, 10.0, 4)
+	assert.ErrorIs(s.T(), err, services.ErrTenantNotFound)
+}
+
+// TestUpdateRestDays tests that rest days must be distinct weekdays leaving a working day
+func (s *LoanServiceTestSuite) TestUpdateRestDays() {
+	t := &models.Tenant{ID: "alpha", RestDays: []string{}}
+	s.tenantRepo.On("GetByID", "alpha").Return(t, nil)
+	s.tenantRepo.On("GetHolidays", "alpha").Return([]models.CalendarHoliday{}, nil)
+	s.tenantRepo.On("Update", t).Return(nil).Once()
+	service := s.service.WithContext(tenant.WithID(context.Background(), "alpha"))
+
+	for _, restDays := range [][]string{
+		{"someday"},
+		{"sunday", "Sunday"},
+		{"sunday", "monday", "tuesday", "wednesday", "thursday", "friday", "saturday"},
+	} {
+		_, err := service.UpdateRestDays(restDays)
+		assert.ErrorIs(s.T(), err, services.ErrInvalidCalendar, "%v", restDays)
+	}
+
+	calendar, err := service.UpdateRestDays([]string{" Sunday ", "friday"})
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), []string{"sunday", "friday"}, calendar.Tenant.RestDays)
+	s.tenantRepo.AssertExpectations(s.T())
+}
+
+// TestSensitiveActionsNeedPermission tests that services check the caller's permissions under the access policy
+func (s *LoanServiceTestSuite) TestSensitiveActionsNeedPermission() {
+	access, err := auth.DefaultPolicy()
+	s.Require().NoError(err)
+	service := s.service.WithAccess(access)
+	officer := auth.WithPrincipal(context.Background(), &auth.Principal{Type: auth.PrincipalStaff, Subject: "joe", Roles: []string{"loan_officer"}})
+	approver := auth.WithPrincipal(context.Background(), &auth.Principal{Type: auth.PrincipalStaff, Subject: "ann", Roles: []string{"approver"}})
+
+	_, err = service.WithContext(officer).WriteOffLoan(uuid.New(), "Uncollectable")
+	s.ErrorIs(err, auth.ErrForbidden)
+	_, err = service.WithContext(approver).WriteOffLoan(uuid.New(), "Uncollectable")
+	s.ErrorIs(err, auth.ErrForbidden)
+	_, err = service.WithContext(officer).ReviewChargeOff(uuid.New(), true, "")
+	s.ErrorIs(err, auth.ErrForbidden)
+	_, err = service.WithContext(officer).GrantPaymentHoliday(uuid.New(), services.HolidayTerms{Installments: 2, ReasonCode: models.HolidayReasonMedical})
+	s.ErrorIs(err, auth.ErrForbidden)
+	collector := auth.WithPrincipal(context.Background(), &auth.Principal{Type: auth.PrincipalStaff, Subject: "sam", Roles: []string{"collector"}})
+	_, err = service.WithContext(collector).ReversePayment(uuid.New(), "Bounced")
+	s.ErrorIs(err, auth.ErrForbidden)
+	borrowerID := uuid.New()
+	s.borrowerRepo.On("GetByID", borrowerID).Return(&models.Borrower{ID: borrowerID, IsDelinquent: true}, nil)
+	s.productRepo.On("GetByCode", models.DefaultProductCode).Return(&models.LoanProduct{Code: models.DefaultProductCode}, nil)
+	s.feeRepo.On("GetDefinitionsByProductCode", models.DefaultProductCode).Return([]models.FeeDefinition{}, nil)
+	_, err = service.WithContext(officer).ApplyForLoan(services.LoanApplication{
+		BorrowerID:   borrowerID,
+		Amount:       1000000,
+		InterestRate: 10.0,
+		TermWeeks:    50,
+		Override:     &services.CreditOverride{Reason: "Known customer"},
+	})
+	s.ErrorIs(err, auth.ErrForbidden)
+
+	// Approvers get past the permission check to the charge-off itself
+	proposalID := uuid.New()
+	s.repoManager.On("WithTransaction", mock.Anything).Return(nil).Once()
+	s.chargeRepo.On("GetByID", proposalID).Return(nil, errors.New("not found")).Once()
+	_, err = service.WithContext(approver).ReviewChargeOff(proposalID, true, "")
+	s.ErrorIs(err, services.ErrChargeOffNotFound)
+}
+
 func TestLoanServiceSuite(t *testing.T) {
 	suite.Run(t, new(LoanServiceTestSuite))
 }
@@ -0,0 +1,170 @@
+package tenant_test
+
+import (
+	"context"
+	"errors"
+	"loan-billing-system/internal/models"
+	"loan-billing-system/internal/tenant"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// PluginTestSuite defines the test suite for the tenant plugin
+type PluginTestSuite struct {
+	suite.Suite
+	DB *gorm.DB
+}
+
+// SetupSuite prepares an in-memory database with the tenant plugin before any tests run
+func (s *PluginTestSuite) SetupSuite() {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		s.T().Fatal(err)
+	}
+
+	// Keep a single connection so every query sees the same in-memory database
+	sqlDB, err := db.DB()
+	if err != nil {
+		s.T().Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+
+	if err := db.Use(tenant.Plugin{}); err != nil {
+		s.T().Fatal(err)
+	}
+
+	// Create tables manually for SQLite compatibility
+	db.Exec(`CREATE TABLE loan_products (
+		code TEXT NOT NULL,
+		tenant_id TEXT NOT NULL DEFAULT 'default',
+		name TEXT NOT NULL,
+		default_after_dpd INTEGER NOT NULL DEFAULT 0,
+		charge_off_after_dpd INTEGER NOT NULL DEFAULT 0,
+		created_at DATETIME,
+		updated_at DATETIME,
+		PRIMARY KEY (tenant_id, code)
+	)`)
+
+	s.DB = db
+}
+
+// TearDownTest cleans up after each test
+func (s *PluginTestSuite) TearDownTest() {
+	s.DB.Exec("DELETE FROM loan_products")
+}
+
+// as returns the database acting for a tenant
+func (s *PluginTestSuite) as(tenantID string) *gorm.DB {
+	return s.DB.WithContext(tenant.WithID(context.Background(), tenantID))
+}
+
+// createProducts creates one product for each of two tenants
+func (s *PluginTestSuite) createProducts() {
+	s.Require().NoError(s.as("alpha").Create(&models.LoanProduct{Code: "alpha-weekly", Name: "Alpha weekly"}).Error)
+	s.Require().NoError(s.as("beta").Create(&models.LoanProduct{Code: "beta-weekly", Name: "Beta weekly"}).Error)
+}
+
+// TestCreateAssignsTenant tests that created rows belong to the caller's tenant
+func (s *PluginTestSuite) TestCreateAssignsTenant() {
+	product := models.LoanProduct{Code: "alpha-weekly", Name: "Alpha weekly"}
+	s.Require().NoError(s.as("alpha").Create(&product).Error)
+	s.Equal("alpha", product.TenantID)
+
+	var stored models.LoanProduct
+	s.Require().NoError(s.DB.Where("code = ?", "alpha-weekly").First(&stored).Error)
+	s.Equal("alpha", stored.TenantID)
+}
+
+// TestCreateRefusesRowsOfNoOrAnotherTenant tests that rows cannot be created without a tenant or for another one
+func (s *PluginTestSuite) TestCreateRefusesRowsOfNoOrAnotherTenant() {
+	err := s.DB.Create(&models.LoanProduct{Code: "orphan", Name: "Orphan"}).Error
+	s.True(errors.Is(err, tenant.ErrNoTenant))
+
+	err = s.as("alpha").Create(&models.LoanProduct{TenantID: "beta", Code: "planted", Name: "Planted"}).Error
+	s.True(errors.Is(err, tenant.ErrCrossTenant))
+
+	// Without a tenant in the context, a row naming its tenant is fine
+	s.NoError(s.DB.Create(&models.LoanProduct{TenantID: "beta", Code: "seeded", Name: "Seeded"}).Error)
+
+	var count int64
+	s.DB.Model(&models.LoanProduct{}).Count(&count)
+	s.Equal(int64(1), count)
+}
+
+// TestQueriesOnlySeeOwnTenant tests that reads never return another tenant's rows
+func (s *PluginTestSuite) TestQueriesOnlySeeOwnTenant() {
+	s.createProducts()
+
+	var product models.LoanProduct
+	err := s.as("beta").Where("code = ?", "alpha-weekly").First(&product).Error
+	s.True(errors.Is(err, gorm.ErrRecordNotFound))
+
+	var products []models.LoanProduct
+	s.Require().NoError(s.as("beta").Find(&products).Error)
+	s.Require().Len(products, 1)
+	s.Equal("beta-weekly", products[0].Code)
+
+	// A condition joined by OR cannot reach past the tenant
+	products = nil
+	s.Require().NoError(s.as("beta").Where("code = ?", "beta-weekly").Or("code = ?", "alpha-weekly").Find(&products).Error)
+	s.Require().Len(products, 1)
+	s.Equal("beta-weekly", products[0].Code)
+
+	var count int64
+	s.Require().NoError(s.as("beta").Model(&models.LoanProduct{}).Count(&count).Error)
+	s.Equal(int64(1), count)
+
+	// The system itself, and unscoped reads, see every tenant
+	s.Require().NoError(s.DB.Model(&models.LoanProduct{}).Count(&count).Error)
+	s.Equal(int64(2), count)
+	unscoped := tenant.Unscoped(tenant.WithID(context.Background(), "beta"))
+	s.Require().NoError(s.DB.WithContext(unscoped).Model(&models.LoanProduct{}).Count(&count).Error)
+	s.Equal(int64(2), count)
+}
+
+// TestChangesOnlyReachOwnTenant tests that updates and deletes leave another tenant's rows alone
+func (s *PluginTestSuite) TestChangesOnlyReachOwnTenant() {
+	s.createProducts()
+
+	result := s.as("beta").Model(&models.LoanProduct{Code: "alpha-weekly"}).Update("name", "Taken")
+	s.Require().NoError(result.Error)
+	s.Equal(int64(0), result.RowsAffected)
+
+	result = s.as("beta").Model(&models.LoanProduct{}).Where("code <> ?", "").Update("default_after_dpd", 5)
+	s.Require().NoError(result.Error)
+	s.Equal(int64(1), result.RowsAffected)
+
+	result = s.as("beta").Where("code = ?", "alpha-weekly").Delete(&models.LoanProduct{})
+	s.Require().NoError(result.Error)
+	s.Equal(int64(0), result.RowsAffected)
+
+	var alpha models.LoanProduct
+	s.Require().NoError(s.DB.Where("code = ?", "alpha-weekly").First(&alpha).Error)
+	s.Equal("Alpha weekly", alpha.Name)
+	s.Equal(uint(0), alpha.DefaultAfterDPD)
+
+	// Statements without conditions are still refused
+	err := s.as("beta").Model(&models.LoanProduct{}).Update("name", "Everything").Error
+	s.True(errors.Is(err, gorm.ErrMissingWhereClause))
+}
+
+// TestSaveCannotTakeOverAnotherTenantsRow tests that an upsert leaves a row of another tenant with the same key alone
+func (s *PluginTestSuite) TestSaveCannotTakeOverAnotherTenantsRow() {
+	s.createProducts()
+
+	s.Require().NoError(s.as("beta").Save(&models.LoanProduct{Code: "alpha-weekly", Name: "Taken", DefaultAfterDPD: 1}).Error)
+
+	var alpha models.LoanProduct
+	s.Require().NoError(s.DB.Where("code = ?", "alpha-weekly").First(&alpha).Error)
+	s.Equal("alpha", alpha.TenantID)
+	s.Equal("Alpha weekly", alpha.Name)
+	s.Equal(uint(0), alpha.DefaultAfterDPD)
+}
+
+// TestPluginTestSuite runs the test suite
+func TestPluginTestSuite(t *testing.T) {
+	suite.Run(t, new(PluginTestSuite))
+}